@@ -6,6 +6,7 @@ import (
 	"runtime"
 
 	"objectsync/internal/app"
+	"objectsync/internal/i18n"
 )
 
 // 版本信息变量（构建时注入）
@@ -25,10 +26,10 @@ func init() {
 }
 
 func main() {
-	app := app.NewApp()
-	app.SetVersion(Version, BuildTime, GitCommit)
-	if err := app.Run(); err != nil {
-		log.Printf("错误: %v", err)
-		os.Exit(1)
+	objectSync := app.NewApp()
+	objectSync.SetVersion(Version, BuildTime, GitCommit)
+	if err := objectSync.Run(); err != nil {
+		log.Print(i18n.Sprintf("错误: %v", err))
+		os.Exit(app.ExitCode(err))
 	}
 }
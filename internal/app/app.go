@@ -1,26 +1,39 @@
 package app
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"runtime"
+	"strings"
 	"time"
 
 	"objectsync/internal/backup"
 	"objectsync/internal/config"
+	"objectsync/internal/i18n"
 	"objectsync/internal/progress"
+	"objectsync/internal/ratelimit"
+	"objectsync/internal/replicate"
+	"objectsync/internal/s3client"
+	"objectsync/internal/state"
+	"objectsync/internal/statestore"
 	"objectsync/internal/upload"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
 )
 
 type App struct {
 	rootCmd   *cobra.Command
+	report    *commandReport // 当前命令的结构化结果，仅在 --output json 时非空
 	version   string
 	buildTime string
 	gitCommit string
+	// stopProfiling 命令结束时停止性能分析，未启用时为nil
+	stopProfiling func()
 }
 
 func NewApp() *App {
@@ -54,27 +67,161 @@ func (a *App) Run() error {
 	// 检查是否有参数，如果没有参数直接启动菜单
 	if len(os.Args) == 1 {
 		// 没有参数，直接启动交互式菜单
+		if err := applyLanguage("", config.Locate("")); err != nil {
+			return err
+		}
+		// 从定时任务或管道运行时无法交互，显示用法后退出而不是一直等待输入
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			a.rootCmd.Usage()
+			return withExitCode(ExitUsage, i18n.Errorf("标准输入不是终端，不能启动交互式菜单，请指定要执行的子命令"))
+		}
 		return a.runMenu(a.rootCmd, []string{})
 	}
-	// 有参数，正常执行cobra命令
-	return a.rootCmd.Execute()
+	// 有参数，正常执行cobra命令。帮助和用法不经过PersistentPreRunE，先确定语言并翻译命令说明
+	lang, configFile := peekLanguageFlags(os.Args[1:])
+	if err := applyLanguage(lang, config.Locate(configFile)); err != nil {
+		return err
+	}
+	translateCommands(a.rootCmd)
+	err := a.rootCmd.Execute()
+	if a.stopProfiling != nil {
+		a.stopProfiling()
+	}
+	return err
 }
 
+// configFlagUsage 各命令 --config 参数的说明
+const configFlagUsage = "配置文件路径（默认使用 OBJECTSYNC_CONFIG 环境变量，或依次查找 ./config.yaml、$XDG_CONFIG_HOME/objectsync/config.yaml（未设置时为 ~/.config/objectsync/config.yaml）、/etc/objectsync/config.yaml）"
+
 func (a *App) initCommands() {
 	a.rootCmd = &cobra.Command{
 		Use:   "objectsync",
 		Short: "对象存储同步工具",
 		Long:  "一个用于与S3兼容对象存储进行数据同步的工具，支持下载和上传功能，支持增量同步",
 		RunE:  a.runDefault, // 智能默认行为
+		// 输入未知的子命令时提示编辑距离相近的命令
+		SuggestionsMinimumDistance: 2,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// 未指定 --config 时按环境变量和搜索路径确定配置文件，命令中直接使用查找到的路径
+			if flag := cmd.Flags().Lookup("config"); flag != nil && !flag.Changed {
+				flag.Value.Set(config.Locate(""))
+			}
+			lang, _ := cmd.Flags().GetString("lang")
+			configFile, _ := cmd.Flags().GetString("config")
+			if err := applyLanguage(lang, configFile); err != nil {
+				return err
+			}
+			if err := setupLogging(cmd, false); err != nil {
+				return err
+			}
+			stop, err := startProfiling(cmd)
+			if err != nil {
+				return err
+			}
+			a.stopProfiling = stop
+			return nil
+		},
 	}
 
+	a.rootCmd.PersistentFlags().String("output", outputText, "输出格式: text 或 json（适用于 backup、upload、run、sync、verify、status、ls、du、config validate）")
+	a.rootCmd.PersistentFlags().String("lang", "", "输出语言: zh 或 en（默认使用配置文件的 language 或 LANG 环境变量）")
+	a.rootCmd.PersistentFlags().BoolP("quiet", "q", false, "只输出错误和一行总结，适合定时任务（适用范围同 --output）")
+	a.rootCmd.PersistentFlags().Bool("non-interactive", false, "不启动交互式菜单和确认提示，需要输入时直接报错（标准输入不是终端时自动启用）")
+	addLoggingFlags(a.rootCmd)
+	addProfilingFlags(a.rootCmd)
+
 	// 添加子命令
 	a.rootCmd.AddCommand(a.newBackupCmd())
 	a.rootCmd.AddCommand(a.newUploadCmd())
+	a.rootCmd.AddCommand(a.newVerifyCmd())
+	a.rootCmd.AddCommand(a.newPruneCmd())
+	a.rootCmd.AddCommand(a.newPutCmd())
+	a.rootCmd.AddCommand(a.newLsCmd())
+	a.rootCmd.AddCommand(a.newRmCmd())
+	a.rootCmd.AddCommand(a.newCpCmd())
+	a.rootCmd.AddCommand(a.newDuCmd())
+	a.rootCmd.AddCommand(a.newPresignCmd())
+	a.rootCmd.AddCommand(a.newMbCmd())
+	a.rootCmd.AddCommand(a.newRbCmd())
+	a.rootCmd.AddCommand(a.newDaemonCmd())
+	a.rootCmd.AddCommand(a.newServiceCmd())
+	a.rootCmd.AddCommand(a.newSystemdCmd())
+	a.rootCmd.AddCommand(a.newHealthcheckCmd())
+	a.rootCmd.AddCommand(a.newDoctorCmd())
+	a.rootCmd.AddCommand(a.newRunCmd())
+	a.rootCmd.AddCommand(a.newMigrateCmd())
+	a.rootCmd.AddCommand(a.newSyncCmd())
 	a.rootCmd.AddCommand(a.newConfigCmd())
 	a.rootCmd.AddCommand(a.newStatusCmd())
+	a.rootCmd.AddCommand(a.newStateCmd())
+	a.rootCmd.AddCommand(a.newHistoryCmd())
+	a.rootCmd.AddCommand(a.newReportCmd())
 	a.rootCmd.AddCommand(a.newVersionCmd())
 	a.rootCmd.AddCommand(a.newMenuCmd()) // 添加交互式菜单命令
+	a.rootCmd.AddCommand(a.newCompletionCmd())
+
+	// 命令行参数错误使用单独的退出码，包括参数个数不对和未知的子命令
+	a.rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return withExitCode(ExitUsage, err)
+	})
+	a.rootCmd.Args = unknownCommand
+	usageArgs(a.rootCmd)
+
+	// 使用自定义的completion命令，补全配置中的桶名
+	a.rootCmd.CompletionOptions.DisableDefaultCmd = true
+}
+
+// applyLanguage 按 --lang 参数、配置文件的 language、LANG环境变量的顺序设置输出语言，默认中文。
+// 配置文件中的无效值留给配置验证报告
+func applyLanguage(lang, configFile string) error {
+	if lang != "" {
+		return i18n.SetLanguage(lang)
+	}
+	if configFile != "" {
+		lang = i18n.Normalize(config.PeekLanguage(configFile))
+	}
+	if lang == "" {
+		lang = i18n.Detect()
+	}
+	if lang != "" {
+		return i18n.SetLanguage(lang)
+	}
+	return nil
+}
+
+// peekLanguageFlags 在解析命令行之前读取 --lang 和 --config 参数的值
+func peekLanguageFlags(args []string) (lang, configFile string) {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name, value, hasValue := strings.Cut(arg, "=")
+		if !hasValue && i+1 < len(args) {
+			value = args[i+1]
+		}
+		switch name {
+		case "--lang":
+			lang = value
+		case "--config", "-c":
+			configFile = value
+		}
+	}
+	return lang, configFile
+}
+
+// translateCommands 将命令及其子命令的说明和参数帮助翻译为当前语言
+func translateCommands(cmd *cobra.Command) {
+	cmd.Use = i18n.T(cmd.Use)
+	cmd.Short = i18n.T(cmd.Short)
+	cmd.Long = i18n.T(cmd.Long)
+	translate := func(flag *pflag.Flag) {
+		flag.Usage = i18n.T(flag.Usage)
+	}
+	cmd.Flags().VisitAll(translate)
+	cmd.PersistentFlags().VisitAll(translate)
+	for _, sub := range cmd.Commands() {
+		translateCommands(sub)
+	}
 }
 
 func (a *App) newBackupCmd() *cobra.Command {
@@ -82,17 +229,24 @@ func (a *App) newBackupCmd() *cobra.Command {
 		Use:   "backup",
 		Short: "执行备份操作",
 		Long:  "从配置文件中指定的所有桶下载对象到本地，支持增量备份，自动创建本地目录",
-		RunE:  a.runBackup,
+		RunE:  a.withReport(a.runBackup),
 	}
 
 	// 添加命令行参数
-	cmd.Flags().StringP("config", "c", "config.yaml", "配置文件路径")
+	cmd.Flags().StringP("config", "c", "", configFlagUsage)
 	cmd.Flags().StringP("endpoint", "e", "", "Ceph对象存储端点URL (覆盖配置文件)")
 	cmd.Flags().StringP("access-key", "a", "", "访问密钥 (覆盖配置文件)")
 	cmd.Flags().StringP("secret-key", "s", "", "秘密密钥 (覆盖配置文件)")
+	cmd.Flags().String("region", "", "请求签名使用的区域 (覆盖配置文件)")
+	cmd.Flags().String("cluster", "", "只处理指定集群（clusters 或 remotes 中的名称）下的桶")
 	cmd.Flags().BoolP("incremental", "i", true, "启用增量备份")
+	cmd.Flags().Bool("resume", true, "上次运行中断时跳过已完成的对象继续 (--resume=false 重新开始)")
+	cmd.Flags().StringToString("label", nil, "运行标签 key=value，记录在运行历史和通知中（可重复）")
 	cmd.Flags().IntP("workers", "w", 5, "并发下载工作数")
+	cmd.Flags().Float64("max-requests-per-second", 0, "每秒最多发送的请求数，所有桶和工作协程共享 (0表示不限制)")
 	cmd.Flags().BoolP("verbose", "v", false, "详细输出")
+	cmd.Flags().Bool("worker-progress", false, "在进度条下方显示每个正在传输的文件的进度和速度，便于发现卡住的传输（仅终端，--verbose 时不显示）")
+	addBucketFlags(cmd)
 
 	return cmd
 }
@@ -102,17 +256,36 @@ func (a *App) newUploadCmd() *cobra.Command {
 		Use:   "upload",
 		Short: "执行上传操作",
 		Long:  "将本地文件上传到配置文件中指定的所有桶，支持增量上传，自动创建不存在的存储桶",
-		RunE:  a.runUpload,
+		RunE:  a.withReport(a.runUpload),
 	}
 
 	// 添加命令行参数
-	cmd.Flags().StringP("config", "c", "config.yaml", "配置文件路径")
+	cmd.Flags().StringP("config", "c", "", configFlagUsage)
 	cmd.Flags().StringP("endpoint", "e", "", "Ceph对象存储端点URL (覆盖配置文件)")
 	cmd.Flags().StringP("access-key", "a", "", "访问密钥 (覆盖配置文件)")
 	cmd.Flags().StringP("secret-key", "s", "", "秘密密钥 (覆盖配置文件)")
+	cmd.Flags().String("region", "", "请求签名使用的区域 (覆盖配置文件)")
+	cmd.Flags().String("cluster", "", "只处理指定集群（clusters 或 remotes 中的名称）下的桶")
 	cmd.Flags().BoolP("incremental", "i", true, "启用增量上传")
+	cmd.Flags().Bool("resume", true, "上次运行中断时跳过已上传的文件继续 (--resume=false 重新开始)")
+	cmd.Flags().StringToString("label", nil, "运行标签 key=value，记录在运行历史和通知中（可重复）")
 	cmd.Flags().IntP("workers", "w", 5, "并发上传工作数")
+	cmd.Flags().Int("scan-workers", 8, "并发扫描本地目录数")
+	cmd.Flags().Int("parts-concurrency", 0, "单个大文件同时上传的分片数 (0表示使用配置文件中的值)")
+	cmd.Flags().Float64("max-requests-per-second", 0, "每秒最多发送的请求数，所有桶和工作协程共享 (0表示不限制)")
+	cmd.Flags().Bool("verify-upload", false, "上传后通过HEAD请求校验对象大小和ETag")
+	cmd.Flags().Duration("stable-window", 0, "文件稳定检查窗口，窗口内大小或修改时间仍在变化的文件推迟上传 (如 5s，0表示不检查)")
+	cmd.Flags().String("max-upload-size", "", "单个文件大小上限，超过的文件跳过并在总结中列出 (如 10GB)")
+	cmd.Flags().Bool("vss", false, "文件被其他进程占用时从卷影副本读取（仅Windows，需要管理员权限）")
+	cmd.Flags().String("checksum-algorithm", "", "上传时附加的校验算法 (CRC32, CRC32C, SHA1, SHA256)")
+	cmd.Flags().String("pack-threshold", "", "小于该大小的文件打包为tar对象上传，下载时自动解包 (如 256KB)")
+	cmd.Flags().String("pack-size", "64MB", "单个打包对象的目标大小")
+	cmd.Flags().String("on-conflict", "", "覆盖前检查远程对象是否比本地文件新: warn 警告后覆盖, skip 跳过 (默认不检查)")
+	cmd.Flags().Bool("force", false, "忽略 --on-conflict，始终覆盖远程对象")
+	cmd.Flags().Bool("dedupe", false, "内容相同的文件（包括重命名的文件）使用服务端复制代替重复上传")
 	cmd.Flags().BoolP("verbose", "v", false, "详细输出")
+	cmd.Flags().Bool("worker-progress", false, "在进度条下方显示每个正在传输的文件的进度和速度，便于发现卡住的传输（仅终端，--verbose 时不显示）")
+	addBucketFlags(cmd)
 
 	return cmd
 }
@@ -128,9 +301,9 @@ func (a *App) newConfigCmd() *cobra.Command {
 		Use:   "validate",
 		Short: "验证配置",
 		Long:  "验证配置文件是否正确，测试Ceph连接",
-		RunE:  a.runValidate,
+		RunE:  a.withReport(a.runValidate),
 	}
-	validateCmd.Flags().StringP("config", "c", "config.yaml", "配置文件路径")
+	validateCmd.Flags().StringP("config", "c", "", configFlagUsage)
 
 	initCmd := &cobra.Command{
 		Use:   "init",
@@ -138,24 +311,230 @@ func (a *App) newConfigCmd() *cobra.Command {
 		Long:  "交互式创建配置文件",
 		RunE:  a.runInit,
 	}
-	initCmd.Flags().StringP("output", "o", "config.yaml", "输出配置文件路径")
+	initCmd.Flags().StringP("output", "o", config.DefaultConfigFile, "输出配置文件路径")
+
+	setSecretCmd := &cobra.Command{
+		Use:   "set-secret <keyring:服务名/账户名>",
+		Short: "保存密钥到系统密钥环",
+		Long:  "将密钥保存到系统密钥环（Windows凭据管理器、macOS钥匙串或Secret Service），之后可在配置文件的 access_key/secret_key 中以相同的引用代替明文",
+		Args:  cobra.ExactArgs(1),
+		RunE:  a.runSetSecret,
+	}
+
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "迁移旧版配置",
+		Long:  "将旧版单桶配置（ceph.bucket 或顶层 bucket）转换为 buckets 数组，修改前自动备份原配置文件",
+		RunE:  a.runMigrate,
+	}
+	migrateCmd.Flags().StringP("config", "c", "", configFlagUsage)
+
+	discoverCmd := &cobra.Command{
+		Use:   "discover",
+		Short: "从对象存储发现桶",
+		Long:  "使用配置的连接列出对象存储中的所有桶，将尚未配置的桶逐个确认后添加到配置文件",
+		RunE:  a.runDiscover,
+	}
+	discoverCmd.Flags().StringP("config", "c", "", configFlagUsage)
+	discoverCmd.Flags().Bool("all", false, "添加所有未配置的桶，不逐个确认")
+
+	lintCmd := &cobra.Command{
+		Use:   "lint",
+		Short: "检查配置文件",
+		Long:  "检查配置文件中的废弃或未知配置项、不会生效的配置项、未替换的示例值，并列出与默认值不同的设置，不连接对象存储",
+		RunE:  a.runLint,
+	}
+	lintCmd.Flags().StringP("config", "c", "", configFlagUsage)
 
 	cmd.AddCommand(validateCmd)
+	cmd.AddCommand(lintCmd)
 	cmd.AddCommand(initCmd)
+	cmd.AddCommand(setSecretCmd)
+	cmd.AddCommand(migrateCmd)
+	cmd.AddCommand(discoverCmd)
 
 	return cmd
 }
 
+func (a *App) runSetSecret(cmd *cobra.Command, args []string) error {
+	ref := args[0]
+	if _, _, ok, err := config.ParseKeyringRef(ref); err != nil {
+		return err
+	} else if !ok {
+		return i18n.Errorf("密钥环引用必须以 %s 开头: %s", config.KeyringPrefix, ref)
+	}
+
+	// 从标准输入读取密钥，终端输入时不回显，也支持管道输入
+	var secret string
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		i18n.Printf("请输入要保存的密钥: ")
+		data, err := term.ReadPassword(fd)
+		fmt.Println()
+		if err != nil {
+			return i18n.Errorf("读取密钥失败: %w", err)
+		}
+		secret = string(data)
+	} else {
+		reader := bufio.NewReader(os.Stdin)
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return i18n.Errorf("读取密钥失败: %w", err)
+		}
+		secret = strings.TrimRight(line, "\r\n")
+	}
+	if secret == "" {
+		return i18n.Errorf("密钥不能为空")
+	}
+
+	if err := config.SetKeyringSecret(ref, secret); err != nil {
+		return i18n.Errorf("保存密钥失败: %w", err)
+	}
+
+	i18n.Printf("密钥已保存，可在配置文件中使用: %s\n", ref)
+	return nil
+}
+
+func (a *App) runMigrate(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+
+	result, err := config.Migrate(configFile)
+	if err != nil {
+		return i18n.Errorf("配置迁移失败: %w", err)
+	}
+	if len(result.Changes) == 0 {
+		i18n.Printf("配置文件 %s 已是多桶格式，无需迁移\n", configFile)
+		return nil
+	}
+
+	i18n.Printf("配置文件已迁移: %s\n", configFile)
+	for _, change := range result.Changes {
+		fmt.Printf("  - %s\n", change)
+	}
+	i18n.Printf("原配置文件已备份到: %s\n", result.BackupPath)
+	return nil
+}
+
+func (a *App) runLint(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+
+	issues, err := config.Lint(configFile)
+	if err != nil {
+		return i18n.Errorf("配置检查失败: %w", err)
+	}
+	if len(issues) == 0 {
+		i18n.Printf("配置文件 %s 未发现问题\n", configFile)
+		return nil
+	}
+
+	errorCount := 0
+	for _, issue := range issues {
+		fmt.Printf("[%s] %s\n", issue.Level, issue.Message)
+		if issue.Level == config.LintError {
+			errorCount++
+		}
+	}
+	if errorCount > 0 {
+		return i18n.Errorf("配置文件有 %d 个错误", errorCount)
+	}
+	return nil
+}
+
+func (a *App) runDiscover(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	all, _ := cmd.Flags().GetBool("all")
+
+	configManager := config.NewConfigManager(configFile)
+	cfg, err := configManager.LoadConfig()
+	if err != nil {
+		return configError(i18n.Errorf("配置加载失败: %w", err))
+	}
+	// 只需要连接配置，buckets可以为空
+	if err := configManager.ValidateConnection(); err != nil {
+		return configError(i18n.Errorf("配置验证失败: %w", err))
+	}
+
+	settings := configManager.ToBucketSettings()
+	b := backup.New(&backup.Options{
+		Endpoint:      settings.Endpoint,
+		AccessKey:     settings.AccessKey,
+		SecretKey:     settings.SecretKey,
+		Profile:       settings.Profile,
+		Region:        settings.Region,
+		VirtualHosted: !settings.PathStyle,
+		Transport:     settings.Transport,
+		TLS:           tlsOptions(settings.TLS),
+		Proxy:         proxyOptions(settings.Proxy),
+		Timeouts:      timeoutOptions(settings.Timeouts),
+	})
+	names, err := b.ListBuckets()
+	if err != nil {
+		return i18n.Errorf("列出桶失败: %w", err)
+	}
+
+	configured := make(map[string]bool)
+	for _, bucket := range cfg.Buckets {
+		configured[bucket.Name] = true
+	}
+
+	// 输出目录默认放在backup.output_dir下，未设置时放在./backup下
+	baseDir := cfg.Backup.OutputDir
+	if baseDir == "" {
+		baseDir = "./backup"
+	}
+
+	var added []config.BucketConfig
+	reader := bufio.NewReader(os.Stdin)
+	for _, name := range names {
+		if configured[name] {
+			continue
+		}
+		if !all {
+			i18n.Printf("添加桶 %s? (y/N): ", name)
+			response, _ := reader.ReadString('\n')
+			response = strings.TrimSpace(response)
+			if response != "y" && response != "Y" {
+				continue
+			}
+		}
+		added = append(added, config.BucketConfig{
+			Name:      name,
+			OutputDir: strings.TrimRight(baseDir, "/\\") + "/" + name,
+			StateFile: state.FileName(state.Backup, "", name, ""),
+		})
+	}
+
+	if len(added) == 0 {
+		i18n.Printf("发现 %d 个桶，没有需要添加的桶\n", len(names))
+		return nil
+	}
+
+	backupPath, err := config.AppendBuckets(configFile, added)
+	if err != nil {
+		return i18n.Errorf("更新配置文件失败: %w", err)
+	}
+
+	i18n.Printf("已添加 %d 个桶到 %s:\n", len(added), configFile)
+	for _, bucket := range added {
+		fmt.Printf("  - %s -> %s\n", bucket.Name, bucket.OutputDir)
+	}
+	i18n.Printf("原配置文件已备份到: %s\n", backupPath)
+	return nil
+}
+
 func (a *App) newStatusCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "查看备份状态",
-		Long:  "查看上次备份状态和统计信息",
-		RunE:  a.runStatus,
+		Long:  "查看上次备份状态和统计信息；--remote 连接对象存储，统计每个桶下次运行需要下载和上传的文件",
+		RunE:  a.withReport(a.runStatus),
 	}
 
-	cmd.Flags().StringP("config", "c", "config.yaml", "配置文件路径")
+	cmd.Flags().StringP("config", "c", "", configFlagUsage)
 	cmd.Flags().StringP("state-file", "f", ".backup_state.json", "状态文件路径")
+	cmd.Flags().Bool("remote", false, "连接对象存储，与状态记录和本地文件比较，统计待下载和待上传的文件")
+	cmd.Flags().String("cluster", "", "只处理指定集群（clusters 或 remotes 中的名称）下的桶（需要 --remote）")
+	cmd.Flags().Float64("max-requests-per-second", 0, "每秒最多发送的请求数，所有桶共享 (0表示不限制)")
+	addBucketFlags(cmd)
 
 	return cmd
 }
@@ -175,10 +554,13 @@ func (a *App) newMenuCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "menu",
 		Short: "交互式菜单（默认行为）",
-		Long:  "提供交互式菜单界面，这也是直接运行 objectsync 的默认行为",
+		Long:  "提供全屏交互界面：桶列表和实时进度、日志窗口、开始/停止任务和编辑配置，这也是直接运行 objectsync 的默认行为",
 		RunE:  a.runMenu,
 	}
 
+	cmd.Flags().StringP("config", "c", "", configFlagUsage)
+	cmd.Flags().Bool("plain", false, "使用逐行输入的文本菜单，适合不支持全屏界面的终端")
+
 	return cmd
 }
 
@@ -188,15 +570,29 @@ func (a *App) runDefault(cmd *cobra.Command, args []string) error {
 	return a.runMenu(cmd, args)
 }
 
+// interactive 是否可以等待用户输入：没有指定 --non-interactive 且标准输入是终端
+func interactive(cmd *cobra.Command) bool {
+	nonInteractive, _ := cmd.Flags().GetBool("non-interactive")
+	return !nonInteractive && term.IsTerminal(int(os.Stdin.Fd()))
+}
+
 func (a *App) runBackup(cmd *cobra.Command, args []string) error {
 	// 获取命令行参数
 	configFile, _ := cmd.Flags().GetString("config")
 	endpoint, _ := cmd.Flags().GetString("endpoint")
 	accessKey, _ := cmd.Flags().GetString("access-key")
 	secretKey, _ := cmd.Flags().GetString("secret-key")
+	region, _ := cmd.Flags().GetString("region")
+	cluster, _ := cmd.Flags().GetString("cluster")
+	buckets, _ := cmd.Flags().GetStringSlice("bucket")
+	excluded, _ := cmd.Flags().GetStringSlice("exclude-bucket")
 	incremental, _ := cmd.Flags().GetBool("incremental")
+	resume, _ := cmd.Flags().GetBool("resume")
+	labels, _ := cmd.Flags().GetStringToString("label")
 	workers, _ := cmd.Flags().GetInt("workers")
+	maxRequests, _ := cmd.Flags().GetFloat64("max-requests-per-second")
 	verbose, _ := cmd.Flags().GetBool("verbose")
+	workerProgress, _ := cmd.Flags().GetBool("worker-progress")
 
 	// 创建配置管理器
 	configManager := config.NewConfigManager(configFile)
@@ -205,46 +601,68 @@ func (a *App) runBackup(cmd *cobra.Command, args []string) error {
 	_, err := configManager.LoadConfig()
 	if err != nil {
 		// 如果是因为需要配置文件而失败，直接退出
-		if configFile == "config.yaml" {
-			return fmt.Errorf("配置加载失败: %w", err)
+		if !cmd.Flags().Changed("config") {
+			return configError(i18n.Errorf("配置加载失败: %w", err))
 		} else {
-			return fmt.Errorf("配置文件 %s 加载失败: %w", configFile, err)
+			return configError(i18n.Errorf("配置文件 %s 加载失败: %w", configFile, err))
 		}
 	}
 
 	// 验证配置
 	if err := configManager.ValidateConfig(); err != nil {
-		return fmt.Errorf("配置验证失败: %w", err)
+		return configError(i18n.Errorf("配置验证失败: %w", err))
+	}
+
+	// 没有指定 --workers 时使用每个桶配置的并发数
+	if !cmd.Flags().Changed("workers") {
+		workers = 0
 	}
 
 	// 统一处理所有桶的备份
-	return a.runBucketsBackup(configManager, endpoint, accessKey, secretKey, incremental, verbose, workers)
+	return a.runBucketsBackup(configManager, endpoint, accessKey, secretKey, region, cluster, buckets, excluded, labels, incremental, resume, verbose, workerProgress, workers, ratelimit.New(maxRequests))
 }
 
-// runBucketsBackup 统一执行桶备份
-func (a *App) runBucketsBackup(configManager *config.ConfigManager, endpoint, accessKey, secretKey string, incremental, verbose bool, workers int) error {
+// runBucketsBackup 统一执行桶备份，workers大于0时覆盖每个桶配置的并发数
+func (a *App) runBucketsBackup(configManager *config.ConfigManager, endpoint, accessKey, secretKey, region, cluster string, buckets, excluded []string, labels map[string]string, incremental, resume, verbose, workerProgress bool, workers int, limiter *ratelimit.Limiter) error {
 	// 获取桶配置
 	settings := configManager.ToBucketSettings()
-
-	// 用命令行参数覆盖连接配置
-	if endpoint != "" {
-		settings.Endpoint = endpoint
+	if cluster != "" {
+		if err := settings.FilterCluster(cluster); err != nil {
+			return withExitCode(ExitUsage, err)
+		}
+	}
+	if err := settings.FilterBuckets(buckets); err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	if err := settings.ExcludeBuckets(excluded); err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	// 复制的桶不经过本地目录，由run命令处理
+	if err := settings.ExcludeDirection(config.DirectionReplicate); err != nil {
+		return withExitCode(ExitUsage, err)
 	}
-	if accessKey != "" {
-		settings.AccessKey = accessKey
+	// 只有 source_dirs 的上传桶没有可以下载到的目录
+	skipped, err := settings.ExcludeNoOutputDir()
+	for _, bucket := range skipped {
+		i18n.Printf("跳过桶 %s：没有设置 output_dir，只能上传\n", bucket.ID())
 	}
-	if secretKey != "" {
-		settings.SecretKey = secretKey
+	if err != nil {
+		return withExitCode(ExitUsage, err)
 	}
+
+	// 用命令行参数覆盖连接配置
+	settings.OverrideConnection(endpoint, accessKey, secretKey, region)
 	settings.Incremental = incremental
+	settings.Resume = resume
+	settings.Labels = labels
 
 	// 备份配置中的所有桶
 	bucketCount := len(settings.Buckets)
-	fmt.Printf("开始备份（共 %d 个桶）\n", bucketCount)
-	fmt.Printf("连接信息: %s\n", settings.Endpoint)
+	i18n.Printf("开始备份（共 %d 个桶）\n", bucketCount)
+	i18n.Printf("连接信息: %s\n", bucketEndpoints(settings))
 
 	if verbose {
-		fmt.Printf("桶列表:\n")
+		i18n.Printf("桶列表:\n")
 		for i, bucket := range settings.Buckets {
 			fmt.Printf("  %d. %s -> %s\n", i+1, bucket.Name, bucket.OutputDir)
 		}
@@ -253,51 +671,58 @@ func (a *App) runBucketsBackup(configManager *config.ConfigManager, endpoint, ac
 
 	// 逐个备份每个桶
 	successCount := 0
-	failureCount := 0
+	var failures []error
+	results := newRunResults("backup")
+	total := newTotalProgress(bucketCount, workerProgress && !verbose)
 
 	for i, bucketSettings := range settings.Buckets {
-		fmt.Printf("\n[%d/%d] 备份桶: %s\n", i+1, bucketCount, bucketSettings.Name)
+		i18n.Printf("\n[%d/%d] 备份桶: %s\n", i+1, bucketCount, bucketSettings.Name)
+		if total != nil {
+			total.StartBucket()
+		}
 
 		// 为每个桶创建备份选项
-		options := &backup.Options{
-			Endpoint:    settings.Endpoint,
-			AccessKey:   settings.AccessKey,
-			SecretKey:   settings.SecretKey,
-			Bucket:      bucketSettings.Name,
-			OutputDir:   bucketSettings.OutputDir,
-			Incremental: settings.Incremental,
-			StateFile:   bucketSettings.StateFile,
-			Workers:     bucketSettings.Workers,
-			Verbose:     bucketSettings.Verbose || verbose,
+		options := bucketBackupOptions(settings, bucketSettings, limiter)
+		options.Parent = total
+		options.WorkerProgress = workerProgress
+		options.Verbose = options.Verbose || verbose
+		if workers > 0 {
+			options.Workers = workers
 		}
 
 		if options.Verbose {
-			fmt.Printf("  端点: %s\n", options.Endpoint)
-			fmt.Printf("  桶名: %s\n", options.Bucket)
-			fmt.Printf("  输出目录: %s\n", options.OutputDir)
-			fmt.Printf("  增量备份: %v\n", options.Incremental)
-			fmt.Printf("  并发数: %d\n", options.Workers)
+			i18n.Printf("  端点: %s\n", options.Endpoint)
+			i18n.Printf("  桶名: %s\n", options.Bucket)
+			i18n.Printf("  输出目录: %s\n", options.OutputDir)
+			i18n.Printf("  增量备份: %v\n", options.Incremental)
+			i18n.Printf("  并发数: %d\n", options.Workers)
 			fmt.Printf("\n")
 		}
 
 		// 创建备份器并执行备份
 		b := backup.New(options)
-		if err := b.Run(); err != nil {
-			fmt.Printf("桶 %s 备份失败: %v\n", bucketSettings.Name, err)
-			failureCount++
+		err := b.Run()
+		a.report.addBucket(bucketSettings.Name, b.Stats(), err)
+		results.add(bucketSettings.Name, b.Stats(), err)
+		if err != nil {
+			i18n.Printf("桶 %s 备份失败: %v\n", bucketSettings.Name, err)
+			failures = append(failures, bucketError(err, b.Stats()))
 			continue
 		}
 
-		fmt.Printf("桶 %s 备份完成!\n", bucketSettings.Name)
+		i18n.Printf("桶 %s 备份完成!\n", bucketSettings.Name)
 		successCount++
 	}
 
+	finishRun(settings, results)
+
 	// 显示备份总结
-	fmt.Printf("\n备份完成!\n")
-	fmt.Printf("成功: %d 个桶\n", successCount)
-	if failureCount > 0 {
-		fmt.Printf("失败: %d 个桶\n", failureCount)
-		return fmt.Errorf("部分桶备份失败")
+	i18n.Printf("\n备份完成!\n")
+	i18n.Printf("成功: %d 个桶\n", successCount)
+	printTotalProgress(total)
+	if len(failures) > 0 {
+		i18n.Printf("失败: %d 个桶\n", len(failures))
+		return bucketsError(i18n.Errorf("部分桶备份失败"), successCount, failures)
 	}
 
 	return nil
@@ -306,7 +731,7 @@ func (a *App) runBucketsBackup(configManager *config.ConfigManager, endpoint, ac
 func (a *App) runValidate(cmd *cobra.Command, args []string) error {
 	configFile, _ := cmd.Flags().GetString("config")
 
-	fmt.Printf("验证配置文件: %s\n", configFile)
+	i18n.Printf("验证配置文件: %s\n", configFile)
 
 	// 创建配置管理器
 	configManager := config.NewConfigManager(configFile)
@@ -314,110 +739,118 @@ func (a *App) runValidate(cmd *cobra.Command, args []string) error {
 	// 加载配置文件
 	_, err := configManager.LoadConfig()
 	if err != nil {
-		fmt.Printf("配置加载失败: %v\n", err)
-		return err
+		i18n.Printf("配置加载失败: %v\n", err)
+		return configError(err)
 	}
 
 	// 验证配置
 	if err := configManager.ValidateConfig(); err != nil {
-		fmt.Printf("配置验证失败: %v\n", err)
-		return err
+		i18n.Printf("配置验证失败: %v\n", err)
+		return configError(err)
+	}
+
+	// 检查本地目录和状态文件
+	if err := configManager.ValidatePaths(); err != nil {
+		i18n.Printf("本地路径检查失败:\n%v\n", err)
+		return configError(i18n.Errorf("本地路径检查失败"))
 	}
 
-	fmt.Println("配置文件验证通过!")
+	i18n.Println("配置文件验证通过!")
 
 	// 测试连接
-	fmt.Println("测试Ceph连接...")
+	i18n.Println("测试Ceph连接...")
 	settings := configManager.ToBucketSettings()
 
 	// 测试第一个桶的连接
 	if len(settings.Buckets) == 0 {
-		fmt.Printf("没有配置要测试的桶\n")
-		return fmt.Errorf("配置中没有桶信息")
+		i18n.Printf("没有配置要测试的桶\n")
+		return configError(i18n.Errorf("配置中没有桶信息"))
 	}
 
 	firstBucket := settings.Buckets[0]
 	options := &backup.Options{
-		Endpoint:  settings.Endpoint,
-		AccessKey: settings.AccessKey,
-		SecretKey: settings.SecretKey,
-		Bucket:    firstBucket.Name,
+		Endpoint:      firstBucket.Endpoint,
+		AccessKey:     firstBucket.AccessKey,
+		SecretKey:     firstBucket.SecretKey,
+		Profile:       firstBucket.Profile,
+		Region:        firstBucket.Region,
+		VirtualHosted: !firstBucket.PathStyle,
+		Transport:     firstBucket.Transport,
+		TLS:           tlsOptions(firstBucket.TLS),
+		Proxy:         proxyOptions(firstBucket.Proxy),
+		Timeouts:      timeoutOptions(firstBucket.Timeouts),
+		Bucket:        firstBucket.Name,
+		Prefix:        firstBucket.Prefix,
 	}
 
 	b := backup.New(options)
 	if err := b.TestConnection(); err != nil {
-		fmt.Printf("连接失败: %v\n", err)
+		i18n.Printf("连接失败: %v\n", err)
+		if isConnectionError(err) {
+			return withExitCode(ExitConnection, err)
+		}
 		return err
 	}
 
-	fmt.Printf("连接成功!\n")
+	i18n.Printf("连接成功!\n")
 	return nil
 }
 
 func (a *App) runVersion(cmd *cobra.Command, args []string) error {
-	fmt.Printf("ObjectSync 对象存储下载工具\n")
-	fmt.Printf("版本: %s\n", a.version)
-	fmt.Printf("构建时间: %s\n", a.buildTime)
-	fmt.Printf("Git提交: %s\n", a.gitCommit)
-	fmt.Printf("Go版本: %s\n", runtime.Version())
-	fmt.Printf("操作系统: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	i18n.Printf("ObjectSync 对象存储下载工具\n")
+	i18n.Printf("版本: %s\n", a.version)
+	i18n.Printf("构建时间: %s\n", a.buildTime)
+	i18n.Printf("Git提交: %s\n", a.gitCommit)
+	i18n.Printf("Go版本: %s\n", runtime.Version())
+	i18n.Printf("操作系统: %s/%s\n", runtime.GOOS, runtime.GOARCH)
 	return nil
 }
 
 func (a *App) runStatus(cmd *cobra.Command, args []string) error {
 	configFile, _ := cmd.Flags().GetString("config")
 	stateFile, _ := cmd.Flags().GetString("state-file")
+	remote, _ := cmd.Flags().GetBool("remote")
+
+	if remote {
+		return a.runStatusPending(cmd)
+	}
 
-	fmt.Printf("查看备份状态\n")
-	fmt.Printf("配置文件: %s\n", configFile)
-	fmt.Printf("状态文件: %s\n", stateFile)
+	i18n.Printf("查看备份状态\n")
+	i18n.Printf("配置文件: %s\n", configFile)
+	i18n.Printf("状态文件: %s\n", stateFile)
 	fmt.Println()
 
 	// 检查状态文件是否存在
 	if _, err := os.Stat(stateFile); os.IsNotExist(err) {
-		fmt.Printf("状态文件不存在，可能是首次备份\n")
+		i18n.Printf("状态文件不存在，可能是首次备份\n")
+		a.report.addState("", stateFile, nil)
 		return nil
 	}
 
 	// 读取状态文件
-	file, err := os.Open(stateFile)
+	store, err := openState(stateFile, statestore.Detect(stateFile), false)
 	if err != nil {
-		return fmt.Errorf("无法读取状态文件: %w", err)
+		return err
 	}
-	defer file.Close()
+	defer store.Close()
 
-	var state backup.State
-	if err := json.NewDecoder(file).Decode(&state); err != nil {
-		return fmt.Errorf("状态文件格式错误: %w", err)
+	summary, err := summarizeState(store)
+	if err != nil {
+		return i18n.Errorf("状态文件格式错误: %w", err)
 	}
+	a.report.addState("", stateFile, summary)
 
 	// 显示状态信息
-	fmt.Printf("最后备份时间: %s\n", state.LastBackup.Format("2006-01-02 15:04:05"))
-	fmt.Printf("已备份文件数: %d\n", len(state.Files))
-
-	// 计算总大小
-	var totalSize int64
-	for _, file := range state.Files {
-		totalSize += file.Size
-	}
-	fmt.Printf("总数据大小: %s\n", progress.FormatSize(totalSize))
+	i18n.Printf("最后备份时间: %s\n", summary.lastRun.Format("2006-01-02 15:04:05"))
+	i18n.Printf("已备份文件数: %d\n", summary.files)
+	i18n.Printf("总数据大小: %s\n", progress.FormatSize(summary.bytes))
+	printStateDeleted(summary, "")
 
 	// 显示最近的几个文件
-	fmt.Println("\n最近备份的文件:")
-	count := 0
-	for filename, fileState := range state.Files {
-		if count >= 5 {
-			break
-		}
-		fmt.Printf("  %s (%s, %s)\n",
-			filename,
-			progress.FormatSize(fileState.Size),
-			fileState.LastModified.Format("2006-01-02 15:04:05"))
-		count++
-	}
-
-	if len(state.Files) > 5 {
-		fmt.Printf("  ... 还有 %d 个文件\n", len(state.Files)-5)
+	i18n.Println("\n最近备份的文件:")
+	printStateFiles(store, "", 5)
+	if summary.files > 5 {
+		i18n.Printf("  ... 还有 %d 个文件\n", summary.files-5)
 	}
 
 	return nil
@@ -425,15 +858,15 @@ func (a *App) runStatus(cmd *cobra.Command, args []string) error {
 
 // runStatusMenu 专为菜单系统设计的状态查看
 func (a *App) runStatusMenu() error {
-	configFile := "config.yaml"       // 默认配置文件
+	configFile := config.Locate("")   // 默认配置文件
 	stateFile := ".backup_state.json" // 默认状态文件
 
-	fmt.Printf("查看备份状态\n")
-	fmt.Printf("配置文件: %s\n", configFile)
+	i18n.Printf("查看备份状态\n")
+	i18n.Printf("配置文件: %s\n", configFile)
 
 	// 先检查配置文件是否存在
 	if _, err := os.Stat(configFile); os.IsNotExist(err) {
-		fmt.Printf("配置文件不存在，请先进行配置初始化\n")
+		i18n.Printf("配置文件不存在，请先进行配置初始化\n")
 		return nil
 	}
 
@@ -445,32 +878,33 @@ func (a *App) runStatusMenu() error {
 		bucketCount := len(settings.Buckets)
 
 		if bucketCount == 0 {
-			fmt.Printf("配置中没有配置桶信息\n")
+			i18n.Printf("配置中没有配置桶信息\n")
 			return nil
 		}
 
-		fmt.Printf("\n显示所有桶的状态（共 %d 个桶）:\n", bucketCount)
+		i18n.Printf("\n显示所有桶的状态（共 %d 个桶）:\n", bucketCount)
 		for i, bucket := range settings.Buckets {
-			fmt.Printf("\n[%d] 桶: %s\n", i+1, bucket.Name)
-			fmt.Printf("    状态文件: %s\n", bucket.StateFile)
+			i18n.Printf("\n[%d] 桶: %s\n", i+1, bucket.Name)
+			stateFile := statestore.Path(bucket.StateFile, settings.StateBackend)
+			i18n.Printf("    状态文件: %s\n", stateFile)
 
-			if err := a.showBucketStatus(bucket.StateFile, true); err != nil { // true表示使用缩进
-				fmt.Printf("    读取状态失败: %v\n", err)
+			if err := a.showBucketStatus(stateFile, settings.StateBackend, true); err != nil { // true表示使用缩进
+				i18n.Printf("    读取状态失败: %v\n", err)
 			}
 		}
 		return nil
 	} else {
-		fmt.Printf("配置文件加载失败: %v\n", err)
-		fmt.Printf("使用默认状态文件: %s\n", stateFile)
+		i18n.Printf("配置文件加载失败: %v\n", err)
+		i18n.Printf("使用默认状态文件: %s\n", stateFile)
 		fmt.Println()
 
 		// 显示默认状态文件的状态
-		return a.showBucketStatus(stateFile, false) // false表示不使用缩进
+		return a.showBucketStatus(stateFile, statestore.BackendJSON, false) // false表示不使用缩进
 	}
 }
 
 // showBucketStatus 显示单个桶的备份状态
-func (a *App) showBucketStatus(stateFile string, withIndent bool) error {
+func (a *App) showBucketStatus(stateFile, backend string, withIndent bool) error {
 	// 根据缩进需要设置前缀
 	indent := ""
 	if withIndent {
@@ -479,70 +913,87 @@ func (a *App) showBucketStatus(stateFile string, withIndent bool) error {
 
 	// 检查状态文件是否存在
 	if _, err := os.Stat(stateFile); os.IsNotExist(err) {
-		fmt.Printf("%s状态文件不存在，可能是首次备份\n", indent)
+		i18n.Printf("%s状态文件不存在，可能是首次备份\n", indent)
 		return nil
 	}
 
 	// 读取状态文件
-	file, err := os.Open(stateFile)
+	store, err := openState(stateFile, backend, false)
 	if err != nil {
-		return fmt.Errorf("无法读取状态文件: %w", err)
+		return err
 	}
-	defer file.Close()
+	defer store.Close()
 
-	var state backup.State
-	if err := json.NewDecoder(file).Decode(&state); err != nil {
-		return fmt.Errorf("状态文件格式错误: %w", err)
+	summary, err := summarizeState(store)
+	if err != nil {
+		return i18n.Errorf("状态文件格式错误: %w", err)
 	}
 
 	// 显示状态信息
-	fmt.Printf("%s最后备份时间: %s\n", indent, state.LastBackup.Format("2006-01-02 15:04:05"))
-	fmt.Printf("%s已备份文件数: %d\n", indent, len(state.Files))
+	i18n.Printf("%s最后备份时间: %s\n", indent, summary.lastRun.Format("2006-01-02 15:04:05"))
+	i18n.Printf("%s已备份文件数: %d\n", indent, summary.files)
+	i18n.Printf("%s总数据大小: %s\n", indent, progress.FormatSize(summary.bytes))
+	printStateDeleted(summary, indent)
 
-	// 计算总大小
-	var totalSize int64
-	for _, file := range state.Files {
-		totalSize += file.Size
+	// 显示最近的几个文件，在菜单模式下显示少一些文件
+	i18n.Printf("%s最近备份的文件:\n", indent)
+	printStateFiles(store, indent, 3)
+	if summary.files > 3 {
+		i18n.Printf("%s  ... 还有 %d 个文件\n", indent, summary.files-3)
 	}
-	fmt.Printf("%s总数据大小: %s\n", indent, progress.FormatSize(totalSize))
 
-	// 显示最近的几个文件
-	fmt.Printf("%s最近备份的文件:\n", indent)
+	return nil
+}
+
+// printStateDeleted 显示从桶中删除的对象数，没有时不显示
+func printStateDeleted(summary *stateSummary, indent string) {
+	if summary.deleted == 0 {
+		return
+	}
+	i18n.Printf("%s已从桶中删除: %d 个对象（最近 %s）\n", indent, summary.deleted,
+		summary.lastDeleted.Local().Format("2006-01-02 15:04:05"))
+}
+
+// printStateFiles 显示状态中的前几个文件，不包括删除记录
+func printStateFiles(store statestore.Store[json.RawMessage], indent string, limit int) {
 	count := 0
-	for filename, fileState := range state.Files {
-		if count >= 3 { // 在菜单模式下显示少一些文件
-			break
+	store.Range("", func(key string, raw json.RawMessage) bool {
+		if count >= limit {
+			return false
+		}
+		entry, _ := parseStateEntry(raw)
+		if entry.Deleted() {
+			return true
 		}
 		fmt.Printf("%s  %s (%s, %s)\n",
 			indent,
-			filename,
-			progress.FormatSize(fileState.Size),
-			fileState.LastModified.Format("2006-01-02 15:04:05"))
+			key,
+			progress.FormatSize(entry.Size),
+			entry.LastModified.Format("2006-01-02 15:04:05"))
 		count++
-	}
-
-	if len(state.Files) > 3 {
-		fmt.Printf("%s  ... 还有 %d 个文件\n", indent, len(state.Files)-3)
-	}
-
-	return nil
+		return true
+	})
 }
 
 func (a *App) runInit(cmd *cobra.Command, args []string) error {
 	output, _ := cmd.Flags().GetString("output")
 
-	fmt.Println("交互式配置初始化")
-	fmt.Printf("将创建配置文件: %s\n", output)
+	if !interactive(cmd) {
+		return withExitCode(ExitUsage, i18n.Errorf("非交互模式下不能运行交互式配置初始化"))
+	}
+
+	i18n.Println("交互式配置初始化")
+	i18n.Printf("将创建配置文件: %s\n", output)
 	fmt.Println()
 
 	// 检查文件是否已存在
 	if _, err := os.Stat(output); err == nil {
-		fmt.Printf("配置文件 %s 已存在\n", output)
-		fmt.Print("是否覆盖? (y/N): ")
+		i18n.Printf("配置文件 %s 已存在\n", output)
+		i18n.Printf("是否覆盖? (y/N): ")
 		var response string
 		fmt.Scanln(&response)
 		if response != "y" && response != "Y" {
-			fmt.Println("操作已取消")
+			i18n.Println("操作已取消")
 			return nil
 		}
 	}
@@ -552,16 +1003,16 @@ func (a *App) runInit(cmd *cobra.Command, args []string) error {
 	var workers int
 	var incremental, verbose bool
 
-	fmt.Print("请输入对象存储端点URL: ")
+	i18n.Printf("请输入对象存储端点URL: ")
 	fmt.Scanln(&endpoint)
 
-	fmt.Print("请输入访问密钥: ")
+	i18n.Printf("请输入访问密钥: ")
 	fmt.Scanln(&accessKey)
 
-	fmt.Print("请输入秘密密钥: ")
+	i18n.Printf("请输入秘密密钥: ")
 	fmt.Scanln(&secretKey)
 
-	fmt.Print("请输入默认并发数 (默认: 5): ")
+	i18n.Printf("请输入默认并发数 (默认: 5): ")
 	var workersInput string
 	fmt.Scanln(&workersInput)
 	if workersInput == "" {
@@ -573,54 +1024,54 @@ func (a *App) runInit(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	fmt.Print("启用增量备份? (Y/n): ")
+	i18n.Printf("启用增量备份? (Y/n): ")
 	var incResponse string
 	fmt.Scanln(&incResponse)
 	incremental = incResponse != "n" && incResponse != "N"
 
-	fmt.Print("启用详细输出? (y/N): ")
+	i18n.Printf("启用详细输出? (y/N): ")
 	var verbResponse string
 	fmt.Scanln(&verbResponse)
 	verbose = verbResponse == "y" || verbResponse == "Y"
 
 	// 生成默认配置（包含示例桶配置）
-	fmt.Println("\n生成配置文件...")
+	i18n.Println("\n生成配置文件...")
 	configContent := a.generateDefaultConfig(endpoint, accessKey, secretKey, workers, incremental, verbose)
 
 	// 写入配置文件
 	file, err := os.Create(output)
 	if err != nil {
-		return fmt.Errorf("创建配置文件失败: %w", err)
+		return i18n.Errorf("创建配置文件失败: %w", err)
 	}
 	defer file.Close()
 
 	_, err = file.WriteString(configContent)
 	if err != nil {
-		return fmt.Errorf("写入配置文件失败: %w", err)
+		return i18n.Errorf("写入配置文件失败: %w", err)
 	}
 
-	fmt.Printf("配置文件已创建: %s\n", output)
-	fmt.Println("请编辑配置文件，填入正确的桶名称和输出目录")
-	fmt.Println("然后运行: objectsync backup --verbose")
+	i18n.Printf("配置文件已创建: %s\n", output)
+	i18n.Println("请编辑配置文件，填入正确的桶名称和输出目录")
+	i18n.Println("然后运行: objectsync backup --verbose")
 	return nil
 }
 
 // runInitMenu 专为菜单系统设计的配置初始化
 func (a *App) runInitMenu() error {
-	output := "config.yaml" // 固定使用默认配置文件名
+	output := config.DefaultConfigFile // 固定使用默认配置文件名
 
-	fmt.Println("交互式配置初始化")
-	fmt.Printf("将创建配置文件: %s\n", output)
+	i18n.Println("交互式配置初始化")
+	i18n.Printf("将创建配置文件: %s\n", output)
 	fmt.Println()
 
 	// 检查文件是否已存在
 	if _, err := os.Stat(output); err == nil {
-		fmt.Printf("配置文件 %s 已存在\n", output)
-		fmt.Print("是否覆盖? (y/N): ")
+		i18n.Printf("配置文件 %s 已存在\n", output)
+		i18n.Printf("是否覆盖? (y/N): ")
 		var response string
 		fmt.Scanln(&response)
 		if response != "y" && response != "Y" {
-			fmt.Println("操作已取消")
+			i18n.Println("操作已取消")
 			return nil
 		}
 	}
@@ -630,16 +1081,16 @@ func (a *App) runInitMenu() error {
 	var workers int
 	var incremental, verbose bool
 
-	fmt.Print("请输入对象存储端点URL: ")
+	i18n.Printf("请输入对象存储端点URL: ")
 	fmt.Scanln(&endpoint)
 
-	fmt.Print("请输入访问密钥: ")
+	i18n.Printf("请输入访问密钥: ")
 	fmt.Scanln(&accessKey)
 
-	fmt.Print("请输入秘密密钥: ")
+	i18n.Printf("请输入秘密密钥: ")
 	fmt.Scanln(&secretKey)
 
-	fmt.Print("请输入默认并发数 (默认: 5): ")
+	i18n.Printf("请输入默认并发数 (默认: 5): ")
 	var workersInput string
 	fmt.Scanln(&workersInput)
 	if workersInput == "" {
@@ -651,35 +1102,35 @@ func (a *App) runInitMenu() error {
 		}
 	}
 
-	fmt.Print("启用增量备份? (Y/n): ")
+	i18n.Printf("启用增量备份? (Y/n): ")
 	var incResponse string
 	fmt.Scanln(&incResponse)
 	incremental = incResponse != "n" && incResponse != "N"
 
-	fmt.Print("启用详细输出? (y/N): ")
+	i18n.Printf("启用详细输出? (y/N): ")
 	var verbResponse string
 	fmt.Scanln(&verbResponse)
 	verbose = verbResponse == "y" || verbResponse == "Y"
 
 	// 生成默认配置（包含示例桶配置）
-	fmt.Println("\n生成配置文件...")
+	i18n.Println("\n生成配置文件...")
 	configContent := a.generateDefaultConfig(endpoint, accessKey, secretKey, workers, incremental, verbose)
 
 	// 写入配置文件
 	file, err := os.Create(output)
 	if err != nil {
-		return fmt.Errorf("创建配置文件失败: %w", err)
+		return i18n.Errorf("创建配置文件失败: %w", err)
 	}
 	defer file.Close()
 
 	_, err = file.WriteString(configContent)
 	if err != nil {
-		return fmt.Errorf("写入配置文件失败: %w", err)
+		return i18n.Errorf("写入配置文件失败: %w", err)
 	}
 
-	fmt.Printf("配置文件已创建: %s\n", output)
-	fmt.Println("请编辑配置文件，填入正确的桶名称和输出目录")
-	fmt.Println("然后运行: objectsync backup --verbose")
+	i18n.Printf("配置文件已创建: %s\n", output)
+	i18n.Println("请编辑配置文件，填入正确的桶名称和输出目录")
+	i18n.Println("然后运行: objectsync backup --verbose")
 	return nil
 }
 
@@ -687,12 +1138,52 @@ func (a *App) runInitMenu() error {
 func (a *App) generateDefaultConfig(endpoint, accessKey, secretKey string, workers int, incremental, verbose bool) string {
 	return fmt.Sprintf(`# ObjectSync - 对象存储下载工具配置文件
 # 由交互式初始化生成
+# 配置值中可以使用 ${VAR} 或 ${VAR:-默认值} 引用环境变量，如 endpoint: "http://${S3_HOST}:7480"
+
+# language: en                           # 可选：输出语言（zh/en），未设置时按 LANG 环境变量，--lang 参数优先
 
 # 对象存储连接配置
 ceph:
   endpoint: "%s"
   access_key: "%s"
   secret_key: "%s"
+  # region: "us-east-1"                  # 可选：请求签名使用的区域，AWS和部分兼容服务要求与桶所在区域一致
+  # path_style: false                    # 可选：使用虚拟主机样式（桶名.域名）寻址，默认使用路径样式
+  # transport: minio                     # 可选：使用minio-go客户端（默认aws），部分Ceph/MinIO版本兼容性更好
+  # profile: "default"                   # 可选：从 ~/.aws/credentials 读取该配置的密钥，代替上面两项
+  # 密钥也可以保存在系统密钥环中，使用 objectsync config set-secret 写入后以 "keyring:objectsync/prod" 形式引用
+  # secret_key_file: "/run/secrets/s3_secret"                # 可选：从文件读取密钥（access_key_file 同理）
+  # secret_key_cmd: "vault kv get -field=secret secret/s3"   # 可选：从命令输出读取密钥（access_key_cmd 同理）
+  # tls:                                  # 可选：HTTPS证书设置
+  #   ca_file: "/etc/pki/internal-ca.pem" # 信任内部CA签发的证书
+  #   cert_file: "/etc/pki/client.pem"    # 双向认证的客户端证书和私钥
+  #   key_file: "/etc/pki/client.key"
+  #   insecure_skip_verify: false         # 不校验服务端证书，仅用于测试
+  # proxy:                                # 可选：通过代理访问对象存储，未设置时使用 HTTP_PROXY/NO_PROXY 环境变量
+  #   url: "http://proxy.example.com:3128"
+  #   no_proxy: [".internal", "10.0.0.0/8"]
+  # timeouts:                             # 可选：HTTP超时，避免网关异常时请求一直挂起
+  #   connect: 30s                        # 建立连接
+  #   response_header: 60s                # 发送请求后等待响应头
+  #   idle: 90s                           # 空闲连接保留时间
+  #   request: 60s                        # 未单独设置时用于 connect 和 response_header，不限制传输数据的时间
+
+# 可选：按名称定义多个对象存储连接，桶通过 remote 字段引用
+# remotes:
+#   prod-ceph:
+#     endpoint: "http://10.0.0.1:7480"
+#     access_key: "prod-access-key"
+#     secret_key: "prod-secret-key"
+
+# 可选：管理多个集群时，每个集群的连接和桶写在一起，使用 --cluster 名称 只处理其中一个
+# clusters:
+#   - name: zone-b
+#     endpoint: "http://10.0.1.1:7480"
+#     access_key: "zone-b-access-key"
+#     secret_key: "zone-b-secret-key"
+#     buckets:
+#       - name: "archive"
+#         output_dir: "./backup/zone-b/archive"
 
 # 桶配置 - 请根据实际情况修改
 # 单桶：保留一个桶配置，删除其他
@@ -708,15 +1199,50 @@ buckets:
   # - name: "photos"
   #   output_dir: "./backup/photos"
   #   state_file: ".state_photos.json"
+  #   remote: prod-ceph                   # 可选：使用 remotes 中定义的连接
+  #   prefix: "jobs/photos/"              # 可选：只处理桶中该前缀下的对象，多个任务可共用同一个桶
+  #   direction: upload                   # 可选：run 命令对该桶执行的操作（backup/upload/sync），默认 backup
+  #   schedule: "0 2 * * *"               # 可选：守护进程模式下的执行时间（cron表达式：分 时 日 月 周）
   #   workers: 8                          # 可选：为特定桶设置不同的并发数
+  #   parts_concurrency: 16               # 可选：单个大文件上传时的并发分片数
   #   verbose: true                       # 可选：为特定桶启用详细输出
+  #   include: ["*.jpg", "raw/"]          # 可选：只上传/下载匹配的文件
+  #   exclude: ["node_modules/", "*.tmp"] # 可选：跳过匹配的文件和目录
+  #   dir_markers: empty                  # 可选：上传时目录标记的创建方式（all/empty/none）
+  #   bandwidth: "10MB"                   # 可选：该桶每秒最多传输的数据量
+  #   storage_class: "STANDARD_IA"        # 可选：上传对象使用的存储类别
+  #   source_dirs:                        # 可选：上传时从多个本地目录汇总到同一个桶
+  #     - path: "/var/www"
+  #       prefix: "www/"
+  #     - path: "/etc"
+  #       prefix: "etc/"
+  #   headers:                            # 可选：上传时按文件名模式设置HTTP头
+  #     - pattern: "*.js"
+  #       cache_control: "max-age=31536000"
+  #     - pattern: "assets/*.js.gz"       # 预压缩的静态资源，浏览器按Content-Encoding解压
+  #       content_encoding: "gzip"        # 注意：启用decompress时这类对象下载后同样会被解压
+  #       content_type: "application/javascript"
+  #   compress:                           # 可选：上传时压缩匹配的文件（gzip/zstd），对象键追加.gz/.zst后缀
+  #     - pattern: "*.log"
+  #       algorithm: "zstd"
+  #   decompress: true                    # 可选：下载时解压压缩上传的对象，恢复原始文件名
 
 # 全局备份配置
 backup:
   incremental: %t                         # 启用增量备份
   workers: %d                             # 默认并发数
+  parts_concurrency: 5                    # 单个大文件上传时的并发分片数
+  # exclude: ["*.tmp", ".git/"]           # 可选：默认排除模式，桶中设置 include/exclude 时替换此默认值
   verbose: %t                             # 默认详细输出
 
+# 可选：所有桶继承的默认设置，桶中设置同名字段时以桶为准（未设置的字段使用上面 backup 中的值）
+# defaults:
+#   workers: 8
+#   verbose: false
+#   exclude: ["*.tmp"]
+#   bandwidth: "20MB"                     # 每个桶每秒最多传输的数据量
+#   storage_class: "STANDARD_IA"          # 上传对象使用的存储类别
+
 # 重试配置
 retry:
   max_attempts: 3
@@ -724,33 +1250,51 @@ retry:
 `, endpoint, accessKey, secretKey, incremental, workers, verbose)
 }
 
+// runMenu 在终端中启动全屏交互界面，指定 --plain 或输出不是终端时使用文本菜单
 func (a *App) runMenu(cmd *cobra.Command, args []string) error {
+	if !interactive(cmd) {
+		return withExitCode(ExitUsage, i18n.Errorf("非交互模式下不能启动交互式菜单，请指定要执行的子命令"))
+	}
+
+	configFile, _ := cmd.Flags().GetString("config")
+	configFile = config.Locate(configFile)
+	plain, _ := cmd.Flags().GetBool("plain")
+	if plain || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return a.runTextMenu(cmd, args)
+	}
+	return a.runTUI(configFile)
+}
+
+// runTextMenu 清屏后逐行读取选择的文本菜单
+func (a *App) runTextMenu(cmd *cobra.Command, args []string) error {
 	for {
 		// 清屏（跨平台兼容）
 		a.clearScreen()
 
 		// 显示标题
 		fmt.Println("========================================")
-		fmt.Println("       ObjectSync - 交互式菜单")
+		i18n.Println("       ObjectSync - 交互式菜单")
 		fmt.Println("========================================")
 		fmt.Println()
-		fmt.Println("欢迎使用 ObjectSync 对象存储下载工具！")
+		i18n.Println("欢迎使用 ObjectSync 对象存储下载工具！")
 		fmt.Println()
 
 		// 显示菜单
 		fmt.Println("========================================")
-		fmt.Println("            主菜单")
+		i18n.Println("            主菜单")
 		fmt.Println("========================================")
 		fmt.Println()
-		fmt.Println("[1] 初始化配置")
-		fmt.Println("[2] 开始下载")
-		fmt.Println("[3] 开始上传")
-		fmt.Println("[4] 查看状态")
-		fmt.Println("[5] 查看配置")
-		fmt.Println("[6] 查看帮助")
-		fmt.Println("[0] 退出")
+		i18n.Println("[1] 初始化配置")
+		i18n.Println("[2] 开始下载")
+		i18n.Println("[3] 开始上传")
+		i18n.Println("[4] 查看状态")
+		i18n.Println("[5] 查看配置")
+		i18n.Println("[6] 测试连接")
+		i18n.Println("[7] 编辑配置")
+		i18n.Println("[8] 查看帮助")
+		i18n.Println("[0] 退出")
 		fmt.Println()
-		fmt.Print("请选择操作 (0-6): ")
+		i18n.Printf("请选择操作 (0-8): ")
 
 		var choice string
 		fmt.Scanln(&choice)
@@ -759,58 +1303,73 @@ func (a *App) runMenu(cmd *cobra.Command, args []string) error {
 		switch choice {
 		case "1":
 			// 初始化配置
-			fmt.Println("[信息] 启动配置向导...")
+			i18n.Println("[信息] 启动配置向导...")
 			if err := a.runInitMenu(); err != nil {
-				fmt.Printf("配置初始化失败: %v\n", err)
+				i18n.Printf("配置初始化失败: %v\n", err)
 			}
 			a.pauseAndContinue()
 
 		case "2":
 			// 开始下载
-			fmt.Println("[信息] 开始下载...")
-			if err := a.runBackup(cmd, args); err != nil {
-				fmt.Printf("下载失败: %v\n", err)
+			i18n.Println("[信息] 开始下载...")
+			if err := a.runBackupMenu(cmd); err != nil {
+				i18n.Printf("下载失败: %v\n", err)
 			}
 			a.pauseAndContinue()
 
 		case "3":
 			// 开始上传
-			fmt.Println("[信息] 开始上传...")
+			i18n.Println("[信息] 开始上传...")
 			if err := a.runUploadMenu(); err != nil {
-				fmt.Printf("上传失败: %v\n", err)
+				i18n.Printf("上传失败: %v\n", err)
 			}
 			a.pauseAndContinue()
 
 		case "4":
 			// 查看状态
-			fmt.Println("[信息] 查看备份状态...")
+			i18n.Println("[信息] 查看备份状态...")
 			if err := a.runStatusMenu(); err != nil {
-				fmt.Printf("查看状态失败: %v\n", err)
+				i18n.Printf("查看状态失败: %v\n", err)
 			}
 			a.pauseAndContinue()
 
 		case "5":
 			// 查看配置
-			fmt.Println("[信息] 当前配置文件内容:")
+			i18n.Println("[信息] 当前配置文件内容:")
 			fmt.Println("========================================")
 			a.showCurrentConfig()
 			fmt.Println("========================================")
 			a.pauseAndContinue()
 
 		case "6":
+			// 测试连接
+			i18n.Println("[信息] 测试所有桶的连接...")
+			if err := a.runConnectionTestMenu(cmd); err != nil {
+				i18n.Printf("测试连接失败: %v\n", err)
+			}
+			a.pauseAndContinue()
+
+		case "7":
+			// 编辑配置
+			if err := a.runEditConfigMenu(cmd); err != nil {
+				i18n.Printf("编辑配置失败: %v\n", err)
+			}
+			a.pauseAndContinue()
+
+		case "8":
 			// 查看帮助
-			fmt.Println("[信息] 显示帮助信息...")
+			i18n.Println("[信息] 显示帮助信息...")
 			a.rootCmd.Help()
 			a.pauseAndContinue()
 
 		case "0":
 			fmt.Println()
-			fmt.Println("[信息] 感谢使用 ObjectSync 工具！")
+			i18n.Println("[信息] 感谢使用 ObjectSync 工具！")
 			fmt.Println()
 			return nil
 
 		default:
-			fmt.Println("[错误] 无效的选择，请重新输入")
+			i18n.Println("[错误] 无效的选择，请重新输入")
 			time.Sleep(1 * time.Second)
 		}
 	}
@@ -819,7 +1378,7 @@ func (a *App) runMenu(cmd *cobra.Command, args []string) error {
 // pauseAndContinue 暂停并等待用户按键继续
 func (a *App) pauseAndContinue() {
 	fmt.Println()
-	fmt.Print("[信息] 按回车键返回主菜单...")
+	i18n.Printf("[信息] 按回车键返回主菜单...")
 	fmt.Scanln()
 }
 
@@ -836,9 +1395,9 @@ func (a *App) clearScreen() {
 
 // showCurrentConfig 显示当前配置文件
 func (a *App) showCurrentConfig() {
-	configFile := "config.yaml"
+	configFile := config.Locate("")
 	if data, err := os.ReadFile(configFile); err != nil {
-		fmt.Println("[警告] 配置文件不存在或无法读取，请先进行配置")
+		i18n.Println("[警告] 配置文件不存在或无法读取，请先进行配置")
 	} else {
 		fmt.Print(string(data))
 	}
@@ -850,51 +1409,127 @@ func (a *App) runUpload(cmd *cobra.Command, args []string) error {
 	endpoint, _ := cmd.Flags().GetString("endpoint")
 	accessKey, _ := cmd.Flags().GetString("access-key")
 	secretKey, _ := cmd.Flags().GetString("secret-key")
+	region, _ := cmd.Flags().GetString("region")
+	cluster, _ := cmd.Flags().GetString("cluster")
+	buckets, _ := cmd.Flags().GetStringSlice("bucket")
+	excluded, _ := cmd.Flags().GetStringSlice("exclude-bucket")
 	incremental, _ := cmd.Flags().GetBool("incremental")
+	resume, _ := cmd.Flags().GetBool("resume")
+	labels, _ := cmd.Flags().GetStringToString("label")
 	workers, _ := cmd.Flags().GetInt("workers")
+	scanWorkers, _ := cmd.Flags().GetInt("scan-workers")
+	partsConcurrency, _ := cmd.Flags().GetInt("parts-concurrency")
+	verifyUpload, _ := cmd.Flags().GetBool("verify-upload")
+	stableWindow, _ := cmd.Flags().GetDuration("stable-window")
+	maxUploadSize, _ := cmd.Flags().GetString("max-upload-size")
+	useVSS, _ := cmd.Flags().GetBool("vss")
+	checksumName, _ := cmd.Flags().GetString("checksum-algorithm")
+	maxRequests, _ := cmd.Flags().GetFloat64("max-requests-per-second")
+	packThreshold, _ := cmd.Flags().GetString("pack-threshold")
+	packSize, _ := cmd.Flags().GetString("pack-size")
+	dedupe, _ := cmd.Flags().GetBool("dedupe")
+	onConflict, _ := cmd.Flags().GetString("on-conflict")
+	force, _ := cmd.Flags().GetBool("force")
 	verbose, _ := cmd.Flags().GetBool("verbose")
+	workerProgress, _ := cmd.Flags().GetBool("worker-progress")
+
+	if partsConcurrency < 0 {
+		return withExitCode(ExitUsage, i18n.Errorf("--parts-concurrency 不能为负数"))
+	}
+
+	var maxFileSize int64
+	if maxUploadSize != "" {
+		size, err := progress.ParseSize(maxUploadSize)
+		if err != nil {
+			return i18n.Errorf("--max-upload-size 参数无效: %w", err)
+		}
+		maxFileSize = size
+	}
+
+	checksumAlgorithm, err := upload.ParseChecksumAlgorithm(checksumName)
+	if err != nil {
+		return err
+	}
+
+	conflictMode, err := upload.ParseConflictMode(onConflict)
+	if err != nil {
+		return err
+	}
+	if force {
+		conflictMode = ""
+	}
+
+	var packThresholdBytes, packSizeBytes int64
+	if packThreshold != "" {
+		if packThresholdBytes, err = progress.ParseSize(packThreshold); err != nil {
+			return i18n.Errorf("--pack-threshold 参数无效: %w", err)
+		}
+		if packSizeBytes, err = progress.ParseSize(packSize); err != nil {
+			return i18n.Errorf("--pack-size 参数无效: %w", err)
+		}
+	}
 
 	// 创建配置管理器
 	configManager := config.NewConfigManager(configFile)
 
 	// 加载配置文件
-	_, err := configManager.LoadConfig()
+	_, err = configManager.LoadConfig()
 	if err != nil {
 		// 如果是因为需要配置文件而失败，直接退出
-		if configFile == "config.yaml" {
-			return fmt.Errorf("配置加载失败: %w", err)
+		if !cmd.Flags().Changed("config") {
+			return configError(i18n.Errorf("配置加载失败: %w", err))
 		} else {
-			return fmt.Errorf("配置文件 %s 加载失败: %w", configFile, err)
+			return configError(i18n.Errorf("配置文件 %s 加载失败: %w", configFile, err))
 		}
 	}
 
 	// 验证配置
 	if err := configManager.ValidateConfig(); err != nil {
-		return fmt.Errorf("配置验证失败: %w", err)
+		return configError(i18n.Errorf("配置验证失败: %w", err))
 	}
 
 	// 获取桶配置
 	settings := configManager.ToBucketSettings()
-
-	// 用命令行参数覆盖连接配置
-	if endpoint != "" {
-		settings.Endpoint = endpoint
+	if cluster != "" {
+		if err := settings.FilterCluster(cluster); err != nil {
+			return withExitCode(ExitUsage, err)
+		}
+	}
+	if err := settings.FilterBuckets(buckets); err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	if err := settings.ExcludeBuckets(excluded); err != nil {
+		return withExitCode(ExitUsage, err)
 	}
-	if accessKey != "" {
-		settings.AccessKey = accessKey
+	// 复制的桶不经过本地目录，由run命令处理
+	if err := settings.ExcludeDirection(config.DirectionReplicate); err != nil {
+		return withExitCode(ExitUsage, err)
 	}
-	if secretKey != "" {
-		settings.SecretKey = secretKey
+	// 只有 source_dirs 的上传桶没有可以下载到的目录
+	skipped, err := settings.ExcludeNoOutputDir()
+	for _, bucket := range skipped {
+		i18n.Printf("跳过桶 %s：没有设置 output_dir，只能上传\n", bucket.ID())
 	}
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	// 用命令行参数覆盖连接配置
+	settings.OverrideConnection(endpoint, accessKey, secretKey, region)
 	settings.Incremental = incremental
+	settings.Resume = resume
+	settings.Labels = labels
+
+	// 所有桶共享同一个请求速率限制器
+	limiter := ratelimit.New(maxRequests)
 
 	// 上传到配置中的所有桶
 	bucketCount := len(settings.Buckets)
-	fmt.Printf("开始上传（共 %d 个桶）\n", bucketCount)
-	fmt.Printf("连接信息: %s\n", settings.Endpoint)
+	i18n.Printf("开始上传（共 %d 个桶）\n", bucketCount)
+	i18n.Printf("连接信息: %s\n", bucketEndpoints(settings))
 
 	if verbose {
-		fmt.Printf("桶列表:\n")
+		i18n.Printf("桶列表:\n")
 		for i, bucket := range settings.Buckets {
 			fmt.Printf("  %d. %s <- %s\n", i+1, bucket.Name, bucket.OutputDir)
 		}
@@ -903,51 +1538,79 @@ func (a *App) runUpload(cmd *cobra.Command, args []string) error {
 
 	// 逐个上传每个桶
 	successCount := 0
-	failureCount := 0
+	var failures []error
+	results := newRunResults("upload")
+	total := newTotalProgress(bucketCount, workerProgress && !verbose)
 
 	for i, bucketSettings := range settings.Buckets {
-		fmt.Printf("\n[%d/%d] 上传桶: %s\n", i+1, bucketCount, bucketSettings.Name)
+		i18n.Printf("\n[%d/%d] 上传桶: %s\n", i+1, bucketCount, bucketSettings.Name)
+		if total != nil {
+			total.StartBucket()
+		}
 
-		// 为每个桶创建上传选项
-		options := &upload.Options{
-			Endpoint:    settings.Endpoint,
-			AccessKey:   settings.AccessKey,
-			SecretKey:   settings.SecretKey,
-			Bucket:      bucketSettings.Name,
-			InputDir:    bucketSettings.OutputDir, // 从各自的输出目录上传
-			Incremental: incremental,
-			StateFile:   fmt.Sprintf(".upload_%s_state.json", bucketSettings.Name), // 每个桶独立的状态文件
-			Workers:     workers,
-			Verbose:     verbose,
+		// 为每个桶创建上传选项，命令行参数覆盖配置
+		options := bucketUploadOptions(settings, bucketSettings, limiter)
+		options.Parent = total
+		options.WorkerProgress = workerProgress
+		options.Incremental = incremental
+		if cmd.Flags().Changed("workers") {
+			options.Workers = workers
+		}
+		options.ScanWorkers = scanWorkers
+		options.Verify = verifyUpload
+		options.StableWindow = stableWindow
+		options.MaxFileSize = maxFileSize
+		options.UseVSS = useVSS
+		options.Checksum = checksumAlgorithm
+		options.PackThreshold = packThresholdBytes
+		options.PackSize = packSizeBytes
+		options.Dedupe = dedupe
+		options.Conflict = conflictMode
+		options.Verbose = options.Verbose || verbose
+
+		if partsConcurrency > 0 {
+			options.PartsConcurrency = partsConcurrency
 		}
 
 		if options.Verbose {
-			fmt.Printf("  端点: %s\n", options.Endpoint)
-			fmt.Printf("  桶名: %s\n", options.Bucket)
-			fmt.Printf("  输入目录: %s\n", options.InputDir)
-			fmt.Printf("  增量上传: %v\n", options.Incremental)
-			fmt.Printf("  并发数: %d\n", options.Workers)
+			i18n.Printf("  端点: %s\n", options.Endpoint)
+			i18n.Printf("  桶名: %s\n", options.Bucket)
+			if len(options.Sources) > 0 {
+				for _, source := range options.Sources {
+					i18n.Printf("  输入目录: %s -> %s\n", source.Dir, source.Prefix)
+				}
+			} else {
+				i18n.Printf("  输入目录: %s\n", options.InputDir)
+			}
+			i18n.Printf("  增量上传: %v\n", options.Incremental)
+			i18n.Printf("  并发数: %d\n", options.Workers)
 			fmt.Printf("\n")
 		}
 
 		// 创建上传器并执行上传
 		u := upload.New(options)
-		if err := u.Run(); err != nil {
-			fmt.Printf("桶 %s 上传失败: %v\n", bucketSettings.Name, err)
-			failureCount++
+		err := u.Run()
+		a.report.addBucket(bucketSettings.Name, u.Stats(), err)
+		results.add(bucketSettings.Name, u.Stats(), err)
+		if err != nil {
+			i18n.Printf("桶 %s 上传失败: %v\n", bucketSettings.Name, err)
+			failures = append(failures, bucketError(err, u.Stats()))
 			continue
 		}
 
-		fmt.Printf("桶 %s 上传完成!\n", bucketSettings.Name)
+		i18n.Printf("桶 %s 上传完成!\n", bucketSettings.Name)
 		successCount++
 	}
 
+	finishRun(settings, results)
+
 	// 显示上传总结
-	fmt.Printf("\n上传完成!\n")
-	fmt.Printf("成功: %d 个桶\n", successCount)
-	if failureCount > 0 {
-		fmt.Printf("失败: %d 个桶\n", failureCount)
-		return fmt.Errorf("部分桶上传失败")
+	i18n.Printf("\n上传完成!\n")
+	i18n.Printf("成功: %d 个桶\n", successCount)
+	printTotalProgress(total)
+	if len(failures) > 0 {
+		i18n.Printf("失败: %d 个桶\n", len(failures))
+		return bucketsError(i18n.Errorf("部分桶上传失败"), successCount, failures)
 	}
 
 	return nil
@@ -955,78 +1618,87 @@ func (a *App) runUpload(cmd *cobra.Command, args []string) error {
 
 func (a *App) runUploadMenu() error {
 	fmt.Println("========================================")
-	fmt.Println("            上传设置")
+	i18n.Println("            上传设置")
 	fmt.Println("========================================")
 	fmt.Println()
 
 	// 创建配置管理器
-	configManager := config.NewConfigManager("config.yaml")
+	configManager := config.NewConfigManager("")
 
 	// 加载配置文件
 	_, err := configManager.LoadConfig()
 	if err != nil {
-		return fmt.Errorf("配置加载失败: %w", err)
+		return configError(i18n.Errorf("配置加载失败: %w", err))
 	}
 
 	// 验证配置
 	if err := configManager.ValidateConfig(); err != nil {
-		return fmt.Errorf("配置验证失败: %w", err)
+		return configError(i18n.Errorf("配置验证失败: %w", err))
 	}
 
-	// 获取桶配置信息
+	// 获取桶配置信息，复制的桶不从本地目录上传
 	settings := configManager.ToBucketSettings()
+	if err := settings.ExcludeDirection(config.DirectionReplicate); err != nil {
+		return err
+	}
 
-	fmt.Printf("发现 %d 个已配置的桶:\n", len(settings.Buckets))
+	i18n.Printf("发现 %d 个已配置的桶:\n", len(settings.Buckets))
 	for i, bucket := range settings.Buckets {
-		fmt.Printf("  %d. 桶名: %s\n", i+1, bucket.Name)
-		fmt.Printf("     本地目录: %s\n", bucket.OutputDir)
+		i18n.Printf("  %d. 桶名: %s\n", i+1, bucket.Name)
+		if len(bucket.SourceDirs) > 0 {
+			for _, source := range bucket.SourceDirs {
+				i18n.Printf("     本地目录: %s -> %s\n", source.Path, source.Prefix)
+			}
+		} else {
+			i18n.Printf("     本地目录: %s\n", bucket.OutputDir)
+		}
 
 		// 检查目录是否存在
-		if _, err := os.Stat(bucket.OutputDir); os.IsNotExist(err) {
-			fmt.Printf("     状态: 目录不存在 ❌\n")
+		if missingSourceDir(bucket) != "" {
+			i18n.Printf("     状态: 目录不存在 ❌\n")
 		} else {
-			fmt.Printf("     状态: 目录存在 ✅\n")
+			i18n.Printf("     状态: 目录存在 ✅\n")
 		}
 		fmt.Println()
 	}
 
-	fmt.Println("上传逻辑:")
-	fmt.Println("  • 每个桶将从其配置的本地目录上传数据")
-	fmt.Println("  • 只有存在本地目录的桶才会被上传")
-	fmt.Println("  • 每个桶使用独立的上传状态文件")
+	i18n.Println("上传逻辑:")
+	i18n.Println("  • 每个桶将从其配置的本地目录上传数据")
+	i18n.Println("  • 只有存在本地目录的桶才会被上传")
+	i18n.Println("  • 每个桶使用独立的上传状态文件")
 	fmt.Println()
 
 	// 询问是否继续
-	fmt.Print("是否继续上传? (Y/n): ")
+	i18n.Printf("是否继续上传? (Y/n): ")
 	var continueInput string
 	fmt.Scanln(&continueInput)
 	if continueInput == "n" || continueInput == "N" {
-		fmt.Println("上传已取消")
+		i18n.Println("上传已取消")
 		return nil
 	}
 
 	// 询问是否使用详细模式
-	fmt.Print("是否启用详细输出? (y/N): ")
+	i18n.Printf("是否启用详细输出? (y/N): ")
 	var verboseInput string
 	fmt.Scanln(&verboseInput)
 	verbose := verboseInput == "y" || verboseInput == "Y"
 
 	fmt.Println()
-	fmt.Println("开始上传...")
+	i18n.Println("开始上传...")
 
 	// 执行上传逻辑
 	// 获取桶配置并处理上传
 	if len(settings.Buckets) == 0 {
-		return fmt.Errorf("没有配置的桶")
+		return i18n.Errorf("没有配置的桶")
 	}
 
 	// 上传到配置中的所有桶
 	bucketCount := len(settings.Buckets)
-	fmt.Printf("开始上传（共 %d 个桶）\n", bucketCount)
-	fmt.Printf("连接信息: %s\n", settings.Endpoint)
+	i18n.Printf("开始上传（共 %d 个桶）\n", bucketCount)
+	i18n.Printf("连接信息: %s\n", bucketEndpoints(settings))
 
 	if verbose {
-		fmt.Printf("桶列表:\n")
+		i18n.Printf("桶列表:\n")
 		for i, bucket := range settings.Buckets {
 			fmt.Printf("  %d. %s <- %s\n", i+1, bucket.Name, bucket.OutputDir)
 		}
@@ -1036,58 +1708,312 @@ func (a *App) runUploadMenu() error {
 	// 逐个上传每个桶
 	successCount := 0
 	failureCount := 0
+	results := newRunResults("upload")
+	total := newTotalProgress(bucketCount, false)
 
 	for i, bucketSettings := range settings.Buckets {
-		fmt.Printf("\n[%d/%d] 上传桶: %s\n", i+1, bucketCount, bucketSettings.Name)
+		i18n.Printf("\n[%d/%d] 上传桶: %s\n", i+1, bucketCount, bucketSettings.Name)
+		if total != nil {
+			total.StartBucket()
+		}
 
 		// 检查桶对应的目录是否存在
-		if _, err := os.Stat(bucketSettings.OutputDir); os.IsNotExist(err) {
-			fmt.Printf("桶 %s 对应的目录不存在: %s，跳过上传\n", bucketSettings.Name, bucketSettings.OutputDir)
+		if missing := missingSourceDir(bucketSettings); missing != "" {
+			i18n.Printf("桶 %s 对应的目录不存在: %s，跳过上传\n", bucketSettings.Name, missing)
 			failureCount++
 			continue
 		}
 
 		// 为每个桶创建上传选项
 		options := &upload.Options{
-			Endpoint:    settings.Endpoint,
-			AccessKey:   settings.AccessKey,
-			SecretKey:   settings.SecretKey,
-			Bucket:      bucketSettings.Name,
-			InputDir:    bucketSettings.OutputDir, // 从各自的输出目录上传
-			Incremental: true,
-			StateFile:   fmt.Sprintf(".upload_%s_state.json", bucketSettings.Name), // 每个桶独立的状态文件
-			Workers:     5,
-			Verbose:     verbose,
+			Endpoint:           bucketSettings.Endpoint,
+			AccessKey:          bucketSettings.AccessKey,
+			SecretKey:          bucketSettings.SecretKey,
+			Profile:            bucketSettings.Profile,
+			Region:             bucketSettings.Region,
+			VirtualHosted:      !bucketSettings.PathStyle,
+			Transport:          bucketSettings.Transport,
+			TLS:                tlsOptions(bucketSettings.TLS),
+			Proxy:              proxyOptions(bucketSettings.Proxy),
+			Timeouts:           timeoutOptions(bucketSettings.Timeouts),
+			Bucket:             bucketSettings.Name,
+			Prefix:             bucketSettings.Prefix,
+			InputDir:           bucketSettings.OutputDir, // 从各自的输出目录上传
+			Incremental:        true,
+			StateFile:          bucketSettings.UploadStateFile(), // 每个桶独立的状态文件
+			StateBackend:       settings.StateBackend,
+			CheckpointFiles:    settings.CheckpointFiles,
+			CheckpointInterval: settings.CheckpointInterval,
+			PruneDeletedAfter:  settings.PruneDeletedAfter,
+			Workers:            5,
+			PartsConcurrency:   bucketSettings.PartsConcurrency,
+			MaxAttempts:        settings.MaxAttempts,
+			RetryDelay:         settings.RetryDelay,
+			DirMarkers:         bucketSettings.DirMarkers,
+			StorageClass:       bucketSettings.StorageClass,
+			Bandwidth:          ratelimit.NewBandwidth(bucketSettings.Bandwidth),
+			Headers:            uploadHeaderRules(bucketSettings.Headers),
+			Compress:           uploadCompressRules(bucketSettings.Compress),
+			Include:            bucketSettings.Include,
+			Exclude:            bucketSettings.Exclude,
+			Sources:            uploadSources(bucketSettings.SourceDirs),
+			Resume:             settings.Resume,
+			Parent:             total,
+			Verbose:            verbose,
 		}
 
 		if options.Verbose {
-			fmt.Printf("  端点: %s\n", options.Endpoint)
-			fmt.Printf("  桶名: %s\n", options.Bucket)
-			fmt.Printf("  输入目录: %s\n", options.InputDir)
-			fmt.Printf("  增量上传: %v\n", options.Incremental)
-			fmt.Printf("  并发数: %d\n", options.Workers)
+			i18n.Printf("  端点: %s\n", options.Endpoint)
+			i18n.Printf("  桶名: %s\n", options.Bucket)
+			if len(options.Sources) > 0 {
+				for _, source := range options.Sources {
+					i18n.Printf("  输入目录: %s -> %s\n", source.Dir, source.Prefix)
+				}
+			} else {
+				i18n.Printf("  输入目录: %s\n", options.InputDir)
+			}
+			i18n.Printf("  增量上传: %v\n", options.Incremental)
+			i18n.Printf("  并发数: %d\n", options.Workers)
 			fmt.Printf("\n")
 		}
 
 		// 创建上传器并执行上传
 		u := upload.New(options)
-		if err := u.Run(); err != nil {
-			fmt.Printf("桶 %s 上传失败: %v\n", bucketSettings.Name, err)
+		err := u.Run()
+		a.report.addBucket(bucketSettings.Name, u.Stats(), err)
+		results.add(bucketSettings.Name, u.Stats(), err)
+		if err != nil {
+			i18n.Printf("桶 %s 上传失败: %v\n", bucketSettings.Name, err)
 			failureCount++
 			continue
 		}
 
-		fmt.Printf("桶 %s 上传完成!\n", bucketSettings.Name)
+		i18n.Printf("桶 %s 上传完成!\n", bucketSettings.Name)
 		successCount++
 	}
 
+	finishRun(settings, results)
+
 	// 显示上传总结
-	fmt.Printf("\n上传完成!\n")
-	fmt.Printf("成功: %d 个桶\n", successCount)
+	i18n.Printf("\n上传完成!\n")
+	i18n.Printf("成功: %d 个桶\n", successCount)
+	printTotalProgress(total)
 	if failureCount > 0 {
-		fmt.Printf("失败: %d 个桶\n", failureCount)
-		return fmt.Errorf("部分桶上传失败")
+		i18n.Printf("失败: %d 个桶\n", failureCount)
+		return i18n.Errorf("部分桶上传失败")
 	}
 
 	return nil
 }
+
+// uploadHeaderRules 将配置中的HTTP头规则转换为上传选项
+func uploadHeaderRules(rules []config.HeaderRule) []upload.HeaderRule {
+	var result []upload.HeaderRule
+	for _, rule := range rules {
+		result = append(result, upload.HeaderRule{
+			Pattern:            rule.Pattern,
+			CacheControl:       rule.CacheControl,
+			ContentEncoding:    rule.ContentEncoding,
+			ContentType:        rule.ContentType,
+			ContentDisposition: rule.ContentDisposition,
+		})
+	}
+	return result
+}
+
+// uploadCompressRules 将配置中的压缩规则转换为上传选项
+func uploadCompressRules(rules []config.CompressRule) []upload.CompressRule {
+	var result []upload.CompressRule
+	for _, rule := range rules {
+		result = append(result, upload.CompressRule{Pattern: rule.Pattern, Algorithm: rule.Algorithm})
+	}
+	return result
+}
+
+// bucketBackupOptions 根据桶配置创建备份选项
+func bucketBackupOptions(settings *config.MultiBucketSettings, bucketSettings config.BucketSettings, limiter *ratelimit.Limiter) *backup.Options {
+	return &backup.Options{
+		Endpoint:           bucketSettings.Endpoint,
+		AccessKey:          bucketSettings.AccessKey,
+		SecretKey:          bucketSettings.SecretKey,
+		Profile:            bucketSettings.Profile,
+		Region:             bucketSettings.Region,
+		VirtualHosted:      !bucketSettings.PathStyle,
+		Transport:          bucketSettings.Transport,
+		TLS:                tlsOptions(bucketSettings.TLS),
+		Proxy:              proxyOptions(bucketSettings.Proxy),
+		Timeouts:           timeoutOptions(bucketSettings.Timeouts),
+		Bucket:             bucketSettings.Name,
+		Prefix:             bucketSettings.Prefix,
+		OutputDir:          bucketSettings.OutputDir,
+		Incremental:        settings.Incremental,
+		StateFile:          bucketSettings.StateFile,
+		StateBackend:       settings.StateBackend,
+		CheckpointFiles:    settings.CheckpointFiles,
+		CheckpointInterval: settings.CheckpointInterval,
+		PruneDeletedAfter:  settings.PruneDeletedAfter,
+		Workers:            bucketSettings.Workers,
+		RateLimiter:        limiter,
+		Bandwidth:          ratelimit.NewBandwidth(bucketSettings.Bandwidth),
+		MaxAttempts:        settings.MaxAttempts,
+		RetryDelay:         settings.RetryDelay,
+		Decompress:         bucketSettings.Decompress,
+		Include:            bucketSettings.Include,
+		Exclude:            bucketSettings.Exclude,
+		Resume:             settings.Resume,
+		Verbose:            bucketSettings.Verbose,
+	}
+}
+
+// bucketUploadOptions 根据桶配置创建上传选项，从桶的输出目录（或source_dirs）上传
+func bucketUploadOptions(settings *config.MultiBucketSettings, bucketSettings config.BucketSettings, limiter *ratelimit.Limiter) *upload.Options {
+	return &upload.Options{
+		Endpoint:           bucketSettings.Endpoint,
+		AccessKey:          bucketSettings.AccessKey,
+		SecretKey:          bucketSettings.SecretKey,
+		Profile:            bucketSettings.Profile,
+		Region:             bucketSettings.Region,
+		VirtualHosted:      !bucketSettings.PathStyle,
+		Transport:          bucketSettings.Transport,
+		TLS:                tlsOptions(bucketSettings.TLS),
+		Proxy:              proxyOptions(bucketSettings.Proxy),
+		Timeouts:           timeoutOptions(bucketSettings.Timeouts),
+		Bucket:             bucketSettings.Name,
+		Prefix:             bucketSettings.Prefix,
+		InputDir:           bucketSettings.OutputDir,
+		Incremental:        settings.Incremental,
+		StateFile:          bucketSettings.UploadStateFile(), // 每个桶独立的状态文件
+		StateBackend:       settings.StateBackend,
+		CheckpointFiles:    settings.CheckpointFiles,
+		CheckpointInterval: settings.CheckpointInterval,
+		PruneDeletedAfter:  settings.PruneDeletedAfter,
+		Workers:            bucketSettings.Workers,
+		MaxAttempts:        settings.MaxAttempts,
+		RetryDelay:         settings.RetryDelay,
+		PartsConcurrency:   bucketSettings.PartsConcurrency,
+		RateLimiter:        limiter,
+		DirMarkers:         bucketSettings.DirMarkers,
+		StorageClass:       bucketSettings.StorageClass,
+		Bandwidth:          ratelimit.NewBandwidth(bucketSettings.Bandwidth),
+		Headers:            uploadHeaderRules(bucketSettings.Headers),
+		Compress:           uploadCompressRules(bucketSettings.Compress),
+		Include:            bucketSettings.Include,
+		Exclude:            bucketSettings.Exclude,
+		Sources:            uploadSources(bucketSettings.SourceDirs),
+		Resume:             settings.Resume,
+		Verbose:            bucketSettings.Verbose,
+	}
+}
+
+// bucketReplicateOptions 根据桶配置创建复制选项，从桶的连接复制到target指定的连接。
+// 带宽限制作用于读取源对象，写入目标的数据量与之相同
+func bucketReplicateOptions(settings *config.MultiBucketSettings, bucketSettings config.BucketSettings, limiter *ratelimit.Limiter) *replicate.Options {
+	target := bucketSettings.TargetSettings()
+	source := connectionOptions(settings, bucketSettings, limiter)
+	source.Bandwidth = ratelimit.NewBandwidth(bucketSettings.Bandwidth)
+	return &replicate.Options{
+		Source:             replicate.Endpoint{Connection: source, Bucket: bucketSettings.Name},
+		Target:             replicate.Endpoint{Connection: connectionOptions(settings, target, limiter), Bucket: target.Name},
+		Prefix:             bucketSettings.Prefix,
+		Incremental:        settings.Incremental,
+		StateFile:          bucketSettings.StateFile,
+		StateBackend:       settings.StateBackend,
+		CheckpointFiles:    settings.CheckpointFiles,
+		CheckpointInterval: settings.CheckpointInterval,
+		PruneDeletedAfter:  settings.PruneDeletedAfter,
+		Workers:            bucketSettings.Workers,
+		PartsConcurrency:   bucketSettings.PartsConcurrency,
+		MaxMemory:          bucketSettings.MaxMemory,
+		Verify:             bucketSettings.VerifyCopy,
+		MaxAttempts:        settings.MaxAttempts,
+		RetryDelay:         settings.RetryDelay,
+		StorageClass:       bucketSettings.StorageClass,
+		Include:            bucketSettings.Include,
+		Exclude:            bucketSettings.Exclude,
+		Verbose:            bucketSettings.Verbose,
+	}
+}
+
+// connectionOptions 根据桶的连接配置创建客户端选项
+func connectionOptions(settings *config.MultiBucketSettings, bucketSettings config.BucketSettings, limiter *ratelimit.Limiter) s3client.Options {
+	return s3client.Options{
+		Endpoint:      bucketSettings.Endpoint,
+		AccessKey:     bucketSettings.AccessKey,
+		SecretKey:     bucketSettings.SecretKey,
+		Profile:       bucketSettings.Profile,
+		Region:        bucketSettings.Region,
+		VirtualHosted: !bucketSettings.PathStyle,
+		Transport:     bucketSettings.Transport,
+		TLS:           tlsOptions(bucketSettings.TLS),
+		Proxy:         proxyOptions(bucketSettings.Proxy),
+		Timeouts:      timeoutOptions(bucketSettings.Timeouts),
+		RateLimiter:   limiter,
+		MaxAttempts:   settings.MaxAttempts,
+		RetryDelay:    settings.RetryDelay,
+	}
+}
+
+// tlsOptions 将配置中的TLS证书设置转换为S3客户端选项
+func tlsOptions(tls config.TLSConfig) s3client.TLSOptions {
+	return s3client.TLSOptions{
+		CAFile:             tls.CAFile,
+		InsecureSkipVerify: tls.InsecureSkipVerify,
+		CertFile:           tls.CertFile,
+		KeyFile:            tls.KeyFile,
+	}
+}
+
+// proxyOptions 将配置中的代理设置转换为S3客户端选项
+func proxyOptions(proxy config.ProxyConfig) s3client.ProxyOptions {
+	return s3client.ProxyOptions{URL: proxy.URL, NoProxy: proxy.NoProxy}
+}
+
+// timeoutOptions 将配置中的超时设置转换为S3客户端选项
+func timeoutOptions(timeouts config.TimeoutConfig) s3client.TimeoutOptions {
+	return s3client.TimeoutOptions{
+		Connect:        timeouts.Connect,
+		ResponseHeader: timeouts.ResponseHeader,
+		Idle:           timeouts.Idle,
+		Request:        timeouts.Request,
+	}
+}
+
+// uploadSources 将配置中的源目录转换为上传选项
+func uploadSources(dirs []config.SourceDir) []upload.Source {
+	var result []upload.Source
+	for _, dir := range dirs {
+		result = append(result, upload.Source{Dir: dir.Path, Prefix: dir.Prefix})
+	}
+	return result
+}
+
+// missingSourceDir 返回桶的第一个不存在的上传源目录，全部存在时返回空字符串
+func missingSourceDir(bucket config.BucketSettings) string {
+	dirs := []string{bucket.OutputDir}
+	if len(bucket.SourceDirs) > 0 {
+		dirs = dirs[:0]
+		for _, source := range bucket.SourceDirs {
+			dirs = append(dirs, source.Path)
+		}
+	}
+
+	for _, dir := range dirs {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			return dir
+		}
+	}
+	return ""
+}
+
+// bucketEndpoints 返回所有桶使用的端点，多个端点用逗号分隔
+func bucketEndpoints(settings *config.MultiBucketSettings) string {
+	var endpoints []string
+	seen := make(map[string]bool)
+	for _, bucket := range settings.Buckets {
+		if !seen[bucket.Endpoint] {
+			seen[bucket.Endpoint] = true
+			endpoints = append(endpoints, bucket.Endpoint)
+		}
+	}
+	return strings.Join(endpoints, ", ")
+}
@@ -0,0 +1,159 @@
+package app
+
+import (
+	"bufio"
+	"os"
+	"slices"
+	"strings"
+
+	"objectsync/internal/config"
+	"objectsync/internal/i18n"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *App) newMbCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mb <s3://桶名>",
+		Short: "创建存储桶",
+		Long:  "在对象存储中创建新桶，可选同时启用版本控制。桶已存在时报错",
+		Args:  cobra.ExactArgs(1),
+		RunE:  a.runMb,
+	}
+
+	// 添加命令行参数
+	addConnectionFlags(cmd)
+	cmd.Flags().Bool("versioning", false, "创建后启用版本控制")
+
+	return cmd
+}
+
+func (a *App) newRbCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rb <s3://桶名>",
+		Short: "删除存储桶",
+		Long:  "删除空桶。使用 --force 先删除桶中的所有对象（包括历史版本），删除前需要输入桶名确认",
+		Args:  cobra.ExactArgs(1),
+		RunE:  a.runRb,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completeRemotePath(cmd, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	// 添加命令行参数
+	addConnectionFlags(cmd)
+	cmd.Flags().Bool("force", false, "删除桶中的所有对象和历史版本后再删除桶")
+	cmd.Flags().BoolP("yes", "y", false, "配合 --force 使用，不再要求输入桶名确认")
+
+	return cmd
+}
+
+func (a *App) runMb(cmd *cobra.Command, args []string) error {
+	versioning, _ := cmd.Flags().GetBool("versioning")
+
+	bucket, err := parseBucketPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := remoteClient(cmd, bucket)
+	if err != nil {
+		return err
+	}
+
+	exists, err := client.BucketExists(bucket)
+	if err != nil {
+		return i18n.Errorf("检查存储桶失败: %w", err)
+	}
+	if exists {
+		return i18n.Errorf("存储桶 %s 已存在", bucket)
+	}
+
+	if err := client.MakeBucket(bucket, versioning); err != nil {
+		return i18n.Errorf("创建存储桶失败: %w", err)
+	}
+
+	if versioning {
+		i18n.Printf("存储桶 %s 创建成功，已启用版本控制\n", bucket)
+	} else {
+		i18n.Printf("存储桶 %s 创建成功\n", bucket)
+	}
+	return nil
+}
+
+func (a *App) runRb(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	force, _ := cmd.Flags().GetBool("force")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	bucket, err := parseBucketPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := remoteClient(cmd, bucket)
+	if err != nil {
+		return err
+	}
+
+	exists, err := client.BucketExists(bucket)
+	if err != nil {
+		return i18n.Errorf("检查存储桶失败: %w", err)
+	}
+	if !exists {
+		return i18n.Errorf("存储桶 %s 不存在", bucket)
+	}
+
+	// 仍在配置中使用的桶删除后，下次上传会重新创建空桶
+	if configured, _ := config.ListNames(configFile); slices.Contains(configured, bucket) {
+		i18n.Printf("警告: 存储桶 %s 仍在配置文件 %s 中使用\n", bucket, configFile)
+	}
+
+	empty, err := client.IsEmpty(bucket)
+	if err != nil {
+		return i18n.Errorf("检查存储桶失败: %w", err)
+	}
+	if !empty {
+		if !force {
+			return i18n.Errorf("存储桶 %s 不为空，使用 --force 删除其中的所有对象", bucket)
+		}
+		if !yes {
+			if !interactive(cmd) {
+				return withExitCode(ExitUsage, i18n.Errorf("非交互模式下需要使用 --yes 确认删除"))
+			}
+			i18n.Printf("将永久删除存储桶 %s 中的所有对象和历史版本，请输入桶名确认: ", bucket)
+			response, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if strings.TrimSpace(response) != bucket {
+				i18n.Println("已取消")
+				return nil
+			}
+		}
+
+		deleted, err := client.EmptyBucket(bucket)
+		if err != nil {
+			return i18n.Errorf("清空存储桶失败（已删除 %d 个对象版本）: %w", deleted, err)
+		}
+		i18n.Printf("已删除 %d 个对象版本\n", deleted)
+	}
+
+	if err := client.RemoveBucket(bucket); err != nil {
+		return i18n.Errorf("删除存储桶失败: %w", err)
+	}
+	i18n.Printf("存储桶 %s 已删除\n", bucket)
+	return nil
+}
+
+// parseBucketPath 解析只包含桶名的远程路径，允许末尾的/
+func parseBucketPath(path string) (string, error) {
+	bucket, key, err := parseRemotePath(path)
+	if err != nil {
+		return "", err
+	}
+	if key != "" {
+		return "", i18n.Errorf("无效的桶名: %s（格式: s3://桶名）", path)
+	}
+	return bucket, nil
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"os"
+	"strings"
+
+	"objectsync/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *App) newCompletionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion <bash|zsh|fish|powershell>",
+		Short: "生成命令行补全脚本",
+		Long: `生成指定shell的命令行补全脚本，--bucket 和 --cluster 等参数会补全配置文件中的名称。
+
+  bash:       source <(objectsync completion bash)
+  zsh:        objectsync completion zsh > "${fpath[1]}/_objectsync"
+  fish:       objectsync completion fish > ~/.config/fish/completions/objectsync.fish
+  powershell: objectsync completion powershell | Out-String | Invoke-Expression`,
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(os.Stdout, true)
+			case "zsh":
+				return root.GenZshCompletion(os.Stdout)
+			case "fish":
+				return root.GenFishCompletion(os.Stdout, true)
+			default:
+				return root.GenPowerShellCompletionWithDesc(os.Stdout)
+			}
+		},
+	}
+}
+
+// addBucketFlags 添加 --bucket 和 --exclude-bucket 参数，并为它们和 --cluster 注册补全
+func addBucketFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("bucket", nil, "只处理指定的桶（可重复或用逗号分隔）")
+	cmd.Flags().StringSlice("exclude-bucket", nil, "跳过指定的桶（可重复或用逗号分隔）")
+	cmd.RegisterFlagCompletionFunc("bucket", completeBuckets)
+	cmd.RegisterFlagCompletionFunc("exclude-bucket", completeBuckets)
+	cmd.RegisterFlagCompletionFunc("cluster", completeClusters)
+}
+
+// completeBuckets 补全配置文件中的桶名
+func completeBuckets(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	configFile, _ := cmd.Flags().GetString("config")
+	buckets, _ := config.ListNames(configFile)
+	return buckets, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeClusters 补全配置文件中的集群和remote名称
+func completeClusters(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	configFile, _ := cmd.Flags().GetString("config")
+	_, clusters := config.ListNames(configFile)
+	return clusters, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRemotePath 补全 s3://桶名/ 形式的远程路径
+func completeRemotePath(cmd *cobra.Command, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// 已经输入了桶名后的对象键，不再补全
+	if strings.Contains(strings.TrimPrefix(toComplete, "s3://"), "/") {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	configFile, _ := cmd.Flags().GetString("config")
+	buckets, _ := config.ListNames(configFile)
+	paths := make([]string, 0, len(buckets))
+	for _, bucket := range buckets {
+		paths = append(paths, "s3://"+bucket+"/")
+	}
+	return paths, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
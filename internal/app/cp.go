@@ -0,0 +1,161 @@
+package app
+
+import (
+	"path"
+	"strings"
+	"sync"
+
+	"objectsync/internal/i18n"
+	"objectsync/internal/progress"
+	"objectsync/internal/remote"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *App) newCpCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cp <s3://源桶/对象键|前缀> <s3://目标桶/对象键|前缀>",
+		Short: "服务端复制对象",
+		Long:  "在同一个端点内复制对象（CopyObject，超过5GB的对象使用UploadPartCopy），数据不经过本机。目标对象键以/结尾时追加源对象名，--recursive 复制前缀下的所有对象",
+		Args:  cobra.ExactArgs(2),
+		RunE:  a.runCp,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) < 2 {
+				return completeRemotePath(cmd, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	// 添加命令行参数
+	addConnectionFlags(cmd)
+	cmd.Flags().BoolP("recursive", "r", false, "复制前缀下的所有对象")
+	cmd.Flags().IntP("workers", "w", 5, "并发复制数")
+	cmd.Flags().BoolP("verbose", "v", false, "详细输出")
+
+	return cmd
+}
+
+func (a *App) runCp(cmd *cobra.Command, args []string) error {
+	recursive, _ := cmd.Flags().GetBool("recursive")
+	workers, _ := cmd.Flags().GetInt("workers")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	srcBucket, srcKey, err := parseRemotePath(args[0])
+	if err != nil {
+		return err
+	}
+	dstBucket, dstKey, err := parseRemotePath(args[1])
+	if err != nil {
+		return err
+	}
+	if !recursive && srcKey == "" {
+		return i18n.Errorf("请指定要复制的对象键，复制前缀下的所有对象请使用 --recursive")
+	}
+
+	// 服务端复制要求两个桶在同一个端点，使用源桶的连接
+	settings, srcConn, err := bucketConnection(cmd, srcBucket)
+	if err != nil {
+		return err
+	}
+	_, dstConn, err := bucketConnection(cmd, dstBucket)
+	if err != nil {
+		return err
+	}
+	if srcConn.Endpoint != dstConn.Endpoint {
+		return i18n.Errorf("源桶 %s（%s）和目标桶 %s（%s）不在同一个端点，无法服务端复制",
+			srcBucket, srcConn.Endpoint, dstBucket, dstConn.Endpoint)
+	}
+
+	client, err := newRemoteClient(settings, srcConn)
+	if err != nil {
+		return err
+	}
+
+	// 收集要复制的对象
+	var objects []remote.Entry
+	if recursive {
+		err = client.Walk(srcBucket, srcKey, true, func(entry remote.Entry) error {
+			objects = append(objects, entry)
+			return nil
+		})
+		if err != nil {
+			return i18n.Errorf("列出对象失败: %w", err)
+		}
+	} else {
+		entry, err := client.Stat(srcBucket, srcKey)
+		if err != nil {
+			return i18n.Errorf("检查对象失败: %w", err)
+		}
+		if entry == nil {
+			return i18n.Errorf("对象不存在: %s/%s（复制前缀下的对象请使用 --recursive）", srcBucket, srcKey)
+		}
+		objects = []remote.Entry{*entry}
+	}
+
+	if len(objects) == 0 {
+		i18n.Printf("没有需要复制的对象\n")
+		return nil
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+	tasks := make(chan remote.Entry)
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var copied int
+	var copiedSize int64
+	var failed []string
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entry := range tasks {
+				target := copyTarget(entry.Key, srcKey, dstKey, recursive)
+				if verbose {
+					i18n.Printf("复制: %s/%s -> %s/%s\n", srcBucket, entry.Key, dstBucket, target)
+				}
+				err := client.Copy(srcBucket, entry.Key, dstBucket, target, entry.Size)
+
+				mutex.Lock()
+				if err != nil {
+					i18n.Printf("复制 %s 失败: %v\n", entry.Key, err)
+					failed = append(failed, entry.Key)
+				} else {
+					copied++
+					copiedSize += entry.Size
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+	for _, entry := range objects {
+		tasks <- entry
+	}
+	close(tasks)
+	wg.Wait()
+
+	i18n.Printf("已复制 %d 个对象（%s）\n", copied, progress.FormatSize(copiedSize))
+	if len(failed) > 0 {
+		return i18n.Errorf("%d 个对象复制失败", len(failed))
+	}
+	return nil
+}
+
+// copyTarget 计算源对象复制后的目标对象键：递归复制时保留相对于源前缀的路径，
+// 复制单个对象且目标以/结尾（或为空）时追加源对象名
+func copyTarget(key, srcPrefix, dstKey string, recursive bool) string {
+	if recursive {
+		relative := strings.TrimPrefix(strings.TrimPrefix(key, srcPrefix), "/")
+		if dstKey != "" && !strings.HasSuffix(dstKey, "/") {
+			dstKey += "/"
+		}
+		return dstKey + relative
+	}
+	if dstKey == "" || strings.HasSuffix(dstKey, "/") {
+		return dstKey + path.Base(key)
+	}
+	return dstKey
+}
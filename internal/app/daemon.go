@@ -0,0 +1,377 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"objectsync/internal/config"
+	"objectsync/internal/i18n"
+	"objectsync/internal/progress"
+	"objectsync/internal/ratelimit"
+	"objectsync/internal/schedule"
+	"objectsync/internal/sdnotify"
+
+	"github.com/spf13/cobra"
+)
+
+// daemonStatus 守护进程的状态，每次运行结束后写入状态文件
+type daemonStatus struct {
+	PID       int                      `json:"pid"`
+	StartedAt time.Time                `json:"started_at"`
+	UpdatedAt time.Time                `json:"updated_at"`
+	Running   string                   `json:"running,omitempty"` // 正在运行的桶，见 config.BucketSettings.ID
+	Buckets   map[string]*bucketStatus `json:"buckets"`           // 按 config.BucketSettings.ID 区分，同名的桶不会相互覆盖
+}
+
+// bucketStatus 单个桶的调度状态
+type bucketStatus struct {
+	Schedule    string     `json:"schedule"`
+	NextRun     time.Time  `json:"next_run"`
+	LastStart   *time.Time `json:"last_start,omitempty"`
+	LastSuccess bool       `json:"last_success"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastFiles   int64      `json:"last_files"`
+	LastBytes   int64      `json:"last_bytes"`
+	Duration    float64    `json:"last_duration_seconds"`
+	Skipped     int        `json:"skipped"` // 因上一次运行尚未结束而跳过的次数
+}
+
+// maxDaemonQueue 等待运行的桶的数量上限
+const maxDaemonQueue = 1024
+
+// daemonHeartbeat 没有其他变化时更新状态文件的间隔，healthcheck 据此判断守护进程是否存活
+const daemonHeartbeat = time.Minute
+
+// daemonJob 一次计划运行，携带触发时的配置，运行期间重新加载配置不影响正在运行的桶
+type daemonJob struct {
+	settings *config.MultiBucketSettings
+	bucket   config.BucketSettings
+}
+
+// scheduledBucket 带有解析后调度表达式的桶
+type scheduledBucket struct {
+	settings config.BucketSettings
+	schedule *schedule.Schedule
+	next     time.Time
+}
+
+func (a *App) newDaemonCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "按计划持续运行",
+		Long:  "常驻运行，按每个桶配置的 schedule（cron表达式）执行同步。同一时间只运行一个桶，到期时上一次运行尚未结束的桶本轮跳过。配置文件修改后自动重新加载，状态写入 --status-file",
+		RunE:  a.runDaemon,
+	}
+
+	// 添加命令行参数
+	cmd.Flags().StringP("config", "c", "", configFlagUsage)
+	cmd.Flags().String("status-file", ".objectsync_daemon.json", "守护进程状态文件路径")
+	cmd.Flags().Float64("max-requests-per-second", 0, "每秒最多发送的请求数，所有桶和工作协程共享 (0表示不限制)")
+	cmd.Flags().BoolP("verbose", "v", false, "详细输出")
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "查看守护进程状态",
+		Long:  "读取守护进程的状态文件，显示每个桶的下次运行时间和上次运行结果",
+		RunE:  a.runDaemonStatus,
+	}
+	statusCmd.Flags().String("status-file", ".objectsync_daemon.json", "守护进程状态文件路径")
+	cmd.AddCommand(statusCmd)
+
+	return cmd
+}
+
+func (a *App) runDaemon(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	statusFile, _ := cmd.Flags().GetString("status-file")
+	maxRequests, _ := cmd.Flags().GetFloat64("max-requests-per-second")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	if err := setupLogging(cmd, true); err != nil {
+		return err
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	return serveDaemon(configFile, statusFile, maxRequests, verbose, stop)
+}
+
+// serveDaemon 按计划运行直到从stop收到信号，等待正在运行的桶结束后返回。
+// 前台运行时stop来自进程信号，作为Windows服务运行时来自服务控制请求
+func serveDaemon(configFile, statusFile string, maxRequests float64, verbose bool, stop <-chan os.Signal) error {
+	configManager := config.NewConfigManager(configFile)
+	if _, err := configManager.LoadConfig(); err != nil {
+		return configError(i18n.Errorf("配置加载失败: %w", err))
+	}
+	if err := configManager.ValidateConfig(); err != nil {
+		return configError(i18n.Errorf("配置验证失败: %w", err))
+	}
+
+	status := &daemonStatus{
+		PID:       os.Getpid(),
+		StartedAt: time.Now(),
+		Buckets:   make(map[string]*bucketStatus),
+	}
+	var mutex sync.Mutex
+
+	// 配置重新加载后从下一次触发开始使用新的桶列表和调度
+	settings := configManager.ToBucketSettings()
+	buckets := scheduledBuckets(settings, time.Now())
+	if len(buckets) == 0 {
+		return i18n.Errorf("配置中没有设置 schedule 的桶")
+	}
+	reload := make(chan *config.MultiBucketSettings, 1)
+	configManager.Watch(func(updated *config.MultiBucketSettings) {
+		select {
+		case reload <- updated:
+		default:
+		}
+	})
+
+	// 所有桶共享同一个请求速率限制器，由单个工作协程依次运行。
+	// pending保证每个桶在队列中最多一项，队列容量只需不小于桶数
+	limiter := ratelimit.New(maxRequests)
+	queue := make(chan daemonJob, maxDaemonQueue)
+	pending := make(map[string]bool)
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			var job daemonJob
+			select {
+			case <-quit:
+				return
+			case job = <-queue:
+			}
+			bucket := job.bucket
+
+			mutex.Lock()
+			start := time.Now()
+			entry := status.Buckets[bucket.ID()]
+			if entry == nil {
+				// 运行前配置已重新加载并移除了该桶
+				entry = &bucketStatus{Schedule: bucket.Schedule}
+			}
+			entry.LastStart = &start
+			status.Running = bucket.ID()
+			writeDaemonStatus(statusFile, status)
+			mutex.Unlock()
+
+			daemonLog.Infof("开始%s桶: %s", directionLabel(bucket.Direction), bucket.ID())
+			results := newRunResults("daemon")
+			stats, err := runBucketDirection(job.settings, bucket, limiter, verbose, false, nil)
+			results.add(bucket.Name, stats, err)
+			finishRun(job.settings, results)
+			if err != nil {
+				daemonLog.Errorf("桶 %s 同步失败: %v", bucket.ID(), err)
+			} else {
+				daemonLog.Infof("桶 %s 同步完成，传输 %d 个文件（%s），用时 %s",
+					bucket.ID(), stats.Files, progress.FormatSize(stats.Bytes), stats.Duration.Round(time.Second))
+			}
+
+			mutex.Lock()
+			entry.LastSuccess = err == nil
+			entry.LastError = ""
+			if err != nil {
+				entry.LastError = err.Error()
+			}
+			entry.LastFiles, entry.LastBytes = stats.Files, stats.Bytes
+			entry.Duration = stats.Duration.Seconds()
+			status.Running = ""
+			delete(pending, bucket.ID())
+			writeDaemonStatus(statusFile, status)
+			mutex.Unlock()
+		}
+	}()
+
+	updateStatus := func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		current := make(map[string]*bucketStatus)
+		for _, bucket := range buckets {
+			id := bucket.settings.ID()
+			entry := status.Buckets[id]
+			if entry == nil {
+				entry = &bucketStatus{}
+			}
+			entry.Schedule = bucket.settings.Schedule
+			entry.NextRun = bucket.next
+			current[id] = entry
+		}
+		status.Buckets = current
+		writeDaemonStatus(statusFile, status)
+	}
+	updateStatus()
+
+	daemonLog.Infof("守护进程已启动，%d 个桶按计划运行", len(buckets))
+	for _, bucket := range buckets {
+		daemonLog.Infof("  %s (%s) 下次运行: %s", bucket.settings.ID(), bucket.settings.Schedule, bucket.next.Format("2006-01-02 15:04"))
+	}
+
+	// 由systemd以 Type=notify 启动时报告就绪，启用了看门狗时在主循环中发送心跳
+	sdnotify.Notify(sdnotify.Ready)
+	var watchdog <-chan time.Time
+	if interval := sdnotify.WatchdogInterval(); interval > 0 {
+		ticker := time.NewTicker(interval / 2)
+		defer ticker.Stop()
+		watchdog = ticker.C
+	}
+
+	heartbeat := time.NewTicker(daemonHeartbeat)
+	defer heartbeat.Stop()
+
+	timer := time.NewTimer(time.Until(buckets[0].next))
+	defer timer.Stop()
+	for {
+		select {
+		case <-stop:
+			daemonLog.Infof("收到退出信号，等待正在运行的桶结束...")
+			sdnotify.Notify(sdnotify.Stopping)
+			close(quit)
+			<-done
+			return nil
+
+		case <-watchdog:
+			sdnotify.Notify(sdnotify.Watchdog)
+			continue
+
+		case <-heartbeat.C:
+			updateStatus()
+			continue
+
+		case updated := <-reload:
+			settings = updated
+			buckets = scheduledBuckets(updated, time.Now())
+			daemonLog.Infof("调度已更新，%d 个桶按计划运行", len(buckets))
+			updateStatus()
+
+		case now := <-timer.C:
+			for i := range buckets {
+				bucket := &buckets[i]
+				if bucket.next.After(now) {
+					continue
+				}
+				bucket.next = bucket.schedule.Next(now)
+
+				// 上一次运行尚未结束时跳过本次，避免同一个桶重叠运行
+				id := bucket.settings.ID()
+				mutex.Lock()
+				if pending[id] {
+					status.Buckets[id].Skipped++
+					mutex.Unlock()
+					daemonLog.Warnf("桶 %s 上一次运行尚未结束，跳过本次", id)
+					continue
+				}
+				if len(pending) >= maxDaemonQueue {
+					mutex.Unlock()
+					daemonLog.Warnf("等待运行的桶过多，跳过桶 %s", id)
+					continue
+				}
+				pending[id] = true
+				mutex.Unlock()
+				queue <- daemonJob{settings: settings, bucket: bucket.settings}
+			}
+			sortScheduled(buckets)
+			updateStatus()
+		}
+
+		if len(buckets) > 0 {
+			timer.Reset(time.Until(buckets[0].next))
+		}
+	}
+}
+
+// scheduledBuckets 返回设置了schedule的桶，按下次运行时间排序
+func scheduledBuckets(settings *config.MultiBucketSettings, now time.Time) []scheduledBucket {
+	var buckets []scheduledBucket
+	for _, bucket := range settings.Buckets {
+		if bucket.Schedule == "" {
+			continue
+		}
+		// 配置验证已检查过表达式
+		parsed, err := schedule.Parse(bucket.Schedule)
+		if err != nil {
+			continue
+		}
+		next := parsed.Next(now)
+		if next.IsZero() {
+			continue
+		}
+		buckets = append(buckets, scheduledBucket{settings: bucket, schedule: parsed, next: next})
+	}
+	sortScheduled(buckets)
+	return buckets
+}
+
+// sortScheduled 按下次运行时间排序
+func sortScheduled(buckets []scheduledBucket) {
+	sort.SliceStable(buckets, func(i, j int) bool {
+		return buckets[i].next.Before(buckets[j].next)
+	})
+}
+
+// writeDaemonStatus 写入状态文件，失败时只输出警告
+func writeDaemonStatus(path string, status *daemonStatus) {
+	status.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err == nil {
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		daemonLog.Warnf("写入状态文件失败: %v", err)
+	}
+}
+
+func (a *App) runDaemonStatus(cmd *cobra.Command, args []string) error {
+	statusFile, _ := cmd.Flags().GetString("status-file")
+
+	data, err := os.ReadFile(statusFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return i18n.Errorf("状态文件 %s 不存在，守护进程可能尚未启动", statusFile)
+		}
+		return i18n.Errorf("无法读取状态文件: %w", err)
+	}
+	var status daemonStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return i18n.Errorf("状态文件格式错误: %w", err)
+	}
+
+	i18n.Printf("进程ID: %d\n", status.PID)
+	i18n.Printf("启动时间: %s\n", status.StartedAt.Format("2006-01-02 15:04:05"))
+	i18n.Printf("更新时间: %s\n", status.UpdatedAt.Format("2006-01-02 15:04:05"))
+	if status.Running != "" {
+		i18n.Printf("正在运行: %s\n", status.Running)
+	}
+
+	names := make([]string, 0, len(status.Buckets))
+	for name := range status.Buckets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		entry := status.Buckets[name]
+		i18n.Printf("\n桶: %s (%s)\n", name, entry.Schedule)
+		i18n.Printf("  下次运行: %s\n", entry.NextRun.Format("2006-01-02 15:04"))
+		if entry.LastStart == nil {
+			i18n.Printf("  尚未运行\n")
+			continue
+		}
+		i18n.Printf("  上次运行: %s，用时 %s\n", entry.LastStart.Format("2006-01-02 15:04:05"),
+			time.Duration(entry.Duration*float64(time.Second)).Round(time.Second))
+		if entry.LastSuccess {
+			i18n.Printf("  结果: 成功，传输 %d 个文件（%s）\n", entry.LastFiles, progress.FormatSize(entry.LastBytes))
+		} else if entry.LastError != "" {
+			i18n.Printf("  结果: 失败: %s\n", entry.LastError)
+		}
+		if entry.Skipped > 0 {
+			i18n.Printf("  跳过次数: %d\n", entry.Skipped)
+		}
+	}
+	return nil
+}
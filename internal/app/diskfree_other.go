@@ -0,0 +1,14 @@
+//go:build !windows
+
+package app
+
+import "golang.org/x/sys/unix"
+
+// diskFree 返回path所在文件系统中当前用户可用的字节数
+func diskFree(path string) (uint64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,18 @@
+//go:build windows
+
+package app
+
+import "golang.org/x/sys/windows"
+
+// diskFree 返回path所在磁盘中当前用户可用的字节数
+func diskFree(path string) (uint64, error) {
+	name, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var available uint64
+	if err := windows.GetDiskFreeSpaceEx(name, &available, nil, nil); err != nil {
+		return 0, err
+	}
+	return available, nil
+}
@@ -0,0 +1,635 @@
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"objectsync/internal/config"
+	"objectsync/internal/i18n"
+	"objectsync/internal/journal"
+	"objectsync/internal/progress"
+	"objectsync/internal/remote"
+	"objectsync/internal/s3client"
+	"objectsync/internal/statestore"
+	"objectsync/internal/storage"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// maxClockSkew S3签名允许的最大时钟偏差，超过时服务端返回RequestTimeTooSkewed
+	maxClockSkew = 15 * time.Minute
+	// warnClockSkew 超过该偏差时提示同步时间
+	warnClockSkew = time.Minute
+	// warnCertExpiry 服务端证书在该时间内过期时提示
+	warnCertExpiry = 30 * 24 * time.Hour
+	// minDiskFree 输出目录所在磁盘的可用空间低于该值时提示
+	minDiskFree = 1 << 30
+)
+
+func (a *App) newDoctorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "诊断配置、网络和本地环境的问题",
+		Long:  "逐项检查配置文件、端点的DNS解析、TCP连接和TLS握手、本机时钟偏差、凭证、桶的访问权限、磁盘空间和状态文件，对发现的问题给出处理建议",
+		Args:  cobra.NoArgs,
+		RunE:  a.runDoctor,
+		// 问题已经逐项输出，不再输出用法说明
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringP("config", "c", "", configFlagUsage)
+	cmd.Flags().StringSliceP("bucket", "b", nil, "只检查指定的桶（可重复或用逗号分隔）")
+	cmd.Flags().Duration("timeout", 10*time.Second, "每项网络检查的超时")
+	cmd.RegisterFlagCompletionFunc("bucket", completeBuckets)
+
+	return cmd
+}
+
+func (a *App) runDoctor(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	buckets, _ := cmd.Flags().GetStringSlice("bucket")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	failed := 0
+	section := func(title string, checks []healthCheck) {
+		fmt.Printf("\n[%s]\n", title)
+		failed += printChecks(checks)
+	}
+
+	checks, settings := checkConfig(configFile)
+	section(i18n.T("配置"), checks)
+	if settings == nil {
+		return configError(i18n.Errorf("配置文件 %s 无法加载", configFile))
+	}
+	if len(buckets) > 0 {
+		if err := settings.FilterBuckets(buckets); err != nil {
+			return withExitCode(ExitUsage, err)
+		}
+	}
+
+	// 使用同一连接的桶只检查一次端点，记录端点是否可用
+	reachable := make(map[string]bool)
+	for _, bucket := range settings.Buckets {
+		key := endpointKey(bucket)
+		if _, ok := reachable[key]; ok {
+			continue
+		}
+		title := i18n.Sprintf("端点 %s", bucket.Endpoint)
+		if bucket.Remote != "" {
+			title = i18n.Sprintf("端点 %s (remote %s)", bucket.Endpoint, bucket.Remote)
+		}
+		checks := checkEndpoint(settings, bucket, timeout)
+		reachable[key] = !slices.ContainsFunc(checks, func(check healthCheck) bool { return !check.ok })
+		section(title, checks)
+	}
+
+	for _, bucket := range settings.Buckets {
+		title := i18n.Sprintf("桶 %s", bucket.Name)
+		if bucket.Prefix != "" {
+			title = i18n.Sprintf("桶 %s 前缀 %s", bucket.Name, bucket.Prefix)
+		}
+		section(title, checkBucket(settings, bucket, reachable[endpointKey(bucket)], timeout))
+	}
+
+	if failed > 0 {
+		i18n.Printf("\n发现 %d 个问题\n", failed)
+		return withExitCode(ExitFailure, i18n.Errorf("诊断发现 %d 个问题", failed))
+	}
+	i18n.Println("\n没有发现问题")
+	return nil
+}
+
+// checkConfig 检查配置文件能否加载和验证、本地路径是否可用以及lint警告。
+// 配置无法加载时返回的settings为nil；验证失败时仍然返回，继续检查其他项
+func checkConfig(configFile string) ([]healthCheck, *config.MultiBucketSettings) {
+	name := i18n.T("配置文件")
+	// 不像其他命令那样在配置文件不存在时生成默认配置
+	if _, err := os.Stat(configFile); err != nil {
+		return []healthCheck{{
+			name:   name,
+			detail: err.Error(),
+			hint:   i18n.Sprintf("使用 objectsync config init -o %s 生成配置文件", configFile),
+		}}, nil
+	}
+
+	configManager := config.NewConfigManager(configFile)
+	if _, err := configManager.LoadConfig(); err != nil {
+		return []healthCheck{{
+			name:   name,
+			detail: err.Error(),
+			hint:   i18n.Sprintf("使用 objectsync config lint -c %s 查看具体的配置项", configFile),
+		}}, nil
+	}
+
+	checks := []healthCheck{{name: name, ok: true, detail: configFile}}
+	if err := configManager.ValidateConfig(); err != nil {
+		checks = append(checks, healthCheck{name: i18n.T("配置验证"), detail: err.Error()})
+	}
+	if err := configManager.ValidatePaths(); err != nil {
+		checks = append(checks, healthCheck{name: i18n.T("本地路径"), detail: err.Error()})
+	}
+	if issues, err := config.Lint(configFile); err == nil {
+		for _, issue := range issues {
+			// 与默认值不同的设置不是问题
+			if issue.Level == config.LintInfo {
+				continue
+			}
+			checks = append(checks, healthCheck{
+				name:    "lint",
+				ok:      issue.Level != config.LintError,
+				warning: true,
+				detail:  issue.Message,
+			})
+		}
+	}
+	return checks, configManager.ToBucketSettings()
+}
+
+// checkEndpoint 依次检查DNS解析、TCP连接、TLS握手、时钟偏差和凭证，
+// 前一步失败时后面的检查只会得到同样的错误，不再继续
+func checkEndpoint(settings *config.MultiBucketSettings, bucket config.BucketSettings, timeout time.Duration) []healthCheck {
+	var checks []healthCheck
+
+	// 本地目录端点只检查目录是否存在
+	if dir, ok := storage.LocalPath(bucket.Endpoint); ok {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return append(checks, healthCheck{name: i18n.T("本地目录"), detail: i18n.Sprintf("%s 不存在或不是目录", dir),
+				hint: i18n.T("检查路径是否正确，以及移动硬盘或网络共享是否已挂载")})
+		}
+		return append(checks, healthCheck{name: i18n.T("本地目录"), ok: true, detail: dir})
+	}
+
+	// WebDAV端点检查服务地址的网络连接
+	endpoint := bucket.Endpoint
+	if address, ok := storage.WebDAVURL(endpoint); ok {
+		endpoint = address
+	}
+	target, err := endpointURL(endpoint)
+	if err != nil {
+		return append(checks, healthCheck{name: "endpoint", detail: err.Error(),
+			hint: i18n.T("endpoint 应为完整的URL，如 http://192.168.1.100:7480")})
+	}
+
+	// 配置了代理时网络检查针对代理服务器，TLS在代理隧道中进行，不单独检查
+	host, port := target.Hostname(), defaultPort(target)
+	proxy, err := proxyOptions(bucket.Proxy).ProxyURL(target)
+	if err != nil {
+		return append(checks, healthCheck{name: i18n.T("代理"), detail: err.Error(), hint: i18n.T("检查 proxy.url")})
+	}
+	if proxy != nil {
+		checks = append(checks, healthCheck{name: i18n.T("代理"), ok: true, detail: i18n.Sprintf("通过代理 %s 访问", proxy.Redacted())})
+		host, port = proxy.Hostname(), defaultPort(proxy)
+	}
+
+	if net.ParseIP(host) == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+		cancel()
+		if err != nil {
+			return append(checks, healthCheck{name: "DNS", detail: err.Error(),
+				hint: i18n.Sprintf("无法解析 %s：检查主机名是否拼写正确，以及本机的DNS设置", host)})
+		}
+		checks = append(checks, healthCheck{name: "DNS", ok: true, detail: fmt.Sprintf("%s -> %s", host, strings.Join(addrs, ", "))})
+	}
+
+	address := net.JoinHostPort(host, port)
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		hint := i18n.Sprintf("无法连接 %s：检查网络是否连通以及防火墙是否放行该端口", address)
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			hint = i18n.Sprintf("%s 拒绝连接：检查端口是否正确，以及对象存储服务是否正在运行", address)
+		}
+		return append(checks, healthCheck{name: "TCP", detail: err.Error(), hint: hint})
+	}
+	conn.Close()
+	checks = append(checks, healthCheck{name: "TCP", ok: true, detail: i18n.Sprintf("%s 连接成功，用时 %s", address, time.Since(start).Round(time.Millisecond))})
+
+	if target.Scheme == "https" && proxy == nil {
+		check := checkTLS(bucket, target.Hostname(), address, timeout)
+		checks = append(checks, check)
+		if !check.ok {
+			return checks
+		}
+	}
+
+	return append(checks, checkCredentials(settings, bucket, timeout)...)
+}
+
+// checkTLS 使用配置中的证书选项单独完成一次TLS握手，区分证书不受信任、主机名不匹配和过期
+func checkTLS(bucket config.BucketSettings, serverName, address string, timeout time.Duration) healthCheck {
+	check := healthCheck{name: "TLS"}
+
+	tlsConfig, err := tlsOptions(bucket.TLS).Config()
+	if err != nil {
+		check.detail = err.Error()
+		check.hint = i18n.T("检查 tls 中的证书和私钥文件路径")
+		return check
+	}
+	tlsConfig.ServerName = serverName
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", address, tlsConfig)
+	if err != nil {
+		check.detail = err.Error()
+		check.hint = tlsHint(err)
+		return check
+	}
+	state := conn.ConnectionState()
+	conn.Close()
+
+	check.ok = true
+	cert := state.PeerCertificates[0]
+	check.detail = i18n.Sprintf("%s，证书 %s 有效期至 %s", tls.VersionName(state.Version), cert.Subject.CommonName, cert.NotAfter.Local().Format("2006-01-02"))
+	switch {
+	case bucket.TLS.InsecureSkipVerify:
+		check.warning = true
+		check.hint = i18n.T("已设置 tls.insecure_skip_verify，不校验服务端证书；建议改为在 tls.ca_file 中配置CA证书")
+	case bucket.TLS.CAFile != "" && os.Getenv("AWS_CA_BUNDLE") != "":
+		// SDK创建会话时用该环境变量替换传输层的根证书
+		check.warning = true
+		check.hint = i18n.T("环境变量 AWS_CA_BUNDLE 会替换 tls.ca_file 中的CA证书：取消该环境变量，或把CA证书加入其中")
+	case time.Until(cert.NotAfter) < warnCertExpiry:
+		check.warning = true
+		check.hint = i18n.T("服务端证书即将过期，请联系管理员更新")
+	}
+	return check
+}
+
+// tlsHint 根据握手错误给出处理建议
+func tlsHint(err error) string {
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	var recordHeader tls.RecordHeaderError
+	switch {
+	case errors.As(err, &unknownAuthority):
+		return i18n.T("服务端证书不是受信任的CA签发的（如自签名证书）：在 tls.ca_file 中配置签发它的CA证书")
+	case errors.As(err, &hostname) && len(hostname.Certificate.DNSNames) == 0 && len(hostname.Certificate.IPAddresses) == 0:
+		return i18n.T("服务端证书只有CN没有SAN（主题备用名称），不再被接受：重新签发包含SAN的证书")
+	case errors.As(err, &hostname):
+		return i18n.T("证书中的主机名与endpoint不一致：endpoint改用证书中的主机名")
+	case errors.As(err, &invalid) && invalid.Reason == x509.Expired:
+		return i18n.T("服务端证书已过期或本机时间不正确：检查系统时间，或联系管理员更新证书")
+	case errors.As(err, &recordHeader):
+		return i18n.T("该端口不是HTTPS服务：检查endpoint是否应该使用 http://")
+	}
+	return i18n.T("检查 tls 中的证书设置，需要双向认证时配置 tls.cert_file 和 tls.key_file")
+}
+
+// checkCredentials 发送一次列出所有桶的请求，检查凭证并根据响应头的时间检查本机时钟偏差
+func checkCredentials(settings *config.MultiBucketSettings, bucket config.BucketSettings, timeout time.Duration) []healthCheck {
+	var checks []healthCheck
+
+	// WebDAV没有签名，不检查时钟偏差
+	if _, ok := storage.WebDAVURL(bucket.Endpoint); ok {
+		check := healthCheck{name: i18n.T("认证")}
+		store, err := doctorStorage(bucket, timeout)
+		var names []string
+		if err == nil {
+			names, err = store.ListBuckets()
+		}
+		if err != nil {
+			check.detail = err.Error()
+			check.hint = i18n.T("检查WebDAV地址，以及作为用户名和密码的 access_key 和 secret_key（Nextcloud建议使用应用密码）")
+			return append(checks, check)
+		}
+		check.ok = true
+		check.detail = i18n.Sprintf("凭证有效，可以访问 %d 个桶", len(names))
+		return append(checks, check)
+	}
+
+	client, err := doctorClient(settings, bucket, timeout)
+	if err != nil {
+		return append(checks, healthCheck{name: i18n.T("认证"), detail: err.Error()})
+	}
+	names, serverTime, err := client.ListBuckets()
+
+	if !serverTime.IsZero() {
+		check := healthCheck{name: i18n.T("时钟"), ok: true}
+		skew := time.Since(serverTime).Round(time.Second)
+		if skew < 0 {
+			skew = -skew
+		}
+		check.detail = i18n.Sprintf("与服务端相差 %s", skew)
+		switch {
+		case skew > maxClockSkew:
+			check.ok = false
+			check.hint = i18n.T("本机时钟偏差超过15分钟，请求签名会被拒绝：同步系统时间（如启用NTP）")
+		case skew > warnClockSkew:
+			check.warning = true
+			check.hint = i18n.T("本机时钟偏差较大：建议启用NTP同步系统时间")
+		}
+		checks = append(checks, check)
+	}
+
+	check := healthCheck{name: i18n.T("认证")}
+	if err == nil {
+		check.ok = true
+		check.detail = i18n.Sprintf("凭证有效，可以访问 %d 个桶", len(names))
+		return append(checks, check)
+	}
+	check.detail = err.Error()
+	var aerr awserr.Error
+	if errors.As(err, &aerr) {
+		switch aerr.Code() {
+		case "AccessDenied":
+			// 很多用户只有指定桶的权限，不影响备份
+			check.ok, check.warning = true, true
+			check.detail = i18n.T("凭证有效，但没有列出所有桶的权限")
+			check.hint = i18n.T("只要能访问配置的桶就不影响使用，见下面各桶的检查")
+		case "InvalidAccessKeyId":
+			check.hint = i18n.T("服务端不存在该access_key：检查 access_key（或 profile）是否正确")
+		case "SignatureDoesNotMatch":
+			check.hint = i18n.T("签名不匹配：检查 secret_key 是否正确，服务端要求特定区域时检查 region")
+		case "RequestTimeTooSkewed":
+			check.hint = i18n.T("本机时钟偏差过大：同步系统时间（如启用NTP）")
+		case "ExpiredToken", "InvalidToken":
+			check.hint = i18n.T("凭证已过期或无效：更新密钥")
+		case "NoCredentialProviders", "SharedCredsLoad":
+			check.hint = i18n.T("无法读取凭证：检查 profile 名称和共享凭证文件（~/.aws/credentials）")
+		case "RequestError", "RequestCanceled":
+			check.hint = i18n.T("TCP连接正常但请求失败：检查endpoint的协议（http/https）和端口、代理及超时设置")
+		}
+	}
+	return append(checks, check)
+}
+
+// checkBucket 检查桶的访问权限、输出目录所在磁盘的可用空间以及状态文件，
+// 端点不可用时不再检查访问权限
+func checkBucket(settings *config.MultiBucketSettings, bucket config.BucketSettings, reachable bool, timeout time.Duration) []healthCheck {
+	var checks []healthCheck
+	if reachable {
+		checks = append(checks, checkBucketAccess(settings, bucket, timeout))
+	} else {
+		checks = append(checks, healthCheck{name: i18n.T("访问"), ok: true, warning: true, detail: i18n.T("端点检查未通过，跳过")})
+	}
+
+	if bucket.Direction == config.DirectionReplicate {
+		if reachable {
+			checks = append(checks, checkTargetAccess(bucket.TargetSettings(), timeout))
+		} else {
+			checks = append(checks, healthCheck{name: i18n.T("复制目标"), ok: true, warning: true, detail: i18n.T("端点检查未通过，跳过")})
+		}
+	}
+
+	if bucket.Direction != config.DirectionUpload && bucket.OutputDir != "" {
+		checks = append(checks, checkDiskFree(bucket.OutputDir))
+	}
+
+	stateFile := bucket.StateFile
+	if bucket.Direction == config.DirectionUpload {
+		stateFile = bucket.UploadStateFile()
+	}
+	checks = append(checks, checkStateFile(statestore.Path(stateFile, settings.StateBackend), settings.StateBackend, bucket.Direction == config.DirectionUpload))
+	if _, err := os.Stat(journal.Path(stateFile)); err == nil {
+		checks = append(checks, healthCheck{
+			name:    i18n.T("运行日志"),
+			ok:      true,
+			warning: true,
+			detail:  i18n.T("上次运行没有完成，下次运行时从中断处继续"),
+		})
+	}
+	return checks
+}
+
+// checkBucketAccess 用HEAD请求检查桶是否存在以及是否有权限访问
+func checkBucketAccess(settings *config.MultiBucketSettings, bucket config.BucketSettings, timeout time.Duration) healthCheck {
+	check := healthCheck{name: i18n.T("访问")}
+
+	// 本地目录和WebDAV的桶是目录，上传时自动创建
+	if !storage.IsS3(bucket.Endpoint) {
+		store, err := doctorStorage(bucket, timeout)
+		var exists bool
+		if err == nil {
+			exists, err = store.BucketExists()
+		}
+		switch {
+		case err != nil:
+			check.detail = err.Error()
+		case exists:
+			check.ok = true
+			check.detail = i18n.T("桶存在，可以访问")
+		default:
+			dir := bucket.Name
+			if local, ok := storage.LocalPath(bucket.Endpoint); ok {
+				dir = filepath.Join(local, bucket.Name)
+			}
+			check.ok = true
+			check.warning = true
+			check.detail = i18n.T("桶不存在")
+			check.hint = i18n.Sprintf("上传时自动创建目录 %s", dir)
+		}
+		return check
+	}
+
+	client, err := doctorClient(settings, bucket, timeout)
+	if err != nil {
+		check.detail = err.Error()
+		return check
+	}
+	exists, err := client.BucketExists(bucket.Name)
+	switch {
+	case err == nil && exists:
+		check.ok = true
+		check.detail = i18n.T("桶存在，可以访问")
+	case err == nil:
+		check.detail = i18n.T("桶不存在")
+		check.hint = i18n.Sprintf("检查桶名是否正确，或使用 objectsync mb %s 创建", bucket.Name)
+	default:
+		check.detail = err.Error()
+		var aerr awserr.Error
+		if errors.As(err, &aerr) {
+			switch aerr.Code() {
+			case "Forbidden", "AccessDenied":
+				check.hint = i18n.T("没有访问该桶的权限：检查用户权限或桶策略")
+			case "MovedPermanently", "PermanentRedirect", "BadRequest":
+				check.hint = i18n.T("桶位于其他区域或寻址方式不对：检查 region 和 path_style")
+			}
+		}
+	}
+	return check
+}
+
+// checkDiskFree 检查输出目录（不存在时为最近的上级目录）所在磁盘的可用空间
+func checkDiskFree(dir string) healthCheck {
+	check := healthCheck{name: i18n.T("磁盘空间")}
+
+	path := filepath.Clean(dir)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			break
+		}
+		path = parent
+	}
+
+	free, err := diskFree(path)
+	if err != nil {
+		check.detail = err.Error()
+		return check
+	}
+	check.ok = true
+	check.detail = i18n.Sprintf("%s 可用 %s", dir, progress.FormatSize(int64(free)))
+	if free < minDiskFree {
+		check.warning = true
+		check.hint = i18n.T("可用空间不足，下载可能因磁盘已满失败：清理磁盘或更换 output_dir")
+	}
+	return check
+}
+
+// checkStateFile 检查状态文件能否解析，以及每个条目的格式
+func checkStateFile(path, backend string, upload bool) healthCheck {
+	check := healthCheck{name: i18n.T("状态文件")}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		check.ok = true
+		check.detail = i18n.Sprintf("%s 尚未创建，首次运行后生成", path)
+		return check
+	}
+	store, err := openState(path, backend, upload)
+	if err != nil {
+		check.detail = err.Error()
+		check.hint = i18n.T("状态文件已损坏：从备份中恢复，或删除后重新运行（会重新传输全部文件）")
+		return check
+	}
+	defer store.Close()
+
+	var invalid []string
+	count := 0
+	err = store.Range("", func(key string, raw json.RawMessage) bool {
+		count++
+		if entry, err := parseStateEntry(raw); err != nil || entry.Size < 0 {
+			invalid = append(invalid, key)
+		}
+		return true
+	})
+	if err != nil {
+		check.detail = err.Error()
+		check.hint = i18n.T("状态文件已损坏：从备份中恢复，或删除后重新运行（会重新传输全部文件）")
+		return check
+	}
+	if len(invalid) > 0 {
+		check.detail = i18n.Sprintf("%s 中有 %d 个条目无法解析，如 %s", path, len(invalid), invalid[0])
+		check.hint = i18n.Sprintf("使用 objectsync state rm -f %s <键> 删除这些条目，下次运行时重新传输对应的文件", path)
+		return check
+	}
+
+	check.ok = true
+	check.detail = i18n.Sprintf("%s，%d 个条目", path, count)
+	if last := store.LastRun(); !last.IsZero() {
+		check.detail += i18n.Sprintf("，上次运行 %s", last.Local().Format("2006-01-02 15:04:05"))
+	}
+	return check
+}
+
+// checkTargetAccess 检查复制的目标桶是否可以访问，目标桶不存在时复制会自动创建
+func checkTargetAccess(target config.BucketSettings, timeout time.Duration) healthCheck {
+	check := healthCheck{name: i18n.T("复制目标")}
+	target.Timeouts.Connect = timeout
+	target.Timeouts.Request = timeout
+	store, err := storage.Open(s3client.Options{
+		Endpoint:      target.Endpoint,
+		AccessKey:     target.AccessKey,
+		SecretKey:     target.SecretKey,
+		Profile:       target.Profile,
+		Region:        target.Region,
+		VirtualHosted: !target.PathStyle,
+		Transport:     target.Transport,
+		MaxAttempts:   1,
+		TLS:           tlsOptions(target.TLS),
+		Proxy:         proxyOptions(target.Proxy),
+		Timeouts:      timeoutOptions(target.Timeouts),
+	}, target.Name)
+	var exists bool
+	if err == nil {
+		exists, err = store.BucketExists()
+	}
+	switch {
+	case err != nil:
+		check.detail = err.Error()
+	case exists:
+		check.ok = true
+		check.detail = i18n.Sprintf("%s（%s）存在，可以访问", target.Name, target.Endpoint)
+	default:
+		check.ok = true
+		check.warning = true
+		check.detail = i18n.Sprintf("%s（%s）不存在", target.Name, target.Endpoint)
+		check.hint = i18n.T("复制时自动创建目标桶")
+	}
+	return check
+}
+
+// doctorClient 创建诊断使用的客户端：只尝试一次，并使用诊断的超时
+func doctorClient(settings *config.MultiBucketSettings, bucket config.BucketSettings, timeout time.Duration) (*remote.Client, error) {
+	single := *settings
+	single.MaxAttempts = 1
+	bucket.Timeouts.Connect = timeout
+	bucket.Timeouts.Request = timeout
+	return newRemoteClient(&single, bucket)
+}
+
+// doctorStorage 打开诊断本地目录或WebDAV端点使用的存储：只尝试一次，并使用诊断的超时
+func doctorStorage(bucket config.BucketSettings, timeout time.Duration) (storage.Storage, error) {
+	bucket.Timeouts.Connect = timeout
+	bucket.Timeouts.Request = timeout
+	return storage.Open(s3client.Options{
+		Endpoint:    bucket.Endpoint,
+		AccessKey:   bucket.AccessKey,
+		SecretKey:   bucket.SecretKey,
+		MaxAttempts: 1,
+		TLS:         tlsOptions(bucket.TLS),
+		Proxy:       proxyOptions(bucket.Proxy),
+		Timeouts:    timeoutOptions(bucket.Timeouts),
+	}, bucket.Name)
+}
+
+// endpointKey 区分不同连接的键，引用同一remote或使用ceph配置的桶共用
+func endpointKey(bucket config.BucketSettings) string {
+	return bucket.Remote + "\x00" + bucket.Endpoint
+}
+
+// endpointURL 解析endpoint，没有协议时与SDK一致按https处理
+func endpointURL(endpoint string) (*url.URL, error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	target, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if target.Hostname() == "" {
+		return nil, i18n.Errorf("缺少主机名: %s", endpoint)
+	}
+	return target, nil
+}
+
+// defaultPort 返回URL中的端口，未指定时按协议使用默认端口
+func defaultPort(target *url.URL) string {
+	if port := target.Port(); port != "" {
+		return port
+	}
+	switch target.Scheme {
+	case "https":
+		return "443"
+	case "socks5":
+		return "1080"
+	}
+	return "80"
+}
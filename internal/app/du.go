@@ -0,0 +1,67 @@
+package app
+
+import (
+	"fmt"
+
+	"objectsync/internal/i18n"
+	"objectsync/internal/progress"
+	"objectsync/internal/remote"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *App) newDuCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "du <s3://桶名[/前缀]>",
+		Short: "统计远程对象占用空间",
+		Long:  "按前缀之后的前 --depth 级目录汇总对象数量和大小，适合容量规划。--output json 时输出每个目录的统计",
+		Args:  cobra.ExactArgs(1),
+		RunE:  a.withReport(a.runDu),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completeRemotePath(cmd, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	// 添加命令行参数
+	addConnectionFlags(cmd)
+	cmd.Flags().IntP("depth", "d", 1, "汇总的目录层级 (0表示只显示总计)")
+
+	return cmd
+}
+
+func (a *App) runDu(cmd *cobra.Command, args []string) error {
+	depth, _ := cmd.Flags().GetInt("depth")
+	if depth < 0 {
+		return i18n.Errorf("--depth 不能为负数")
+	}
+
+	bucket, prefix, err := parseRemotePath(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := remoteClient(cmd, bucket)
+	if err != nil {
+		return err
+	}
+
+	usage, err := client.DiskUsage(bucket, prefix, depth)
+	if err != nil {
+		return i18n.Errorf("列出对象失败: %w", err)
+	}
+
+	var total remote.Usage
+	for _, entry := range usage {
+		total.Objects += entry.Objects
+		total.Bytes += entry.Bytes
+		a.report.addUsage(entry)
+		if depth > 0 {
+			fmt.Printf("%10s  %10d  %s\n", progress.FormatSize(entry.Bytes), entry.Objects, entry.Prefix)
+		}
+	}
+	i18n.Printf("总计: %d 个对象，%s（%s/%s）\n", total.Objects, progress.FormatSize(total.Bytes), bucket, prefix)
+	return nil
+}
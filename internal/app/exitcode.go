@@ -0,0 +1,158 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"objectsync/internal/progress"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/spf13/cobra"
+)
+
+// 进程退出码，供包装脚本和调度器区分失败类型
+const (
+	ExitOK         = 0 // 成功
+	ExitConfig     = 1 // 配置文件缺失、无法解析或验证失败
+	ExitConnection = 2 // 无法连接对象存储或认证失败
+	ExitPartial    = 3 // 部分桶或对象失败，其余成功
+	ExitFailure    = 4 // 全部失败或其他错误
+	ExitUsage      = 5 // 命令行参数错误
+)
+
+// connectionErrorCodes 表示网络不可达或认证失败的S3错误码
+var connectionErrorCodes = map[string]bool{
+	"RequestError":          true, // 网络错误
+	"RequestCanceled":       true,
+	"NoCredentialProviders": true,
+	"SharedCredsLoad":       true,
+	"InvalidAccessKeyId":    true,
+	"SignatureDoesNotMatch": true,
+	"AccessDenied":          true,
+	"Forbidden":             true,
+	"InvalidToken":          true,
+	"ExpiredToken":          true,
+	"RequestTimeTooSkewed":  true,
+}
+
+// exitError 带有退出码的错误
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// withExitCode 为错误指定退出码，err为nil时返回nil
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{code: code, err: err}
+}
+
+// configError 标记配置错误
+func configError(err error) error {
+	return withExitCode(ExitConfig, err)
+}
+
+// partialError 桶中有对象失败，但其他对象已经成功
+type partialError struct {
+	err error
+}
+
+func (e *partialError) Error() string { return e.err.Error() }
+func (e *partialError) Unwrap() error { return e.err }
+
+// objectsFailed 标记桶中只有部分对象失败的错误，使单个桶的运行也能返回部分失败
+func objectsFailed(err error) error {
+	return &partialError{err: err}
+}
+
+// bucketError 按对象级的结果标记失败的桶：已有对象传输成功时记为部分失败
+func bucketError(err error, stats progress.Stats) error {
+	if stats.Files > 0 {
+		return objectsFailed(err)
+	}
+	return err
+}
+
+// isPartial 判断失败的桶中是否有对象已经成功
+func isPartial(err error) bool {
+	var partial *partialError
+	return errors.As(err, &partial)
+}
+
+// bucketsError 按成功的桶数和各个失败桶的错误确定多桶运行的退出码：
+// 有桶成功或失败的桶中有对象成功时为部分失败，全部因连接或认证失败时为连接错误，否则为完全失败
+func bucketsError(err error, successCount int, failures []error) error {
+	if successCount > 0 || slices.ContainsFunc(failures, isPartial) {
+		return withExitCode(ExitPartial, err)
+	}
+	for _, failure := range failures {
+		if !isConnectionError(failure) {
+			return withExitCode(ExitFailure, err)
+		}
+	}
+	return withExitCode(ExitConnection, err)
+}
+
+// isConnectionError 判断错误是否由网络不可达或认证失败引起
+func isConnectionError(err error) bool {
+	var aerr awserr.Error
+	if errors.As(err, &aerr) {
+		if connectionErrorCodes[aerr.Code()] {
+			return true
+		}
+		// 请求错误的原始原因可能是更具体的网络错误
+		if orig := aerr.OrigErr(); orig != nil && orig != err {
+			return isConnectionError(orig)
+		}
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// ExitCode 返回错误对应的进程退出码
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var exit *exitError
+	if errors.As(err, &exit) {
+		return exit.code
+	}
+	if isConnectionError(err) {
+		return ExitConnection
+	}
+	return ExitFailure
+}
+
+// unknownCommand 根命令的参数检查：根命令本身不接受参数，出现的参数是未知的子命令
+func unknownCommand(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	message := fmt.Sprintf("unknown command %q for %q", args[0], cmd.CommandPath())
+	if suggestions := cmd.SuggestionsFor(args[0]); len(suggestions) > 0 {
+		message += "\n\nDid you mean this?\n\t" + strings.Join(suggestions, "\n\t") + "\n"
+	}
+	return errors.New(message)
+}
+
+// usageArgs 让命令及其子命令的参数检查错误使用命令行参数错误的退出码
+func usageArgs(cmd *cobra.Command) {
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			return withExitCode(ExitUsage, validate(cmd, args))
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		usageArgs(sub)
+	}
+}
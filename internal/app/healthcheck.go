@@ -0,0 +1,226 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"objectsync/internal/backup"
+	"objectsync/internal/config"
+	"objectsync/internal/history"
+	"objectsync/internal/i18n"
+	"objectsync/internal/schedule"
+
+	"github.com/spf13/cobra"
+)
+
+// exitUnhealthy Docker HEALTHCHECK 只接受0（健康）和1（不健康）两种退出码
+const exitUnhealthy = 1
+
+// defaultMaxAge 没有设置schedule的桶（由外部定时任务运行）最近一次成功运行距今的最长时间
+const defaultMaxAge = 25 * time.Hour
+
+// scheduleGrace 按schedule计算最长时间时额外留出的时间，容纳运行本身的用时和启动延迟
+const scheduleGrace = time.Hour
+
+// healthCheck 单项检查的结果，healthcheck 和 doctor 共用
+type healthCheck struct {
+	name    string
+	ok      bool
+	warning bool // 不影响健康状态，只提示
+	detail  string
+	hint    string // 建议的处理方法
+}
+
+func (a *App) newHealthcheckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "healthcheck",
+		Short: "检查运行状况，供容器健康检查使用",
+		Long:  "检查守护进程是否存活、每个桶最近一次成功运行距今的时间以及对象存储端点是否可达，健康时退出码为0，否则为1，可用于Docker HEALTHCHECK和Kubernetes探针",
+		Args:  cobra.NoArgs,
+		RunE:  a.runHealthcheck,
+		// 探针会反复执行，失败时不输出用法说明
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringP("config", "c", "", configFlagUsage)
+	cmd.Flags().String("status-file", ".objectsync_daemon.json", "守护进程状态文件路径")
+	cmd.Flags().Bool("no-daemon", false, "不检查守护进程（使用定时任务运行 run 时）")
+	cmd.Flags().Duration("max-age", 0, "每个桶最近一次成功运行距今的最长时间 (0表示按桶的schedule计算，允许错过一次运行；没有schedule的桶为25h)")
+	cmd.Flags().Bool("no-last-run", false, "不检查每个桶最近一次成功运行的时间")
+	cmd.Flags().Bool("no-endpoint", false, "不检查对象存储端点是否可达")
+	cmd.Flags().Duration("timeout", 10*time.Second, "连接端点的超时")
+
+	return cmd
+}
+
+func (a *App) runHealthcheck(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	statusFile, _ := cmd.Flags().GetString("status-file")
+	noDaemon, _ := cmd.Flags().GetBool("no-daemon")
+	maxAge, _ := cmd.Flags().GetDuration("max-age")
+	noLastRun, _ := cmd.Flags().GetBool("no-last-run")
+	noEndpoint, _ := cmd.Flags().GetBool("no-endpoint")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	configManager := config.NewConfigManager(configFile)
+	if _, err := configManager.LoadConfig(); err != nil {
+		return withExitCode(exitUnhealthy, i18n.Errorf("配置加载失败: %w", err))
+	}
+	settings := configManager.ToBucketSettings()
+
+	var checks []healthCheck
+	if !noDaemon {
+		checks = append(checks, checkDaemon(statusFile))
+	}
+	if !noLastRun {
+		checks = append(checks, checkLastRuns(settings, maxAge)...)
+	}
+	if !noEndpoint {
+		checks = append(checks, checkEndpoints(settings, timeout)...)
+	}
+
+	if unhealthy := printChecks(checks); unhealthy > 0 {
+		return withExitCode(exitUnhealthy, i18n.Errorf("%d 项检查未通过", unhealthy))
+	}
+	return nil
+}
+
+// printChecks 每项检查输出一行，有处理建议时在下一行缩进输出，返回未通过的项数
+func printChecks(checks []healthCheck) int {
+	failed := 0
+	for _, check := range checks {
+		status := "OK  "
+		switch {
+		case !check.ok:
+			status = "FAIL"
+			failed++
+		case check.warning:
+			status = "WARN"
+		}
+		fmt.Printf("%s %s: %s\n", status, check.name, check.detail)
+		if check.hint != "" {
+			fmt.Printf("     -> %s\n", check.hint)
+		}
+	}
+	return failed
+}
+
+// checkDaemon 守护进程定期更新状态文件，超过三个心跳间隔没有更新视为已退出或卡住
+func checkDaemon(statusFile string) healthCheck {
+	check := healthCheck{name: "daemon"}
+
+	data, err := os.ReadFile(statusFile)
+	if err != nil {
+		check.detail = i18n.Sprintf("无法读取状态文件: %v", err)
+		return check
+	}
+	var status daemonStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		check.detail = i18n.Sprintf("状态文件格式错误: %v", err)
+		return check
+	}
+
+	age := time.Since(status.UpdatedAt)
+	if age > 3*daemonHeartbeat {
+		check.detail = i18n.Sprintf("状态文件已经 %s 没有更新", age.Round(time.Second))
+		return check
+	}
+	check.ok = true
+	check.detail = i18n.Sprintf("进程 %d 运行中，%s 前更新状态", status.PID, age.Round(time.Second))
+	return check
+}
+
+// checkLastRuns 按运行历史检查每个桶最近一次成功运行的时间，maxAge为0时按每个桶的schedule计算（见 lastRunMaxAge）。
+// 从未运行过的桶只给出警告，避免新部署的容器在首次运行前被判定为不健康
+func checkLastRuns(settings *config.MultiBucketSettings, maxAge time.Duration) []healthCheck {
+	records, err := history.Load(settings.HistoryFile)
+	if err != nil {
+		return []healthCheck{{name: "history", detail: i18n.Sprintf("读取运行历史失败: %v", err)}}
+	}
+
+	lastSuccess := make(map[string]time.Time)
+	for _, record := range records {
+		if !record.Failed() && record.Ended.After(lastSuccess[record.Bucket]) {
+			lastSuccess[record.Bucket] = record.Ended
+		}
+	}
+
+	var checks []healthCheck
+	seen := make(map[string]bool)
+	for _, bucket := range settings.Buckets {
+		if seen[bucket.Name] {
+			continue
+		}
+		seen[bucket.Name] = true
+
+		check := healthCheck{name: "bucket " + bucket.Name}
+		last, ok := lastSuccess[bucket.Name]
+		age := time.Since(last)
+		limit := maxAge
+		if limit == 0 {
+			limit = lastRunMaxAge(bucket.Schedule, last)
+		}
+		switch {
+		case !ok:
+			check.ok, check.warning = true, true
+			check.detail = i18n.T("还没有成功的运行")
+		case age > limit:
+			check.detail = i18n.Sprintf("最近一次成功运行在 %s 前，超过 %s", age.Round(time.Minute), limit.Round(time.Minute))
+		default:
+			check.ok = true
+			check.detail = i18n.Sprintf("最近一次成功运行在 %s 前", age.Round(time.Minute))
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// lastRunMaxAge 按桶的schedule计算最近一次成功运行距今的最长时间：last之后的第二次计划运行时间再加 scheduleGrace，
+// 即允许错过或失败一次运行。按实际的计划时间计算，工作日运行等间隔不均匀的计划在周末不会误报
+func lastRunMaxAge(expr string, last time.Time) time.Duration {
+	if expr == "" {
+		return defaultMaxAge
+	}
+	// 配置验证已检查过表达式
+	parsed, err := schedule.Parse(expr)
+	if err != nil {
+		return defaultMaxAge
+	}
+	next := parsed.Next(parsed.Next(last))
+	if next.IsZero() {
+		return defaultMaxAge
+	}
+	return next.Sub(last) + scheduleGrace
+}
+
+// checkEndpoints 对每个端点用其中一个桶发送一次列出请求，只尝试一次；
+// 桶不存在等服务端返回的错误说明端点可达，不算失败
+func checkEndpoints(settings *config.MultiBucketSettings, timeout time.Duration) []healthCheck {
+	var checks []healthCheck
+	seen := make(map[string]bool)
+	for _, bucket := range settings.Buckets {
+		if seen[bucket.Endpoint] {
+			continue
+		}
+		seen[bucket.Endpoint] = true
+
+		options := bucketBackupOptions(settings, bucket, nil)
+		options.MaxAttempts = 1
+		options.Timeouts.Connect = timeout
+		options.Timeouts.Request = timeout
+
+		check := healthCheck{name: "endpoint " + bucket.Endpoint}
+		start := time.Now()
+		err := backup.New(options).TestConnection()
+		if err != nil && isConnectionError(err) {
+			check.detail = err.Error()
+		} else {
+			check.ok = true
+			check.detail = i18n.Sprintf("可达，用时 %s", time.Since(start).Round(time.Millisecond))
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"objectsync/internal/config"
+	"objectsync/internal/history"
+	"objectsync/internal/i18n"
+	"objectsync/internal/notify"
+	"objectsync/internal/progress"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *App) newHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "查看运行历史",
+		Long:  "按时间倒序列出以往每次运行中各桶的开始时间、结果、传输的文件数和数据量以及错误摘要",
+		Args:  cobra.NoArgs,
+		RunE:  a.runHistory,
+	}
+
+	cmd.Flags().StringP("config", "c", "", configFlagUsage)
+	cmd.Flags().StringP("history-file", "f", "", "直接指定历史记录文件，不读取配置文件")
+	cmd.Flags().StringSliceP("bucket", "b", nil, "只显示指定的桶（可重复或用逗号分隔）")
+	cmd.Flags().Bool("failed", false, "只显示失败的运行")
+	cmd.Flags().StringToString("label", nil, "只显示带有指定标签 key=value 的运行（可重复）")
+	cmd.Flags().IntP("limit", "n", 20, "最多显示的记录数 (0表示全部)")
+	cmd.RegisterFlagCompletionFunc("bucket", completeBuckets)
+
+	return cmd
+}
+
+func (a *App) runHistory(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	historyFile, _ := cmd.Flags().GetString("history-file")
+	buckets, _ := cmd.Flags().GetStringSlice("bucket")
+	failed, _ := cmd.Flags().GetBool("failed")
+	labels, _ := cmd.Flags().GetStringToString("label")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	if historyFile == "" {
+		configManager := config.NewConfigManager(configFile)
+		if _, err := configManager.LoadConfig(); err != nil {
+			return configError(i18n.Errorf("配置加载失败: %w", err))
+		}
+		historyFile = configManager.ToBucketSettings().HistoryFile
+	}
+
+	records, err := history.Load(historyFile)
+	if err != nil {
+		return i18n.Errorf("读取运行历史失败: %w", err)
+	}
+
+	// 从最近的运行开始筛选
+	var selected []history.Record
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		if len(buckets) > 0 && !slices.Contains(buckets, record.Bucket) {
+			continue
+		}
+		if failed && !record.Failed() {
+			continue
+		}
+		if !record.HasLabels(labels) {
+			continue
+		}
+		selected = append(selected, record)
+	}
+
+	if len(selected) == 0 {
+		i18n.Println("没有运行记录")
+		return nil
+	}
+
+	shown := selected
+	if limit > 0 && len(shown) > limit {
+		shown = shown[:limit]
+	}
+	for _, record := range shown {
+		result := i18n.T("成功")
+		if record.Failed() {
+			result = i18n.T("失败")
+		}
+		fmt.Printf("%s  %-7s  %-20s  %-4s  %6d  %10s  %8s",
+			record.Started.Local().Format("2006-01-02 15:04:05"),
+			record.Command,
+			record.Bucket,
+			result,
+			record.Files,
+			progress.FormatSize(record.Bytes),
+			record.Duration().Round(time.Second))
+		if len(record.Labels) > 0 {
+			fmt.Printf("  [%s]", notify.FormatLabels(record.Labels))
+		}
+		fmt.Println()
+		if record.Error != "" {
+			// 只显示错误的第一行，完整内容保存在历史记录文件中
+			summary, _, _ := strings.Cut(record.Error, "\n")
+			fmt.Printf("    %s\n", summary)
+		}
+	}
+
+	if len(shown) < len(selected) {
+		i18n.Printf("显示最近 %d 条，共 %d 条记录（使用 --limit 0 显示全部）\n", len(shown), len(selected))
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"log/slog"
+
+	"objectsync/internal/logging"
+	"objectsync/internal/progress"
+
+	"github.com/spf13/cobra"
+)
+
+// daemonLog 守护进程和Windows服务的日志
+var daemonLog = logging.New("daemon")
+
+// notifyLog 运行历史和通知的日志
+var notifyLog = logging.New("notify")
+
+func init() {
+	// 传输过程中输出日志时先清除进度条，进度条在下次刷新时重新显示
+	logging.SetInterrupt(progress.Clear)
+}
+
+// addLoggingFlags 添加日志参数。日志和进度条分开控制：--verbose 只改变进度的显示方式，
+// 没有指定 --log-level 时同时显示调试日志（每个文件的传输）
+func addLoggingFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("log-level", "info", "日志级别: debug、info、warn 或 error")
+	cmd.PersistentFlags().String("log-format", logging.FormatText, "日志格式: text 或 json（每行一个JSON对象，便于日志系统收集）")
+}
+
+// setupLogging 按参数设置日志级别和格式，timestamps 为true时文本日志每行显示时间（用于长时间运行的守护进程）
+func setupLogging(cmd *cobra.Command, timestamps bool) error {
+	name, _ := cmd.Flags().GetString("log-level")
+	format, _ := cmd.Flags().GetString("log-format")
+
+	level, err := logging.ParseLevel(name)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose && !cmd.Flags().Changed("log-level") {
+		level = slog.LevelDebug
+	}
+	if err := logging.Setup(logging.Options{Level: level, Format: format, Time: timestamps}); err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"fmt"
+
+	"objectsync/internal/i18n"
+	"objectsync/internal/progress"
+	"objectsync/internal/remote"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *App) newLsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ls <s3://桶名[/前缀]>",
+		Short: "列出远程对象",
+		Long:  "列出桶中指定前缀下的对象，默认只列出下一级，更深的对象显示为目录。--output json 时输出对象列表",
+		Args:  cobra.ExactArgs(1),
+		RunE:  a.withReport(a.runLs),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completeRemotePath(cmd, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	// 添加命令行参数
+	addConnectionFlags(cmd)
+	cmd.Flags().BoolP("recursive", "r", false, "递归列出前缀下的所有对象")
+	cmd.Flags().BoolP("long", "l", false, "显示大小、修改时间、ETag和存储类型")
+
+	return cmd
+}
+
+func (a *App) runLs(cmd *cobra.Command, args []string) error {
+	recursive, _ := cmd.Flags().GetBool("recursive")
+	long, _ := cmd.Flags().GetBool("long")
+
+	bucket, prefix, err := parseRemotePath(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := remoteClient(cmd, bucket)
+	if err != nil {
+		return err
+	}
+
+	var count int
+	var totalSize int64
+	err = client.Walk(bucket, prefix, recursive, func(entry remote.Entry) error {
+		count++
+		totalSize += entry.Size
+		// 结构化输出时只记录结果，不打印文本列表
+		if a.report != nil {
+			a.report.addObject(entry)
+			return nil
+		}
+		printEntry(entry, long)
+		return nil
+	})
+	if err != nil {
+		return i18n.Errorf("列出对象失败: %w", err)
+	}
+
+	if long {
+		i18n.Printf("共 %d 项，%s\n", count, progress.FormatSize(totalSize))
+	}
+	return nil
+}
+
+// printEntry 打印一个对象或目录，long为true时包含详细信息
+func printEntry(entry remote.Entry, long bool) {
+	if !long {
+		fmt.Println(entry.Key)
+		return
+	}
+	if entry.IsPrefix {
+		fmt.Printf("%19s  %10s  %-34s  %-12s  %s\n", "", "DIR", "", "", entry.Key)
+		return
+	}
+	fmt.Printf("%s  %10s  %-34s  %-12s  %s\n",
+		entry.LastModified.Local().Format("2006-01-02 15:04:05"),
+		progress.FormatSize(entry.Size),
+		entry.ETag,
+		entry.StorageClass,
+		entry.Key)
+}
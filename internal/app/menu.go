@@ -0,0 +1,350 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"objectsync/internal/backup"
+	"objectsync/internal/config"
+	"objectsync/internal/i18n"
+	"objectsync/internal/progress"
+	"objectsync/internal/ratelimit"
+
+	"github.com/spf13/cobra"
+)
+
+// runBackupMenu 文本菜单的下载：列出配置的桶供选择，询问增量、试运行和并发数后
+// 使用与 backup 命令相同的流程下载
+func (a *App) runBackupMenu(cmd *cobra.Command) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	configManager := config.NewConfigManager(configFile)
+	if _, err := configManager.LoadConfig(); err != nil {
+		return configError(i18n.Errorf("配置加载失败: %w", err))
+	}
+	if err := configManager.ValidateConfig(); err != nil {
+		return configError(i18n.Errorf("配置验证失败: %w", err))
+	}
+	settings := configManager.ToBucketSettings()
+
+	// 同一个桶的多个前缀作为一项，选择时一起下载
+	var names []string
+	dirs := make(map[string][]string)
+	for _, bucket := range settings.Buckets {
+		if bucket.Direction == config.DirectionUpload || bucket.Direction == config.DirectionReplicate {
+			continue
+		}
+		if !slices.Contains(names, bucket.Name) {
+			names = append(names, bucket.Name)
+		}
+		dirs[bucket.Name] = append(dirs[bucket.Name], bucket.OutputDir)
+	}
+	if len(names) == 0 {
+		i18n.Println("没有需要下载的桶（所有桶的方向都是 upload 或 replicate）")
+		return nil
+	}
+
+	i18n.Printf("已配置 %d 个下载的桶:\n", len(names))
+	for i, name := range names {
+		fmt.Printf("  [%d] %s -> %s\n", i+1, name, strings.Join(dirs[name], ", "))
+	}
+	fmt.Println()
+
+	reader := bufio.NewReader(os.Stdin)
+	var selected []string
+	for selected == nil {
+		input := promptLine(reader, i18n.T("选择要下载的桶（如 1,3 或 1-3，直接回车选择全部，0 取消）: "))
+		if input == "0" {
+			i18n.Println("下载已取消")
+			return nil
+		}
+		indexes, err := parseSelection(input, len(names))
+		if err != nil {
+			i18n.Printf("[错误] %v\n", err)
+			continue
+		}
+		selected = []string{}
+		for _, index := range indexes {
+			selected = append(selected, names[index])
+		}
+	}
+
+	incremental := promptYesNo(reader, i18n.T("增量下载，只下载有变化的对象?"), settings.Incremental)
+	dryRun := promptYesNo(reader, i18n.T("试运行，只统计需要下载的对象，不实际下载?"), false)
+	workers := 0
+	if !dryRun {
+		for {
+			input := promptLine(reader, i18n.T("并发数（直接回车使用配置中的值）: "))
+			if input == "" {
+				break
+			}
+			n, err := strconv.Atoi(input)
+			if err == nil && n > 0 {
+				workers = n
+				break
+			}
+			i18n.Println("[错误] 并发数必须是正整数")
+		}
+	}
+	fmt.Println()
+
+	if dryRun {
+		return a.runBackupDryRun(settings, selected, incremental)
+	}
+	return a.runBucketsBackup(configManager, "", "", "", "", "", selected, nil, nil, incremental, settings.Resume, false, false, workers, ratelimit.New(0))
+}
+
+// runBackupDryRun 统计所选桶下次下载需要传输的对象，不下载
+func (a *App) runBackupDryRun(settings *config.MultiBucketSettings, names []string, incremental bool) error {
+	if err := settings.FilterBuckets(names); err != nil {
+		return err
+	}
+
+	limiter := ratelimit.New(0)
+	var totalFiles, totalBytes int64
+	for _, bucketSettings := range settings.Buckets {
+		if bucketSettings.Direction == config.DirectionUpload || bucketSettings.Direction == config.DirectionReplicate {
+			continue
+		}
+		options := bucketBackupOptions(settings, bucketSettings, limiter)
+		options.Incremental = incremental
+		files, bytes, err := backup.New(options).Pending()
+		if err != nil {
+			i18n.Printf("桶 %s: 检查失败: %v\n", bucketSettings.Name, err)
+			continue
+		}
+		i18n.Printf("桶 %s: 需要下载 %d 个对象（%s）\n", bucketSettings.Name, files, progress.FormatSize(bytes))
+		totalFiles += files
+		totalBytes += bytes
+	}
+	i18n.Printf("\n试运行完成，共需要下载 %d 个对象（%s）\n", totalFiles, progress.FormatSize(totalBytes))
+	return nil
+}
+
+// promptLine 显示提示并读取一行输入，去掉首尾空白
+func promptLine(reader *bufio.Reader, prompt string) string {
+	fmt.Print(prompt)
+	line, _ := reader.ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+// promptYesNo 询问是否，直接回车时使用默认值
+func promptYesNo(reader *bufio.Reader, question string, def bool) bool {
+	hint := "(y/N)"
+	if def {
+		hint = "(Y/n)"
+	}
+	switch strings.ToLower(promptLine(reader, question+" "+hint+": ")) {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	}
+	return def
+}
+
+// parseSelection 解析菜单中的编号选择，支持逗号或空格分隔的编号和范围（1-3），
+// 返回从0开始的下标；输入为空时选择全部
+func parseSelection(input string, count int) ([]int, error) {
+	if input == "" {
+		indexes := make([]int, count)
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes, nil
+	}
+
+	var indexes []int
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		first, last, isRange := strings.Cut(field, "-")
+		start, err := strconv.Atoi(first)
+		end := start
+		if err == nil && isRange {
+			end, err = strconv.Atoi(last)
+		}
+		if err != nil || start < 1 || end > count || start > end {
+			return nil, i18n.Errorf("无效的选择: %s（可选 1-%d）", field, count)
+		}
+		for i := start - 1; i < end; i++ {
+			if !slices.Contains(indexes, i) {
+				indexes = append(indexes, i)
+			}
+		}
+	}
+	return indexes, nil
+}
+
+// runConnectionTestMenu 对配置中的每个桶测试连接，逐个显示通过或失败
+func (a *App) runConnectionTestMenu(cmd *cobra.Command) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	configManager := config.NewConfigManager(configFile)
+	if _, err := configManager.LoadConfig(); err != nil {
+		return configError(i18n.Errorf("配置加载失败: %w", err))
+	}
+	if err := configManager.ValidateConfig(); err != nil {
+		return configError(i18n.Errorf("配置验证失败: %w", err))
+	}
+	settings := configManager.ToBucketSettings()
+
+	failed := 0
+	for _, bucket := range settings.Buckets {
+		elapsed, err := testBucketConnection(settings, bucket)
+		if err != nil {
+			i18n.Printf("[失败] %s (%s): %v\n", bucketPath(bucket), bucket.Endpoint, err)
+			failed++
+			continue
+		}
+		i18n.Printf("[通过] %s (%s)，用时 %s\n", bucketPath(bucket), bucket.Endpoint, elapsed.Round(time.Millisecond))
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		i18n.Printf("%d 个桶连接失败，可以运行 objectsync doctor 查看详细的诊断\n", failed)
+		return nil
+	}
+	i18n.Printf("全部 %d 个桶连接正常\n", len(settings.Buckets))
+	return nil
+}
+
+// testBucketConnection 列出桶中的一个对象测试连接和权限，只尝试一次，返回用时
+func testBucketConnection(settings *config.MultiBucketSettings, bucket config.BucketSettings) (time.Duration, error) {
+	options := bucketBackupOptions(settings, bucket, nil)
+	options.MaxAttempts = 1
+	start := time.Now()
+	err := backup.New(options).TestConnection()
+	return time.Since(start), err
+}
+
+// bucketPath 显示桶名，配置了前缀时带上前缀
+func bucketPath(bucket config.BucketSettings) string {
+	if bucket.Prefix == "" {
+		return bucket.Name
+	}
+	return bucket.Name + "/" + bucket.Prefix
+}
+
+// runEditConfigMenu 用外部编辑器修改配置文件的副本，验证通过后才覆盖配置文件，
+// 验证失败时可以继续修改或放弃
+func (a *App) runEditConfigMenu(cmd *cobra.Command) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	configFile = config.Locate(configFile)
+	original, err := os.ReadFile(configFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			i18n.Printf("配置文件 %s 不存在，请先初始化配置\n", configFile)
+			return nil
+		}
+		return i18n.Errorf("读取配置文件失败: %w", err)
+	}
+
+	temp, err := writeConfigDraft(configFile, original)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp)
+
+	reader := bufio.NewReader(os.Stdin)
+	var settings *config.MultiBucketSettings
+	for settings == nil {
+		if err := runEditor(temp); err != nil {
+			return err
+		}
+		edited, err := os.ReadFile(temp)
+		if err != nil {
+			return i18n.Errorf("读取配置文件失败: %w", err)
+		}
+		if bytes.Equal(edited, original) {
+			i18n.Println("配置没有修改")
+			return nil
+		}
+		if settings, err = validateConfigFile(temp); err != nil {
+			i18n.Printf("[错误] %v\n", err)
+			if !promptYesNo(reader, i18n.T("继续修改?"), false) {
+				i18n.Println("修改已放弃，配置文件没有改变")
+				return nil
+			}
+		}
+	}
+
+	if err := replaceConfigFile(temp, configFile); err != nil {
+		return err
+	}
+	i18n.Printf("配置验证通过（共 %d 个桶），已保存: %s\n", len(settings.Buckets), configFile)
+	return nil
+}
+
+// editorCommand 返回编辑配置使用的编辑器：依次使用 VISUAL 和 EDITOR 环境变量，
+// 都未设置时Windows使用记事本，其他系统使用vi
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+// runEditor 在当前终端中打开编辑器，等待编辑器退出
+func runEditor(path string) error {
+	editor := editorCommand()
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return i18n.Errorf("运行编辑器 %s 失败: %w（可以通过 EDITOR 环境变量指定编辑器）", editor[0], err)
+	}
+	return nil
+}
+
+// writeConfigDraft 将编辑中的配置写入配置文件同目录的临时文件，保留yaml扩展名供解析，
+// 同目录保证替换配置文件时是原子的重命名
+func writeConfigDraft(configFile string, data []byte) (string, error) {
+	file, err := os.CreateTemp(filepath.Dir(configFile), ".objectsync-*.yaml")
+	if err != nil {
+		return "", i18n.Errorf("保存配置文件失败: %w", err)
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", i18n.Errorf("保存配置文件失败: %w", err)
+	}
+	return file.Name(), nil
+}
+
+// validateConfigFile 加载并验证配置文件，返回其中的桶配置
+func validateConfigFile(path string) (*config.MultiBucketSettings, error) {
+	configManager := config.NewConfigManager(path)
+	if _, err := configManager.LoadConfig(); err != nil {
+		return nil, i18n.Errorf("配置加载失败: %w", err)
+	}
+	if err := configManager.ValidateConfig(); err != nil {
+		return nil, i18n.Errorf("配置验证失败: %w", err)
+	}
+	return configManager.ToBucketSettings(), nil
+}
+
+// replaceConfigFile 用验证过的临时文件替换配置文件，保留原配置文件的权限
+func replaceConfigFile(temp, configFile string) error {
+	if info, err := os.Stat(configFile); err == nil {
+		os.Chmod(temp, info.Mode().Perm())
+	}
+	if err := os.Rename(temp, configFile); err != nil {
+		return i18n.Errorf("保存配置文件失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,313 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"objectsync/internal/config"
+	"objectsync/internal/i18n"
+	"objectsync/internal/progress"
+	"objectsync/internal/ratelimit"
+	"objectsync/internal/replicate"
+	"objectsync/internal/state"
+	"objectsync/internal/storage"
+
+	"github.com/spf13/cobra"
+)
+
+// 迁移后的校验方式
+const (
+	migrateVerifyFull = "full" // 比较对象列表，并通过HEAD比较元数据、标签和ACL
+	migrateVerifyList = "list" // 只比较对象列表中的存在性、大小和ETag
+	migrateVerifyNone = "none" // 不校验
+)
+
+// migrateBucket 单个桶的迁移结果
+type migrateBucket struct {
+	Bucket          string                  `json:"bucket"`
+	Error           string                  `json:"error,omitempty"`
+	Files           int64                   `json:"files"`
+	Bytes           int64                   `json:"bytes"`
+	AttributeErrors int64                   `json:"attribute_errors,omitempty"` // 复制标签或ACL失败的次数
+	Verify          *replicate.VerifyResult `json:"verify,omitempty"`
+}
+
+// migrateReportFile 迁移结束后写入的不一致报告
+type migrateReportFile struct {
+	Time    time.Time       `json:"time"`
+	From    string          `json:"from"`
+	To      string          `json:"to"`
+	Prefix  string          `json:"prefix,omitempty"`
+	Verify  string          `json:"verify"`
+	Buckets []migrateBucket `json:"buckets"`
+}
+
+func (a *App) newMigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate --from <remote> --to <remote>",
+		Short: "把一个对象存储端点的所有桶迁移到另一个端点",
+		Long: "把 --from 端点中的所有桶（或 --bucket 指定的桶）复制到 --to 端点的同名桶，用于更换Ceph集群等场景。" +
+			"对象内容只经过内存，不写入本地磁盘；保留用户元数据和HTTP头，两端都支持时复制对象的标签和ACL" +
+			"（授权给源对象所有者的项改为目标对象的所有者）。复制后逐个比较两端的对象，把不一致的对象写入报告文件。" +
+			"桶的策略、生命周期和版本设置不迁移。中断后重新运行时跳过已经复制的对象",
+		RunE: a.withReport(a.runMigrateEndpoints),
+	}
+
+	cmd.Flags().StringP("config", "c", "", configFlagUsage)
+	cmd.Flags().String("from", "", "源端点（remotes 或 clusters 中的名称）")
+	cmd.Flags().String("to", "", "目标端点（remotes 或 clusters 中的名称）")
+	cmd.Flags().StringSlice("bucket", nil, "只迁移指定的桶（可重复或用逗号分隔），默认迁移源端点的所有桶")
+	cmd.Flags().StringSlice("exclude-bucket", nil, "跳过指定的桶（可重复或用逗号分隔）")
+	cmd.Flags().String("prefix", "", "只迁移桶中该前缀下的对象")
+	cmd.Flags().BoolP("incremental", "i", true, "跳过上次迁移后没有变化的对象")
+	cmd.Flags().IntP("workers", "w", 5, "每个桶的并发复制数")
+	cmd.Flags().Int("parts-concurrency", 0, "单个大对象同时上传的分片数 (0表示使用默认值)")
+	cmd.Flags().String("max-memory", "", "所有工作协程缓冲的数据量上限，如 1GB (默认512MB)")
+	cmd.Flags().Bool("attributes", true, "复制对象的标签和ACL（两端都支持时）")
+	cmd.Flags().String("verify", migrateVerifyFull, "复制后的校验方式：full（比较对象列表、元数据、标签和ACL）、list（只比较对象列表）、none")
+	cmd.Flags().String("report", "", "不一致报告的文件路径 (默认为当前目录下的 migrate_report_<from>_<to>.json)")
+	cmd.Flags().Float64("max-requests-per-second", 0, "每秒最多发送的请求数，两端和所有工作协程共享 (0表示不限制)")
+	cmd.Flags().BoolP("verbose", "v", false, "详细输出，列出所有不一致的对象")
+	cmd.Flags().Bool("worker-progress", false, "在进度条下方显示每个正在复制的对象的进度和速度（仅终端，--verbose 时不显示）")
+	cmd.MarkFlagRequired("from")
+	cmd.MarkFlagRequired("to")
+	cmd.RegisterFlagCompletionFunc("from", completeClusters)
+	cmd.RegisterFlagCompletionFunc("to", completeClusters)
+
+	return cmd
+}
+
+func (a *App) runMigrateEndpoints(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	buckets, _ := cmd.Flags().GetStringSlice("bucket")
+	excluded, _ := cmd.Flags().GetStringSlice("exclude-bucket")
+	prefix, _ := cmd.Flags().GetString("prefix")
+	incremental, _ := cmd.Flags().GetBool("incremental")
+	workers, _ := cmd.Flags().GetInt("workers")
+	partsConcurrency, _ := cmd.Flags().GetInt("parts-concurrency")
+	maxMemory, _ := cmd.Flags().GetString("max-memory")
+	attributes, _ := cmd.Flags().GetBool("attributes")
+	verify, _ := cmd.Flags().GetString("verify")
+	reportFile, _ := cmd.Flags().GetString("report")
+	maxRequests, _ := cmd.Flags().GetFloat64("max-requests-per-second")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	workerProgress, _ := cmd.Flags().GetBool("worker-progress")
+
+	if strings.EqualFold(from, to) {
+		return withExitCode(ExitUsage, i18n.Errorf("--from 和 --to 不能是同一个端点: %s", from))
+	}
+	switch verify {
+	case migrateVerifyFull, migrateVerifyList, migrateVerifyNone:
+	default:
+		return withExitCode(ExitUsage, i18n.Errorf("--verify 参数无效: %s（可选值: full, list, none）", verify))
+	}
+	var memoryLimit int64
+	if maxMemory != "" {
+		size, err := progress.ParseSize(maxMemory)
+		if err != nil {
+			return withExitCode(ExitUsage, i18n.Errorf("--max-memory 参数无效: %w", err))
+		}
+		memoryLimit = size
+	}
+	if partsConcurrency < 0 {
+		return withExitCode(ExitUsage, i18n.Errorf("--parts-concurrency 不能为负数"))
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if reportFile == "" {
+		reportFile = fmt.Sprintf("migrate_report_%s_%s.json", from, to)
+	}
+
+	configManager := config.NewConfigManager(configFile)
+	if _, err := configManager.LoadConfig(); err != nil {
+		return configError(i18n.Errorf("配置加载失败: %w", err))
+	}
+	source, err := configManager.RemoteSettings(from)
+	if err != nil {
+		return configError(i18n.Errorf("配置验证失败: %w", err))
+	}
+	target, err := configManager.RemoteSettings(to)
+	if err != nil {
+		return configError(i18n.Errorf("配置验证失败: %w", err))
+	}
+	settings := configManager.ToBucketSettings()
+	settings.Incremental = incremental
+
+	// 两端共享同一个请求速率限制器
+	limiter := ratelimit.New(maxRequests)
+
+	names, err := migrateBucketNames(settings, source, limiter, buckets, excluded)
+	if err != nil {
+		return err
+	}
+
+	i18n.Printf("开始迁移: %s（%s） -> %s（%s），共 %d 个桶\n", from, source.Endpoint, to, target.Endpoint, len(names))
+
+	successCount, mismatchCount := 0, 0
+	var failures []error
+	report := migrateReportFile{Time: time.Now(), From: from, To: to, Prefix: prefix, Verify: verify}
+	total := newTotalProgress(len(names), workerProgress && !verbose)
+
+	for i, name := range names {
+		i18n.Printf("\n[%d/%d] 迁移桶: %s\n", i+1, len(names), name)
+		if total != nil {
+			total.StartBucket()
+		}
+
+		source.Name, target.Name = name, name
+		r := replicate.New(&replicate.Options{
+			Source:             replicate.Endpoint{Connection: connectionOptions(settings, source, limiter), Bucket: name},
+			Target:             replicate.Endpoint{Connection: connectionOptions(settings, target, limiter), Bucket: name},
+			Prefix:             config.NormalizePrefix(prefix),
+			Incremental:        incremental,
+			StateFile:          state.FileName("migrate", "", from+"_"+to+"_"+name, prefix),
+			StateBackend:       settings.StateBackend,
+			CheckpointFiles:    settings.CheckpointFiles,
+			CheckpointInterval: settings.CheckpointInterval,
+			PruneDeletedAfter:  settings.PruneDeletedAfter,
+			Workers:            workers,
+			PartsConcurrency:   partsConcurrency,
+			MaxMemory:          memoryLimit,
+			MaxAttempts:        settings.MaxAttempts,
+			RetryDelay:         settings.RetryDelay,
+			CopyAttributes:     attributes,
+			Parent:             total,
+			WorkerProgress:     workerProgress,
+			Verbose:            verbose,
+		})
+
+		err := r.Run()
+		stats := r.Stats()
+		bucket := migrateBucket{Bucket: name, Files: stats.Files, Bytes: stats.Bytes, AttributeErrors: r.AttributeErrors()}
+		if err == nil && verify != migrateVerifyNone {
+			if bucket.Verify, err = r.Verify(verify == migrateVerifyFull); err != nil {
+				err = i18n.Errorf("校验失败: %w", err)
+			}
+		}
+		if err != nil {
+			bucket.Error = err.Error()
+			report.Buckets = append(report.Buckets, bucket)
+			a.report.addBucket(name, stats, err)
+			i18n.Printf("桶 %s 迁移失败: %v\n", name, err)
+			failures = append(failures, bucketError(err, stats))
+			continue
+		}
+		report.Buckets = append(report.Buckets, bucket)
+
+		if bucket.AttributeErrors > 0 {
+			i18n.Printf("复制标签或ACL失败 %d 次，见校验结果\n", bucket.AttributeErrors)
+		}
+		if bucket.Verify != nil {
+			printMigrateResult(bucket.Verify, verbose)
+			if len(bucket.Verify.Mismatches) > 0 {
+				// 有不一致的对象时在命令报告中记为失败
+				mismatchCount += len(bucket.Verify.Mismatches)
+				err := i18n.Errorf("桶 %s 有 %d 个不一致的对象", name, len(bucket.Verify.Mismatches))
+				a.report.addBucket(name, stats, err)
+				failures = append(failures, objectsFailed(err))
+				continue
+			}
+		}
+
+		a.report.addBucket(name, stats, nil)
+		i18n.Printf("桶 %s 迁移完成!\n", name)
+		successCount++
+	}
+
+	if err := writeMigrateReport(reportFile, report); err != nil {
+		return err
+	}
+
+	i18n.Printf("\n迁移完成!\n")
+	i18n.Printf("成功: %d 个桶\n", successCount)
+	printTotalProgress(total)
+	i18n.Printf("迁移报告已写入: %s\n", reportFile)
+	if len(failures) > 0 {
+		i18n.Printf("不一致或失败: %d 个桶（%d 个不一致的对象）\n", len(failures), mismatchCount)
+		return bucketsError(i18n.Errorf("迁移发现问题"), successCount, failures)
+	}
+	return nil
+}
+
+// migrateBucketNames 返回要迁移的桶：指定了桶时使用指定的桶，否则列出源端点的所有桶，再去掉排除的桶
+func migrateBucketNames(settings *config.MultiBucketSettings, source config.BucketSettings, limiter *ratelimit.Limiter, buckets, excluded []string) ([]string, error) {
+	names := buckets
+	if len(names) == 0 {
+		store, err := storage.Open(connectionOptions(settings, source, limiter), "")
+		if err != nil {
+			return nil, i18n.Errorf("初始化源端客户端失败: %w", err)
+		}
+		if names, err = store.ListBuckets(); err != nil {
+			return nil, i18n.Errorf("列出源端点的桶失败: %w", err)
+		}
+	}
+
+	var result []string
+	for _, name := range names {
+		if !slices.Contains(excluded, name) && !slices.Contains(result, name) {
+			result = append(result, name)
+		}
+	}
+	if len(result) == 0 {
+		return nil, withExitCode(ExitUsage, i18n.Errorf("没有要迁移的桶"))
+	}
+	return result, nil
+}
+
+// printMigrateResult 打印一个桶的校验总结和不一致的对象，非详细模式下只列出前几个
+func printMigrateResult(result *replicate.VerifyResult, verbose bool) {
+	i18n.Printf("校验 %d 个对象，%d 个不一致\n", result.Checked, len(result.Mismatches))
+
+	for i, mismatch := range result.Mismatches {
+		if !verbose && i == maxListedMismatches {
+			i18n.Printf("  ……还有 %d 个，使用 --verbose 或查看报告文件\n", len(result.Mismatches)-i)
+			break
+		}
+		line := fmt.Sprintf("  [%s] %s", migrateProblemLabel(mismatch.Problem), mismatch.Key)
+		if mismatch.Detail != "" {
+			line += fmt.Sprintf(" (%s)", mismatch.Detail)
+		}
+		fmt.Println(line)
+	}
+}
+
+// migrateProblemLabel 返回迁移校验问题类型在当前语言下的名称
+func migrateProblemLabel(problem string) string {
+	switch problem {
+	case replicate.ProblemMissing:
+		return i18n.T("缺失")
+	case replicate.ProblemExtra:
+		return i18n.T("多余")
+	case replicate.ProblemSize:
+		return i18n.T("大小不一致")
+	case replicate.ProblemETag:
+		return i18n.T("内容不一致")
+	case replicate.ProblemMetadata:
+		return i18n.T("元数据不一致")
+	case replicate.ProblemTags:
+		return i18n.T("标签不一致")
+	case replicate.ProblemACL:
+		return i18n.T("ACL不一致")
+	default:
+		return i18n.T("读取失败")
+	}
+}
+
+// writeMigrateReport 将迁移和校验结果写入JSON报告文件
+func writeMigrateReport(path string, report migrateReportFile) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return i18n.Errorf("生成迁移报告失败: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return i18n.Errorf("写入迁移报告失败: %w", err)
+	}
+	return nil
+}
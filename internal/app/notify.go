@@ -0,0 +1,85 @@
+package app
+
+import (
+	"time"
+
+	"objectsync/internal/config"
+	"objectsync/internal/history"
+	"objectsync/internal/notify"
+	"objectsync/internal/progress"
+)
+
+// runResults 收集一次运行中各桶的结果，运行结束后写入历史记录并发送通知
+type runResults struct {
+	summary notify.Summary
+	records []history.Record
+	start   time.Time
+}
+
+// newRunResults 开始收集一次运行的结果
+func newRunResults(command string) *runResults {
+	return &runResults{summary: notify.Summary{Command: command}, start: time.Now()}
+}
+
+// add 记录单个桶的结果
+func (r *runResults) add(bucket string, stats progress.Stats, err error) {
+	result := notify.BucketResult{Bucket: bucket, Files: stats.Files, Bytes: stats.Bytes, Failed: stats.Failed}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	r.summary.Buckets = append(r.summary.Buckets, result)
+
+	ended := time.Now()
+	record := history.Record{
+		Command: r.summary.Command,
+		Bucket:  bucket,
+		Started: ended.Add(-stats.Duration),
+		Ended:   ended,
+		Files:   stats.Files,
+		Bytes:   stats.Bytes,
+		Result:  history.ResultSuccess,
+		Error:   result.Error,
+	}
+	if err != nil {
+		record.Result = history.ResultFailed
+	}
+	r.records = append(r.records, record)
+}
+
+// finishRun 运行结束后写入历史记录并发送通知，两者都带上运行标签
+func finishRun(settings *config.MultiBucketSettings, results *runResults) {
+	results.summary.Labels = settings.Labels
+	for i := range results.records {
+		results.records[i].Labels = settings.Labels
+	}
+
+	if settings.HistoryFile != "" {
+		if err := history.Append(settings.HistoryFile, results.records...); err != nil {
+			notifyLog.Warnf("写入运行历史失败: %v", err)
+		}
+	}
+	sendNotifications(settings.Notifications, results)
+}
+
+// sendNotifications 将运行总结发送到配置的所有聊天渠道，发送失败只输出警告，不影响运行结果
+func sendNotifications(notifications []config.NotificationConfig, results *runResults) {
+	if len(notifications) == 0 {
+		return
+	}
+	summary := results.summary
+	summary.Duration = time.Since(results.start)
+
+	for _, n := range notifications {
+		err := notify.Send(notify.Options{
+			Type:       n.Type,
+			WebhookURL: n.WebhookURL,
+			Secret:     n.Secret,
+			BotToken:   n.BotToken,
+			ChatID:     n.ChatID,
+			On:         n.On,
+		}, summary)
+		if err != nil {
+			notifyLog.Warnf("发送 %s 通知失败: %v", n.Type, err)
+		}
+	}
+}
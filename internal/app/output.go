@@ -0,0 +1,234 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"objectsync/internal/i18n"
+	"objectsync/internal/progress"
+	"objectsync/internal/remote"
+
+	"github.com/spf13/cobra"
+)
+
+// 输出格式
+const (
+	outputText = "text" // 面向人的文本输出（默认）
+	outputJSON = "json" // 结束后在标准输出打印结构化结果，过程输出转到标准错误
+)
+
+// commandReport 命令的执行结果，--output json 时输出
+type commandReport struct {
+	Command  string          `json:"command"`
+	Success  bool            `json:"success"`
+	Error    string          `json:"error,omitempty"`
+	Duration float64         `json:"duration_seconds"`
+	Buckets  []bucketReport  `json:"buckets,omitempty"`
+	States   []stateReport   `json:"states,omitempty"`
+	Objects  []remote.Entry  `json:"objects,omitempty"`
+	Usage    []remote.Usage  `json:"usage,omitempty"`
+	Verify   []verifyReport  `json:"verify,omitempty"`
+	Pending  []pendingReport `json:"pending,omitempty"`
+
+	startTime time.Time
+}
+
+// bucketReport 单个桶的传输结果
+type bucketReport struct {
+	Bucket   string  `json:"bucket"`
+	Success  bool    `json:"success"`
+	Error    string  `json:"error,omitempty"`
+	Files    int64   `json:"files"`
+	Bytes    int64   `json:"bytes"`
+	Duration float64 `json:"duration_seconds"`
+}
+
+// stateReport 状态文件的统计信息
+type stateReport struct {
+	Bucket     string     `json:"bucket,omitempty"`
+	StateFile  string     `json:"state_file"`
+	Exists     bool       `json:"exists"`
+	LastBackup *time.Time `json:"last_backup,omitempty"`
+	Files      int        `json:"files"`
+	Bytes      int64      `json:"bytes"`
+	Deleted    int        `json:"deleted"` // 已从桶中删除的对象的删除记录数
+}
+
+// addBucket 记录单个桶的结果，未启用JSON输出时不做任何事
+func (r *commandReport) addBucket(name string, stats progress.Stats, err error) {
+	if r == nil {
+		return
+	}
+	bucket := bucketReport{
+		Bucket:   name,
+		Success:  err == nil,
+		Files:    stats.Files,
+		Bytes:    stats.Bytes,
+		Duration: stats.Duration.Seconds(),
+	}
+	if err != nil {
+		bucket.Error = err.Error()
+	}
+	r.Buckets = append(r.Buckets, bucket)
+}
+
+// addState 记录状态文件的统计，summary为nil表示状态文件不存在
+func (r *commandReport) addState(bucket, stateFile string, summary *stateSummary) {
+	if r == nil {
+		return
+	}
+	report := stateReport{Bucket: bucket, StateFile: stateFile}
+	if summary != nil {
+		report.Exists = true
+		report.LastBackup = &summary.lastRun
+		report.Files = summary.files
+		report.Bytes = summary.bytes
+		report.Deleted = summary.deleted
+	}
+	r.States = append(r.States, report)
+}
+
+// addObject 记录 ls 列出的对象或目录
+func (r *commandReport) addObject(entry remote.Entry) {
+	if r == nil {
+		return
+	}
+	r.Objects = append(r.Objects, entry)
+}
+
+// addUsage 记录 du 汇总的目录统计
+func (r *commandReport) addUsage(usage remote.Usage) {
+	if r == nil {
+		return
+	}
+	r.Usage = append(r.Usage, usage)
+}
+
+// addVerify 记录 verify 单个桶的校验结果
+func (r *commandReport) addVerify(report verifyReport) {
+	if r == nil {
+		return
+	}
+	r.Verify = append(r.Verify, report)
+}
+
+// addPending 记录 status --remote 单个桶待传输的数据
+func (r *commandReport) addPending(report pendingReport) {
+	if r == nil {
+		return
+	}
+	r.Pending = append(r.Pending, report)
+}
+
+// summary 返回一行执行总结
+func (r *commandReport) summary() string {
+	if len(r.Pending) > 0 {
+		var pending pendingReport
+		for _, bucket := range r.Pending {
+			pending.DownloadFiles += bucket.DownloadFiles
+			pending.DownloadBytes += bucket.DownloadBytes
+			pending.UploadFiles += bucket.UploadFiles
+			pending.UploadBytes += bucket.UploadBytes
+		}
+		return i18n.Sprintf("%s: %d 个桶，待下载 %d 个对象（%s），待上传 %d 个文件（%s）",
+			r.Command, len(r.Pending), pending.DownloadFiles, progress.FormatSize(pending.DownloadBytes),
+			pending.UploadFiles, progress.FormatSize(pending.UploadBytes))
+	}
+	if len(r.Verify) > 0 {
+		var checked, mismatches, failed int
+		for _, bucket := range r.Verify {
+			checked += bucket.Checked
+			mismatches += len(bucket.Mismatches)
+			if bucket.Error != "" {
+				failed++
+			}
+		}
+		return i18n.Sprintf("%s: 校验 %d 个桶 %d 个对象，%d 个不一致，%d 个桶失败",
+			r.Command, len(r.Verify), checked, mismatches, failed)
+	}
+	if len(r.Buckets) == 0 {
+		if r.Error != "" {
+			return i18n.Sprintf("%s: 失败: %s", r.Command, r.Error)
+		}
+		return i18n.Sprintf("%s: 完成", r.Command)
+	}
+
+	var succeeded, failed int
+	var stats progress.Stats
+	for _, bucket := range r.Buckets {
+		if bucket.Success {
+			succeeded++
+		} else {
+			failed++
+		}
+		stats.Files += bucket.Files
+		stats.Bytes += bucket.Bytes
+	}
+	return i18n.Sprintf("%s: 成功 %d 个桶，失败 %d 个桶，传输 %d 个文件（%s），用时 %s",
+		r.Command, succeeded, failed, stats.Files, progress.FormatSize(stats.Bytes),
+		time.Duration(r.Duration*float64(time.Second)).Round(time.Second))
+}
+
+// withReport 包装命令的执行函数：--output json 时把过程输出转到标准错误，结束后在标准输出打印JSON结果；
+// --quiet 时丢弃过程输出，只打印失败桶的错误和一行总结。失败时仍返回原错误以保留退出码
+func (a *App) withReport(run func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("output")
+		quiet, _ := cmd.Flags().GetBool("quiet")
+		switch format {
+		case outputText:
+			if !quiet {
+				return run(cmd, args)
+			}
+		case outputJSON:
+		default:
+			return i18n.Errorf("不支持的输出格式: %s（可选 %s、%s）", format, outputText, outputJSON)
+		}
+
+		stdout := os.Stdout
+		if quiet {
+			devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+			if err != nil {
+				return err
+			}
+			defer devNull.Close()
+			os.Stdout = devNull
+			// 出错时不打印用法说明，避免淹没定时任务的邮件
+			cmd.SilenceUsage = true
+		} else {
+			os.Stdout = os.Stderr
+		}
+		a.report = &commandReport{Command: cmd.CommandPath(), startTime: time.Now()}
+
+		err := run(cmd, args)
+
+		os.Stdout = stdout
+		report := a.report
+		a.report = nil
+
+		report.Success = err == nil
+		if err != nil {
+			report.Error = err.Error()
+		}
+		report.Duration = time.Since(report.startTime).Seconds()
+
+		if format == outputText {
+			for _, bucket := range report.Buckets {
+				if !bucket.Success {
+					fmt.Fprint(os.Stderr, i18n.Sprintf("桶 %s 失败: %s\n", bucket.Bucket, bucket.Error))
+				}
+			}
+			fmt.Fprintln(stdout, report.summary())
+			return err
+		}
+
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if encodeErr := encoder.Encode(report); encodeErr != nil {
+			return i18n.Errorf("输出JSON结果失败: %w", encodeErr)
+		}
+		return err
+	}
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"objectsync/internal/backup"
+	"objectsync/internal/config"
+	"objectsync/internal/i18n"
+	"objectsync/internal/progress"
+	"objectsync/internal/ratelimit"
+	"objectsync/internal/replicate"
+	"objectsync/internal/upload"
+
+	"github.com/spf13/cobra"
+)
+
+// pendingReport 单个桶下次运行需要传输的数据
+type pendingReport struct {
+	Bucket         string `json:"bucket"`
+	Direction      string `json:"direction"`
+	Error          string `json:"error,omitempty"`
+	DownloadFiles  int64  `json:"download_files"`
+	DownloadBytes  int64  `json:"download_bytes"`
+	UploadFiles    int64  `json:"upload_files"`
+	UploadBytes    int64  `json:"upload_bytes"`
+	ReplicateFiles int64  `json:"replicate_files,omitempty"`
+	ReplicateBytes int64  `json:"replicate_bytes,omitempty"`
+}
+
+// runStatusPending 连接对象存储，按每个桶的方向统计下次运行需要下载和上传的文件
+func (a *App) runStatusPending(cmd *cobra.Command) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	cluster, _ := cmd.Flags().GetString("cluster")
+	buckets, _ := cmd.Flags().GetStringSlice("bucket")
+	excluded, _ := cmd.Flags().GetStringSlice("exclude-bucket")
+	maxRequests, _ := cmd.Flags().GetFloat64("max-requests-per-second")
+
+	configManager := config.NewConfigManager(configFile)
+	if _, err := configManager.LoadConfig(); err != nil {
+		return configError(i18n.Errorf("配置加载失败: %w", err))
+	}
+	if err := configManager.ValidateConfig(); err != nil {
+		return configError(i18n.Errorf("配置验证失败: %w", err))
+	}
+
+	settings := configManager.ToBucketSettings()
+	if cluster != "" {
+		if err := settings.FilterCluster(cluster); err != nil {
+			return withExitCode(ExitUsage, err)
+		}
+	}
+	if err := settings.FilterBuckets(buckets); err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	if err := settings.ExcludeBuckets(excluded); err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	limiter := ratelimit.New(maxRequests)
+	i18n.Printf("检查待同步的变化（共 %d 个桶）\n", len(settings.Buckets))
+	i18n.Printf("连接信息: %s\n", bucketEndpoints(settings))
+
+	successCount := 0
+	var failures []error
+	for _, bucketSettings := range settings.Buckets {
+		report, err := bucketPending(settings, bucketSettings, limiter)
+		a.report.addPending(report)
+		if err != nil {
+			i18n.Printf("\n桶 %s: 检查失败: %v\n", bucketSettings.Name, err)
+			failures = append(failures, err)
+			continue
+		}
+		successCount++
+
+		i18n.Printf("\n桶 %s（%s）:\n", bucketSettings.Name, directionLabel(bucketSettings.Direction))
+		if bucketSettings.Direction == config.DirectionReplicate {
+			i18n.Printf("  待复制: %d 个对象（%s）\n", report.ReplicateFiles, progress.FormatSize(report.ReplicateBytes))
+			continue
+		}
+		if bucketSettings.Direction != config.DirectionUpload {
+			i18n.Printf("  待下载: %d 个对象（%s）\n", report.DownloadFiles, progress.FormatSize(report.DownloadBytes))
+		}
+		if bucketSettings.Direction != config.DirectionBackup {
+			i18n.Printf("  待上传: %d 个文件（%s）\n", report.UploadFiles, progress.FormatSize(report.UploadBytes))
+		}
+	}
+
+	if len(failures) > 0 {
+		return bucketsError(i18n.Errorf("部分桶检查失败"), successCount, failures)
+	}
+	return nil
+}
+
+// bucketPending 统计单个桶按配置的方向下次运行需要传输的文件
+func bucketPending(settings *config.MultiBucketSettings, bucketSettings config.BucketSettings, limiter *ratelimit.Limiter) (pendingReport, error) {
+	report := pendingReport{Bucket: bucketSettings.Name, Direction: bucketSettings.Direction}
+	direction := bucketSettings.Direction
+
+	if direction == config.DirectionBackup || direction == config.DirectionSync {
+		files, bytes, err := backup.New(bucketBackupOptions(settings, bucketSettings, limiter)).Pending()
+		if err != nil {
+			report.Error = err.Error()
+			return report, err
+		}
+		report.DownloadFiles, report.DownloadBytes = files, bytes
+	}
+
+	if direction == config.DirectionReplicate {
+		files, bytes, err := replicate.New(bucketReplicateOptions(settings, bucketSettings, limiter)).Pending()
+		if err != nil {
+			report.Error = err.Error()
+			return report, err
+		}
+		report.ReplicateFiles, report.ReplicateBytes = files, bytes
+	}
+
+	if direction == config.DirectionUpload || direction == config.DirectionSync {
+		if missing := missingSourceDir(bucketSettings); missing != "" {
+			err := i18n.Errorf("本地目录不存在: %s", missing)
+			report.Error = err.Error()
+			return report, err
+		}
+		files, bytes, err := upload.New(bucketUploadOptions(settings, bucketSettings, limiter)).Pending()
+		if err != nil {
+			report.Error = err.Error()
+			return report, err
+		}
+		report.UploadFiles, report.UploadBytes = files, bytes
+	}
+
+	return report, nil
+}
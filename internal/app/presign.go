@@ -0,0 +1,61 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"objectsync/internal/i18n"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *App) newPresignCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "presign <s3://桶名/对象键>",
+		Short: "生成临时访问URL",
+		Long:  "为对象生成预签名URL，持有URL的人无需密钥即可在有效期内下载（GET）或上传（PUT）该对象，有效期最长7天",
+		Args:  cobra.ExactArgs(1),
+		RunE:  a.runPresign,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completeRemotePath(cmd, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	// 添加命令行参数
+	addConnectionFlags(cmd)
+	cmd.Flags().Duration("expires", time.Hour, "URL有效期 (如 30m、24h，最长168h)")
+	cmd.Flags().String("method", "GET", "请求方法: GET 下载, PUT 上传")
+	cmd.RegisterFlagCompletionFunc("method", cobra.FixedCompletions([]string{"GET", "PUT"}, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+func (a *App) runPresign(cmd *cobra.Command, args []string) error {
+	expires, _ := cmd.Flags().GetDuration("expires")
+	method, _ := cmd.Flags().GetString("method")
+
+	bucket, key, err := parseRemotePath(args[0])
+	if err != nil {
+		return err
+	}
+	if key == "" {
+		return i18n.Errorf("请指定对象键: %s", args[0])
+	}
+
+	client, err := remoteClient(cmd, bucket)
+	if err != nil {
+		return err
+	}
+
+	url, err := client.Presign(method, bucket, key, expires)
+	if err != nil {
+		return i18n.Errorf("生成预签名URL失败: %w", err)
+	}
+
+	// 只输出URL，便于在脚本中使用
+	fmt.Println(url)
+	return nil
+}
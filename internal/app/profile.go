@@ -0,0 +1,96 @@
+package app
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"runtime"
+	runtimepprof "runtime/pprof"
+
+	"objectsync/internal/i18n"
+
+	"github.com/spf13/cobra"
+)
+
+// addProfilingFlags 添加性能分析参数，用于在现场诊断大桶的性能问题，不需要专门构建
+func addProfilingFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("pprof", "", "在指定地址提供pprof分析接口，如 localhost:6060（适合 daemon 等长时间运行的命令）")
+	cmd.PersistentFlags().String("cpu-profile", "", "将运行期间的CPU分析写入文件，用 go tool pprof 查看")
+	cmd.PersistentFlags().String("mem-profile", "", "命令结束时将堆内存分析写入文件")
+}
+
+// startProfiling 按参数启动性能分析，返回命令结束时调用的函数，写出分析文件并关闭接口；
+// 启动失败时已经启动的部分会被停止
+func startProfiling(cmd *cobra.Command) (func(), error) {
+	address, _ := cmd.Flags().GetString("pprof")
+	cpuFile, _ := cmd.Flags().GetString("cpu-profile")
+	memFile, _ := cmd.Flags().GetString("mem-profile")
+
+	var stops []func()
+	stop := func() {
+		for i := len(stops) - 1; i >= 0; i-- {
+			stops[i]()
+		}
+	}
+
+	if address != "" {
+		listener, err := net.Listen("tcp", address)
+		if err != nil {
+			return nil, i18n.Errorf("启动pprof接口失败: %w", err)
+		}
+		// 不使用 http.DefaultServeMux，避免暴露其他包注册的处理器
+		mux := http.NewServeMux()
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		server := &http.Server{Handler: mux}
+		go server.Serve(listener)
+		fmt.Fprintln(os.Stderr, i18n.Sprintf("pprof接口: http://%s/debug/pprof/", listener.Addr()))
+		stops = append(stops, func() { server.Close() })
+	}
+
+	if cpuFile != "" {
+		file, err := os.Create(cpuFile)
+		if err != nil {
+			stop()
+			return nil, i18n.Errorf("创建CPU分析文件失败: %w", err)
+		}
+		if err := runtimepprof.StartCPUProfile(file); err != nil {
+			file.Close()
+			stop()
+			return nil, i18n.Errorf("启动CPU分析失败: %w", err)
+		}
+		stops = append(stops, func() {
+			runtimepprof.StopCPUProfile()
+			file.Close()
+			fmt.Fprintln(os.Stderr, i18n.Sprintf("CPU分析已写入: %s", cpuFile))
+		})
+	}
+
+	if memFile != "" {
+		stops = append(stops, func() {
+			if err := writeHeapProfile(memFile); err != nil {
+				fmt.Fprintln(os.Stderr, i18n.Sprintf("写入内存分析失败: %v", err))
+				return
+			}
+			fmt.Fprintln(os.Stderr, i18n.Sprintf("内存分析已写入: %s", memFile))
+		})
+	}
+
+	return stop, nil
+}
+
+// writeHeapProfile 先执行一次GC，使分析反映仍在使用的内存
+func writeHeapProfile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	runtime.GC()
+	return runtimepprof.WriteHeapProfile(file)
+}
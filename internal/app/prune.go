@@ -0,0 +1,169 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"objectsync/internal/backup"
+	"objectsync/internal/config"
+	"objectsync/internal/i18n"
+	"objectsync/internal/progress"
+
+	"github.com/spf13/cobra"
+)
+
+// pruneReport 单个桶的清理结果
+type pruneReport struct {
+	Bucket string `json:"bucket"`
+	Error  string `json:"error,omitempty"`
+	backup.PruneResult
+}
+
+// pruneReportFile --report 写入的清理报告
+type pruneReportFile struct {
+	Time    time.Time     `json:"time"`
+	Cutoff  time.Time     `json:"cutoff"`
+	DryRun  bool          `json:"dry_run"`
+	Buckets []pruneReport `json:"buckets"`
+}
+
+func (a *App) newPruneCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "清理已从桶中删除的对象的本地备份",
+		Long:  "备份时桶中已删除的对象的本地文件仍然保留，状态中记录删除的时间。prune 删除超过 --older-than 的这些本地文件，并清除对应的删除记录；仍在桶中的对象不会被删除。删除记录超过 prune_deleted_after 后会被清理，--older-than 需要比它短",
+		Args:  cobra.NoArgs,
+		RunE:  a.runPrune,
+	}
+
+	cmd.Flags().StringP("config", "c", "", configFlagUsage)
+	cmd.Flags().String("cluster", "", "只处理指定集群（clusters 或 remotes 中的名称）下的桶")
+	cmd.Flags().String("older-than", "", "对象从桶中删除超过该时间的本地文件，如 30d、720h 或日期 2006-01-02")
+	cmd.Flags().Bool("dry-run", false, "只列出将要删除的文件，不实际删除")
+	cmd.Flags().String("report", "", "将删除的文件写入JSON报告文件")
+	cmd.Flags().BoolP("verbose", "v", false, "详细输出，列出所有删除的文件")
+	cmd.MarkFlagRequired("older-than")
+	addBucketFlags(cmd)
+
+	return cmd
+}
+
+func (a *App) runPrune(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	cluster, _ := cmd.Flags().GetString("cluster")
+	buckets, _ := cmd.Flags().GetStringSlice("bucket")
+	excluded, _ := cmd.Flags().GetStringSlice("exclude-bucket")
+	olderThan, _ := cmd.Flags().GetString("older-than")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	reportFile, _ := cmd.Flags().GetString("report")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	now := time.Now()
+	cutoff, err := parseSince(olderThan, now)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	configManager := config.NewConfigManager(configFile)
+	if _, err := configManager.LoadConfig(); err != nil {
+		return configError(i18n.Errorf("配置加载失败: %w", err))
+	}
+	if err := configManager.ValidateConfig(); err != nil {
+		return configError(i18n.Errorf("配置验证失败: %w", err))
+	}
+
+	settings := configManager.ToBucketSettings()
+	if cluster != "" {
+		if err := settings.FilterCluster(cluster); err != nil {
+			return withExitCode(ExitUsage, err)
+		}
+	}
+	if err := settings.FilterBuckets(buckets); err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	if err := settings.ExcludeBuckets(excluded); err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	// 复制和上传的桶没有下载到本地的副本
+	if err := settings.ExcludeDirection(config.DirectionReplicate, config.DirectionUpload); err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	if dryRun {
+		i18n.Printf("预演模式: 只列出在 %s 之前从桶中删除的对象的本地文件\n", cutoff.Format("2006-01-02 15:04:05"))
+	}
+
+	successCount := 0
+	var failures []error
+	var totalFiles int
+	var totalBytes int64
+	report := pruneReportFile{Time: now, Cutoff: cutoff, DryRun: dryRun}
+
+	for _, bucketSettings := range settings.Buckets {
+		i18n.Printf("\n桶 %s -> %s\n", bucketSettings.Name, bucketSettings.OutputDir)
+
+		result, err := backup.New(bucketBackupOptions(settings, bucketSettings, nil)).Prune(cutoff, dryRun)
+		bucket := pruneReport{Bucket: bucketSettings.Name}
+		if result != nil {
+			bucket.PruneResult = *result
+			printPruneResult(result, dryRun, verbose)
+			totalFiles += len(result.Files)
+			totalBytes += result.Bytes
+		}
+		if err != nil {
+			bucket.Error = err.Error()
+			i18n.Printf("桶 %s 清理失败: %v\n", bucketSettings.Name, err)
+			failures = append(failures, err)
+		} else {
+			successCount++
+		}
+		report.Buckets = append(report.Buckets, bucket)
+	}
+
+	if reportFile != "" {
+		if err := writePruneReport(reportFile, report); err != nil {
+			return err
+		}
+		i18n.Printf("\n清理报告已写入: %s\n", reportFile)
+	}
+
+	if dryRun {
+		i18n.Printf("\n预演模式: 共 %d 个文件（%s）将被删除\n", totalFiles, progress.FormatSize(totalBytes))
+	} else {
+		i18n.Printf("\n已删除 %d 个文件（%s）\n", totalFiles, progress.FormatSize(totalBytes))
+	}
+	if len(failures) > 0 {
+		return bucketsError(i18n.Errorf("部分桶清理失败"), successCount, failures)
+	}
+	return nil
+}
+
+// printPruneResult 打印一个桶的清理结果，非详细模式下只列出前几个文件
+func printPruneResult(result *backup.PruneResult, dryRun, verbose bool) {
+	for i, file := range result.Files {
+		if !verbose && i == maxListedMismatches {
+			i18n.Printf("  ……还有 %d 个，使用 --verbose 或 --report 查看全部\n", len(result.Files)-i)
+			break
+		}
+		fmt.Printf("  %s  %9s  %s\n", file.DeletedAt.Local().Format("2006-01-02 15:04:05"), progress.FormatSize(file.Size), file.Path)
+	}
+	if dryRun {
+		i18n.Printf("将删除 %d 个文件（%s）\n", len(result.Files), progress.FormatSize(result.Bytes))
+		return
+	}
+	i18n.Printf("删除 %d 个文件（%s），清除 %d 条删除记录\n", len(result.Files), progress.FormatSize(result.Bytes), result.Records)
+}
+
+// writePruneReport 将清理结果写入JSON报告文件
+func writePruneReport(path string, report pruneReportFile) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return i18n.Errorf("生成清理报告失败: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return i18n.Errorf("写入清理报告失败: %w", err)
+	}
+	return nil
+}
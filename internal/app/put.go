@@ -0,0 +1,90 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+
+	"objectsync/internal/i18n"
+	"objectsync/internal/upload"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *App) newPutCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "put <本地文件|-> <s3://桶名/对象键>",
+		Short: "上传单个文件",
+		Long:  "将单个本地文件或标准输入（使用\"-\"）直接上传到指定的对象键，无需准备目录。对象键以/结尾时自动追加本地文件名",
+		Args:  cobra.ExactArgs(2),
+		RunE:  a.runPut,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			// 第一个参数是本地文件，使用shell默认的文件补全
+			if len(args) == 0 {
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+			if len(args) == 1 {
+				return completeRemotePath(cmd, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	// 添加命令行参数
+	addConnectionFlags(cmd)
+	cmd.Flags().BoolP("verbose", "v", false, "详细输出")
+
+	return cmd
+}
+
+func (a *App) runPut(cmd *cobra.Command, args []string) error {
+	// 获取命令行参数
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	source := args[0]
+	bucket, key, err := parseRemotePath(args[1])
+	if err != nil {
+		return err
+	}
+
+	// 对象键为空或以/结尾时使用本地文件名
+	if key == "" || strings.HasSuffix(key, "/") {
+		if source == upload.StdinPath {
+			return i18n.Errorf("从标准输入上传时必须指定完整的对象键")
+		}
+		key += filepath.Base(source)
+	}
+
+	settings, conn, err := bucketConnection(cmd, bucket)
+	if err != nil {
+		return err
+	}
+
+	options := &upload.Options{
+		Endpoint:      conn.Endpoint,
+		AccessKey:     conn.AccessKey,
+		SecretKey:     conn.SecretKey,
+		Profile:       conn.Profile,
+		Region:        conn.Region,
+		VirtualHosted: !conn.PathStyle,
+		Transport:     conn.Transport,
+		TLS:           tlsOptions(conn.TLS),
+		Proxy:         proxyOptions(conn.Proxy),
+		Timeouts:      timeoutOptions(conn.Timeouts),
+		Bucket:        bucket,
+		MaxAttempts:   settings.MaxAttempts,
+		RetryDelay:    settings.RetryDelay,
+		Verbose:       verbose,
+	}
+
+	return upload.New(options).Put(source, key)
+}
+
+// parseRemotePath 解析远程路径，支持 s3://桶名/对象键 和 桶名/对象键 两种格式
+func parseRemotePath(path string) (string, string, error) {
+	trimmed := strings.TrimPrefix(path, "s3://")
+	bucket, key, _ := strings.Cut(trimmed, "/")
+	if bucket == "" {
+		return "", "", i18n.Errorf("无效的远程路径: %s（格式: s3://桶名/对象键）", path)
+	}
+	return bucket, key, nil
+}
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"objectsync/internal/config"
+	"objectsync/internal/i18n"
+	"objectsync/internal/remote"
+	"objectsync/internal/s3client"
+	"objectsync/internal/storage"
+
+	"github.com/spf13/cobra"
+)
+
+// addConnectionFlags 添加直接操作远程对象的命令共用的配置文件和连接参数
+func addConnectionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("config", "c", "", configFlagUsage)
+	cmd.Flags().StringP("endpoint", "e", "", "Ceph对象存储端点URL (覆盖配置文件)")
+	cmd.Flags().StringP("access-key", "a", "", "访问密钥 (覆盖配置文件)")
+	cmd.Flags().StringP("secret-key", "s", "", "秘密密钥 (覆盖配置文件)")
+	cmd.Flags().String("region", "", "请求签名使用的区域 (覆盖配置文件)")
+}
+
+// bucketConnection 加载配置并返回访问指定桶使用的连接：桶在配置中引用了remote时使用该连接，
+// 否则使用ceph配置，再用命令行参数覆盖。只需要连接配置，不要求配置桶列表
+func bucketConnection(cmd *cobra.Command, bucket string) (*config.MultiBucketSettings, config.BucketSettings, error) {
+	configFile, _ := cmd.Flags().GetString("config")
+	endpoint, _ := cmd.Flags().GetString("endpoint")
+	accessKey, _ := cmd.Flags().GetString("access-key")
+	secretKey, _ := cmd.Flags().GetString("secret-key")
+	region, _ := cmd.Flags().GetString("region")
+
+	// 创建配置管理器并加载配置文件
+	configManager := config.NewConfigManager(configFile)
+	if _, err := configManager.LoadConfig(); err != nil {
+		return nil, config.BucketSettings{}, configError(i18n.Errorf("配置加载失败: %w", err))
+	}
+
+	settings := configManager.ToBucketSettings()
+	conn := config.BucketSettings{
+		Endpoint:  settings.Endpoint,
+		AccessKey: settings.AccessKey,
+		SecretKey: settings.SecretKey,
+		Profile:   settings.Profile,
+		Region:    settings.Region,
+		PathStyle: settings.PathStyle,
+		Transport: settings.Transport,
+		TLS:       settings.TLS,
+		Proxy:     settings.Proxy,
+		Timeouts:  settings.Timeouts,
+	}
+	for _, bucketSettings := range settings.Buckets {
+		if bucketSettings.Name == bucket && bucketSettings.Remote != "" {
+			conn = bucketSettings
+		}
+	}
+
+	var err error
+	if conn.Remote != "" {
+		err = configManager.ValidateRemote(conn.Remote)
+	} else {
+		err = configManager.ValidateConnection()
+	}
+	if err != nil {
+		return nil, config.BucketSettings{}, configError(i18n.Errorf("配置验证失败: %w", err))
+	}
+
+	// 用命令行参数覆盖连接配置
+	if endpoint != "" {
+		conn.Endpoint = endpoint
+	}
+	if accessKey != "" {
+		conn.AccessKey = accessKey
+		conn.Profile = "" // 命令行指定密钥时不再使用共享凭证文件
+	}
+	if secretKey != "" {
+		conn.SecretKey = secretKey
+	}
+	if region != "" {
+		conn.Region = region
+	}
+	conn.Name = bucket
+
+	return settings, conn, nil
+}
+
+// remoteClient 创建访问指定桶的远程操作客户端
+func remoteClient(cmd *cobra.Command, bucket string) (*remote.Client, error) {
+	settings, conn, err := bucketConnection(cmd, bucket)
+	if err != nil {
+		return nil, err
+	}
+	return newRemoteClient(settings, conn)
+}
+
+// newRemoteClient 使用桶的连接配置创建远程操作客户端
+func newRemoteClient(settings *config.MultiBucketSettings, conn config.BucketSettings) (*remote.Client, error) {
+	if _, ok := storage.LocalPath(conn.Endpoint); ok {
+		return nil, i18n.Errorf("桶 %s 的端点是本地目录（%s），请直接使用文件管理命令", conn.Name, conn.Endpoint)
+	}
+	if _, ok := storage.WebDAVURL(conn.Endpoint); ok {
+		return nil, i18n.Errorf("桶 %s 的端点是WebDAV服务（%s），请使用WebDAV客户端管理文件", conn.Name, conn.Endpoint)
+	}
+	client, err := remote.New(s3client.Options{
+		Endpoint:      conn.Endpoint,
+		AccessKey:     conn.AccessKey,
+		SecretKey:     conn.SecretKey,
+		Profile:       conn.Profile,
+		Region:        conn.Region,
+		VirtualHosted: !conn.PathStyle,
+		MaxAttempts:   settings.MaxAttempts,
+		RetryDelay:    settings.RetryDelay,
+		TLS:           tlsOptions(conn.TLS),
+		Proxy:         proxyOptions(conn.Proxy),
+		Timeouts:      timeoutOptions(conn.Timeouts),
+	})
+	if err != nil {
+		return nil, i18n.Errorf("初始化S3客户端失败: %w", err)
+	}
+	return client, nil
+}
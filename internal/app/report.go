@@ -0,0 +1,276 @@
+package app
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"objectsync/internal/config"
+	"objectsync/internal/history"
+	"objectsync/internal/i18n"
+	"objectsync/internal/progress"
+	"objectsync/internal/statestore"
+
+	"github.com/spf13/cobra"
+)
+
+// maxReportFailures 报告中最多列出的失败种类
+const maxReportFailures = 10
+
+// reportBucket 报告中单个桶的一行
+type reportBucket struct {
+	history.BucketSummary
+	StateFiles int   // 状态文件中记录的文件数，即当前备份或上传的总量
+	StateBytes int64 // 状态文件中记录的数据量
+}
+
+// reportData 渲染报告使用的数据
+type reportData struct {
+	Generated time.Time
+	Since     time.Time
+	Runs      int
+	Failed    int
+	Buckets   []reportBucket
+	Failures  []history.Failure
+}
+
+func (a *App) newReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "生成运行报告",
+		Long:  "根据运行历史和状态文件生成HTML或CSV报告：每个桶的成功率、传输的数据量、当前总量和最常见的失败，便于定期检查备份情况",
+		Args:  cobra.NoArgs,
+		RunE:  a.runReport,
+	}
+
+	cmd.Flags().StringP("config", "c", "", configFlagUsage)
+	cmd.Flags().String("since", "7d", "统计的时间范围，如 7d、24h 或起始日期 2006-01-02")
+	cmd.Flags().String("format", "html", "报告格式: html 或 csv")
+	cmd.Flags().StringP("file", "f", "", "写入报告的文件（默认输出到标准输出）")
+	cmd.Flags().String("history-file", "", "历史记录文件（默认使用配置文件中的 history_file）")
+
+	return cmd
+}
+
+func (a *App) runReport(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	sinceValue, _ := cmd.Flags().GetString("since")
+	format, _ := cmd.Flags().GetString("format")
+	reportFile, _ := cmd.Flags().GetString("file")
+	historyFile, _ := cmd.Flags().GetString("history-file")
+
+	format = strings.ToLower(format)
+	if format != "html" && format != "csv" {
+		return withExitCode(ExitUsage, i18n.Errorf("不支持的报告格式: %s（可选 html、csv）", format))
+	}
+	since, err := parseSince(sinceValue, time.Now())
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	configManager := config.NewConfigManager(configFile)
+	if _, err := configManager.LoadConfig(); err != nil {
+		return configError(i18n.Errorf("配置加载失败: %w", err))
+	}
+	settings := configManager.ToBucketSettings()
+	if historyFile == "" {
+		historyFile = settings.HistoryFile
+	}
+
+	records, err := history.Load(historyFile)
+	if err != nil {
+		return i18n.Errorf("读取运行历史失败: %w", err)
+	}
+	data := buildReport(settings, history.Since(records, since), since)
+
+	out := io.Writer(os.Stdout)
+	if reportFile != "" {
+		file, err := os.Create(reportFile)
+		if err != nil {
+			return i18n.Errorf("写入报告失败: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if format == "csv" {
+		err = writeReportCSV(out, data)
+	} else {
+		err = writeReportHTML(out, data)
+	}
+	if err != nil {
+		return i18n.Errorf("写入报告失败: %w", err)
+	}
+
+	if reportFile != "" {
+		i18n.Printf("报告已写入: %s（%d 个桶，%d 次运行）\n", reportFile, len(data.Buckets), data.Runs)
+	}
+	return nil
+}
+
+// parseSince 解析 --since：天数（7d）、Go时间长度（24h）或日期（2006-01-02）
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+		return now.Add(-duration), nil
+	}
+	if date, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return date, nil
+	}
+	return time.Time{}, i18n.Errorf("无效的时间范围: %s（如 7d、24h 或 2006-01-02）", value)
+}
+
+// buildReport 汇总运行记录，并读取配置中每个桶的状态文件得到当前总量。
+// 配置中已删除但有运行记录的桶也会列出
+func buildReport(settings *config.MultiBucketSettings, records []history.Record, since time.Time) reportData {
+	summaries, failures := history.Summarize(records)
+	if len(failures) > maxReportFailures {
+		failures = failures[:maxReportFailures]
+	}
+
+	buckets := make(map[string]*reportBucket)
+	for _, summary := range summaries {
+		buckets[summary.Bucket] = &reportBucket{BucketSummary: summary}
+	}
+
+	for _, bucketSettings := range settings.Buckets {
+		bucket := buckets[bucketSettings.Name]
+		if bucket == nil {
+			bucket = &reportBucket{BucketSummary: history.BucketSummary{Bucket: bucketSettings.Name}}
+			buckets[bucketSettings.Name] = bucket
+		}
+
+		// 同一个桶的不同前缀分别有状态文件，合计
+		stateFile := bucketSettings.StateFile
+		if bucketSettings.Direction == config.DirectionUpload {
+			stateFile = bucketSettings.UploadStateFile()
+		}
+		store, err := openState(statestore.Path(stateFile, settings.StateBackend), settings.StateBackend, bucketSettings.Direction == config.DirectionUpload)
+		if err != nil {
+			continue
+		}
+		if summary, err := summarizeState(store); err == nil {
+			bucket.StateFiles += summary.files
+			bucket.StateBytes += summary.bytes
+		}
+		store.Close()
+	}
+
+	data := reportData{Generated: time.Now(), Since: since, Failures: failures}
+	for _, bucket := range buckets {
+		data.Buckets = append(data.Buckets, *bucket)
+		data.Runs += bucket.Runs
+		data.Failed += bucket.Failed
+	}
+	sort.Slice(data.Buckets, func(i, j int) bool {
+		return data.Buckets[i].Bucket < data.Buckets[j].Bucket
+	})
+	return data
+}
+
+// writeReportCSV 每个桶一行，列名固定为英文便于导入表格软件
+func writeReportCSV(w io.Writer, data reportData) error {
+	topErrors := make(map[string]string)
+	for _, failure := range data.Failures {
+		if _, ok := topErrors[failure.Bucket]; !ok {
+			topErrors[failure.Bucket] = failure.Error
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"bucket", "runs", "failed", "success_rate", "files", "bytes",
+		"last_run", "last_success", "state_files", "state_bytes", "top_error"})
+	for _, bucket := range data.Buckets {
+		rate := ""
+		if bucket.Runs > 0 {
+			rate = strconv.FormatFloat(bucket.SuccessRate()*100, 'f', 1, 64)
+		}
+		writer.Write([]string{
+			bucket.Bucket,
+			strconv.Itoa(bucket.Runs),
+			strconv.Itoa(bucket.Failed),
+			rate,
+			strconv.FormatInt(bucket.Files, 10),
+			strconv.FormatInt(bucket.Bytes, 10),
+			reportTime(bucket.LastRun),
+			reportTime(bucket.LastSuccess),
+			strconv.Itoa(bucket.StateFiles),
+			strconv.FormatInt(bucket.StateBytes, 10),
+			topErrors[bucket.Bucket],
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// reportTime 格式化报告中的时间，零值输出为空
+func reportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// writeReportHTML 生成不依赖外部资源的单个HTML文件，可以直接作为邮件附件分享
+func writeReportHTML(w io.Writer, data reportData) error {
+	tmpl, err := template.New("report").Funcs(template.FuncMap{
+		"t":    i18n.T,
+		"time": reportTime,
+		"size": progress.FormatSize,
+		"rate": func(bucket reportBucket) string {
+			if bucket.Runs == 0 {
+				return "-"
+			}
+			return fmt.Sprintf("%.1f%%", bucket.SuccessRate()*100)
+		},
+	}).Parse(reportTemplate)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, data)
+}
+
+const reportTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{t "ObjectSync 运行报告"}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: left; }
+th { background: #f0f0f0; }
+td.num { text-align: right; }
+tr.failed td { background: #fdecea; }
+</style>
+</head>
+<body>
+<h1>{{t "ObjectSync 运行报告"}}</h1>
+<p>{{t "统计范围"}}: {{time .Since}} - {{time .Generated}}<br>
+{{t "运行次数"}}: {{.Runs}}, {{t "失败"}}: {{.Failed}}</p>
+
+<h2>{{t "各桶情况"}}</h2>
+<table>
+<tr><th>{{t "桶"}}</th><th>{{t "运行次数"}}</th><th>{{t "失败"}}</th><th>{{t "成功率"}}</th><th>{{t "传输文件数"}}</th><th>{{t "传输数据量"}}</th><th>{{t "当前文件数"}}</th><th>{{t "当前数据量"}}</th><th>{{t "最后运行"}}</th><th>{{t "最后成功"}}</th></tr>
+{{range .Buckets}}<tr{{if .Failed}} class="failed"{{end}}><td>{{.Bucket}}</td><td class="num">{{.Runs}}</td><td class="num">{{.Failed}}</td><td class="num">{{rate .}}</td><td class="num">{{.Files}}</td><td class="num">{{size .Bytes}}</td><td class="num">{{.StateFiles}}</td><td class="num">{{size .StateBytes}}</td><td>{{time .LastRun}}</td><td>{{time .LastSuccess}}</td></tr>
+{{end}}</table>
+
+<h2>{{t "最常见的失败"}}</h2>
+{{if .Failures}}<table>
+<tr><th>{{t "桶"}}</th><th>{{t "次数"}}</th><th>{{t "最后出现"}}</th><th>{{t "错误"}}</th></tr>
+{{range .Failures}}<tr><td>{{.Bucket}}</td><td class="num">{{.Count}}</td><td>{{time .LastSeen}}</td><td>{{.Error}}</td></tr>
+{{end}}</table>
+{{else}}<p>{{t "没有失败的运行"}}</p>
+{{end}}</body>
+</html>
+`
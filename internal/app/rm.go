@@ -0,0 +1,125 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"objectsync/internal/i18n"
+	"objectsync/internal/remote"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *App) newRmCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rm <s3://桶名/对象键|前缀>",
+		Short: "删除远程对象",
+		Long:  "删除桶中的单个对象，或使用 --recursive 删除前缀下的所有对象（通过DeleteObjects每批删除1000个）。前缀按目录处理，logs 只匹配 logs/ 下的对象。交互模式下删除前需要确认，--yes 跳过确认",
+		Args:  cobra.ExactArgs(1),
+		RunE:  a.runRm,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completeRemotePath(cmd, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	// 添加命令行参数
+	addConnectionFlags(cmd)
+	cmd.Flags().BoolP("recursive", "r", false, "删除前缀下的所有对象")
+	cmd.Flags().Bool("dry-run", false, "只列出将要删除的对象，不实际删除")
+	cmd.Flags().BoolP("yes", "y", false, "不再询问确认")
+	cmd.Flags().BoolP("verbose", "v", false, "详细输出")
+
+	return cmd
+}
+
+func (a *App) runRm(cmd *cobra.Command, args []string) error {
+	recursive, _ := cmd.Flags().GetBool("recursive")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	bucket, key, err := parseRemotePath(args[0])
+	if err != nil {
+		return err
+	}
+	// 不递归时必须指定对象键，避免误以为删除了整个桶
+	if key == "" && !recursive {
+		return i18n.Errorf("请指定要删除的对象键，删除整个桶的对象请使用 --recursive")
+	}
+
+	client, err := remoteClient(cmd, bucket)
+	if err != nil {
+		return err
+	}
+
+	// 收集要删除的对象
+	var keys []string
+	if recursive {
+		// 前缀按目录处理，避免 logs 同时匹配 logs2/ 下的对象
+		if key != "" && !strings.HasSuffix(key, "/") {
+			key += "/"
+		}
+		err = client.Walk(bucket, key, true, func(entry remote.Entry) error {
+			keys = append(keys, entry.Key)
+			return nil
+		})
+		if err != nil {
+			return i18n.Errorf("列出对象失败: %w", err)
+		}
+	} else {
+		entry, err := client.Stat(bucket, key)
+		if err != nil {
+			return i18n.Errorf("检查对象失败: %w", err)
+		}
+		if entry == nil {
+			return i18n.Errorf("对象不存在: %s/%s（删除前缀下的对象请使用 --recursive）", bucket, key)
+		}
+		keys = []string{key}
+	}
+
+	if len(keys) == 0 {
+		i18n.Printf("没有需要删除的对象\n")
+		return nil
+	}
+
+	if dryRun {
+		for _, k := range keys {
+			i18n.Printf("将删除: %s/%s\n", bucket, k)
+		}
+		i18n.Printf("预演模式: 共 %d 个对象将被删除\n", len(keys))
+		return nil
+	}
+
+	if !yes && interactive(cmd) {
+		question := i18n.Sprintf("将永久删除 %s 中的 %d 个对象，是否继续?", bucket, len(keys))
+		if !promptYesNo(bufio.NewReader(os.Stdin), question, false) {
+			i18n.Println("已取消")
+			return nil
+		}
+	}
+
+	if verbose {
+		for _, k := range keys {
+			i18n.Printf("删除: %s/%s\n", bucket, k)
+		}
+	}
+
+	failed, err := client.Delete(bucket, keys)
+	if err != nil {
+		return i18n.Errorf("删除对象失败: %w", err)
+	}
+	for _, f := range failed {
+		fmt.Printf("  %s: %s\n", f.Key, f.Message)
+	}
+
+	i18n.Printf("已删除 %d 个对象\n", len(keys)-len(failed))
+	if len(failed) > 0 {
+		return i18n.Errorf("%d 个对象删除失败", len(failed))
+	}
+	return nil
+}
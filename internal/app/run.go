@@ -0,0 +1,241 @@
+package app
+
+import (
+	"time"
+
+	"objectsync/internal/backup"
+	"objectsync/internal/config"
+	"objectsync/internal/i18n"
+	"objectsync/internal/progress"
+	"objectsync/internal/ratelimit"
+	"objectsync/internal/replicate"
+	"objectsync/internal/upload"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *App) newRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "按配置的方向同步所有桶",
+		Long:  "按每个桶配置的 direction 执行操作：backup 下载到本地，upload 上传到对象存储，sync 先下载后上传，replicate 复制到另一个对象存储",
+		RunE:  a.withReport(a.runRun),
+	}
+
+	// 添加命令行参数
+	cmd.Flags().StringP("config", "c", "", configFlagUsage)
+	cmd.Flags().StringP("endpoint", "e", "", "Ceph对象存储端点URL (覆盖配置文件)")
+	cmd.Flags().StringP("access-key", "a", "", "访问密钥 (覆盖配置文件)")
+	cmd.Flags().StringP("secret-key", "s", "", "秘密密钥 (覆盖配置文件)")
+	cmd.Flags().String("region", "", "请求签名使用的区域 (覆盖配置文件)")
+	cmd.Flags().String("cluster", "", "只处理指定集群（clusters 或 remotes 中的名称）下的桶")
+	cmd.Flags().BoolP("incremental", "i", true, "启用增量同步")
+	cmd.Flags().Bool("resume", true, "上次运行中断时跳过已完成的部分继续 (--resume=false 重新开始)")
+	cmd.Flags().StringToString("label", nil, "运行标签 key=value，记录在运行历史和通知中（可重复）")
+	cmd.Flags().Float64("max-requests-per-second", 0, "每秒最多发送的请求数，所有桶和工作协程共享 (0表示不限制)")
+	cmd.Flags().BoolP("verbose", "v", false, "详细输出")
+	cmd.Flags().Bool("worker-progress", false, "在进度条下方显示每个正在传输的文件的进度和速度，便于发现卡住的传输（仅终端，--verbose 时不显示）")
+	addBucketFlags(cmd)
+
+	return cmd
+}
+
+func (a *App) runRun(cmd *cobra.Command, args []string) error {
+	// 获取命令行参数
+	configFile, _ := cmd.Flags().GetString("config")
+	endpoint, _ := cmd.Flags().GetString("endpoint")
+	accessKey, _ := cmd.Flags().GetString("access-key")
+	secretKey, _ := cmd.Flags().GetString("secret-key")
+	region, _ := cmd.Flags().GetString("region")
+	cluster, _ := cmd.Flags().GetString("cluster")
+	buckets, _ := cmd.Flags().GetStringSlice("bucket")
+	excluded, _ := cmd.Flags().GetStringSlice("exclude-bucket")
+	incremental, _ := cmd.Flags().GetBool("incremental")
+	resume, _ := cmd.Flags().GetBool("resume")
+	labels, _ := cmd.Flags().GetStringToString("label")
+	maxRequests, _ := cmd.Flags().GetFloat64("max-requests-per-second")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	workerProgress, _ := cmd.Flags().GetBool("worker-progress")
+
+	// 创建配置管理器并加载配置文件
+	configManager := config.NewConfigManager(configFile)
+	if _, err := configManager.LoadConfig(); err != nil {
+		return configError(i18n.Errorf("配置加载失败: %w", err))
+	}
+	if err := configManager.ValidateConfig(); err != nil {
+		return configError(i18n.Errorf("配置验证失败: %w", err))
+	}
+
+	settings := configManager.ToBucketSettings()
+	if cluster != "" {
+		if err := settings.FilterCluster(cluster); err != nil {
+			return withExitCode(ExitUsage, err)
+		}
+	}
+	if err := settings.FilterBuckets(buckets); err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	if err := settings.ExcludeBuckets(excluded); err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	settings.OverrideConnection(endpoint, accessKey, secretKey, region)
+	settings.Incremental = incremental
+	settings.Resume = resume
+	settings.Labels = labels
+
+	// 所有桶共享同一个请求速率限制器
+	limiter := ratelimit.New(maxRequests)
+
+	bucketCount := len(settings.Buckets)
+	i18n.Printf("开始同步（共 %d 个桶）\n", bucketCount)
+	i18n.Printf("连接信息: %s\n", bucketEndpoints(settings))
+
+	successCount := 0
+	var failures []error
+	results := newRunResults("run")
+	total := newTotalProgress(bucketCount, workerProgress && !verbose)
+
+	for i, bucketSettings := range settings.Buckets {
+		i18n.Printf("\n[%d/%d] %s桶: %s\n", i+1, bucketCount, directionLabel(bucketSettings.Direction), bucketSettings.Name)
+		if total != nil {
+			total.StartBucket()
+		}
+
+		stats, err := runBucketDirection(settings, bucketSettings, limiter, verbose, workerProgress, total)
+		a.report.addBucket(bucketSettings.Name, stats, err)
+		results.add(bucketSettings.Name, stats, err)
+		if err != nil {
+			i18n.Printf("桶 %s 同步失败: %v\n", bucketSettings.Name, err)
+			failures = append(failures, bucketError(err, stats))
+			continue
+		}
+
+		i18n.Printf("桶 %s 同步完成!\n", bucketSettings.Name)
+		successCount++
+	}
+
+	finishRun(settings, results)
+
+	// 显示同步总结
+	i18n.Printf("\n同步完成!\n")
+	i18n.Printf("成功: %d 个桶\n", successCount)
+	printTotalProgress(total)
+	if len(failures) > 0 {
+		i18n.Printf("失败: %d 个桶\n", len(failures))
+		return bucketsError(i18n.Errorf("部分桶同步失败"), successCount, failures)
+	}
+
+	return nil
+}
+
+// transfer 正在运行的下载或上传，交互界面用它显示进度和停止任务
+type transfer interface {
+	Progress() progress.Snapshot
+	Stop()
+}
+
+// newTotalProgress 有多个桶时创建汇总进度的跟踪器，只有一个桶时返回nil，显示桶自己的进度；
+// workers 为true时同时显示每个工作协程的进度
+func newTotalProgress(buckets int, workers bool) *progress.Tracker {
+	if buckets < 2 {
+		return nil
+	}
+	total := progress.NewTotal(buckets)
+	if workers {
+		total.ShowWorkers()
+	}
+	return total
+}
+
+// printTotalProgress 输出所有桶合计的传输统计，total为nil时不输出
+func printTotalProgress(total *progress.Tracker) {
+	if total == nil {
+		return
+	}
+	stats := total.Stats()
+	i18n.Printf("合计: 传输 %d 个文件（%s），用时 %s\n", stats.Files, progress.FormatSize(stats.Bytes), stats.Duration.Round(time.Second))
+}
+
+// runBucketDirection 按桶配置的方向执行下载、上传或复制，返回合计的传输统计；
+// total 不为nil时进度同时计入其中，workerProgress 为true时显示每个工作协程的进度
+func runBucketDirection(settings *config.MultiBucketSettings, bucketSettings config.BucketSettings, limiter *ratelimit.Limiter, verbose, workerProgress bool, total *progress.Tracker) (progress.Stats, error) {
+	return runBucketTransfers(settings, bucketSettings, limiter, verbose, workerProgress, total, nil)
+}
+
+// runBucketTransfers 与runBucketDirection相同，每次开始下载、上传或复制时调用started（可以为nil）
+func runBucketTransfers(settings *config.MultiBucketSettings, bucketSettings config.BucketSettings, limiter *ratelimit.Limiter, verbose, workerProgress bool, total *progress.Tracker, started func(transfer)) (stats progress.Stats, err error) {
+	direction := bucketSettings.Direction
+	start := time.Now()
+	defer func() { stats.Duration = time.Since(start) }()
+
+	// sync先下载远程的变化，避免上传时覆盖远程较新的内容
+	if direction == config.DirectionBackup || direction == config.DirectionSync {
+		options := bucketBackupOptions(settings, bucketSettings, limiter)
+		options.Parent = total
+		options.WorkerProgress = workerProgress
+		options.Verbose = options.Verbose || verbose
+		b := backup.New(options)
+		if started != nil {
+			started(b)
+		}
+		err := b.Run()
+		stats = b.Stats()
+		if err != nil {
+			return stats, i18n.Errorf("下载失败: %w", err)
+		}
+	}
+
+	if direction == config.DirectionReplicate {
+		options := bucketReplicateOptions(settings, bucketSettings, limiter)
+		options.Parent = total
+		options.WorkerProgress = workerProgress
+		options.Verbose = options.Verbose || verbose
+		r := replicate.New(options)
+		if started != nil {
+			started(r)
+		}
+		err := r.Run()
+		stats = r.Stats()
+		if err != nil {
+			return stats, i18n.Errorf("复制失败: %w", err)
+		}
+	}
+
+	if direction == config.DirectionUpload || direction == config.DirectionSync {
+		if missing := missingSourceDir(bucketSettings); missing != "" {
+			return stats, i18n.Errorf("本地目录不存在: %s", missing)
+		}
+		options := bucketUploadOptions(settings, bucketSettings, limiter)
+		options.Parent = total
+		options.WorkerProgress = workerProgress
+		options.Verbose = options.Verbose || verbose
+		u := upload.New(options)
+		if started != nil {
+			started(u)
+		}
+		err := u.Run()
+		uploaded := u.Stats()
+		stats.Files += uploaded.Files
+		stats.Bytes += uploaded.Bytes
+		stats.Failed += uploaded.Failed
+		if err != nil {
+			return stats, i18n.Errorf("上传失败: %w", err)
+		}
+	}
+
+	return stats, nil
+}
+
+// directionLabel 返回同步方向在当前语言下的名称
+func directionLabel(direction string) string {
+	switch direction {
+	case config.DirectionUpload:
+		return i18n.T("上传")
+	case config.DirectionSync:
+		return i18n.T("双向同步")
+	case config.DirectionReplicate:
+		return i18n.T("复制")
+	default:
+		return i18n.T("备份")
+	}
+}
@@ -0,0 +1,207 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"objectsync/internal/i18n"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultServiceName 默认的Windows服务名
+const defaultServiceName = "ObjectSync"
+
+// serviceConfig 安装服务时记录的守护进程参数，服务启动时按这些参数运行daemon
+type serviceConfig struct {
+	name        string
+	configFile  string // 绝对路径
+	statusFile  string // 绝对路径
+	logFile     string // 绝对路径，服务没有控制台，日志写入该文件
+	maxRequests float64
+	verbose     bool
+	logLevel    string
+	logFormat   string
+}
+
+func (a *App) newServiceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "service",
+		Short: "管理Windows服务",
+		Long:  "将守护进程安装为Windows服务，开机后无人值守地按计划运行；服务停止时等待正在运行的桶结束后退出（仅Windows）",
+	}
+	cmd.PersistentFlags().String("name", defaultServiceName, "服务名")
+
+	installCmd := &cobra.Command{
+		Use:   "install",
+		Short: "安装服务，开机自动启动",
+		Args:  cobra.NoArgs,
+		RunE:  a.runServiceInstall,
+	}
+	installCmd.Flags().StringP("config", "c", "", configFlagUsage)
+	installCmd.Flags().String("status-file", ".objectsync_daemon.json", "守护进程状态文件路径（相对于配置文件所在目录）")
+	installCmd.Flags().String("log-file", "objectsync-service.log", "服务日志文件路径（相对于配置文件所在目录）")
+	installCmd.Flags().Float64("max-requests-per-second", 0, "每秒最多发送的请求数，所有桶和工作协程共享 (0表示不限制)")
+	installCmd.Flags().BoolP("verbose", "v", false, "详细输出")
+
+	uninstallCmd := &cobra.Command{
+		Use:   "uninstall",
+		Short: "卸载服务",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("name")
+			if err := uninstallService(name); err != nil {
+				return i18n.Errorf("卸载服务 %s 失败: %w", name, err)
+			}
+			i18n.Printf("服务 %s 已卸载\n", name)
+			return nil
+		},
+	}
+
+	startCmd := &cobra.Command{
+		Use:   "start",
+		Short: "启动服务",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("name")
+			if err := startService(name); err != nil {
+				return i18n.Errorf("启动服务 %s 失败: %w", name, err)
+			}
+			i18n.Printf("服务 %s 已启动\n", name)
+			return nil
+		},
+	}
+
+	stopCmd := &cobra.Command{
+		Use:   "stop",
+		Short: "停止服务，等待正在运行的桶结束",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("name")
+			i18n.Printf("正在停止服务 %s，等待正在运行的桶结束...\n", name)
+			if err := stopService(name); err != nil {
+				return i18n.Errorf("停止服务 %s 失败: %w", name, err)
+			}
+			i18n.Printf("服务 %s 已停止\n", name)
+			return nil
+		},
+	}
+
+	// 服务控制管理器启动服务时执行的入口，不直接使用
+	runCmd := &cobra.Command{
+		Use:    "run",
+		Hidden: true,
+		Args:   cobra.NoArgs,
+		RunE:   a.runServiceRun,
+	}
+	runCmd.Flags().String("config", "", "")
+	runCmd.Flags().String("status-file", "", "")
+	runCmd.Flags().String("log-file", "", "")
+	runCmd.Flags().Float64("max-requests-per-second", 0, "")
+	runCmd.Flags().Bool("verbose", false, "")
+
+	cmd.AddCommand(installCmd, uninstallCmd, startCmd, stopCmd, runCmd)
+	return cmd
+}
+
+func (a *App) runServiceInstall(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+	configFile, _ := cmd.Flags().GetString("config")
+	statusFile, _ := cmd.Flags().GetString("status-file")
+	logFile, _ := cmd.Flags().GetString("log-file")
+	maxRequests, _ := cmd.Flags().GetFloat64("max-requests-per-second")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	logLevel, _ := cmd.Flags().GetString("log-level")
+	logFormat, _ := cmd.Flags().GetString("log-format")
+
+	// 服务以系统目录为工作目录启动，所有路径都转换为绝对路径
+	configFile, err := filepath.Abs(configFile)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(configFile); err != nil {
+		return configError(i18n.Errorf("配置文件不存在: %s", configFile))
+	}
+	dir := filepath.Dir(configFile)
+	if !filepath.IsAbs(statusFile) {
+		statusFile = filepath.Join(dir, statusFile)
+	}
+	if !filepath.IsAbs(logFile) {
+		logFile = filepath.Join(dir, logFile)
+	}
+
+	service := serviceConfig{
+		name:        name,
+		configFile:  configFile,
+		statusFile:  statusFile,
+		logFile:     logFile,
+		maxRequests: maxRequests,
+		verbose:     verbose,
+		logLevel:    logLevel,
+		logFormat:   logFormat,
+	}
+	if err := installService(service); err != nil {
+		return i18n.Errorf("安装服务 %s 失败: %w", name, err)
+	}
+
+	i18n.Printf("服务 %s 已安装，开机自动启动\n", name)
+	i18n.Printf("配置文件: %s\n", configFile)
+	i18n.Printf("日志文件: %s\n", logFile)
+	i18n.Printf("使用 objectsync service start 立即启动\n")
+	return nil
+}
+
+func (a *App) runServiceRun(cmd *cobra.Command, args []string) error {
+	service := serviceConfig{}
+	service.name, _ = cmd.Flags().GetString("name")
+	service.configFile, _ = cmd.Flags().GetString("config")
+	service.statusFile, _ = cmd.Flags().GetString("status-file")
+	service.logFile, _ = cmd.Flags().GetString("log-file")
+	service.maxRequests, _ = cmd.Flags().GetFloat64("max-requests-per-second")
+	service.verbose, _ = cmd.Flags().GetBool("verbose")
+
+	// 配置中的相对路径（状态文件、输出目录）相对于配置文件所在目录
+	if err := os.Chdir(filepath.Dir(service.configFile)); err != nil {
+		return err
+	}
+
+	// 服务没有控制台，输出写入日志文件
+	log, err := os.OpenFile(service.logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer log.Close()
+	os.Stdout = log
+	os.Stderr = log
+	if err := setupLogging(cmd, true); err != nil {
+		return err
+	}
+
+	return runService(service.name, func(stop <-chan os.Signal) error {
+		return serveDaemon(service.configFile, service.statusFile, service.maxRequests, service.verbose, stop)
+	})
+}
+
+// serviceArgs 返回服务启动时传给程序的参数
+func serviceArgs(service serviceConfig) []string {
+	args := []string{"service", "run",
+		"--name", service.name,
+		"--config", service.configFile,
+		"--status-file", service.statusFile,
+		"--log-file", service.logFile,
+	}
+	if service.maxRequests > 0 {
+		args = append(args, "--max-requests-per-second", strconv.FormatFloat(service.maxRequests, 'f', -1, 64))
+	}
+	if service.verbose {
+		args = append(args, "--verbose")
+	}
+	if service.logLevel != "" {
+		args = append(args, "--log-level", service.logLevel)
+	}
+	if service.logFormat != "" {
+		args = append(args, "--log-format", service.logFormat)
+	}
+	return args
+}
@@ -0,0 +1,34 @@
+//go:build !windows
+
+package app
+
+import (
+	"os"
+
+	"objectsync/internal/i18n"
+)
+
+// errServiceUnsupported 非Windows系统请使用systemd等服务管理器运行 objectsync daemon
+func errServiceUnsupported() error {
+	return i18n.Errorf("仅Windows支持安装服务，其他系统请使用服务管理器运行 objectsync daemon")
+}
+
+func installService(service serviceConfig) error {
+	return errServiceUnsupported()
+}
+
+func uninstallService(name string) error {
+	return errServiceUnsupported()
+}
+
+func startService(name string) error {
+	return errServiceUnsupported()
+}
+
+func stopService(name string) error {
+	return errServiceUnsupported()
+}
+
+func runService(name string, run func(stop <-chan os.Signal) error) error {
+	return errServiceUnsupported()
+}
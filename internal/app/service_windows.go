@@ -0,0 +1,175 @@
+//go:build windows
+
+package app
+
+import (
+	"os"
+	"time"
+
+	"objectsync/internal/i18n"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// serviceStopTimeout service stop 等待服务停止的最长时间，正在运行的桶可能需要较长时间结束
+const serviceStopTimeout = 10 * time.Minute
+
+func installService(service serviceConfig) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	if s, err := m.OpenService(service.name); err == nil {
+		s.Close()
+		return i18n.Errorf("服务已存在，请先卸载")
+	}
+
+	s, err := m.CreateService(service.name, exe, mgr.Config{
+		DisplayName: service.name,
+		Description: i18n.T("ObjectSync 守护进程，按计划同步对象存储"),
+		StartType:   mgr.StartAutomatic,
+	}, serviceArgs(service)...)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	// 异常退出后一分钟自动重启
+	err = s.SetRecoveryActions([]mgr.RecoveryAction{
+		{Type: mgr.ServiceRestart, Delay: time.Minute},
+	}, uint32((24 * time.Hour).Seconds()))
+	if err != nil {
+		return err
+	}
+	// 默认只在进程崩溃时重启，守护进程出错后以非零退出码停止服务时同样需要重启
+	return s.SetRecoveryActionsOnNonCrashFailures(true)
+}
+
+func uninstallService(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	// 先停止正在运行的服务，删除标记在服务停止后生效
+	if status, err := s.Query(); err == nil && status.State != svc.Stopped {
+		s.Control(svc.Stop)
+	}
+	return s.Delete()
+}
+
+func startService(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	return s.Start()
+}
+
+func stopService(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	status, err := s.Control(svc.Stop)
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(serviceStopTimeout)
+	for status.State != svc.Stopped {
+		if time.Now().After(deadline) {
+			return i18n.Errorf("等待服务停止超时")
+		}
+		time.Sleep(time.Second)
+		if status, err = s.Query(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runService 由服务控制管理器启动时运行守护进程，把停止和关机请求转换为stop信号
+func runService(name string, run func(stop <-chan os.Signal) error) error {
+	return svc.Run(name, &serviceHandler{run: run})
+}
+
+// serviceHandler 处理服务控制请求
+type serviceHandler struct {
+	run func(stop <-chan os.Signal) error
+}
+
+func (h *serviceHandler) Execute(args []string, requests <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.StartPending}
+
+	stop := make(chan os.Signal, 1)
+	done := make(chan error, 1)
+	go func() { done <- h.run(stop) }()
+
+	accepts := svc.AcceptStop | svc.AcceptShutdown
+	changes <- svc.Status{State: svc.Running, Accepts: accepts}
+
+	for {
+		select {
+		case err := <-done:
+			// 守护进程出错退出（如配置错误）时返回非零退出码，安装时启用了非崩溃失败的恢复策略，服务会被重启
+			if err != nil {
+				daemonLog.Errorf("守护进程退出: %v", err)
+				return true, 1
+			}
+			return false, 0
+
+		case request := <-requests:
+			switch request.Cmd {
+			case svc.Interrogate:
+				changes <- request.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				// 等待正在运行的桶结束，期间定期报告进度避免被判定为无响应
+				changes <- svc.Status{State: svc.StopPending, WaitHint: 30000}
+				stop <- os.Interrupt
+				checkpoint := uint32(0)
+				for {
+					select {
+					case err := <-done:
+						if err != nil {
+							daemonLog.Errorf("守护进程退出: %v", err)
+						}
+						return false, 0
+					case <-time.After(10 * time.Second):
+						checkpoint++
+						changes <- svc.Status{State: svc.StopPending, CheckPoint: checkpoint, WaitHint: 30000}
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,711 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"objectsync/internal/backup"
+	"objectsync/internal/config"
+	"objectsync/internal/i18n"
+	"objectsync/internal/progress"
+	"objectsync/internal/state"
+	"objectsync/internal/statestore"
+
+	"github.com/spf13/cobra"
+)
+
+// 导出和导入的格式
+const (
+	stateFormatJSON = "json" // 与JSON后端的状态文件相同，可以直接作为状态文件使用
+	stateFormatCSV  = "csv"  // 每个条目一行，便于用表格软件查看
+)
+
+// stateCSVHeader CSV格式的列名，导入时按列名对应，列的顺序可以不同
+var stateCSVHeader = []string{"key", "etag", "last_modified", "size", "checksum", "sha256", "pack", "deleted_at"}
+
+// stateTarget 要操作的状态文件，bucket为nil表示直接通过 --state-file 指定
+type stateTarget struct {
+	path     string
+	backend  string
+	upload   bool
+	bucket   *config.BucketSettings
+	settings *config.MultiBucketSettings
+}
+
+// stateSummary 状态的统计，files和bytes不包括删除记录
+type stateSummary struct {
+	lastRun     time.Time
+	files       int
+	bytes       int64
+	deleted     int       // 删除记录数
+	lastDeleted time.Time // 最近一次删除的时间
+}
+
+func (a *App) newStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "查看和修改状态文件",
+		Long:  "查看备份或上传状态文件中的条目，删除错误的条目使其在下次运行时重新传输，不需要手工编辑JSON",
+	}
+
+	cmd.PersistentFlags().StringP("config", "c", "", configFlagUsage)
+	cmd.PersistentFlags().StringP("bucket", "b", "", "桶名（配置了多个桶时必须指定）")
+	cmd.PersistentFlags().Bool("upload", false, "操作上传状态而不是备份状态")
+	cmd.PersistentFlags().StringP("state-file", "f", "", "直接指定状态文件路径，不读取配置文件")
+	cmd.RegisterFlagCompletionFunc("bucket", completeBuckets)
+
+	listCmd := &cobra.Command{
+		Use:   "list [前缀]",
+		Short: "列出状态中的条目",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  a.runStateList,
+	}
+	listCmd.Flags().Bool("deleted", false, "只列出已删除的对象（或本地文件）的删除记录")
+
+	showCmd := &cobra.Command{
+		Use:   "show <键>",
+		Short: "显示单个条目和对应的本地文件",
+		Args:  cobra.ExactArgs(1),
+		RunE:  a.runStateShow,
+	}
+
+	rmCmd := &cobra.Command{
+		Use:   "rm <键>...",
+		Short: "删除条目，下次运行时重新传输对应的文件",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  a.runStateRm,
+	}
+	rmCmd.Flags().BoolP("recursive", "r", false, "把参数作为前缀，删除前缀下的所有条目")
+
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "显示状态文件的统计信息",
+		Args:  cobra.NoArgs,
+		RunE:  a.runStateStats,
+	}
+
+	rebuildCmd := &cobra.Command{
+		Use:   "rebuild",
+		Short: "按输出目录中已有的文件重新生成备份状态",
+		Long:  "列出远程对象并与输出目录中的文件比较，把一致的文件记录到新的状态文件中，状态文件丢失或损坏后不需要重新下载已有的文件。原来的状态文件改名为 .bak 保留",
+		Args:  cobra.NoArgs,
+		RunE:  a.runStateRebuild,
+	}
+	rebuildCmd.Flags().Bool("size-only", false, "只比较大小，不计算本地文件的MD5")
+	rebuildCmd.Flags().IntP("workers", "w", 5, "并发计算MD5的工作数")
+
+	exportCmd := &cobra.Command{
+		Use:   "export [文件]",
+		Short: "导出状态为JSON或CSV",
+		Long:  "把状态中的所有条目导出为JSON或CSV，用于在表格软件中查看、归档，或者导入到另一个状态存储后端。不指定文件时输出到标准输出",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  a.runStateExport,
+	}
+	exportCmd.Flags().String("format", stateFormatJSON, "导出格式: json 或 csv")
+
+	importCmd := &cobra.Command{
+		Use:   "import <文件>",
+		Short: "从导出的JSON或CSV文件导入状态",
+		Long:  "把 state export 导出的条目写入状态，与已有的条目合并，相同的键被替换。状态文件不存在时创建，可以用来在JSON和bbolt后端之间迁移状态",
+		Args:  cobra.ExactArgs(1),
+		RunE:  a.runStateImport,
+	}
+	importCmd.Flags().String("format", "", "导入格式: json 或 csv（默认按文件扩展名判断）")
+	importCmd.Flags().Bool("replace", false, "导入前清空状态中原有的条目")
+
+	cmd.AddCommand(listCmd, showCmd, rmCmd, statsCmd, rebuildCmd, exportCmd, importCmd)
+	return cmd
+}
+
+// stateTargetFromFlags 按 --state-file 或配置中的桶确定要操作的状态文件
+func stateTargetFromFlags(cmd *cobra.Command) (*stateTarget, error) {
+	configFile, _ := cmd.Flags().GetString("config")
+	bucket, _ := cmd.Flags().GetString("bucket")
+	upload, _ := cmd.Flags().GetBool("upload")
+	stateFile, _ := cmd.Flags().GetString("state-file")
+
+	if stateFile != "" {
+		return &stateTarget{path: stateFile, backend: statestore.Detect(stateFile), upload: upload}, nil
+	}
+
+	configManager := config.NewConfigManager(configFile)
+	if _, err := configManager.LoadConfig(); err != nil {
+		return nil, configError(i18n.Errorf("配置加载失败: %w", err))
+	}
+	settings := configManager.ToBucketSettings()
+
+	if bucket != "" {
+		if err := settings.FilterBuckets([]string{bucket}); err != nil {
+			return nil, withExitCode(ExitUsage, err)
+		}
+	}
+	switch {
+	case len(settings.Buckets) == 0:
+		return nil, configError(i18n.Errorf("没有配置的桶"))
+	case len(settings.Buckets) > 1 && bucket == "":
+		return nil, withExitCode(ExitUsage, i18n.Errorf("配置了多个桶，请使用 --bucket 指定"))
+	case len(settings.Buckets) > 1:
+		return nil, withExitCode(ExitUsage, i18n.Errorf("桶 %s 配置了多个前缀，请使用 --state-file 指定状态文件", bucket))
+	}
+
+	target := &stateTarget{backend: settings.StateBackend, upload: upload, bucket: &settings.Buckets[0], settings: settings}
+	if upload {
+		target.path = statestore.Path(target.bucket.UploadStateFile(), target.backend)
+	} else {
+		target.path = statestore.Path(target.bucket.StateFile, target.backend)
+	}
+	return target, nil
+}
+
+// open 打开目标状态文件
+func (t *stateTarget) open() (statestore.Store[json.RawMessage], error) {
+	return openState(t.path, t.backend, t.upload)
+}
+
+// openState 按原始JSON打开已有的状态文件，修改条目时原样保留条目中的其他字段，备份和上传状态通用。
+// path为实际保存状态的文件（bbolt后端为数据库文件）
+func openState(path, backend string, upload bool) (statestore.Store[json.RawMessage], error) {
+	state.MigrateName(path, backend)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, i18n.Errorf("状态文件不存在: %s", path)
+	}
+	store, err := statestore.Open[json.RawMessage](path, state.Options(stateDirection(upload), backend))
+	if err != nil {
+		return nil, i18n.Errorf("无法读取状态文件: %w", err)
+	}
+	return store, nil
+}
+
+// stateDirection 返回状态的方向
+func stateDirection(upload bool) string {
+	if upload {
+		return state.Upload
+	}
+	return state.Backup
+}
+
+// parseStateEntry 解析条目的公共字段
+func parseStateEntry(raw json.RawMessage) (state.Entry, error) {
+	var entry state.Entry
+	err := json.Unmarshal(raw, &entry)
+	return entry, err
+}
+
+// summarizeState 统计状态中的条目数和数据量
+func summarizeState(store statestore.Store[json.RawMessage]) (*stateSummary, error) {
+	summary := &stateSummary{lastRun: store.LastRun()}
+	err := store.Range("", func(key string, raw json.RawMessage) bool {
+		entry, _ := parseStateEntry(raw)
+		if entry.Deleted() {
+			summary.deleted++
+			if entry.DeletedAt.After(summary.lastDeleted) {
+				summary.lastDeleted = *entry.DeletedAt
+			}
+			return true
+		}
+		summary.files++
+		summary.bytes += entry.Size
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+func (a *App) runStateList(cmd *cobra.Command, args []string) error {
+	deleted, _ := cmd.Flags().GetBool("deleted")
+
+	target, err := stateTargetFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	store, err := target.open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	prefix := ""
+	if len(args) > 0 {
+		prefix = args[0]
+	}
+
+	count := 0
+	err = store.Range(prefix, func(key string, raw json.RawMessage) bool {
+		entry, _ := parseStateEntry(raw)
+		if (entry.Deleted()) != deleted {
+			return true
+		}
+		// 删除记录显示删除的时间
+		modified := entry.LastModified
+		if entry.Deleted() {
+			modified = *entry.DeletedAt
+		}
+		fmt.Printf("%s  %10s  %s  %s\n",
+			modified.Local().Format("2006-01-02 15:04:05"),
+			progress.FormatSize(entry.Size),
+			entry.ETag,
+			key)
+		count++
+		return true
+	})
+	if err != nil {
+		return i18n.Errorf("无法读取状态文件: %w", err)
+	}
+	i18n.Printf("共 %d 个条目\n", count)
+	return nil
+}
+
+func (a *App) runStateShow(cmd *cobra.Command, args []string) error {
+	target, err := stateTargetFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	store, err := target.open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	key := args[0]
+	raw, ok := store.Get(key)
+	if !ok {
+		return i18n.Errorf("状态文件 %s 中没有 %s", target.path, key)
+	}
+
+	var pretty bytes.Buffer
+	json.Indent(&pretty, raw, "", "  ")
+	i18n.Printf("状态文件: %s\n", target.path)
+	i18n.Printf("键: %s\n", key)
+	fmt.Println(pretty.String())
+
+	entry, _ := parseStateEntry(raw)
+	if entry.Deleted() {
+		fmt.Println()
+		i18n.Printf("删除记录: 于 %s 发现已删除\n", entry.DeletedAt.Local().Format("2006-01-02 15:04:05"))
+	}
+
+	// 与本地文件比较，帮助判断为什么文件被反复传输
+	if target.bucket != nil && !target.upload {
+		localPath := filepath.Join(target.bucket.OutputDir, strings.TrimPrefix(key, target.bucket.Prefix))
+		fmt.Println()
+		i18n.Printf("本地文件: %s\n", localPath)
+		info, err := os.Stat(localPath)
+		switch {
+		case os.IsNotExist(err):
+			i18n.Println("  不存在，下次备份时会重新下载")
+		case err != nil:
+			i18n.Printf("  无法读取: %v\n", err)
+		default:
+			i18n.Printf("  大小: %d（状态记录 %d）\n", info.Size(), entry.Size)
+			i18n.Printf("  修改时间: %s\n", info.ModTime().Format("2006-01-02 15:04:05"))
+		}
+	}
+	return nil
+}
+
+func (a *App) runStateRm(cmd *cobra.Command, args []string) error {
+	recursive, _ := cmd.Flags().GetBool("recursive")
+
+	target, err := stateTargetFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	lock, err := statestore.Acquire(target.path)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	store, err := target.open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	removed := 0
+	for _, arg := range args {
+		var keys []string
+		if recursive {
+			// 遍历时不能修改存储，先收集前缀下的键
+			err := store.Range(arg, func(key string, _ json.RawMessage) bool {
+				keys = append(keys, key)
+				return true
+			})
+			if err != nil {
+				return i18n.Errorf("无法读取状态文件: %w", err)
+			}
+		} else if _, ok := store.Get(arg); ok {
+			keys = []string{arg}
+		}
+
+		for _, key := range keys {
+			if err := store.Delete(key); err != nil {
+				return i18n.Errorf("保存状态文件失败: %w", err)
+			}
+			fmt.Printf("%s\n", key)
+			removed++
+		}
+		if len(keys) == 0 {
+			i18n.Printf("警告: 状态文件中没有 %s\n", arg)
+		}
+	}
+
+	if removed == 0 {
+		return i18n.Errorf("没有删除任何条目")
+	}
+	if err := store.Save(); err != nil {
+		return i18n.Errorf("保存状态文件失败: %w", err)
+	}
+	i18n.Printf("已从 %s 删除 %d 个条目\n", target.path, removed)
+	return nil
+}
+
+func (a *App) runStateStats(cmd *cobra.Command, args []string) error {
+	target, err := stateTargetFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	store, err := target.open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	summary, err := summarizeState(store)
+	if err != nil {
+		return i18n.Errorf("无法读取状态文件: %w", err)
+	}
+
+	i18n.Printf("状态文件: %s\n", target.path)
+	if info, err := os.Stat(target.path); err == nil {
+		i18n.Printf("文件大小: %s\n", progress.FormatSize(info.Size()))
+	}
+	if !summary.lastRun.IsZero() {
+		i18n.Printf("上次运行时间: %s\n", summary.lastRun.Local().Format("2006-01-02 15:04:05"))
+	}
+	i18n.Printf("条目数: %d\n", summary.files)
+	i18n.Printf("总数据大小: %s\n", progress.FormatSize(summary.bytes))
+	if summary.deleted > 0 {
+		i18n.Printf("删除记录: %d（最近 %s）\n", summary.deleted, summary.lastDeleted.Local().Format("2006-01-02 15:04:05"))
+	}
+	return nil
+}
+
+func (a *App) runStateRebuild(cmd *cobra.Command, args []string) error {
+	sizeOnly, _ := cmd.Flags().GetBool("size-only")
+	workers, _ := cmd.Flags().GetInt("workers")
+
+	target, err := stateTargetFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if target.bucket == nil {
+		return withExitCode(ExitUsage, i18n.Errorf("重新生成状态需要从配置文件读取桶的连接信息和输出目录，不能使用 --state-file"))
+	}
+	if target.upload {
+		return withExitCode(ExitUsage, i18n.Errorf("只能重新生成备份状态"))
+	}
+
+	options := bucketBackupOptions(target.settings, *target.bucket, nil)
+	options.Workers = workers
+	i18n.Printf("重新生成状态: 桶 %s，输出目录 %s\n", target.bucket.Name, target.bucket.OutputDir)
+
+	result, err := backup.New(options).Rebuild(sizeOnly)
+	if err != nil {
+		return err
+	}
+
+	if result.Previous != "" {
+		i18n.Printf("原来的状态文件已保存为: %s\n", result.Previous)
+	}
+	i18n.Printf("已记录与本地文件一致的对象: %d\n", result.Matched)
+	i18n.Printf("本地不存在或不一致的对象: %d（下次备份时下载）\n", result.Missing)
+	if result.Skipped > 0 {
+		i18n.Printf("无法比较的打包或压缩对象: %d（下次备份时下载）\n", result.Skipped)
+	}
+	i18n.Printf("状态文件已重新生成: %s\n", target.path)
+	return nil
+}
+
+func (a *App) runStateExport(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	format = strings.ToLower(format)
+	if format != stateFormatJSON && format != stateFormatCSV {
+		return withExitCode(ExitUsage, i18n.Errorf("不支持的格式: %s（可选 json、csv）", format))
+	}
+
+	target, err := stateTargetFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	store, err := target.open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	out := io.Writer(os.Stdout)
+	if len(args) > 0 {
+		file, err := os.Create(args[0])
+		if err != nil {
+			return i18n.Errorf("导出状态失败: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	var count int
+	if format == stateFormatCSV {
+		count, err = writeStateCSV(out, store)
+	} else {
+		count, err = writeStateJSON(out, store, state.LastRunField)
+	}
+	if err != nil {
+		return i18n.Errorf("导出状态失败: %w", err)
+	}
+
+	// 输出到标准输出时不打印提示，避免混入导出的内容
+	if len(args) > 0 {
+		i18n.Printf("已导出 %d 个条目到 %s\n", count, args[0])
+	}
+	return nil
+}
+
+// writeStateJSON 按JSON状态文件的格式逐个写出条目，不把整个状态加载到内存
+func writeStateJSON(w io.Writer, store statestore.Store[json.RawMessage], field string) (int, error) {
+	out := bufio.NewWriter(w)
+	out.WriteString("{\n")
+	if lastRun := store.LastRun(); !lastRun.IsZero() {
+		value, _ := json.Marshal(lastRun)
+		fmt.Fprintf(out, "  %q: %s,\n", field, value)
+	}
+	out.WriteString(`  "files": {`)
+
+	count := 0
+	var entry bytes.Buffer
+	err := store.Range("", func(key string, raw json.RawMessage) bool {
+		name, _ := json.Marshal(key)
+		entry.Reset()
+		json.Compact(&entry, raw)
+		if count > 0 {
+			out.WriteString(",")
+		}
+		fmt.Fprintf(out, "\n    %s: %s", name, entry.Bytes())
+		count++
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		out.WriteString("\n  ")
+	}
+	out.WriteString("}\n}\n")
+	return count, out.Flush()
+}
+
+// writeStateCSV 每个条目一行，时间为RFC 3339格式。条目中的其他字段不导出
+func writeStateCSV(w io.Writer, store statestore.Store[json.RawMessage]) (int, error) {
+	writer := csv.NewWriter(w)
+	writer.Write(stateCSVHeader)
+
+	count := 0
+	err := store.Range("", func(key string, raw json.RawMessage) bool {
+		entry, _ := parseStateEntry(raw)
+		writer.Write([]string{
+			key,
+			entry.ETag,
+			entry.LastModified.Format(time.RFC3339Nano),
+			strconv.FormatInt(entry.Size, 10),
+			entry.Checksum,
+			entry.SHA256,
+			entry.Pack,
+			formatDeletedAt(entry.DeletedAt),
+		})
+		count++
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	writer.Flush()
+	return count, writer.Error()
+}
+
+// formatDeletedAt 返回CSV中删除记录的时间，不是删除记录时为空
+func formatDeletedAt(deletedAt *time.Time) string {
+	if deletedAt == nil {
+		return ""
+	}
+	return deletedAt.Format(time.RFC3339Nano)
+}
+
+func (a *App) runStateImport(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	replace, _ := cmd.Flags().GetBool("replace")
+
+	input := args[0]
+	if format == "" {
+		format = stateFormatJSON
+		if strings.EqualFold(filepath.Ext(input), ".csv") {
+			format = stateFormatCSV
+		}
+	}
+	format = strings.ToLower(format)
+	if format != stateFormatJSON && format != stateFormatCSV {
+		return withExitCode(ExitUsage, i18n.Errorf("不支持的格式: %s（可选 json、csv）", format))
+	}
+
+	target, err := stateTargetFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	// 先读取并检查所有条目，格式错误时不修改状态
+	file, err := os.Open(input)
+	if err != nil {
+		return i18n.Errorf("导入状态失败: %w", err)
+	}
+	defer file.Close()
+
+	var entries map[string]json.RawMessage
+	var lastRun time.Time
+	if format == stateFormatCSV {
+		entries, err = readStateCSV(file)
+	} else {
+		entries, lastRun, err = readStateJSON(file, stateDirection(target.upload))
+	}
+	if err != nil {
+		return i18n.Errorf("导入状态失败: %w", err)
+	}
+
+	lock, err := statestore.Acquire(target.path)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	options := state.Options(stateDirection(target.upload), target.backend)
+	var store statestore.Store[json.RawMessage]
+	if replace {
+		store, err = statestore.Create[json.RawMessage](target.path, options)
+	} else {
+		store, err = statestore.Open[json.RawMessage](target.path, options)
+	}
+	if err != nil {
+		return i18n.Errorf("无法读取状态文件: %w", err)
+	}
+	defer store.Close()
+
+	for key, raw := range entries {
+		if err := store.Put(key, raw); err != nil {
+			return i18n.Errorf("保存状态文件失败: %w", err)
+		}
+	}
+	if !lastRun.IsZero() {
+		store.SetLastRun(lastRun)
+	}
+	if err := store.Save(); err != nil {
+		return i18n.Errorf("保存状态文件失败: %w", err)
+	}
+	i18n.Printf("已导入 %d 个条目到 %s\n", len(entries), target.path)
+	return nil
+}
+
+// readStateJSON 读取JSON格式导出的状态（也可以是JSON后端的状态文件，包括旧版本的），检查每个条目的格式
+func readStateJSON(r io.Reader, direction string) (map[string]json.RawMessage, time.Time, error) {
+	var document map[string]json.RawMessage
+	var lastRun time.Time
+	if err := json.NewDecoder(r).Decode(&document); err != nil {
+		return nil, lastRun, i18n.Errorf("状态文件格式错误: %w", err)
+	}
+
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(document["files"], &entries); err != nil || entries == nil {
+		return nil, lastRun, i18n.Errorf("状态文件格式错误: 没有 files 字段")
+	}
+	for key, raw := range entries {
+		if _, err := parseStateEntry(raw); err != nil {
+			return nil, lastRun, i18n.Errorf("条目 %s 格式错误: %w", key, err)
+		}
+	}
+	options := state.Options(direction, "")
+	for _, field := range []string{options.LastRunField, options.LegacyField} {
+		if raw, ok := document[field]; ok {
+			json.Unmarshal(raw, &lastRun)
+			break
+		}
+	}
+	return entries, lastRun, nil
+}
+
+// readStateCSV 读取CSV格式导出的状态，第一行为列名，必须有 key 列
+func readStateCSV(r io.Reader) (map[string]json.RawMessage, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, i18n.Errorf("CSV格式错误: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.ToLower(name))] = i
+	}
+	if _, ok := columns["key"]; !ok {
+		return nil, i18n.Errorf("CSV格式错误: 没有 key 列")
+	}
+
+	entries := make(map[string]json.RawMessage)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, i18n.Errorf("CSV格式错误: %w", err)
+		}
+		column := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		key := column("key")
+		if key == "" {
+			continue
+		}
+		entry := state.Entry{ETag: column("etag"), Checksum: column("checksum"), SHA256: column("sha256"), Pack: column("pack")}
+		if value := column("last_modified"); value != "" {
+			if entry.LastModified, err = time.Parse(time.RFC3339Nano, value); err != nil {
+				return nil, i18n.Errorf("条目 %s 格式错误: %w", key, err)
+			}
+		}
+		if value := column("deleted_at"); value != "" {
+			deletedAt, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return nil, i18n.Errorf("条目 %s 格式错误: %w", key, err)
+			}
+			entry.DeletedAt = &deletedAt
+		}
+		if value := column("size"); value != "" {
+			if entry.Size, err = strconv.ParseInt(value, 10, 64); err != nil {
+				return nil, i18n.Errorf("条目 %s 格式错误: %w", key, err)
+			}
+		}
+		if entries[key], err = json.Marshal(entry); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+
+	"objectsync/internal/i18n"
+	"objectsync/internal/progress"
+	"objectsync/internal/state"
+	"objectsync/internal/storage"
+	"objectsync/internal/upload"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *App) newSyncCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync <源目录> <目标目录>",
+		Short: "同步两个本地目录",
+		Long: "把源目录中新增和修改的文件复制到目标目录（如NAS到U盘），与上传使用相同的增量状态、过滤规则和进度显示，" +
+			"保留文件的修改时间和权限，不删除目标目录中多出的文件。配置文件中把端点设为 local:目录 可以在 backup、upload、run 中使用本地目录",
+		Args: cobra.ExactArgs(2),
+		RunE: a.withReport(a.runSync),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) < 2 {
+				return nil, cobra.ShellCompDirectiveFilterDirs
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	// 添加命令行参数
+	cmd.Flags().BoolP("incremental", "i", true, "启用增量同步，只复制上次同步后变化的文件")
+	cmd.Flags().String("state-file", "", "状态文件路径 (默认为当前目录下的 .sync_state_<目标目录名>.json)")
+	cmd.Flags().Bool("resume", true, "上次运行中断时跳过已复制的文件继续 (--resume=false 重新开始)")
+	cmd.Flags().IntP("workers", "w", 5, "并发复制数")
+	cmd.Flags().StringSlice("include", nil, "只同步匹配的文件（可重复）")
+	cmd.Flags().StringSlice("exclude", nil, "不同步匹配的文件（可重复）")
+	cmd.Flags().Bool("verify", false, "复制后校验目标文件的大小")
+	cmd.Flags().BoolP("verbose", "v", false, "详细输出")
+	cmd.Flags().Bool("worker-progress", false, "在进度条下方显示每个正在复制的文件的进度和速度（仅终端，--verbose 时不显示）")
+
+	return cmd
+}
+
+func (a *App) runSync(cmd *cobra.Command, args []string) error {
+	// 获取命令行参数
+	incremental, _ := cmd.Flags().GetBool("incremental")
+	stateFile, _ := cmd.Flags().GetString("state-file")
+	resume, _ := cmd.Flags().GetBool("resume")
+	workers, _ := cmd.Flags().GetInt("workers")
+	include, _ := cmd.Flags().GetStringSlice("include")
+	exclude, _ := cmd.Flags().GetStringSlice("exclude")
+	verify, _ := cmd.Flags().GetBool("verify")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	workerProgress, _ := cmd.Flags().GetBool("worker-progress")
+
+	source, err := filepath.Abs(args[0])
+	if err != nil {
+		return err
+	}
+	target, err := filepath.Abs(args[1])
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(source); err != nil || !info.IsDir() {
+		return withExitCode(ExitUsage, i18n.Errorf("源目录不存在: %s", args[0]))
+	}
+	// 目标目录在源目录中时，复制的文件会在下次同步时被再次复制
+	if relative, err := filepath.Rel(source, target); err == nil && (relative == "." || filepath.IsLocal(relative)) {
+		return withExitCode(ExitUsage, i18n.Errorf("目标目录不能位于源目录中: %s", args[1]))
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	// 目标目录的上级目录作为端点，目标目录作为桶
+	name := filepath.Base(target)
+	if stateFile == "" {
+		stateFile = state.FileName("sync", "", name, "")
+	}
+	options := &upload.Options{
+		Endpoint:       storage.LocalScheme + filepath.Dir(target),
+		Bucket:         name,
+		InputDir:       source,
+		Incremental:    incremental,
+		StateFile:      stateFile,
+		Workers:        workers,
+		DirMarkers:     upload.DirMarkersEmpty, // 非空目录在复制其中的文件时创建
+		Verify:         verify,
+		Include:        include,
+		Exclude:        exclude,
+		Resume:         resume,
+		WorkerProgress: workerProgress,
+		Verbose:        verbose,
+	}
+
+	i18n.Printf("同步: %s -> %s\n", source, target)
+	if verbose && incremental {
+		i18n.Printf("状态文件: %s\n", stateFile)
+	}
+
+	u := upload.New(options)
+	err = u.Run()
+	a.report.addBucket(target, u.Stats(), err)
+	if err != nil {
+		return i18n.Errorf("同步失败: %w", err)
+	}
+
+	stats := u.Stats()
+	i18n.Printf("同步完成! 复制 %d 个文件（%s）\n", stats.Files, progress.FormatSize(stats.Bytes))
+	return nil
+}
@@ -0,0 +1,255 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"objectsync/internal/i18n"
+
+	"github.com/spf13/cobra"
+)
+
+// systemd单元的运行方式
+const (
+	systemdDaemon = "daemon" // 常驻服务，按配置中每个桶的 schedule 运行
+	systemdTimer  = "timer"  // 定时器按 --on-calendar 触发一次 run
+)
+
+// systemdUnit 要写入的单元文件
+type systemdUnit struct {
+	name    string // 文件名，如 objectsync.service
+	content string
+}
+
+func (a *App) newSystemdCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "systemd",
+		Short: "管理systemd单元",
+		Long:  "生成并安装systemd单元：daemon 模式安装常驻服务（支持就绪通知和看门狗），timer 模式安装定时触发 run 的服务和定时器",
+	}
+	cmd.PersistentFlags().String("name", "objectsync", "单元名（不含后缀）")
+	cmd.PersistentFlags().Bool("user", false, "安装为当前用户的单元（systemctl --user），不需要root权限")
+
+	installCmd := &cobra.Command{
+		Use:   "install",
+		Short: "生成并安装单元，设置开机启动",
+		Args:  cobra.NoArgs,
+		RunE:  a.runSystemdInstall,
+	}
+	installCmd.Flags().StringP("config", "c", "", configFlagUsage)
+	installCmd.Flags().String("mode", systemdDaemon, "运行方式: daemon 常驻按各桶 schedule 运行, timer 按 --on-calendar 定时运行 run")
+	installCmd.Flags().String("on-calendar", "daily", "timer 模式的触发时间（systemd OnCalendar 格式，如 daily、*-*-* 02:00:00）")
+	installCmd.Flags().String("run-as", "", "运行服务的用户（默认root，--user 时忽略）")
+	installCmd.Flags().Bool("print", false, "只输出生成的单元文件，不安装")
+	installCmd.Flags().Bool("no-enable", false, "只写入单元文件，不启用和启动")
+
+	uninstallCmd := &cobra.Command{
+		Use:   "uninstall",
+		Short: "停止并删除安装的单元",
+		Args:  cobra.NoArgs,
+		RunE:  a.runSystemdUninstall,
+	}
+
+	cmd.AddCommand(installCmd, uninstallCmd)
+	return cmd
+}
+
+func (a *App) runSystemdInstall(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+	user, _ := cmd.Flags().GetBool("user")
+	configFile, _ := cmd.Flags().GetString("config")
+	mode, _ := cmd.Flags().GetString("mode")
+	onCalendar, _ := cmd.Flags().GetString("on-calendar")
+	runAs, _ := cmd.Flags().GetString("run-as")
+	printOnly, _ := cmd.Flags().GetBool("print")
+	noEnable, _ := cmd.Flags().GetBool("no-enable")
+
+	if mode != systemdDaemon && mode != systemdTimer {
+		return withExitCode(ExitUsage, i18n.Errorf("不支持的运行方式: %s（可选 daemon、timer）", mode))
+	}
+
+	// 单元以根目录为工作目录启动，配置文件使用绝对路径，配置中的相对路径相对于配置文件所在目录
+	configFile, err := filepath.Abs(configFile)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(configFile); err != nil {
+		return configError(i18n.Errorf("配置文件不存在: %s", configFile))
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if user {
+		runAs = ""
+	}
+
+	units := systemdUnits(name, mode, exe, configFile, onCalendar, runAs, user)
+	if printOnly {
+		for _, unit := range units {
+			fmt.Printf("# %s\n%s\n", unit.name, unit.content)
+		}
+		return nil
+	}
+
+	dir, err := systemdUnitDir(user)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return i18n.Errorf("写入单元文件失败: %w", err)
+	}
+	for _, unit := range units {
+		path := filepath.Join(dir, unit.name)
+		if err := os.WriteFile(path, []byte(unit.content), 0644); err != nil {
+			return i18n.Errorf("写入单元文件失败: %w", err)
+		}
+		i18n.Printf("已写入: %s\n", path)
+	}
+
+	if err := systemctl(user, "daemon-reload"); err != nil {
+		return err
+	}
+	// 定时器模式启用的是定时器，服务由定时器触发
+	enabled := units[len(units)-1].name
+	if noEnable {
+		i18n.Printf("使用 systemctl%s enable --now %s 启用\n", systemctlUserFlag(user), enabled)
+		return nil
+	}
+	if err := systemctl(user, "enable", "--now", enabled); err != nil {
+		return err
+	}
+	i18n.Printf("已启用并启动 %s，使用 journalctl%s -u %s.service 查看日志\n", enabled, systemctlUserFlag(user), name)
+	return nil
+}
+
+func (a *App) runSystemdUninstall(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+	user, _ := cmd.Flags().GetBool("user")
+
+	dir, err := systemdUnitDir(user)
+	if err != nil {
+		return err
+	}
+
+	removed := 0
+	for _, unit := range []string{name + ".timer", name + ".service"} {
+		path := filepath.Join(dir, unit)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		// 停止失败（如单元未运行）不影响删除
+		systemctl(user, "disable", "--now", unit)
+		if err := os.Remove(path); err != nil {
+			return i18n.Errorf("删除单元文件失败: %w", err)
+		}
+		i18n.Printf("已删除: %s\n", path)
+		removed++
+	}
+	if removed == 0 {
+		return i18n.Errorf("%s 中没有 %s 的单元文件", dir, name)
+	}
+	return systemctl(user, "daemon-reload")
+}
+
+// systemdUnits 生成单元文件，定时器模式下最后一个是定时器
+func systemdUnits(name, mode, exe, configFile, onCalendar, runAs string, user bool) []systemdUnit {
+	dir := filepath.Dir(configFile)
+
+	var service strings.Builder
+	service.WriteString("[Unit]\n")
+	service.WriteString("Description=ObjectSync object storage sync\n")
+	service.WriteString("Wants=network-online.target\n")
+	service.WriteString("After=network-online.target\n\n")
+	service.WriteString("[Service]\n")
+	if mode == systemdDaemon {
+		// daemon启动完成后发送READY=1，并在主循环中发送看门狗心跳
+		service.WriteString("Type=notify\n")
+		fmt.Fprintf(&service, "ExecStart=%s daemon --config %s\n", systemdQuote(exe), systemdQuote(configFile))
+		service.WriteString("Restart=on-failure\n")
+		service.WriteString("RestartSec=30\n")
+		service.WriteString("WatchdogSec=120\n")
+		// 退出时等待正在运行的桶结束
+		service.WriteString("TimeoutStopSec=infinity\n")
+	} else {
+		service.WriteString("Type=oneshot\n")
+		fmt.Fprintf(&service, "ExecStart=%s run --config %s --non-interactive\n", systemdQuote(exe), systemdQuote(configFile))
+	}
+	fmt.Fprintf(&service, "WorkingDirectory=%s\n", systemdQuote(dir))
+	if runAs != "" {
+		fmt.Fprintf(&service, "User=%s\n", runAs)
+	}
+	if mode == systemdDaemon {
+		service.WriteString("\n[Install]\n")
+		service.WriteString("WantedBy=" + systemdTarget(user) + "\n")
+	}
+
+	units := []systemdUnit{{name: name + ".service", content: service.String()}}
+	if mode == systemdTimer {
+		var timer strings.Builder
+		timer.WriteString("[Unit]\n")
+		fmt.Fprintf(&timer, "Description=Run ObjectSync %s\n\n", onCalendar)
+		timer.WriteString("[Timer]\n")
+		fmt.Fprintf(&timer, "OnCalendar=%s\n", onCalendar)
+		// 错过的触发（如关机期间）在开机后补上
+		timer.WriteString("Persistent=true\n")
+		timer.WriteString("RandomizedDelaySec=60\n\n")
+		timer.WriteString("[Install]\n")
+		timer.WriteString("WantedBy=timers.target\n")
+		units = append(units, systemdUnit{name: name + ".timer", content: timer.String()})
+	}
+	return units
+}
+
+// systemdTarget 用户单元没有multi-user.target
+func systemdTarget(user bool) string {
+	if user {
+		return "default.target"
+	}
+	return "multi-user.target"
+}
+
+// systemdUnitDir 返回单元文件目录
+func systemdUnitDir(user bool) (string, error) {
+	if !user {
+		return "/etc/systemd/system", nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "systemd", "user"), nil
+}
+
+// systemdQuote 包含空白或引号的路径按systemd的规则加双引号
+func systemdQuote(value string) string {
+	if !strings.ContainsAny(value, " \t\"'\\") {
+		return value
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// systemctl 运行systemctl，输出直接显示给用户
+func systemctl(user bool, args ...string) error {
+	if user {
+		args = append([]string{"--user"}, args...)
+	}
+	command := exec.Command("systemctl", args...)
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	if err := command.Run(); err != nil {
+		return i18n.Errorf("systemctl %s 失败: %w", strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// systemctlUserFlag 提示命令中使用的 --user 参数
+func systemctlUserFlag(user bool) string {
+	if user {
+		return " --user"
+	}
+	return ""
+}
@@ -0,0 +1,538 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"objectsync/internal/config"
+	"objectsync/internal/i18n"
+	"objectsync/internal/progress"
+	"objectsync/internal/ratelimit"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// 菜单界面中任务的状态
+const (
+	jobIdle     = ""
+	jobRunning  = "running"
+	jobStopping = "stopping"
+	jobDone     = "done"
+	jobStopped  = "stopped"
+	jobFailed   = "failed"
+)
+
+// tuiRefreshInterval 刷新进度条的间隔
+const tuiRefreshInterval = 500 * time.Millisecond
+
+// menuJob 一个桶的同步任务
+type menuJob struct {
+	state   string
+	current transfer
+	stats   progress.Stats
+}
+
+// menuUI 全屏交互菜单：桶列表和进度、日志窗口、配置编辑
+type menuUI struct {
+	configFile string
+	settings   *config.MultiBucketSettings
+	limiter    *ratelimit.Limiter
+	jobs       map[string]*menuJob
+	mutex      sync.Mutex
+
+	app     *tview.Application
+	pages   *tview.Pages
+	table   *tview.Table
+	logView *tview.TextView
+	editor  *tview.TextArea
+}
+
+// runTUI 启动全屏交互菜单，程序输出显示在日志窗口中
+func (a *App) runTUI(configFile string) error {
+	ui := &menuUI{
+		configFile: configFile,
+		limiter:    ratelimit.New(0),
+		jobs:       make(map[string]*menuJob),
+	}
+	ui.build()
+
+	// 同步过程中的输出转到日志窗口，界面直接绘制到终端不受影响
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return i18n.Errorf("创建日志管道失败: %w", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	go io.Copy(ui.logView, reader)
+	defer func() {
+		os.Stdout = stdout
+		writer.Close()
+	}()
+
+	ui.reload()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(tuiRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ui.app.QueueUpdateDraw(ui.refresh)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	if err := ui.app.Run(); err != nil {
+		return i18n.Errorf("启动交互界面失败: %w", err)
+	}
+	return nil
+}
+
+// build 创建界面组件和快捷键
+func (ui *menuUI) build() {
+	ui.app = tview.NewApplication()
+
+	ui.table = tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	ui.table.SetBorder(true).SetTitle(" ObjectSync ")
+
+	ui.logView = tview.NewTextView().SetMaxLines(1000).SetChangedFunc(func() {
+		ui.app.Draw()
+	})
+	ui.logView.SetBorder(true).SetTitle(i18n.Sprintf(" 日志 "))
+
+	help := tview.NewTextView().SetText(i18n.Sprintf("Enter 开始  a 全部开始  x 停止  t 测试连接  e 编辑配置  r 重新加载  c 清空日志  q 退出"))
+
+	main := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(ui.table, 0, 1, true).
+		AddItem(ui.logView, 0, 1, false).
+		AddItem(help, 1, 0, false)
+
+	ui.editor = tview.NewTextArea()
+	ui.editor.SetBorder(true).SetTitle(i18n.Sprintf(" %s （Ctrl-S 保存，Esc 返回） ", ui.configFile))
+
+	ui.pages = tview.NewPages().
+		AddPage("main", main, true, true).
+		AddPage("editor", ui.editor, true, false)
+
+	ui.table.SetSelectedFunc(func(row, column int) {
+		ui.start(ui.selectedBucket())
+	})
+	ui.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'a':
+			ui.startAll()
+		case 'x':
+			ui.stop(ui.selectedBucket())
+		case 't':
+			ui.testConnections()
+		case 'e':
+			ui.openEditor()
+		case 'r':
+			ui.reload()
+		case 'c':
+			ui.logView.Clear()
+		case 'q':
+			ui.quit()
+		default:
+			return event
+		}
+		return nil
+	})
+	ui.editor.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyCtrlS:
+			ui.saveConfig()
+		case tcell.KeyEscape:
+			ui.pages.SwitchToPage("main")
+		default:
+			return event
+		}
+		return nil
+	})
+	ui.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// Ctrl-C与q相同，避免中断正在运行的任务
+		if event.Key() == tcell.KeyCtrlC {
+			ui.quit()
+			return nil
+		}
+		return event
+	})
+
+	ui.app.SetRoot(ui.pages, true)
+}
+
+// logf 向日志窗口写入一行
+func (ui *menuUI) logf(format string, args ...interface{}) {
+	fmt.Fprintf(ui.logView, "[%s] %s\n", time.Now().Format("15:04:05"), i18n.Sprintf(format, args...))
+}
+
+// busy 是否有任务正在运行
+func (ui *menuUI) busy() bool {
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	for _, job := range ui.jobs {
+		if job.state == jobRunning || job.state == jobStopping {
+			return true
+		}
+	}
+	return false
+}
+
+// reload 重新加载配置文件，有任务运行时不重新加载
+func (ui *menuUI) reload() {
+	if ui.busy() {
+		ui.logf("有任务正在运行，完成后再重新加载配置")
+		return
+	}
+
+	configManager := config.NewConfigManager(ui.configFile)
+	if _, err := configManager.LoadConfig(); err != nil {
+		ui.logf("配置加载失败: %v", err)
+		ui.logf("按 e 编辑配置文件")
+		return
+	}
+	if err := configManager.ValidateConfig(); err != nil {
+		ui.logf("配置验证失败: %v", err)
+		ui.logf("按 e 编辑配置文件")
+		return
+	}
+
+	ui.mutex.Lock()
+	ui.settings = configManager.ToBucketSettings()
+	ui.jobs = make(map[string]*menuJob)
+	ui.mutex.Unlock()
+
+	ui.logf("已加载配置 %s（共 %d 个桶）", ui.configFile, len(ui.settings.Buckets))
+	ui.refresh()
+}
+
+// refresh 重新绘制桶列表和进度
+func (ui *menuUI) refresh() {
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	row, _ := ui.table.GetSelection()
+	ui.table.Clear()
+
+	headers := []string{i18n.T("桶"), i18n.T("方向"), i18n.T("状态"), i18n.T("进度"), i18n.T("文件"), i18n.T("数据量")}
+	for column, header := range headers {
+		ui.table.SetCell(0, column, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false))
+	}
+	if ui.settings == nil {
+		return
+	}
+
+	// 同时运行多个桶时在最后一行显示合计
+	var total progress.Snapshot
+	started, running := 0, 0
+
+	for i, bucket := range ui.settings.Buckets {
+		job := ui.jobs[bucket.Name]
+		if job == nil {
+			job = &menuJob{}
+		}
+
+		snapshot := progress.Snapshot{Files: job.stats.Files, Bytes: job.stats.Bytes}
+		if job.current != nil && (job.state == jobRunning || job.state == jobStopping) {
+			snapshot = job.current.Progress()
+			running++
+		}
+		if job.state != jobIdle {
+			started++
+			total.Files += snapshot.Files
+			total.Bytes += snapshot.Bytes
+			total.TotalBytes += max(snapshot.TotalBytes, snapshot.Bytes)
+		}
+
+		cells := []string{
+			bucket.Name,
+			directionLabel(bucket.Direction),
+			jobLabel(job.state),
+			progressBar(job.state, snapshot),
+			fmt.Sprintf("%d", snapshot.Files),
+			progress.FormatSize(snapshot.Bytes),
+		}
+		for column, text := range cells {
+			cell := tview.NewTableCell(tview.Escape(text))
+			if column == 0 {
+				cell.SetReference(bucket.Name)
+			}
+			if column == 2 {
+				cell.SetTextColor(jobColor(job.state))
+			}
+			ui.table.SetCell(i+1, column, cell)
+		}
+	}
+
+	if started > 1 {
+		cells := []string{
+			i18n.T("合计"),
+			"",
+			i18n.Sprintf("%d 个运行中", running),
+			progressBar(jobRunning, total),
+			fmt.Sprintf("%d", total.Files),
+			progress.FormatSize(total.Bytes),
+		}
+		for column, text := range cells {
+			ui.table.SetCell(len(ui.settings.Buckets)+1, column, tview.NewTableCell(tview.Escape(text)).
+				SetTextColor(tcell.ColorYellow).
+				SetSelectable(false))
+		}
+	}
+
+	if row < 1 {
+		row = 1
+	}
+	ui.table.Select(row, 0)
+}
+
+// selectedBucket 返回当前选中的桶名，没有桶时返回空字符串
+func (ui *menuUI) selectedBucket() string {
+	row, _ := ui.table.GetSelection()
+	if row < 1 {
+		return ""
+	}
+	name, _ := ui.table.GetCell(row, 0).GetReference().(string)
+	return name
+}
+
+// startAll 开始所有没有在运行的桶
+func (ui *menuUI) startAll() {
+	if ui.settings == nil {
+		return
+	}
+	for _, bucket := range ui.settings.Buckets {
+		ui.start(bucket.Name)
+	}
+}
+
+// start 在后台开始同步指定的桶，桶已在运行时忽略
+func (ui *menuUI) start(name string) {
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	if ui.settings == nil || name == "" {
+		return
+	}
+	var bucketSettings config.BucketSettings
+	found := false
+	for _, bucket := range ui.settings.Buckets {
+		if bucket.Name == name {
+			bucketSettings, found = bucket, true
+			break
+		}
+	}
+	if !found {
+		return
+	}
+
+	job := ui.jobs[name]
+	if job != nil && (job.state == jobRunning || job.state == jobStopping) {
+		return
+	}
+	job = &menuJob{state: jobRunning}
+	ui.jobs[name] = job
+	settings := ui.settings
+
+	ui.logf("开始%s桶: %s", directionLabel(bucketSettings.Direction), name)
+	go func() {
+		stats, err := runBucketTransfers(settings, bucketSettings, ui.limiter, false, false, nil, func(current transfer) {
+			ui.mutex.Lock()
+			defer ui.mutex.Unlock()
+
+			job.current = current
+			if job.state == jobStopping {
+				current.Stop()
+			}
+		})
+
+		ui.mutex.Lock()
+		job.stats = stats
+		switch {
+		case err == nil:
+			job.state = jobDone
+		case job.state == jobStopping:
+			job.state = jobStopped
+		default:
+			job.state = jobFailed
+		}
+		state := job.state
+		ui.mutex.Unlock()
+
+		switch state {
+		case jobDone:
+			ui.logf("桶 %s 同步完成!", name)
+		case jobStopped:
+			ui.logf("桶 %s 已停止", name)
+		default:
+			ui.logf("桶 %s 同步失败: %v", name, err)
+		}
+
+		results := newRunResults("menu")
+		results.add(name, stats, err)
+		finishRun(settings, results)
+
+		ui.app.QueueUpdateDraw(ui.refresh)
+	}()
+}
+
+// stop 停止指定的桶，正在传输的文件完成后停止
+func (ui *menuUI) stop(name string) {
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	job := ui.jobs[name]
+	if job == nil || job.state != jobRunning {
+		return
+	}
+	job.state = jobStopping
+	if job.current != nil {
+		job.current.Stop()
+	}
+	ui.logf("正在停止桶 %s，等待正在传输的文件完成...", name)
+}
+
+// quit 退出界面，有任务运行时要求先停止
+func (ui *menuUI) quit() {
+	if ui.busy() {
+		ui.logf("有任务正在运行，请先按 x 停止或等待完成")
+		return
+	}
+	ui.app.Stop()
+}
+
+// openEditor 打开配置编辑器
+func (ui *menuUI) openEditor() {
+	data, err := os.ReadFile(ui.configFile)
+	if err != nil && !os.IsNotExist(err) {
+		ui.logf("读取配置文件失败: %v", err)
+		return
+	}
+	ui.editor.SetText(string(data), false)
+	ui.pages.SwitchToPage("editor")
+	ui.app.SetFocus(ui.editor)
+}
+
+// saveConfig 验证编辑后的配置，通过后才覆盖配置文件
+func (ui *menuUI) saveConfig() {
+	temp, err := writeConfigDraft(ui.configFile, []byte(ui.editor.GetText()))
+	if err != nil {
+		ui.showError(err.Error())
+		return
+	}
+	defer os.Remove(temp)
+
+	if _, err := validateConfigFile(temp); err != nil {
+		ui.showError(err.Error())
+		return
+	}
+	if err := replaceConfigFile(temp, ui.configFile); err != nil {
+		ui.showError(err.Error())
+		return
+	}
+
+	ui.logf("配置文件已保存: %s", ui.configFile)
+	ui.pages.SwitchToPage("main")
+	ui.app.SetFocus(ui.table)
+	ui.reload()
+}
+
+// testConnections 在后台测试每个桶的连接，结果写入日志窗口
+func (ui *menuUI) testConnections() {
+	ui.mutex.Lock()
+	settings := ui.settings
+	ui.mutex.Unlock()
+	if settings == nil {
+		return
+	}
+
+	ui.logf("开始测试 %d 个桶的连接...", len(settings.Buckets))
+	go func() {
+		for _, bucket := range settings.Buckets {
+			elapsed, err := testBucketConnection(settings, bucket)
+			if err != nil {
+				ui.logf("[失败] %s (%s): %v", bucketPath(bucket), bucket.Endpoint, err)
+				continue
+			}
+			ui.logf("[通过] %s (%s)，用时 %s", bucketPath(bucket), bucket.Endpoint, elapsed.Round(time.Millisecond))
+		}
+	}()
+}
+
+// showError 在编辑器上方显示错误，关闭后返回编辑器
+func (ui *menuUI) showError(message string) {
+	modal := tview.NewModal().
+		SetText(message).
+		AddButtons([]string{i18n.T("确定")}).
+		SetDoneFunc(func(int, string) {
+			ui.pages.RemovePage("error")
+			ui.app.SetFocus(ui.editor)
+		})
+	ui.pages.AddPage("error", modal, true, true)
+	ui.app.SetFocus(modal)
+}
+
+// jobLabel 返回任务状态在当前语言下的名称
+func jobLabel(state string) string {
+	switch state {
+	case jobRunning:
+		return i18n.T("运行中")
+	case jobStopping:
+		return i18n.T("正在停止")
+	case jobDone:
+		return i18n.T("完成")
+	case jobStopped:
+		return i18n.T("已停止")
+	case jobFailed:
+		return i18n.T("失败")
+	default:
+		return i18n.T("空闲")
+	}
+}
+
+// jobColor 返回任务状态的显示颜色
+func jobColor(state string) tcell.Color {
+	switch state {
+	case jobRunning, jobStopping:
+		return tcell.ColorAqua
+	case jobDone:
+		return tcell.ColorGreen
+	case jobFailed:
+		return tcell.ColorRed
+	default:
+		return tcell.ColorWhite
+	}
+}
+
+// progressBar 生成按数据量计算的进度条，没有运行过的任务返回空字符串
+func progressBar(state string, snapshot progress.Snapshot) string {
+	const width = 20
+
+	var percent float64
+	switch {
+	case state == jobIdle:
+		return ""
+	case state == jobDone:
+		percent = 100
+	case snapshot.TotalBytes > 0:
+		percent = float64(snapshot.Bytes) / float64(snapshot.TotalBytes) * 100
+	}
+	if percent > 100 {
+		percent = 100
+	}
+
+	filled := int(percent / 100 * width)
+	return fmt.Sprintf("%s%s %5.1f%%", strings.Repeat("█", filled), strings.Repeat("░", width-filled), percent)
+}
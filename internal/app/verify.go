@@ -0,0 +1,208 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"objectsync/internal/backup"
+	"objectsync/internal/config"
+	"objectsync/internal/i18n"
+	"objectsync/internal/ratelimit"
+	"objectsync/internal/upload"
+
+	"github.com/spf13/cobra"
+)
+
+// maxListedMismatches 非详细模式下每个桶最多列出的不一致文件数，完整列表见报告文件
+const maxListedMismatches = 20
+
+// verifyReport 单个桶的校验结果
+type verifyReport struct {
+	Bucket string `json:"bucket"`
+	Error  string `json:"error,omitempty"`
+	backup.VerifyResult
+}
+
+// verifyReportFile --report 写入的校验报告
+type verifyReportFile struct {
+	Time    time.Time      `json:"time"`
+	Deep    bool           `json:"deep"`
+	Buckets []verifyReport `json:"buckets"`
+}
+
+func (a *App) newVerifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "校验本地备份与远程对象是否一致",
+		Long:  "逐个比较远程对象与本地备份文件的存在性和大小，并检查状态记录是否过期；--deep 重新计算本地文件的内容，与下载时记录的SHA-256、上传时附加的校验值（--checksum-algorithm）或远程ETag比较",
+		RunE:  a.withReport(a.runVerify),
+	}
+
+	cmd.Flags().StringP("config", "c", "", configFlagUsage)
+	cmd.Flags().StringP("endpoint", "e", "", "Ceph对象存储端点URL (覆盖配置文件)")
+	cmd.Flags().StringP("access-key", "a", "", "访问密钥 (覆盖配置文件)")
+	cmd.Flags().StringP("secret-key", "s", "", "秘密密钥 (覆盖配置文件)")
+	cmd.Flags().String("region", "", "请求签名使用的区域 (覆盖配置文件)")
+	cmd.Flags().String("cluster", "", "只处理指定集群（clusters 或 remotes 中的名称）下的桶")
+	cmd.Flags().Bool("deep", false, "重新计算本地文件的SHA-256与下载时的记录比较，没有记录时计算MD5与远程ETag比较（分片上传的对象只比较大小）")
+	cmd.Flags().String("checksum-algorithm", "", "深度校验时优先比较对象上传时附加的该算法的校验值，分片上传的组合校验值除外 (CRC32, CRC32C, SHA1, SHA256)")
+	cmd.Flags().String("report", "", "将所有不一致的文件写入JSON报告文件")
+	cmd.Flags().IntP("workers", "w", 5, "深度校验时并发计算MD5的工作数")
+	cmd.Flags().Float64("max-requests-per-second", 0, "每秒最多发送的请求数，所有桶和工作协程共享 (0表示不限制)")
+	cmd.Flags().BoolP("verbose", "v", false, "详细输出，列出所有不一致的文件")
+	addBucketFlags(cmd)
+
+	return cmd
+}
+
+func (a *App) runVerify(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	endpoint, _ := cmd.Flags().GetString("endpoint")
+	accessKey, _ := cmd.Flags().GetString("access-key")
+	secretKey, _ := cmd.Flags().GetString("secret-key")
+	region, _ := cmd.Flags().GetString("region")
+	cluster, _ := cmd.Flags().GetString("cluster")
+	buckets, _ := cmd.Flags().GetStringSlice("bucket")
+	excluded, _ := cmd.Flags().GetStringSlice("exclude-bucket")
+	deep, _ := cmd.Flags().GetBool("deep")
+	checksumName, _ := cmd.Flags().GetString("checksum-algorithm")
+	reportFile, _ := cmd.Flags().GetString("report")
+	workers, _ := cmd.Flags().GetInt("workers")
+	maxRequests, _ := cmd.Flags().GetFloat64("max-requests-per-second")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	checksumAlgorithm, err := upload.ParseChecksumAlgorithm(checksumName)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	configManager := config.NewConfigManager(configFile)
+	if _, err := configManager.LoadConfig(); err != nil {
+		return configError(i18n.Errorf("配置加载失败: %w", err))
+	}
+	if err := configManager.ValidateConfig(); err != nil {
+		return configError(i18n.Errorf("配置验证失败: %w", err))
+	}
+
+	settings := configManager.ToBucketSettings()
+	if cluster != "" {
+		if err := settings.FilterCluster(cluster); err != nil {
+			return withExitCode(ExitUsage, err)
+		}
+	}
+	if err := settings.FilterBuckets(buckets); err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	if err := settings.ExcludeBuckets(excluded); err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	// 复制和上传的桶没有下载到本地的副本
+	if err := settings.ExcludeDirection(config.DirectionReplicate, config.DirectionUpload); err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	settings.OverrideConnection(endpoint, accessKey, secretKey, region)
+
+	limiter := ratelimit.New(maxRequests)
+	bucketCount := len(settings.Buckets)
+	i18n.Printf("开始校验（共 %d 个桶）\n", bucketCount)
+	i18n.Printf("连接信息: %s\n", bucketEndpoints(settings))
+
+	successCount, mismatchCount := 0, 0
+	var failures []error
+	report := verifyReportFile{Time: time.Now(), Deep: deep}
+
+	for i, bucketSettings := range settings.Buckets {
+		i18n.Printf("\n[%d/%d] 校验桶: %s -> %s\n", i+1, bucketCount, bucketSettings.Name, bucketSettings.OutputDir)
+
+		options := bucketBackupOptions(settings, bucketSettings, limiter)
+		options.Workers = workers
+		options.Checksum = checksumAlgorithm
+		options.Verbose = options.Verbose || verbose
+
+		result, err := backup.New(options).Verify(deep)
+		bucket := verifyReport{Bucket: bucketSettings.Name}
+		if err != nil {
+			bucket.Error = err.Error()
+			i18n.Printf("桶 %s 校验失败: %v\n", bucketSettings.Name, err)
+			failures = append(failures, err)
+		} else {
+			bucket.VerifyResult = *result
+			printVerifyResult(result, options.Verbose)
+			if len(result.Mismatches) > 0 {
+				mismatchCount += len(result.Mismatches)
+				failures = append(failures, objectsFailed(i18n.Errorf("桶 %s 有 %d 个不一致的文件", bucketSettings.Name, len(result.Mismatches))))
+			} else {
+				successCount++
+			}
+		}
+		report.Buckets = append(report.Buckets, bucket)
+		a.report.addVerify(bucket)
+	}
+
+	if reportFile != "" {
+		if err := writeVerifyReport(reportFile, report); err != nil {
+			return err
+		}
+		i18n.Printf("\n校验报告已写入: %s\n", reportFile)
+	}
+
+	i18n.Printf("\n校验完成!\n")
+	i18n.Printf("一致: %d 个桶\n", successCount)
+	if len(failures) > 0 {
+		i18n.Printf("不一致或失败: %d 个桶（%d 个不一致的文件）\n", len(failures), mismatchCount)
+		return bucketsError(i18n.Errorf("校验发现问题"), successCount, failures)
+	}
+
+	return nil
+}
+
+// printVerifyResult 打印一个桶的校验总结和不一致的文件，非详细模式下只列出前几个
+func printVerifyResult(result *backup.VerifyResult, verbose bool) {
+	i18n.Printf("校验 %d 个对象，%d 个不一致", result.Checked, len(result.Mismatches))
+	if result.Skipped > 0 {
+		i18n.Printf("，跳过 %d 个打包对象", result.Skipped)
+	}
+	fmt.Println()
+
+	for i, mismatch := range result.Mismatches {
+		if !verbose && i == maxListedMismatches {
+			i18n.Printf("  ……还有 %d 个，使用 --verbose 或 --report 查看全部\n", len(result.Mismatches)-i)
+			break
+		}
+		line := fmt.Sprintf("  [%s] %s", mismatchLabel(mismatch.Problem), mismatch.Path)
+		if mismatch.Detail != "" {
+			line += fmt.Sprintf(" (%s)", mismatch.Detail)
+		}
+		fmt.Println(line)
+	}
+}
+
+// mismatchLabel 返回问题类型在当前语言下的名称
+func mismatchLabel(problem string) string {
+	switch problem {
+	case backup.ProblemMissing:
+		return i18n.T("缺失")
+	case backup.ProblemSize:
+		return i18n.T("大小不一致")
+	case backup.ProblemChecksum:
+		return i18n.T("内容不一致")
+	case backup.ProblemOutdated:
+		return i18n.T("已过期")
+	default:
+		return i18n.T("读取失败")
+	}
+}
+
+// writeVerifyReport 将校验结果写入JSON报告文件
+func writeVerifyReport(path string, report verifyReportFile) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return i18n.Errorf("生成校验报告失败: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return i18n.Errorf("写入校验报告失败: %w", err)
+	}
+	return nil
+}
@@ -1,236 +1,454 @@
 package backup
 
 import (
-	"encoding/json"
-	"fmt"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"objectsync/internal/fileattr"
+	"objectsync/internal/filter"
+	"objectsync/internal/i18n"
+	"objectsync/internal/journal"
+	"objectsync/internal/logging"
+	"objectsync/internal/pack"
 	"objectsync/internal/progress"
-
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
+	"objectsync/internal/ratelimit"
+	"objectsync/internal/s3client"
+	"objectsync/internal/state"
+	"objectsync/internal/statestore"
+	"objectsync/internal/storage"
 )
 
+// logger 备份模块的日志
+var logger = logging.New("backup")
+
 // Options 备份配置选项
 type Options struct {
-	Endpoint    string
-	AccessKey   string
-	SecretKey   string
-	Bucket      string
-	OutputDir   string
-	Incremental bool
-	StateFile   string
-	Workers     int
-	Verbose     bool
-}
-
-// State 备份状态
-type State struct {
-	LastBackup time.Time            `json:"last_backup"`
-	Files      map[string]FileState `json:"files"`
-}
-
-// FileState 文件状态
-type FileState struct {
-	ETag         string    `json:"etag"`
-	LastModified time.Time `json:"last_modified"`
-	Size         int64     `json:"size"`
+	Endpoint           string
+	AccessKey          string
+	SecretKey          string
+	Profile            string                  // 共享凭证文件中的配置名，设置后忽略AccessKey和SecretKey
+	Region             string                  // 签名使用的区域，为空时使用默认值
+	VirtualHosted      bool                    // 使用虚拟主机样式寻址，默认使用路径样式
+	Transport          string                  // S3客户端实现，见 s3client.TransportMinio，为空时使用aws-sdk-go
+	TLS                s3client.TLSOptions     // HTTPS证书选项
+	Proxy              s3client.ProxyOptions   // 代理设置
+	Timeouts           s3client.TimeoutOptions // HTTP超时设置
+	Bucket             string
+	Prefix             string // 只备份该前缀下的对象（以/结尾），本地路径去掉前缀
+	OutputDir          string
+	Incremental        bool
+	StateFile          string
+	StateBackend       string        // 状态存储后端（statestore.BackendJSON/BackendBolt），空值使用JSON文件
+	CheckpointFiles    int           // 运行中每下载多少个对象保存一次状态，0表示不按数量保存
+	CheckpointInterval time.Duration // 运行中每隔多久保存一次状态，0表示不按时间保存
+	PruneDeletedAfter  time.Duration // 已从桶中删除的对象的记录在状态中保留的时间，0表示运行成功后立即清理
+	Workers            int
+	RateLimiter        *ratelimit.Limiter   // 请求速率限制器，可在多个桶之间共享
+	Bandwidth          *ratelimit.Bandwidth // 带宽限制器，同一个桶的所有工作协程共享
+	MaxAttempts        int                  // 单个请求的最大尝试次数
+	RetryDelay         time.Duration        // 首次重试前的等待时间，之后按指数增长
+	Decompress         bool                 // 下载时解压上传时压缩的对象，并去掉压缩后缀
+	Checksum           string               // 深度校验时比较的附加校验算法（见 storage.ChecksumSHA256 等），对象带有该算法的校验值时优先于ETag使用
+	Include            []string             // 包含模式，为空时包含所有对象
+	Exclude            []string             // 排除模式
+	Resume             bool                 // 上次运行中断时从运行日志继续，不重新列出对象
+	Parent             *progress.Tracker    // 汇总多个桶进度的跟踪器，可以为空
+	WorkerProgress     bool                 // 在终端中显示每个工作协程的进度，详细模式下不显示
+	Reporter           progress.Reporter    // 接收进度事件，设置后不在终端中输出进度，用于嵌入时显示自己的界面
+	Storage            storage.Storage      // 访问桶使用的存储，为nil时按连接选项创建S3客户端；可以换成其他后端或测试用的实现
+	Verbose            bool
 }
 
 // Backup 备份器
 type Backup struct {
-	options  *Options
-	s3       *s3.S3
-	state    *State
-	progress *progress.Tracker
+	options     *Options
+	storage     storage.Storage
+	state       statestore.Store[state.Entry] // 未启用增量备份时为nil
+	checkpoint  *statestore.Checkpoint[state.Entry]
+	progress    *progress.Tracker
+	pendingDirs []pendingDir
+	journal     *journal.Journal // 进行中运行的日志，没有需要下载的对象时为nil
+	stopped     atomic.Bool
+	mutex       sync.Mutex
+}
+
+// pendingDir 等待设置属性的目录
+type pendingDir struct {
+	path     string
+	attrs    fileattr.Attrs
+	fallback time.Time
 }
 
 // New 创建新的备份器
 func New(options *Options) *Backup {
+	tracker := progress.New(options.Verbose)
+	if options.Parent != nil {
+		tracker.SetParent(options.Parent)
+	}
+	if options.WorkerProgress && !options.Verbose {
+		tracker.ShowWorkers()
+	}
+	if options.Reporter != nil {
+		tracker.SetReporter(options.Reporter)
+	}
 	return &Backup{
 		options:  options,
-		state:    &State{Files: make(map[string]FileState)},
-		progress: progress.New(options.Verbose),
+		progress: tracker,
 	}
 }
 
 // Run 执行备份
 func (b *Backup) Run() error {
+	// 锁定状态文件，另一个运行正在改写时立即失败
+	lock, err := b.lockState()
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
 	// 初始化S3客户端
-	if err := b.initS3Client(); err != nil {
-		return fmt.Errorf("初始化S3客户端失败: %w", err)
+	if err := b.initStorage(); err != nil {
+		return i18n.Errorf("初始化S3客户端失败: %w", err)
 	}
 
 	// 加载备份状态
 	if err := b.loadState(); err != nil {
-		return fmt.Errorf("加载备份状态失败: %w", err)
+		return i18n.Errorf("加载备份状态失败: %w", err)
 	}
+	defer b.closeState()
 
 	// 创建输出目录
 	if err := os.MkdirAll(b.options.OutputDir, 0755); err != nil {
-		return fmt.Errorf("创建输出目录失败: %w", err)
+		return i18n.Errorf("创建输出目录失败: %w", err)
 	}
 
-	// 列出桶中的所有对象
-	objects, err := b.listObjects()
+	// 确定需要下载的对象，上次运行中断时从运行日志继续
+	objects, toDownload, err := b.plan()
 	if err != nil {
-		return fmt.Errorf("列出对象失败: %w", err)
-	}
-
-	if b.options.Verbose {
-		fmt.Printf("发现 %d 个对象\n", len(objects))
-	}
-
-	// 过滤需要下载的对象
-	toDownload := b.filterObjects(objects)
-	if b.options.Verbose {
-		fmt.Printf("需要下载 %d 个对象\n", len(toDownload))
+		return err
 	}
 
 	if len(toDownload) == 0 {
-		fmt.Println("没有需要下载的文件")
+		logger.Infof("没有需要下载的文件")
+		if b.journal != nil {
+			// 继续的运行在中断前已经下载完所有对象，只差保存状态
+			if err := b.saveState(objects); err != nil {
+				b.closeJournal()
+				return i18n.Errorf("保存备份状态失败: %w", err)
+			}
+			b.finishJournal()
+		} else if err := b.saveDeleted(objects); err != nil {
+			return i18n.Errorf("保存备份状态失败: %w", err)
+		}
 		return nil
 	}
 
 	// 计算总大小并设置进度跟踪
 	var totalSize int64
 	for _, obj := range toDownload {
-		totalSize += *obj.Size
+		totalSize += obj.Size
 	}
 	b.progress.SetTotal(int64(len(toDownload)), totalSize)
 
-	// 下载对象
-	if err := b.downloadObjects(toDownload); err != nil {
-		return fmt.Errorf("下载对象失败: %w", err)
+	// 下载对象，打包对象在普通对象之后按顺序解压
+	regular, packs := splitPacks(toDownload)
+	err = b.downloadObjects(regular)
+	if err == nil {
+		err = b.extractPacks(packs, objects)
+	}
+
+	// 所有文件写入完成后再设置目录属性
+	b.applyDirAttrs()
+
+	if err != nil {
+		b.closeJournal()
+		return i18n.Errorf("下载对象失败: %w", err)
 	}
 
 	// 显示最终统计信息
 	b.progress.PrintFinal()
 
-	// 更新备份状态
-	b.updateState(objects)
-
-	// 保存状态
-	if err := b.saveState(); err != nil {
-		return fmt.Errorf("保存备份状态失败: %w", err)
+	// 更新并保存备份状态
+	if err := b.saveState(objects); err != nil {
+		b.closeJournal()
+		return i18n.Errorf("保存备份状态失败: %w", err)
 	}
 
+	b.finishJournal()
 	return nil
 }
 
+// plan 列出对象并过滤出需要下载的对象；启用Resume且有上次中断的运行日志时直接使用日志中的计划
+func (b *Backup) plan() ([]storage.Object, []storage.Object, error) {
+	if b.options.Resume && b.options.StateFile != "" {
+		if objects, toDownload, ok := b.resumePlan(); ok {
+			return objects, toDownload, nil
+		}
+	}
+
+	// 列出桶中的所有对象
+	objects, err := b.listObjects()
+	if err != nil {
+		return nil, nil, i18n.Errorf("列出对象失败: %w", err)
+	}
+
+	logger.Debugf("发现 %d 个对象", len(objects))
+
+	// 过滤需要下载的对象
+	toDownload := b.filterObjects(objects)
+	logger.Debugf("需要下载 %d 个对象", len(toDownload))
+
+	if b.options.StateFile != "" {
+		// 不继续时丢弃上次留下的运行日志，避免以后误用过期的计划
+		os.Remove(journal.Path(b.options.StateFile))
+	}
+	if len(toDownload) > 0 && b.options.StateFile != "" {
+		if err := b.startJournal(objects, toDownload); err != nil {
+			// 运行日志只用于中断后继续，写不了不影响本次备份
+			logger.Warnf("无法创建运行日志: %v", err)
+		}
+	}
+	return objects, toDownload, nil
+}
+
+// Stop 停止备份，正在下载的对象完成后不再下载新的对象
+func (b *Backup) Stop() {
+	b.stopped.Store(true)
+}
+
+// Progress 返回本次备份的当前进度
+func (b *Backup) Progress() progress.Snapshot {
+	return b.progress.Snapshot()
+}
+
+// Stats 返回本次备份的下载统计
+func (b *Backup) Stats() progress.Stats {
+	return b.progress.Stats()
+}
+
+// Pending 列出远程对象并与状态记录和本地文件比较，返回下次备份需要下载的对象数和数据量
+func (b *Backup) Pending() (int64, int64, error) {
+	if err := b.initStorage(); err != nil {
+		return 0, 0, i18n.Errorf("初始化S3客户端失败: %w", err)
+	}
+	if err := b.loadState(); err != nil {
+		return 0, 0, i18n.Errorf("加载备份状态失败: %w", err)
+	}
+	defer b.closeState()
+
+	objects, err := b.listObjects()
+	if err != nil {
+		return 0, 0, i18n.Errorf("列出对象失败: %w", err)
+	}
+
+	var count, size int64
+	for _, obj := range b.filterObjects(objects) {
+		count++
+		size += obj.Size
+	}
+	return count, size, nil
+}
+
 // TestConnection 测试连接
 func (b *Backup) TestConnection() error {
 	// 初始化S3客户端
-	if err := b.initS3Client(); err != nil {
+	if err := b.initStorage(); err != nil {
 		return err
 	}
 
-	// 尝试列出桶内容(仅获取第一页)
-	input := &s3.ListObjectsV2Input{
-		Bucket:  aws.String(b.options.Bucket),
-		MaxKeys: aws.Int64(1),
+	// 尝试列出桶内容，读到第一个对象就停止
+	err := b.storage.List(b.options.Prefix, func(storage.Object) error {
+		return errStopList
+	})
+	if err == errStopList {
+		return nil
 	}
-
-	_, err := b.s3.ListObjectsV2(input)
 	return err
 }
 
-// initS3Client 初始化S3客户端
-func (b *Backup) initS3Client() error {
-	sess, err := session.NewSession(&aws.Config{
-		Endpoint:         aws.String(b.options.Endpoint),
-		Credentials:      credentials.NewStaticCredentials(b.options.AccessKey, b.options.SecretKey, ""),
-		Region:           aws.String("us-east-1"), // Ceph通常使用us-east-1
-		S3ForcePathStyle: aws.Bool(true),          // Ceph需要路径样式
-	})
-	if err != nil {
-		return err
+// errStopList 读到需要的对象后停止列出
+var errStopList = errors.New("stop")
+
+// ListBuckets 列出当前凭证可以访问的所有桶
+func (b *Backup) ListBuckets() ([]string, error) {
+	if err := b.initStorage(); err != nil {
+		return nil, err
 	}
 
-	b.s3 = s3.New(sess)
-	return nil
+	return b.storage.ListBuckets()
 }
 
-// loadState 加载备份状态
-func (b *Backup) loadState() error {
-	if !b.options.Incremental {
+// initStorage 初始化访问桶的存储，选项中指定了存储时直接使用，否则按端点的类型打开
+func (b *Backup) initStorage() error {
+	if b.options.Storage != nil {
+		b.storage = b.options.Storage
 		return nil
 	}
 
-	file, err := os.Open(b.options.StateFile)
+	store, err := storage.Open(s3client.Options{
+		Endpoint:      b.options.Endpoint,
+		AccessKey:     b.options.AccessKey,
+		SecretKey:     b.options.SecretKey,
+		Profile:       b.options.Profile,
+		Region:        b.options.Region,
+		VirtualHosted: b.options.VirtualHosted,
+		Transport:     b.options.Transport,
+		RateLimiter:   b.options.RateLimiter,
+		Bandwidth:     b.options.Bandwidth,
+		MaxAttempts:   b.options.MaxAttempts,
+		RetryDelay:    b.options.RetryDelay,
+		TLS:           b.options.TLS,
+		Proxy:         b.options.Proxy,
+		Timeouts:      b.options.Timeouts,
+	}, b.options.Bucket)
 	if err != nil {
-		if os.IsNotExist(err) {
-			// 状态文件不存在，使用默认状态
-			return nil
-		}
 		return err
 	}
-	defer file.Close()
 
-	return json.NewDecoder(file).Decode(b.state)
+	b.storage = store
+	return nil
 }
 
-// saveState 保存备份状态
-func (b *Backup) saveState() error {
+// loadState 打开备份状态，状态文件不存在时使用空的状态
+func (b *Backup) loadState() error {
 	if !b.options.Incremental {
 		return nil
 	}
 
-	b.state.LastBackup = time.Now()
-
-	file, err := os.Create(b.options.StateFile)
+	store, err := state.Open(b.options.StateFile, state.Backup, b.options.StateBackend)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	b.state = store
+	b.checkpoint = statestore.NewCheckpoint(store, b.options.CheckpointFiles, b.options.CheckpointInterval)
+	return nil
+}
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(b.state)
+// lockState 锁定备份状态文件，没有状态文件时不锁定。未启用增量备份时运行日志仍然写在状态文件旁边，同样需要锁定
+func (b *Backup) lockState() (*statestore.Lock, error) {
+	if b.options.StateFile == "" {
+		return nil, nil
+	}
+	return statestore.Acquire(statestore.Path(b.options.StateFile, b.options.StateBackend))
 }
 
-// listObjects 列出桶中的所有对象
-func (b *Backup) listObjects() ([]*s3.Object, error) {
-	var objects []*s3.Object
+// closeState 关闭备份状态。运行失败或被停止时先保存已下载完成的对象，下次运行不再重新下载
+func (b *Backup) closeState() {
+	if b.state == nil {
+		return
+	}
+	if unsaved := b.checkpoint.Unsaved(); unsaved > 0 {
+		if err := b.checkpoint.Flush(); err != nil {
+			logger.Warnf("保存备份状态失败: %v", err)
+		} else {
+			logger.Infof("已保存中断前下载完成的 %d 个对象的状态", unsaved)
+		}
+	}
+	b.state.Close()
+	b.state = nil
+	b.checkpoint = nil
+}
 
-	input := &s3.ListObjectsV2Input{
-		Bucket: aws.String(b.options.Bucket),
+// saveState 按本次的对象列表更新备份状态并保存
+func (b *Backup) saveState(objects []storage.Object) error {
+	if b.state == nil {
+		return nil
 	}
 
-	for {
-		result, err := b.s3.ListObjectsV2(input)
-		if err != nil {
-			return nil, err
-		}
+	if err := b.updateState(objects); err != nil {
+		return err
+	}
+	if _, err := b.pruneState(); err != nil {
+		return err
+	}
+	b.state.SetLastRun(time.Now())
+	return b.checkpoint.Flush()
+}
 
-		objects = append(objects, result.Contents...)
+// checkpointDone 记录下载完成的对象及本地内容的SHA-256，定期保存状态
+func (b *Backup) checkpointDone(obj storage.Object, sum string) {
+	if b.checkpoint == nil {
+		return
+	}
+	entry := objectState(obj)
+	entry.SHA256 = sum
+	if err := b.checkpoint.Done(obj.Key, entry); err != nil {
+		// 结束时还会再保存一次，检查点失败不影响本次备份
+		logger.Warnf("保存备份状态失败: %v", err)
+	}
+}
 
-		if !*result.IsTruncated {
-			break
-		}
+// saveDeleted 没有需要下载的对象时只记录从桶中消失的对象并清理过期的删除记录，没有变化时不改写状态
+func (b *Backup) saveDeleted(objects []storage.Object) error {
+	if b.state == nil {
+		return nil
+	}
+	deleted, err := b.markDeleted(objects)
+	if err != nil {
+		return err
+	}
+	pruned, err := b.pruneState()
+	if err != nil || deleted+pruned == 0 {
+		return err
+	}
+	return b.checkpoint.Flush()
+}
 
-		input.ContinuationToken = result.NextContinuationToken
+// pruneState 清理超过保留时间的删除记录，返回清理的条目数
+func (b *Backup) pruneState() (int, error) {
+	pruned, err := state.Prune(b.state, b.options.PruneDeletedAfter)
+	if pruned > 0 {
+		logger.Infof("已从状态中清理 %d 个已删除对象的记录", pruned)
 	}
+	return pruned, err
+}
 
+// recorded 返回状态中记录的对象，未启用增量备份时总是不存在，已删除的对象视为不存在
+func (b *Backup) recorded(key string) (state.Entry, bool) {
+	if b.state == nil {
+		return state.Entry{}, false
+	}
+	entry, ok := b.state.Get(key)
+	return entry, ok && !entry.Deleted()
+}
+
+// listObjects 列出桶中（前缀下）的所有对象
+func (b *Backup) listObjects() ([]storage.Object, error) {
+	var objects []storage.Object
+	err := b.storage.List(b.options.Prefix, func(obj storage.Object) error {
+		objects = append(objects, obj)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 	return objects, nil
 }
 
 // filterObjects 过滤需要下载的对象
-func (b *Backup) filterObjects(objects []*s3.Object) []*s3.Object {
-	var toDownload []*s3.Object
+func (b *Backup) filterObjects(objects []storage.Object) []storage.Object {
+	var toDownload []storage.Object
+	include := filter.New(b.options.Include, b.options.Exclude)
 
 	for _, obj := range objects {
-		key := *obj.Key
+		key := obj.Key
 
-		// 跳过空文件名
-		if key == "" {
+		// 跳过空文件名和打包索引（索引仅用于查看打包内容）
+		if key == "" || pack.IsIndex(key) {
+			continue
+		}
+
+		// 跳过不匹配包含/排除规则的对象，打包对象解包时再逐个过滤
+		if !pack.IsPack(key) && !include.MatchRelative(key, b.options.Prefix) {
 			continue
 		}
 
@@ -241,21 +459,19 @@ func (b *Backup) filterObjects(objects []*s3.Object) []*s3.Object {
 		}
 
 		// 对于目录标记（以/结尾且大小为0），检查本地目录是否存在
-		if strings.HasSuffix(key, "/") && *obj.Size == 0 {
-			localPath := filepath.Join(b.options.OutputDir, key)
+		if strings.HasSuffix(key, "/") && obj.Size == 0 {
+			localPath := b.localPath(key)
 			if _, err := os.Stat(localPath); os.IsNotExist(err) {
 				// 目录不存在，需要创建
 				toDownload = append(toDownload, obj)
-			} else if b.options.Verbose {
-				fmt.Printf("目录已存在: %s\n", key)
+			} else {
+				logger.Debugf("目录已存在: %s", key)
 			}
 			continue
 		}
 
-		etag := strings.Trim(*obj.ETag, "\"")
-
 		// 检查文件是否需要下载
-		if b.needsDownload(key, etag, *obj.LastModified, *obj.Size) {
+		if b.needsDownload(key, obj.ETag, obj.LastModified, obj.Size) {
 			toDownload = append(toDownload, obj)
 		}
 	}
@@ -263,31 +479,39 @@ func (b *Backup) filterObjects(objects []*s3.Object) []*s3.Object {
 	return toDownload
 }
 
+// localPath 返回对象在输出目录中的本地路径，去掉配置的前缀
+func (b *Backup) localPath(key string) string {
+	return filepath.Join(b.options.OutputDir, strings.TrimPrefix(key, b.options.Prefix))
+}
+
 // needsDownload 检查文件是否需要下载
 func (b *Backup) needsDownload(key, etag string, lastModified time.Time, size int64) bool {
 	// 检查本地路径是否存在
-	localPath := filepath.Join(b.options.OutputDir, key)
+	localPath := b.localPath(key)
 
-	// 对于目录标记，检查目录是否存在
-	if strings.HasSuffix(key, "/") && size == 0 {
-		if _, err := os.Stat(localPath); os.IsNotExist(err) {
-			return true // 目录不存在，需要创建
-		}
-	} else {
-		// 对于文件，检查文件是否存在
-		if _, err := os.Stat(localPath); os.IsNotExist(err) {
-			return true // 文件不存在，需要下载
+	// 打包对象解压后没有对应的本地文件，只根据状态记录判断
+	if !pack.IsPack(key) {
+		if strings.HasSuffix(key, "/") && size == 0 {
+			// 对于目录标记，检查目录是否存在
+			if _, err := os.Stat(localPath); os.IsNotExist(err) {
+				return true // 目录不存在，需要创建
+			}
+		} else {
+			// 对于文件，检查文件是否存在
+			if !b.localFileExists(key) {
+				return true // 文件不存在，需要下载
+			}
 		}
 	}
 
 	// 检查状态记录
-	state, exists := b.state.Files[key]
+	entry, exists := b.recorded(key)
 	if !exists {
 		return true
 	}
 
 	// 比较ETag和修改时间
-	if state.ETag != etag || !state.LastModified.Equal(lastModified) || state.Size != size {
+	if entry.ETag != etag || !entry.LastModified.Equal(lastModified) || entry.Size != size {
 		return true
 	}
 
@@ -295,10 +519,11 @@ func (b *Backup) needsDownload(key, etag string, lastModified time.Time, size in
 }
 
 // downloadObjects 下载对象
-func (b *Backup) downloadObjects(objects []*s3.Object) error {
-	objectChan := make(chan *s3.Object, len(objects))
+func (b *Backup) downloadObjects(objects []storage.Object) error {
+	objectChan := make(chan storage.Object, len(objects))
 	errorChan := make(chan error, b.options.Workers)
 	var wg sync.WaitGroup
+	var failed atomic.Bool // 有工作协程出错后其他工作协程不再开始新的传输
 
 	// 启动工作协程
 	for i := 0; i < b.options.Workers; i++ {
@@ -306,10 +531,19 @@ func (b *Backup) downloadObjects(objects []*s3.Object) error {
 		go func() {
 			defer wg.Done()
 			for obj := range objectChan {
-				if err := b.downloadObject(obj); err != nil {
-					errorChan <- fmt.Errorf("下载 %s 失败: %w", *obj.Key, err)
+				if b.stopped.Load() {
+					errorChan <- i18n.Errorf("已停止")
 					return
 				}
+				if failed.Load() {
+					return
+				}
+				sum, err := b.downloadObject(obj)
+				if err != nil {
+					errorChan <- i18n.Errorf("下载 %s 失败: %w", obj.Key, err)
+					return
+				}
+				b.markDone(obj, sum)
 			}
 		}()
 	}
@@ -328,107 +562,196 @@ func (b *Backup) downloadObjects(objects []*s3.Object) error {
 		close(errorChan)
 	}()
 
-	// 检查错误
+	// 检查错误，出错后等待正在进行的传输完成，使其记录到状态中
+	var firstErr error
 	for err := range errorChan {
-		if err != nil {
-			return err
+		if err != nil && firstErr == nil {
+			firstErr = err
+			failed.Store(true)
 		}
 	}
 
-	return nil
+	return firstErr
 }
 
-// downloadObject 下载单个对象
-func (b *Backup) downloadObject(obj *s3.Object) error {
-	key := *obj.Key
-	localPath := filepath.Join(b.options.OutputDir, key)
+// downloadObject 下载单个对象，返回写入本地文件的内容的SHA-256，目录标记返回空
+func (b *Backup) downloadObject(obj storage.Object) (string, error) {
+	key := obj.Key
+	localPath := b.localPath(key)
 
-	if b.options.Verbose {
-		fmt.Printf("下载: %s -> %s\n", key, localPath)
-	}
+	logger.Debugf("下载: %s -> %s", key, localPath)
 
 	// 如果是目录标记（以/结尾且大小为0），只创建目录
-	if strings.HasSuffix(key, "/") && *obj.Size == 0 {
+	if strings.HasSuffix(key, "/") && obj.Size == 0 {
 		if err := os.MkdirAll(localPath, 0755); err != nil {
-			return fmt.Errorf("创建目录失败: %w", err)
+			return "", i18n.Errorf("创建目录失败: %w", err)
 		}
 
-		// 设置目录修改时间
-		if err := os.Chtimes(localPath, *obj.LastModified, *obj.LastModified); err != nil {
-			// 忽略时间设置错误，不是致命的
-			if b.options.Verbose {
-				fmt.Printf("警告: 设置目录时间失败 %s: %v\n", localPath, err)
-			}
+		// 目录标记的元数据需要单独获取
+		var attrs fileattr.Attrs
+		head, err := b.storage.Head(key, "")
+		if err == nil {
+			attrs = fileattr.Parse(head.Metadata)
+		} else {
+			logger.Debugf("获取目录元数据失败 %s: %v", key, err)
 		}
 
+		// 目录属性在所有文件下载完成后再设置，否则写入子文件会改变目录的修改时间
+		b.mutex.Lock()
+		b.pendingDirs = append(b.pendingDirs, pendingDir{
+			path:     localPath,
+			attrs:    attrs,
+			fallback: obj.LastModified,
+		})
+		b.mutex.Unlock()
+
 		// 更新进度
-		b.progress.AddFile(*obj.Size)
-		return nil
+		b.progress.AddFile(key, obj.Size)
+		return "", nil
 	}
 
 	// 创建父目录
 	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
-		return err
+		return "", err
 	}
 
-	// 下载对象
-	input := &s3.GetObjectInput{
-		Bucket: aws.String(b.options.Bucket),
-		Key:    aws.String(key),
-	}
+	// 按接收的字节数更新进度，大对象下载过程中进度也会变化
+	counter := b.progress.NewCounter(key, obj.Size)
+	defer counter.Close()
 
-	result, err := b.s3.GetObject(input)
+	// 下载对象，内容保持上传时的编码
+	result, object, err := b.storage.Get(key, counter)
 	if err != nil {
-		return err
+		return "", err
+	}
+	defer result.Close()
+
+	// 上传时压缩的对象解压后写入原始文件名
+	var body io.Reader = result
+	if b.options.Decompress {
+		reader, path, err := b.decompress(key, result, object)
+		if err != nil {
+			return "", err
+		}
+		if reader != nil {
+			defer reader.Close()
+			body = reader
+			localPath = path
+		}
 	}
-	defer result.Body.Close()
 
 	// 写入本地文件
 	file, err := os.Create(localPath)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer file.Close()
 
-	_, err = io.Copy(file, result.Body)
+	// 写入的同时计算内容的SHA-256，记录到状态中用于之后校验本地文件
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(file, hash), body)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	// 设置文件修改时间
-	if err := os.Chtimes(localPath, *obj.LastModified, *obj.LastModified); err != nil {
-		// 忽略时间设置错误，不是致命的
-		if b.options.Verbose {
-			fmt.Printf("警告: 设置文件时间失败 %s: %v\n", localPath, err)
-		}
+	// 设置文件属性（权限、属主、修改时间），元数据中没有修改时间时使用LastModified
+	if err := fileattr.Apply(localPath, fileattr.Parse(object.Metadata), obj.LastModified); err != nil {
+		// 忽略属性设置错误，不是致命的
+		logger.Debugf("设置文件属性失败 %s: %v", localPath, err)
 	}
 
 	// 更新进度
-	b.progress.AddFile(*obj.Size)
+	counter.Done(obj.Size)
 
-	return nil
+	return hex.EncodeToString(hash.Sum(nil)), nil
 }
 
-// updateState 更新备份状态
-func (b *Backup) updateState(objects []*s3.Object) {
-	if !b.options.Incremental {
-		return
+// applyDirAttrs 设置已下载目录的属性，先处理深层目录，避免设置父目录后再修改子目录
+func (b *Backup) applyDirAttrs() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	sort.Slice(b.pendingDirs, func(i, j int) bool {
+		return len(b.pendingDirs[i].path) > len(b.pendingDirs[j].path)
+	})
+
+	for _, dir := range b.pendingDirs {
+		if err := fileattr.Apply(dir.path, dir.attrs, dir.fallback); err != nil {
+			// 忽略属性设置错误，不是致命的
+			logger.Debugf("设置目录属性失败 %s: %v", dir.path, err)
+		}
 	}
+	b.pendingDirs = nil
+}
+
+// updateState 更新备份状态，只写入有变化的条目
+func (b *Backup) updateState(objects []storage.Object) error {
 
 	for _, obj := range objects {
-		key := *obj.Key
+		key := obj.Key
 
 		// 跳过空文件名
 		if key == "" {
 			continue
 		}
 
-		etag := strings.Trim(*obj.ETag, "\"")
+		entry := objectState(obj)
+		old, ok := b.state.Get(key)
+		if ok && !old.Deleted() && old.ETag == entry.ETag && old.Size == entry.Size && old.LastModified.Equal(entry.LastModified) {
+			// 对象没有变化，保留下载时计算的SHA-256
+			entry.SHA256 = old.SHA256
+		}
+		if ok && old.Equal(entry) {
+			continue
+		}
+		if err := b.state.Put(key, entry); err != nil {
+			return err
+		}
+	}
+
+	_, err := b.markDeleted(objects)
+	return err
+}
+
+// markDeleted 把状态中有记录、但本次列出的对象中已经没有的条目标记为已删除，返回新标记的数量。
+// 删除记录保留删除的时间，对象重新出现时被新的状态替换
+func (b *Backup) markDeleted(objects []storage.Object) (int, error) {
+	listed := make(map[string]bool, len(objects))
+	for _, obj := range objects {
+		listed[obj.Key] = true
+	}
 
-		b.state.Files[key] = FileState{
-			ETag:         etag,
-			LastModified: *obj.LastModified,
-			Size:         *obj.Size,
+	// 遍历时不能修改存储，先收集消失的对象
+	var deleted []string
+	err := b.state.Range("", func(key string, entry state.Entry) bool {
+		if !listed[key] && !entry.Deleted() {
+			deleted = append(deleted, key)
 		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	for _, key := range deleted {
+		entry, _ := b.state.Get(key)
+		entry.DeletedAt = &now
+		if err := b.state.Put(key, entry); err != nil {
+			return 0, err
+		}
+	}
+	if len(deleted) > 0 {
+		logger.Infof("%d 个对象已从桶中删除，已在状态中记录", len(deleted))
+	}
+	return len(deleted), nil
+}
+
+// objectState 生成对象的状态记录
+func objectState(obj storage.Object) state.Entry {
+	return state.Entry{
+		ETag:         obj.ETag,
+		LastModified: obj.LastModified,
+		Size:         obj.Size,
 	}
 }
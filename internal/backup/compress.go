@@ -0,0 +1,48 @@
+package backup
+
+import (
+	"io"
+	"os"
+
+	"objectsync/internal/compress"
+	"objectsync/internal/i18n"
+	"objectsync/internal/storage"
+)
+
+// decompress 对上传时压缩的对象返回解压读取器和去掉压缩后缀的本地路径，不需要解压时返回nil
+func (b *Backup) decompress(key string, body io.Reader, object *storage.Object) (io.ReadCloser, string, error) {
+	algorithm, err := compress.Parse(object.Headers.ContentEncoding)
+	if err != nil || algorithm == "" {
+		return nil, "", nil
+	}
+	original, ok := compress.TrimSuffix(key, algorithm)
+	if !ok {
+		return nil, "", nil
+	}
+
+	reader, err := compress.NewReader(body, algorithm)
+	if err != nil {
+		return nil, "", i18n.Errorf("解压 %s 失败: %w", key, err)
+	}
+
+	logger.Debugf("解压: %s -> %s", key, original)
+	return reader, b.localPath(original), nil
+}
+
+// localFileExists 检查对象对应的本地文件是否存在，启用解压时也检查去掉压缩后缀的文件
+func (b *Backup) localFileExists(key string) bool {
+	if _, err := os.Stat(b.localPath(key)); !os.IsNotExist(err) {
+		return true
+	}
+	if !b.options.Decompress {
+		return false
+	}
+	for _, algorithm := range []string{compress.Gzip, compress.Zstd} {
+		if original, ok := compress.TrimSuffix(key, algorithm); ok {
+			if _, err := os.Stat(b.localPath(original)); !os.IsNotExist(err) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package backup
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"objectsync/internal/journal"
+	"objectsync/internal/storage"
+)
+
+// journalObject 运行日志中记录的列出对象
+type journalObject struct {
+	Key          string    `json:"key"`
+	ETag         string    `json:"etag"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+	Download     bool      `json:"download,omitempty"` // 本次运行需要下载
+}
+
+// startJournal 记录本次运行列出的对象和需要下载的对象，中断后下次运行可以跳过列出和已完成的下载
+func (b *Backup) startJournal(objects, toDownload []storage.Object) error {
+	download := make(map[string]bool, len(toDownload))
+	for _, obj := range toDownload {
+		download[obj.Key] = true
+	}
+
+	items := make([]journalObject, 0, len(objects))
+	for _, obj := range objects {
+		items = append(items, journalObject{
+			Key:          obj.Key,
+			ETag:         obj.ETag,
+			Size:         obj.Size,
+			LastModified: obj.LastModified,
+			Download:     download[obj.Key],
+		})
+	}
+
+	j, err := journal.Create(journal.Path(b.options.StateFile), b.options.Bucket, b.options.Prefix, b.journalOptions(), items)
+	if err != nil {
+		return err
+	}
+	b.journal = j
+	return nil
+}
+
+// journalOptions 返回影响下载计划的选项的哈希，这些选项改变后不能继续中断的运行
+func (b *Backup) journalOptions() string {
+	return journal.Hash(struct {
+		OutputDir  string
+		Include    []string
+		Exclude    []string
+		Decompress bool
+	}{b.options.OutputDir, b.options.Include, b.options.Exclude, b.options.Decompress})
+}
+
+// resumePlan 读取上次中断的运行日志，返回当时列出的对象和尚未完成的下载；
+// 没有日志或日志不能用于本次运行（见 journal.Unfinished.Check）时ok为false。下载到一半的对象会重新下载
+func (b *Backup) resumePlan() (objects, toDownload []storage.Object, ok bool) {
+	path := journal.Path(b.options.StateFile)
+	unfinished, err := journal.Load(path)
+	if err != nil {
+		logger.Warnf("无法读取运行日志 %s，重新开始: %v", path, err)
+		return nil, nil, false
+	}
+	if unfinished == nil {
+		return nil, nil, false
+	}
+	if err := unfinished.Check(b.options.Bucket, b.options.Prefix, b.journalOptions()); err != nil {
+		logger.Infof("不继续上次中断的运行: %v", err)
+		return nil, nil, false
+	}
+
+	var items []journalObject
+	if err := json.Unmarshal(unfinished.Items, &items); err != nil {
+		logger.Warnf("无法读取运行日志 %s，重新开始: %v", path, err)
+		return nil, nil, false
+	}
+
+	j, err := journal.Open(path, unfinished.Elapsed)
+	if err != nil {
+		logger.Warnf("无法读取运行日志 %s，重新开始: %v", path, err)
+		return nil, nil, false
+	}
+	b.journal = j
+
+	var done, doneSize int64
+	for _, item := range items {
+		// 旧版本的运行日志中ETag带有引号
+		obj := storage.Object{
+			Key:          item.Key,
+			ETag:         strings.Trim(item.ETag, "\""),
+			Size:         item.Size,
+			LastModified: item.LastModified,
+		}
+		objects = append(objects, obj)
+		if !item.Download {
+			continue
+		}
+		if _, finished := unfinished.Done[item.Key]; finished {
+			done++
+			doneSize += item.Size
+			continue
+		}
+		toDownload = append(toDownload, obj)
+	}
+
+	logger.Infof("继续 %s 开始的中断运行：已完成 %d 个对象，剩余 %d 个",
+		unfinished.Started.Format("2006-01-02 15:04:05"), done, len(toDownload))
+	b.progress.Resume(done, doneSize, unfinished.Elapsed)
+	return objects, toDownload, true
+}
+
+// markDone 在运行日志和状态中记录对象已下载完成，sum为本地内容的SHA-256
+func (b *Backup) markDone(obj storage.Object, sum string) {
+	b.checkpointDone(obj, sum)
+	if b.journal == nil {
+		return
+	}
+	if err := b.journal.Done(obj.Key, nil); err != nil {
+		logger.Debugf("写入运行日志失败: %v", err)
+	}
+}
+
+// closeJournal 运行失败时保留运行日志，下次运行从中断处继续
+func (b *Backup) closeJournal() {
+	if b.journal != nil {
+		b.journal.Close()
+	}
+}
+
+// finishJournal 运行正常结束，删除运行日志
+func (b *Backup) finishJournal() {
+	if b.journal == nil {
+		return
+	}
+	if err := b.journal.Finish(); err != nil {
+		logger.Warnf("删除运行日志失败: %v", err)
+	}
+}
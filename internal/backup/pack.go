@@ -0,0 +1,82 @@
+package backup
+
+import (
+	"sort"
+	"time"
+
+	"objectsync/internal/filter"
+	"objectsync/internal/i18n"
+	"objectsync/internal/pack"
+	"objectsync/internal/storage"
+)
+
+// splitPacks 将打包对象与普通对象分开
+func splitPacks(objects []storage.Object) ([]storage.Object, []storage.Object) {
+	var regular, packs []storage.Object
+	for _, obj := range objects {
+		if pack.IsPack(obj.Key) {
+			packs = append(packs, obj)
+		} else {
+			regular = append(regular, obj)
+		}
+	}
+	return regular, packs
+}
+
+// extractPacks 按写入顺序依次下载并解压打包对象
+// 较新的打包对象覆盖较旧的，与同名普通对象按修改时间取较新的一方
+func (b *Backup) extractPacks(packs []storage.Object, objects []storage.Object) error {
+	if len(packs) == 0 {
+		return nil
+	}
+
+	// 打包对象键以时间戳开头，按键排序即为写入顺序
+	sort.Slice(packs, func(i, j int) bool {
+		return packs[i].Key < packs[j].Key
+	})
+
+	// 普通对象已先下载，打包对象更新时才覆盖
+	regular := make(map[string]time.Time)
+	for _, obj := range objects {
+		if !pack.IsInternal(obj.Key) {
+			regular[obj.Key] = obj.LastModified
+		}
+	}
+
+	for _, obj := range packs {
+		if err := b.extractPack(obj, regular); err != nil {
+			return i18n.Errorf("解压打包对象 %s 失败: %w", obj.Key, err)
+		}
+		b.markDone(obj, "")
+	}
+	return nil
+}
+
+// extractPack 下载单个打包对象并解压到输出目录
+func (b *Backup) extractPack(obj storage.Object, regular map[string]time.Time) error {
+	counter := b.progress.NewCounter(obj.Key, obj.Size)
+	defer counter.Close()
+
+	body, _, err := b.storage.Get(obj.Key, counter)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	include := filter.New(b.options.Include, b.options.Exclude)
+	entries, err := pack.Extract(body, b.options.OutputDir, b.options.Prefix, func(key string) bool {
+		if modified, ok := regular[key]; ok && !modified.Before(obj.LastModified) {
+			return true
+		}
+		return !include.MatchRelative(key, b.options.Prefix)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("解压: %s（%d 个文件）-> %s", obj.Key, len(entries), b.options.OutputDir)
+
+	// 更新进度
+	counter.Done(obj.Size)
+	return nil
+}
@@ -0,0 +1,115 @@
+package backup
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"objectsync/internal/i18n"
+	"objectsync/internal/state"
+)
+
+// PruneResult 清理本地备份文件的结果
+type PruneResult struct {
+	Files   []PrunedFile `json:"files"`   // 删除（预演时为将要删除）的本地文件
+	Bytes   int64        `json:"bytes"`   // 删除的文件合计大小
+	Records int          `json:"records"` // 清除的删除记录数，包括本地文件已经不存在的记录
+}
+
+// PrunedFile 被清理的本地文件
+type PrunedFile struct {
+	Key       string    `json:"key"`
+	Path      string    `json:"path"`
+	Size      int64     `json:"size"`
+	DeletedAt time.Time `json:"deleted_at"` // 发现对象从桶中删除的时间
+}
+
+// Prune 删除在cutoff之前就已经从桶中删除的对象的本地备份文件，并清除对应的删除记录。
+// 只处理状态中的删除记录，仍在桶中的对象的文件不会被删除，下次备份也不会重新下载。
+// dryRun为true时只列出将要删除的文件，不修改本地文件和状态
+func (b *Backup) Prune(cutoff time.Time, dryRun bool) (*PruneResult, error) {
+	if !b.options.Incremental || b.options.StateFile == "" {
+		return nil, i18n.Errorf("未启用增量备份，没有删除记录可以清理")
+	}
+	lock, err := b.lockState()
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
+	if err := b.loadState(); err != nil {
+		return nil, i18n.Errorf("加载备份状态失败: %w", err)
+	}
+	defer b.closeState()
+
+	// 遍历时不能修改存储，先收集过期的删除记录
+	result := &PruneResult{Files: []PrunedFile{}}
+	var expired []PrunedFile
+	err = b.state.Range("", func(key string, entry state.Entry) bool {
+		if entry.Deleted() && entry.DeletedAt.Before(cutoff) {
+			path, _ := b.verifyPath(key)
+			expired = append(expired, PrunedFile{Key: key, Path: path, DeletedAt: *entry.DeletedAt})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, i18n.Errorf("读取备份状态失败: %w", err)
+	}
+	// 先删除目录中的文件，再删除目录
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].Key > expired[j].Key
+	})
+
+	for _, file := range expired {
+		removed, err := b.pruneFile(&file, dryRun)
+		if err != nil {
+			return result, err
+		}
+		if removed {
+			result.Files = append(result.Files, file)
+			result.Bytes += file.Size
+		}
+		result.Records++
+		if !dryRun {
+			if err := b.state.Delete(file.Key); err != nil {
+				return result, i18n.Errorf("更新备份状态失败: %w", err)
+			}
+		}
+	}
+
+	if dryRun || result.Records == 0 {
+		return result, nil
+	}
+	if err := b.state.Save(); err != nil {
+		return result, i18n.Errorf("保存备份状态失败: %w", err)
+	}
+	return result, nil
+}
+
+// pruneFile 删除单个本地文件，返回文件是否存在。目录标记只在目录为空时删除
+func (b *Backup) pruneFile(file *PrunedFile, dryRun bool) (bool, error) {
+	info, err := os.Lstat(file.Path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, i18n.Errorf("读取 %s 失败: %w", file.Path, err)
+	}
+
+	if strings.HasSuffix(file.Key, "/") {
+		entries, err := os.ReadDir(file.Path)
+		if err != nil || len(entries) > 0 {
+			return false, nil
+		}
+	} else {
+		file.Size = info.Size()
+	}
+	if dryRun {
+		return true, nil
+	}
+	if err := os.Remove(file.Path); err != nil {
+		return false, i18n.Errorf("删除 %s 失败: %w", file.Path, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,127 @@
+package backup
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"objectsync/internal/filter"
+	"objectsync/internal/i18n"
+	"objectsync/internal/journal"
+	"objectsync/internal/pack"
+	"objectsync/internal/state"
+	"objectsync/internal/statestore"
+	"objectsync/internal/storage"
+)
+
+// RebuildResult 重新生成状态的结果
+type RebuildResult struct {
+	Matched  int    // 本地文件与远程对象一致，已记录到状态中
+	Missing  int    // 本地文件不存在或不一致，下次备份时下载
+	Skipped  int    // 打包对象和解压后的文件无法与远程对象比较，下次备份时下载
+	Previous string // 原来的状态文件改名后的路径，没有原状态文件时为空
+}
+
+// Rebuild 扫描输出目录，把与远程对象一致的本地文件记录到新的状态中，
+// 状态文件丢失或损坏后不需要重新下载已经存在的文件。
+// 默认比较大小和MD5（分片上传的对象只比较大小），sizeOnly为true时只比较大小。
+// 原来的状态文件改名为 .bak 保留
+func (b *Backup) Rebuild(sizeOnly bool) (*RebuildResult, error) {
+	if !b.options.Incremental || b.options.StateFile == "" {
+		return nil, i18n.Errorf("未启用增量备份，没有状态文件需要重新生成")
+	}
+	lock, err := b.lockState()
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
+	if err := b.initStorage(); err != nil {
+		return nil, i18n.Errorf("初始化S3客户端失败: %w", err)
+	}
+
+	objects, err := b.listObjects()
+	if err != nil {
+		return nil, i18n.Errorf("列出对象失败: %w", err)
+	}
+
+	result := &RebuildResult{}
+	include := filter.New(b.options.Include, b.options.Exclude)
+	var matched, toHash []storage.Object
+
+	for _, obj := range objects {
+		key := obj.Key
+		if key == "" || pack.IsIndex(key) || !include.MatchRelative(key, b.options.Prefix) {
+			continue
+		}
+		if pack.IsPack(key) {
+			result.Skipped++
+			continue
+		}
+		if _, compressed := b.verifyPath(key); compressed {
+			result.Skipped++
+			continue
+		}
+		if b.verifyObject(obj) != nil {
+			result.Missing++
+			continue
+		}
+		if sizeOnly || strings.HasSuffix(key, "/") {
+			matched = append(matched, obj)
+		} else {
+			toHash = append(toHash, obj)
+		}
+	}
+
+	if len(toHash) > 0 {
+		logger.Infof("计算 %d 个本地文件的MD5", len(toHash))
+	}
+	different := make(map[string]bool)
+	for _, mismatch := range b.verifyChecksums(toHash) {
+		logger.Debugf("与远程对象不一致: %s（%s）", mismatch.Path, mismatch.Detail)
+		different[mismatch.Key] = true
+	}
+	for _, obj := range toHash {
+		if different[obj.Key] {
+			result.Missing++
+		} else {
+			matched = append(matched, obj)
+		}
+	}
+	result.Matched = len(matched)
+
+	if result.Previous, err = backupStateFile(statestore.Path(b.options.StateFile, b.options.StateBackend)); err != nil {
+		return nil, i18n.Errorf("保留原来的状态文件失败: %w", err)
+	}
+	// 原来的运行日志按旧的状态生成，不再继续
+	os.Remove(journal.Path(b.options.StateFile))
+
+	store, err := state.Create(b.options.StateFile, state.Backup, b.options.StateBackend)
+	if err != nil {
+		return nil, i18n.Errorf("创建状态文件失败: %w", err)
+	}
+	defer store.Close()
+
+	for _, obj := range matched {
+		if err := store.Put(obj.Key, objectState(obj)); err != nil {
+			return nil, i18n.Errorf("保存备份状态失败: %w", err)
+		}
+	}
+	store.SetLastRun(time.Now())
+	if err := store.Save(); err != nil {
+		return nil, i18n.Errorf("保存备份状态失败: %w", err)
+	}
+	return result, nil
+}
+
+// backupStateFile 把存在的状态文件改名为 .bak，返回新的路径，文件不存在时返回空
+func backupStateFile(path string) (string, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", nil
+	}
+	bak := path + ".bak"
+	if err := os.Rename(path, bak); err != nil {
+		return "", err
+	}
+	return bak, nil
+}
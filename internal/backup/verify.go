@@ -0,0 +1,290 @@
+package backup
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"objectsync/internal/compress"
+	"objectsync/internal/filter"
+	"objectsync/internal/i18n"
+	"objectsync/internal/pack"
+	"objectsync/internal/state"
+	"objectsync/internal/storage"
+)
+
+// 校验发现的问题类型
+const (
+	ProblemMissing  = "missing"  // 本地文件不存在
+	ProblemSize     = "size"     // 本地文件大小与远程对象不一致
+	ProblemChecksum = "checksum" // 本地文件的SHA-256与下载时记录的不一致，或与远程对象的附加校验值、ETag不一致（仅深度校验）
+	ProblemOutdated = "outdated" // 远程对象在上次备份之后有变化
+	ProblemError    = "error"    // 读取本地文件失败
+)
+
+// Mismatch 本地备份与远程对象不一致的文件
+type Mismatch struct {
+	Key     string `json:"key"`
+	Path    string `json:"path"`
+	Problem string `json:"problem"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// VerifyResult 一个桶的校验结果
+type VerifyResult struct {
+	Checked    int        `json:"checked"`
+	Skipped    int        `json:"skipped"` // 打包对象无法逐个与本地文件比较
+	Mismatches []Mismatch `json:"mismatches"`
+}
+
+// Verify 逐个比较远程对象与本地备份的存在性和大小，并检查状态记录的ETag是否过期；
+// deep为true时重新计算本地文件的内容：状态中记录了下载时的SHA-256时与之比较，可以发现本地文件的损坏，
+// 否则计算MD5与远程ETag比较（分片上传的对象和解压后的文件只比较大小）
+func (b *Backup) Verify(deep bool) (*VerifyResult, error) {
+	if err := b.initStorage(); err != nil {
+		return nil, i18n.Errorf("初始化S3客户端失败: %w", err)
+	}
+	if err := b.loadState(); err != nil {
+		return nil, i18n.Errorf("加载备份状态失败: %w", err)
+	}
+	defer b.closeState()
+
+	objects, err := b.listObjects()
+	if err != nil {
+		return nil, i18n.Errorf("列出对象失败: %w", err)
+	}
+
+	result := &VerifyResult{Mismatches: []Mismatch{}}
+	include := filter.New(b.options.Include, b.options.Exclude)
+	var toHash []storage.Object
+
+	for _, obj := range objects {
+		key := obj.Key
+		if key == "" || pack.IsIndex(key) || !include.MatchRelative(key, b.options.Prefix) {
+			continue
+		}
+		if pack.IsPack(key) {
+			result.Skipped++
+			continue
+		}
+		result.Checked++
+
+		if mismatch := b.verifyObject(obj); mismatch != nil {
+			result.Mismatches = append(result.Mismatches, *mismatch)
+			continue
+		}
+
+		// 目录标记没有内容；解压后的文件无法与ETag比较，只能与记录的SHA-256比较
+		if !deep || strings.HasSuffix(key, "/") {
+			continue
+		}
+		if _, compressed := b.verifyPath(key); !compressed || b.recordedSHA256(key) != "" {
+			toHash = append(toHash, obj)
+		}
+	}
+
+	result.Mismatches = append(result.Mismatches, b.verifyChecksums(toHash)...)
+	sort.Slice(result.Mismatches, func(i, j int) bool {
+		return result.Mismatches[i].Key < result.Mismatches[j].Key
+	})
+	return result, nil
+}
+
+// verifyObject 检查对象对应的本地文件是否存在、大小是否一致以及状态记录是否过期
+func (b *Backup) verifyObject(obj storage.Object) *Mismatch {
+	key := obj.Key
+	size := obj.Size
+	path, compressed := b.verifyPath(key)
+	mismatch := &Mismatch{Key: key, Path: path}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		mismatch.Problem = ProblemMissing
+		return mismatch
+	}
+	if err != nil {
+		mismatch.Problem, mismatch.Detail = ProblemError, err.Error()
+		return mismatch
+	}
+
+	// 目录标记只检查目录是否存在
+	if strings.HasSuffix(key, "/") && size == 0 {
+		return nil
+	}
+
+	if !compressed && info.Size() != size {
+		mismatch.Problem = ProblemSize
+		mismatch.Detail = i18n.Sprintf("本地 %d，远程 %d", info.Size(), size)
+		return mismatch
+	}
+
+	etag := obj.ETag
+	if entry, exists := b.recorded(key); exists && entry.ETag != etag {
+		mismatch.Problem = ProblemOutdated
+		mismatch.Detail = i18n.Sprintf("备份时 ETag %s，远程 %s", entry.ETag, etag)
+		return mismatch
+	}
+
+	return nil
+}
+
+// verifyPath 返回对象对应的本地路径，启用解压且本地是解压后的文件时compressed为true
+func (b *Backup) verifyPath(key string) (string, bool) {
+	path := b.localPath(key)
+	if _, err := os.Stat(path); !os.IsNotExist(err) || !b.options.Decompress {
+		return path, false
+	}
+	for _, algorithm := range []string{compress.Gzip, compress.Zstd} {
+		if original, ok := compress.TrimSuffix(key, algorithm); ok {
+			if _, err := os.Stat(b.localPath(original)); !os.IsNotExist(err) {
+				return b.localPath(original), true
+			}
+		}
+	}
+	return path, false
+}
+
+// verifyChecksums 并发计算本地文件的SHA-256或MD5，与状态记录或远程ETag比较
+func (b *Backup) verifyChecksums(objects []storage.Object) []Mismatch {
+	objectChan := make(chan storage.Object, len(objects))
+	for _, obj := range objects {
+		objectChan <- obj
+	}
+	close(objectChan)
+
+	var mismatches []Mismatch
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	workers := b.options.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for obj := range objectChan {
+				if mismatch := b.verifyChecksum(obj); mismatch != nil {
+					mutex.Lock()
+					mismatches = append(mismatches, *mismatch)
+					mutex.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return mismatches
+}
+
+// verifyChecksum 比较单个本地文件的内容：有下载时记录的SHA-256时与之比较，
+// 否则对象带有上传时附加的校验值时与之比较，再否则比较MD5与远程ETag，分片上传的ETag不是内容MD5，跳过比较
+func (b *Backup) verifyChecksum(obj storage.Object) *Mismatch {
+	key := obj.Key
+	path, _ := b.verifyPath(key)
+
+	if recorded := b.recordedSHA256(key); recorded != "" {
+		logger.Debugf("校验: %s", path)
+		sum, err := state.HashFile(path)
+		if err != nil {
+			return &Mismatch{Key: key, Path: path, Problem: ProblemError, Detail: err.Error()}
+		}
+		if sum != recorded {
+			return &Mismatch{
+				Key:     key,
+				Path:    path,
+				Problem: ProblemChecksum,
+				Detail:  i18n.Sprintf("SHA-256 %s，下载时 %s", sum, recorded),
+			}
+		}
+		return nil
+	}
+
+	if b.options.Checksum != "" {
+		if mismatch, ok := b.verifyAdditionalChecksum(key, path); ok {
+			return mismatch
+		}
+	}
+
+	etag := obj.ETag
+	if etag == "" || strings.Contains(etag, "-") {
+		return nil
+	}
+
+	logger.Debugf("校验: %s", path)
+	localMD5, err := fileMD5(path)
+	if err != nil {
+		return &Mismatch{Key: key, Path: path, Problem: ProblemError, Detail: err.Error()}
+	}
+	if !strings.EqualFold(localMD5, etag) {
+		return &Mismatch{
+			Key:     key,
+			Path:    path,
+			Problem: ProblemChecksum,
+			Detail:  fmt.Sprintf("MD5 %s, ETag %s", localMD5, etag),
+		}
+	}
+	return nil
+}
+
+// verifyAdditionalChecksum 比较本地文件与远程对象上传时附加的校验值，对象没有该算法的整体校验值
+// （未附加或分片上传的组合校验值）时返回false，由调用方改用ETag比较
+func (b *Backup) verifyAdditionalChecksum(key, path string) (*Mismatch, bool) {
+	head, err := b.storage.Head(key, b.options.Checksum)
+	if err != nil {
+		return &Mismatch{Key: key, Path: path, Problem: ProblemError, Detail: err.Error()}, true
+	}
+	remote := head.Checksum
+	if remote == "" || strings.Contains(remote, "-") {
+		return nil, false
+	}
+
+	logger.Debugf("校验: %s", path)
+	file, err := os.Open(path)
+	if err != nil {
+		return &Mismatch{Key: key, Path: path, Problem: ProblemError, Detail: err.Error()}, true
+	}
+	defer file.Close()
+
+	h := storage.NewChecksumHash(b.options.Checksum)
+	if _, err := io.Copy(h, file); err != nil {
+		return &Mismatch{Key: key, Path: path, Problem: ProblemError, Detail: err.Error()}, true
+	}
+	if local := base64.StdEncoding.EncodeToString(h.Sum(nil)); local != remote {
+		return &Mismatch{
+			Key:     key,
+			Path:    path,
+			Problem: ProblemChecksum,
+			Detail:  fmt.Sprintf("%s %s, %s", b.options.Checksum, local, remote),
+		}, true
+	}
+	return nil, true
+}
+
+// recordedSHA256 返回状态中记录的下载时的SHA-256，没有记录时为空
+func (b *Backup) recordedSHA256(key string) string {
+	entry, _ := b.recorded(key)
+	return entry.SHA256
+}
+
+// fileMD5 计算本地文件的MD5
+func fileMD5(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
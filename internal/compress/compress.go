@@ -0,0 +1,84 @@
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"strings"
+
+	"objectsync/internal/i18n"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// 支持的压缩算法，取值与Content-Encoding一致
+const (
+	Gzip = "gzip"
+	Zstd = "zstd"
+)
+
+// Parse 解析压缩算法名称（不区分大小写），空字符串表示不压缩
+func Parse(name string) (string, error) {
+	algorithm := strings.ToLower(strings.TrimSpace(name))
+	switch algorithm {
+	case "", Gzip, Zstd:
+		return algorithm, nil
+	default:
+		return "", i18n.Errorf("不支持的压缩算法: %s（可选值: gzip, zstd）", name)
+	}
+}
+
+// Suffix 返回压缩后对象键追加的后缀
+func Suffix(algorithm string) string {
+	switch algorithm {
+	case Gzip:
+		return ".gz"
+	case Zstd:
+		return ".zst"
+	}
+	return ""
+}
+
+// FromSuffix 按文件名或对象键的压缩后缀返回压缩算法，没有压缩后缀时返回空字符串
+func FromSuffix(name string) string {
+	for _, algorithm := range []string{Gzip, Zstd} {
+		if strings.HasSuffix(name, Suffix(algorithm)) {
+			return algorithm
+		}
+	}
+	return ""
+}
+
+// TrimSuffix 去掉对象键的压缩后缀，返回原始键和是否带有该后缀
+func TrimSuffix(key, algorithm string) (string, bool) {
+	suffix := Suffix(algorithm)
+	if suffix == "" || !strings.HasSuffix(key, suffix) {
+		return key, false
+	}
+	return strings.TrimSuffix(key, suffix), true
+}
+
+// NewWriter 创建压缩写入器，关闭写入器时写入剩余数据
+func NewWriter(w io.Writer, algorithm string) (io.WriteCloser, error) {
+	switch algorithm {
+	case Gzip:
+		return gzip.NewWriter(w), nil
+	case Zstd:
+		return zstd.NewWriter(w)
+	}
+	return nil, i18n.Errorf("不支持的压缩算法: %s", algorithm)
+}
+
+// NewReader 创建解压读取器
+func NewReader(r io.Reader, algorithm string) (io.ReadCloser, error) {
+	switch algorithm {
+	case Gzip:
+		return gzip.NewReader(r)
+	case Zstd:
+		decoder, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	}
+	return nil, i18n.Errorf("不支持的压缩算法: %s", algorithm)
+}
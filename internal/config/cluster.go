@@ -0,0 +1,172 @@
+package config
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"objectsync/internal/i18n"
+)
+
+// remoteKey 返回remote（或集群）名称的查找形式，viper读取时会将remotes的键转为小写
+func remoteKey(name string) string {
+	return strings.ToLower(name)
+}
+
+// expandClusters 将每个集群注册为同名的remote，并把集群下的桶追加到buckets中
+func (cm *ConfigManager) expandClusters() error {
+	for i, cluster := range cm.config.Load().Clusters {
+		if cluster.Name == "" {
+			return i18n.Errorf("clusters[%d] 缺少 name", i)
+		}
+		name := remoteKey(cluster.Name)
+		if _, ok := cm.config.Load().Remotes[name]; ok {
+			return i18n.Errorf("clusters[%d] 的名称 %s 与 remotes 中的连接重复", i, cluster.Name)
+		}
+
+		if cm.config.Load().Remotes == nil {
+			cm.config.Load().Remotes = make(map[string]CephConfig)
+		}
+		cm.config.Load().Remotes[name] = cluster.CephConfig
+
+		for j, bucket := range cluster.Buckets {
+			if bucket.Remote != "" && remoteKey(bucket.Remote) != name {
+				return i18n.Errorf("clusters[%d].buckets[%d] 不能引用其他 remote: %s", i, j, bucket.Remote)
+			}
+			bucket.Remote = name
+			cm.config.Load().Buckets = append(cm.config.Load().Buckets, bucket)
+		}
+	}
+
+	// 展开后不再需要，避免重新加载时重复展开
+	cm.config.Load().Clusters = nil
+
+	// 桶引用的remote名称同样按小写查找
+	for i := range cm.config.Load().Buckets {
+		cm.config.Load().Buckets[i].Remote = remoteKey(cm.config.Load().Buckets[i].Remote)
+		cm.config.Load().Buckets[i].Target = remoteKey(cm.config.Load().Buckets[i].Target)
+	}
+	return nil
+}
+
+// FilterCluster 只保留属于指定集群（或remote）的桶
+func (s *MultiBucketSettings) FilterCluster(name string) error {
+	name = remoteKey(name)
+	var buckets []BucketSettings
+	names := make(map[string]bool)
+	for _, bucket := range s.Buckets {
+		if bucket.Remote != "" {
+			names[bucket.Remote] = true
+		}
+		if bucket.Remote == name {
+			buckets = append(buckets, bucket)
+		}
+	}
+
+	if len(buckets) == 0 {
+		available := make([]string, 0, len(names))
+		for remote := range names {
+			available = append(available, remote)
+		}
+		sort.Strings(available)
+		return i18n.Errorf("集群 %s 下没有配置桶（可选值: %v）", name, available)
+	}
+
+	s.Buckets = buckets
+	return nil
+}
+
+// FilterBuckets 只保留指定名称的桶，names为空时不过滤
+func (s *MultiBucketSettings) FilterBuckets(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	buckets, err := s.matchBuckets(names, true)
+	if err != nil {
+		return err
+	}
+	s.Buckets = buckets
+	return nil
+}
+
+// ExcludeBuckets 去掉指定名称的桶，names为空时不过滤，去掉后没有剩余的桶时返回错误
+func (s *MultiBucketSettings) ExcludeBuckets(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	buckets, err := s.matchBuckets(names, false)
+	if err != nil {
+		return err
+	}
+	if len(buckets) == 0 {
+		return i18n.Errorf("排除后没有要处理的桶")
+	}
+	s.Buckets = buckets
+	return nil
+}
+
+// matchBuckets 按名称匹配配置中的桶，keep为true时返回匹配的桶，否则返回不匹配的桶。
+// 同一个桶可能按不同前缀配置了多次，按名称一起处理；names中有配置中不存在的桶时返回错误
+func (s *MultiBucketSettings) matchBuckets(names []string, keep bool) ([]BucketSettings, error) {
+	named := make(map[string]bool, len(names))
+	for _, name := range names {
+		named[name] = true
+	}
+
+	var buckets []BucketSettings
+	found := make(map[string]bool)
+	available := make([]string, 0, len(s.Buckets))
+	for _, bucket := range s.Buckets {
+		available = append(available, bucket.Name)
+		if named[bucket.Name] {
+			found[bucket.Name] = true
+		}
+		if named[bucket.Name] == keep {
+			buckets = append(buckets, bucket)
+		}
+	}
+
+	for _, name := range names {
+		if !found[name] {
+			return nil, i18n.Errorf("配置中没有桶 %s（可选值: %v）", name, available)
+		}
+	}
+	return buckets, nil
+}
+
+// ExcludeDirection 去掉指定方向的桶，用于只处理本地目录的命令，去掉后没有剩余的桶时返回错误
+func (s *MultiBucketSettings) ExcludeDirection(directions ...string) error {
+	var buckets []BucketSettings
+	for _, bucket := range s.Buckets {
+		if !slices.Contains(directions, bucket.Direction) {
+			buckets = append(buckets, bucket)
+		}
+	}
+	if len(buckets) == 0 {
+		return i18n.Errorf("没有要处理的桶（所有桶的方向都是 %s）", strings.Join(directions, "/"))
+	}
+
+	s.Buckets = buckets
+	return nil
+}
+
+// ExcludeNoOutputDir 去掉没有设置输出目录的桶（只有 source_dirs 的上传桶），返回被去掉的桶。
+// 这些桶无法下载到本地，backup 跳过它们
+func (s *MultiBucketSettings) ExcludeNoOutputDir() ([]BucketSettings, error) {
+	var buckets, skipped []BucketSettings
+	for _, bucket := range s.Buckets {
+		if bucket.OutputDir == "" {
+			skipped = append(skipped, bucket)
+			continue
+		}
+		buckets = append(buckets, bucket)
+	}
+	if len(buckets) == 0 {
+		return skipped, i18n.Errorf("没有要处理的桶（所有桶都没有设置 output_dir）")
+	}
+
+	s.Buckets = buckets
+	return skipped, nil
+}
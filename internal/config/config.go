@@ -1,11 +1,30 @@
 package config
 
 import (
+	"cmp"
 	"fmt"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"objectsync/internal/compress"
+	"objectsync/internal/filter"
+	"objectsync/internal/history"
+	"objectsync/internal/i18n"
+	"objectsync/internal/notify"
+	"objectsync/internal/progress"
+	"objectsync/internal/s3client"
+	"objectsync/internal/schedule"
+	"objectsync/internal/state"
+	"objectsync/internal/statestore"
+	"objectsync/internal/storage"
 
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 )
 
 // Config 主配置结构
@@ -13,13 +32,95 @@ type Config struct {
 	Ceph    CephConfig       `mapstructure:"ceph" yaml:"ceph"`
 	Backup  BackupFileConfig `mapstructure:"backup" yaml:"backup"`
 	Buckets []BucketConfig   `mapstructure:"buckets" yaml:"buckets"` // 统一使用桶数组
+	Retry   RetryConfig      `mapstructure:"retry" yaml:"retry"`
+	// Defaults 所有桶继承的默认设置，桶中设置了同名字段时以桶为准
+	Defaults DefaultsConfig `mapstructure:"defaults" yaml:"defaults,omitempty"`
+	// Remotes 按名称定义的多个对象存储连接，桶通过remote字段引用
+	Remotes map[string]CephConfig `mapstructure:"remotes" yaml:"remotes,omitempty"`
+	// Clusters 多个集群各自的连接和桶，加载时展开为remotes和buckets
+	Clusters []ClusterConfig `mapstructure:"clusters" yaml:"clusters,omitempty"`
+	// Language 输出语言（zh、en），命令行的 --lang 优先，未设置时按LANG环境变量
+	Language string `mapstructure:"language" yaml:"language,omitempty"`
+	// Notifications backup、upload、run 和守护进程运行结束后发送总结的聊天渠道
+	Notifications []NotificationConfig `mapstructure:"notifications" yaml:"notifications,omitempty"`
+}
+
+// NotificationConfig 聊天通知渠道配置
+type NotificationConfig struct {
+	// Type 渠道类型：slack、dingtalk、wecom、telegram
+	Type string `mapstructure:"type" yaml:"type"`
+	// WebhookURL slack、dingtalk、wecom机器人的Webhook地址
+	WebhookURL string `mapstructure:"webhook_url" yaml:"webhook_url,omitempty"`
+	// Secret 钉钉机器人启用加签时的密钥
+	Secret string `mapstructure:"secret" yaml:"secret,omitempty"`
+	// BotToken/ChatID Telegram机器人令牌和接收消息的会话
+	BotToken string `mapstructure:"bot_token" yaml:"bot_token,omitempty"`
+	ChatID   string `mapstructure:"chat_id" yaml:"chat_id,omitempty"`
+	// On 发送时机：always（默认，每次运行后）或 failure（只在有桶失败时）
+	On string `mapstructure:"on" yaml:"on,omitempty"`
+}
+
+// ClusterConfig 一个集群的连接配置及其下的桶
+type ClusterConfig struct {
+	Name       string `mapstructure:"name" yaml:"name"`
+	CephConfig `mapstructure:",squash" yaml:",inline"`
+	Buckets    []BucketConfig `mapstructure:"buckets" yaml:"buckets"`
 }
 
 // CephConfig Ceph连接配置
 type CephConfig struct {
+	// Endpoint 对象存储端点URL；设为 local:目录 时把该目录下的子目录当作桶，用于目录之间的同步；
+	// 设为 webdav:URL 时把WebDAV服务（如Nextcloud）中的目录当作桶，access_key 和 secret_key 作为用户名和密码
 	Endpoint  string `mapstructure:"endpoint" yaml:"endpoint"`
 	AccessKey string `mapstructure:"access_key" yaml:"access_key"`
 	SecretKey string `mapstructure:"secret_key" yaml:"secret_key"`
+	// Profile 共享凭证文件（~/.aws/credentials）中的配置名，设置后从该文件读取密钥
+	Profile string `mapstructure:"profile" yaml:"profile,omitempty"`
+	// Region 请求签名使用的区域，为空时使用us-east-1
+	Region string `mapstructure:"region" yaml:"region,omitempty"`
+	// PathStyle 使用路径样式寻址（默认），设为false时使用虚拟主机样式，部分服务只支持后者
+	PathStyle *bool `mapstructure:"path_style" yaml:"path_style,omitempty"`
+	// Transport 访问S3兼容对象存储使用的客户端实现：aws（默认，aws-sdk-go）或 minio（minio-go），
+	// 部分Ceph/MinIO版本在尾部校验头、判断桶是否存在等方面与minio-go配合更好
+	Transport string `mapstructure:"transport" yaml:"transport,omitempty"`
+	// 从文件（如Kubernetes/Docker secrets）或外部命令（如Vault）读取密钥，设置后覆盖上面的明文值
+	AccessKeyFile string `mapstructure:"access_key_file" yaml:"access_key_file,omitempty"`
+	AccessKeyCmd  string `mapstructure:"access_key_cmd" yaml:"access_key_cmd,omitempty"`
+	SecretKeyFile string `mapstructure:"secret_key_file" yaml:"secret_key_file,omitempty"`
+	SecretKeyCmd  string `mapstructure:"secret_key_cmd" yaml:"secret_key_cmd,omitempty"`
+	// TLS HTTPS连接的证书配置，用于内部CA签发的证书或双向认证
+	TLS TLSConfig `mapstructure:"tls" yaml:"tls,omitempty"`
+	// Proxy 访问对象存储使用的代理，未设置时使用HTTP_PROXY/NO_PROXY环境变量
+	Proxy ProxyConfig `mapstructure:"proxy" yaml:"proxy,omitempty"`
+	// Timeouts HTTP超时设置，避免网关异常时请求一直挂起
+	Timeouts TimeoutConfig `mapstructure:"timeouts" yaml:"timeouts,omitempty"`
+}
+
+// UsePathStyle 返回是否使用路径样式寻址，未设置时为true
+func (c CephConfig) UsePathStyle() bool {
+	return c.PathStyle == nil || *c.PathStyle
+}
+
+// TLSConfig TLS证书配置
+type TLSConfig struct {
+	CAFile             string `mapstructure:"ca_file" yaml:"ca_file,omitempty"`                           // 额外信任的CA证书（PEM）
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify" yaml:"insecure_skip_verify,omitempty"` // 不校验服务端证书，仅用于测试
+	CertFile           string `mapstructure:"cert_file" yaml:"cert_file,omitempty"`                       // 客户端证书（PEM）
+	KeyFile            string `mapstructure:"key_file" yaml:"key_file,omitempty"`                         // 客户端私钥（PEM）
+}
+
+// ProxyConfig 代理配置
+type ProxyConfig struct {
+	URL     string   `mapstructure:"url" yaml:"url,omitempty"`           // 代理地址，支持http、https和socks5
+	NoProxy []string `mapstructure:"no_proxy" yaml:"no_proxy,omitempty"` // 不经过代理的主机
+}
+
+// TimeoutConfig HTTP超时配置，未设置的项保持默认行为
+type TimeoutConfig struct {
+	Connect        time.Duration `mapstructure:"connect" yaml:"connect,omitempty"`                 // 建立连接的超时，默认30s
+	ResponseHeader time.Duration `mapstructure:"response_header" yaml:"response_header,omitempty"` // 等待响应头的超时，默认不限制
+	Idle           time.Duration `mapstructure:"idle" yaml:"idle,omitempty"`                       // 空闲连接的保留时间，默认90s
+	Request        time.Duration `mapstructure:"request" yaml:"request,omitempty"`                 // 建立连接和等待响应头的超时（不限制传输数据），默认不限制
 }
 
 // BackupFileConfig 备份文件配置
@@ -27,17 +128,119 @@ type BackupFileConfig struct {
 	OutputDir   string `mapstructure:"output_dir" yaml:"output_dir"`
 	Incremental bool   `mapstructure:"incremental" yaml:"incremental"`
 	StateFile   string `mapstructure:"state_file" yaml:"state_file"`
+	// StateBackend 状态存储后端：json（默认）或 bbolt（对象数很多的桶，按键读写，不需要把整个状态加载到内存）
+	StateBackend string `mapstructure:"state_backend" yaml:"state_backend,omitempty"`
+	// CheckpointFiles/CheckpointInterval 运行中每传输多少个文件或每隔多久保存一次状态，运行失败时不丢失已完成的部分；0表示不按该条件保存
+	CheckpointFiles    int           `mapstructure:"checkpoint_files" yaml:"checkpoint_files,omitempty"`
+	CheckpointInterval time.Duration `mapstructure:"checkpoint_interval" yaml:"checkpoint_interval,omitempty"`
+	// PruneDeletedAfter 已删除的对象（或本地文件）的删除记录在状态中保留的时间，运行成功后清理；0表示不保留
+	PruneDeletedAfter time.Duration `mapstructure:"prune_deleted_after" yaml:"prune_deleted_after,omitempty"`
+	// HistoryFile 记录每次运行结果的文件，供 history 命令查询
+	HistoryFile string `mapstructure:"history_file" yaml:"history_file,omitempty"`
 	Workers     int    `mapstructure:"workers" yaml:"workers"`
 	Verbose     bool   `mapstructure:"verbose" yaml:"verbose"`
+	// PartsConcurrency 单个大文件分片上传时的并发分片数，与文件级并发数无关
+	PartsConcurrency int `mapstructure:"parts_concurrency" yaml:"parts_concurrency"`
+	// Include/Exclude 默认的包含/排除模式，桶未设置时使用
+	Include []string `mapstructure:"include" yaml:"include,omitempty"`
+	Exclude []string `mapstructure:"exclude" yaml:"exclude,omitempty"`
+}
+
+// DefaultsConfig 桶的默认设置，未设置的字段继续使用backup中的全局值
+type DefaultsConfig struct {
+	Workers          int      `mapstructure:"workers" yaml:"workers,omitempty"`
+	Verbose          *bool    `mapstructure:"verbose" yaml:"verbose,omitempty"`
+	PartsConcurrency int      `mapstructure:"parts_concurrency" yaml:"parts_concurrency,omitempty"`
+	DirMarkers       string   `mapstructure:"dir_markers" yaml:"dir_markers,omitempty"`
+	Include          []string `mapstructure:"include" yaml:"include,omitempty"`
+	Exclude          []string `mapstructure:"exclude" yaml:"exclude,omitempty"`
+	// Bandwidth 每个桶每秒最多传输的数据量，如 10MB
+	Bandwidth string `mapstructure:"bandwidth" yaml:"bandwidth,omitempty"`
+	// StorageClass 上传对象使用的存储类别，如 STANDARD_IA
+	StorageClass string `mapstructure:"storage_class" yaml:"storage_class,omitempty"`
+}
+
+// RetryConfig 重试配置
+type RetryConfig struct {
+	MaxAttempts int           `mapstructure:"max_attempts" yaml:"max_attempts"`
+	Delay       time.Duration `mapstructure:"delay" yaml:"delay"`
 }
 
 // BucketConfig 单个桶的配置
 type BucketConfig struct {
-	Name      string `mapstructure:"name" yaml:"name"`
+	Name string `mapstructure:"name" yaml:"name"`
+	// Remote 引用remotes中定义的连接，为空时使用ceph配置
+	Remote string `mapstructure:"remote" yaml:"remote,omitempty"`
+	// Direction run命令对该桶执行的操作：backup（下载，默认）、upload（上传）、sync（先下载后上传）、
+	// replicate（复制到target指定的另一个对象存储）
+	Direction string `mapstructure:"direction" yaml:"direction,omitempty"`
+	// Target 复制时的目标连接，引用remotes中的名称，direction为replicate时必需
+	Target string `mapstructure:"target" yaml:"target,omitempty"`
+	// TargetBucket 复制的目标桶，为空时与源桶同名
+	TargetBucket string `mapstructure:"target_bucket" yaml:"target_bucket,omitempty"`
+	// MaxMemory 复制时所有工作协程缓冲的数据量上限，如 512MB，为空时使用默认值
+	MaxMemory string `mapstructure:"max_memory" yaml:"max_memory,omitempty"`
+	// VerifyCopy 复制后通过HEAD请求校验目标对象的大小和ETag，不一致时重新复制
+	VerifyCopy bool `mapstructure:"verify_copy" yaml:"verify_copy,omitempty"`
+	// Schedule 守护进程模式下该桶的执行时间，标准cron表达式（分 时 日 月 周），如 "0 2 * * *"
+	Schedule string `mapstructure:"schedule" yaml:"schedule,omitempty"`
+	// Prefix 只处理桶中该前缀下的对象，备份时本地路径去掉前缀，上传时对象键加上前缀，
+	// 用于多个任务共用同一个桶的不同区域
+	Prefix    string `mapstructure:"prefix" yaml:"prefix,omitempty"`
 	OutputDir string `mapstructure:"output_dir" yaml:"output_dir"`
+	// StateFile 状态文件路径，以 .gz 或 .zst 结尾时压缩保存（上传状态文件使用相同的压缩方式）
 	StateFile string `mapstructure:"state_file" yaml:"state_file,omitempty"`
 	Workers   int    `mapstructure:"workers" yaml:"workers,omitempty"`
-	Verbose   bool   `mapstructure:"verbose" yaml:"verbose,omitempty"`
+	// Verbose 未设置时继承defaults，设置为false时不会被覆盖
+	Verbose *bool `mapstructure:"verbose" yaml:"verbose,omitempty"`
+	// PartsConcurrency 单个大文件的并发分片数，为0时使用全局配置
+	PartsConcurrency int `mapstructure:"parts_concurrency" yaml:"parts_concurrency,omitempty"`
+	// DirMarkers 上传时目录标记对象的创建方式：all（默认）、empty（仅空目录）、none（不创建）
+	DirMarkers string `mapstructure:"dir_markers" yaml:"dir_markers,omitempty"`
+	// Bandwidth 该桶每秒最多传输的数据量，如 10MB，为空时使用defaults
+	Bandwidth string `mapstructure:"bandwidth" yaml:"bandwidth,omitempty"`
+	// StorageClass 上传对象使用的存储类别，为空时使用defaults
+	StorageClass string `mapstructure:"storage_class" yaml:"storage_class,omitempty"`
+	// Headers 上传时按文件名模式设置的HTTP头
+	Headers []HeaderRule `mapstructure:"headers" yaml:"headers,omitempty"`
+	// Compress 上传时按文件名模式压缩文件，对象键追加.gz/.zst后缀
+	Compress []CompressRule `mapstructure:"compress" yaml:"compress,omitempty"`
+	// Decompress 下载时解压上传时压缩的对象，恢复原始文件名
+	Decompress bool `mapstructure:"decompress" yaml:"decompress,omitempty"`
+	// Include/Exclude 上传和下载时的包含/排除模式，设置后替换backup中的默认值
+	Include []string `mapstructure:"include" yaml:"include,omitempty"`
+	Exclude []string `mapstructure:"exclude" yaml:"exclude,omitempty"`
+	// SourceDirs 上传时使用的多个本地源目录，为空时从output_dir上传
+	SourceDirs []SourceDir `mapstructure:"source_dirs" yaml:"source_dirs,omitempty"`
+}
+
+// 桶的同步方向
+const (
+	DirectionBackup    = "backup"    // 从对象存储下载到本地
+	DirectionUpload    = "upload"    // 从本地上传到对象存储
+	DirectionSync      = "sync"      // 先下载远程的变化，再上传本地的变化
+	DirectionReplicate = "replicate" // 从一个对象存储复制到另一个，不经过本地磁盘
+)
+
+// SourceDir 上传源目录，prefix为该目录在桶中对应的键前缀
+type SourceDir struct {
+	Path   string `mapstructure:"path" yaml:"path"`
+	Prefix string `mapstructure:"prefix" yaml:"prefix,omitempty"`
+}
+
+// CompressRule 按文件名模式压缩上传的规则，algorithm可选gzip或zstd
+type CompressRule struct {
+	Pattern   string `mapstructure:"pattern" yaml:"pattern"`
+	Algorithm string `mapstructure:"algorithm" yaml:"algorithm"`
+}
+
+// HeaderRule 按文件名模式设置的HTTP头规则，多条规则匹配时后面的覆盖前面的
+type HeaderRule struct {
+	Pattern            string `mapstructure:"pattern" yaml:"pattern"`
+	CacheControl       string `mapstructure:"cache_control" yaml:"cache_control,omitempty"`
+	ContentEncoding    string `mapstructure:"content_encoding" yaml:"content_encoding,omitempty"`
+	ContentType        string `mapstructure:"content_type" yaml:"content_type,omitempty"`
+	ContentDisposition string `mapstructure:"content_disposition" yaml:"content_disposition,omitempty"`
 }
 
 // MultiBucketSettings 多桶备份设置
@@ -45,18 +248,75 @@ type MultiBucketSettings struct {
 	Endpoint    string
 	AccessKey   string
 	SecretKey   string
+	Profile     string
+	Region      string
+	PathStyle   bool
+	Transport   string
+	TLS         TLSConfig
+	Proxy       ProxyConfig
+	Timeouts    TimeoutConfig
 	Buckets     []BucketSettings
 	Incremental bool
+	// StateBackend 状态存储后端，所有桶相同
+	StateBackend string
+	// CheckpointFiles/CheckpointInterval 运行中保存状态的间隔
+	CheckpointFiles    int
+	CheckpointInterval time.Duration
+	// PruneDeletedAfter 删除记录在状态中保留的时间
+	PruneDeletedAfter time.Duration
+	Resume            bool   // 上次运行中断时从运行日志继续
+	HistoryFile       string // 运行历史记录文件
+	// Labels 命令行指定的运行标签，记录在运行历史和通知中
+	Labels      map[string]string
 	ConfigFile  string
+	MaxAttempts int
+	RetryDelay  time.Duration
+	// Notifications 运行结束后发送总结的聊天渠道
+	Notifications []NotificationConfig
 }
 
 // BucketSettings 单个桶的备份设置
 type BucketSettings struct {
-	Name      string
-	OutputDir string
-	StateFile string
-	Workers   int
-	Verbose   bool
+	Name             string
+	Remote           string
+	Direction        string
+	Schedule         string
+	Prefix           string
+	Endpoint         string
+	AccessKey        string
+	SecretKey        string
+	Profile          string
+	Region           string
+	PathStyle        bool
+	Transport        string
+	TLS              TLSConfig
+	Proxy            ProxyConfig
+	Timeouts         TimeoutConfig
+	OutputDir        string
+	StateFile        string
+	Workers          int
+	PartsConcurrency int
+	Verbose          bool
+	DirMarkers       string
+	Bandwidth        int64 // 每秒最多传输的字节数，0表示不限制
+	StorageClass     string
+	Headers          []HeaderRule
+	Compress         []CompressRule
+	Decompress       bool
+	Include          []string
+	Exclude          []string
+	SourceDirs       []SourceDir
+	// Target 复制的目标连接和桶，direction为replicate时设置
+	Target     *ReplicateTarget
+	MaxMemory  int64 // 复制时缓冲的数据量上限，0表示使用默认值
+	VerifyCopy bool
+}
+
+// ReplicateTarget 复制的目标：remotes中的连接和目标桶
+type ReplicateTarget struct {
+	Remote string
+	Bucket string
+	CephConfig
 }
 
 // 默认配置文件内容
@@ -67,73 +327,152 @@ ceph:
   endpoint: "http://192.168.1.100:7480"  # 对象存储端点URL
   access_key: "your-access-key"          # 访问密钥
   secret_key: "your-secret-key"          # 秘密密钥
+  # 密钥也可以保存在系统密钥环中，使用 objectsync config set-secret 写入后以 "keyring:objectsync/prod" 形式引用
+  # secret_key_file: "/run/secrets/s3_secret"                # 可选：从文件读取密钥（access_key_file 同理）
+  # secret_key_cmd: "vault kv get -field=secret secret/s3"   # 可选：从命令输出读取密钥（access_key_cmd 同理）
+  # profile: "default"                  # 可选：从 ~/.aws/credentials 读取该配置的密钥，代替上面两项
 
 # 桶配置 - 可以配置一个或多个桶
 buckets:
   - name: "your-bucket-name"             # 桶名称，请修改为实际的桶名称
     output_dir: "./backup"               # 本地输出目录
-    state_file: ".backup_state.json"    # 状态文件路径
+    state_file: ".backup_state.json"    # 状态文件路径（以 .gz 或 .zst 结尾时压缩保存）
+  # - name: "archive"                    # 可选：复制到另一个对象存储，数据只经过内存，不写入本地磁盘
+  #   direction: "replicate"
+  #   target: "new-cluster"              # remotes 中定义的目标连接
+  #   target_bucket: "archive"           # 可选：目标桶，默认与源桶同名
+  #   max_memory: "512MB"                # 可选：复制时缓冲的数据量上限
+  #   verify_copy: true                  # 可选：复制后校验目标对象的大小和ETag
 
 # 全局备份配置
 backup:
   incremental: true                      # 启用增量备份
+  # state_backend: "bbolt"               # 可选：状态存储后端，对象数很多的桶使用 bbolt 数据库代替JSON文件
+  # checkpoint_files: 1000               # 可选：运行中每传输多少个文件保存一次状态（默认1000，JSON状态文件至少间隔条目总数的1/10；0表示不按数量保存）
+  # checkpoint_interval: "5m"            # 可选：运行中每隔多久保存一次状态（默认5m，0表示不按时间保存）
+  # prune_deleted_after: "720h"          # 可选：已删除对象的记录在状态中保留多久（默认720h，0表示运行成功后立即清理）
   workers: 5                             # 默认并发下载数
+  parts_concurrency: 5                   # 单个大文件上传时的并发分片数
+  # history_file: ".objectsync_history.jsonl"  # 可选：运行历史记录文件，使用 objectsync history 查看
+  # exclude: ["*.tmp", ".git/"]          # 可选：默认排除模式，桶中设置 include/exclude 时替换此默认值
   verbose: false                         # 详细输出
 
 # 重试配置
 retry:
   max_attempts: 3                        # 最大重试次数
   delay: "5s"                           # 重试延迟
+
+# 可选：运行结束后发送总结到聊天工具（slack、dingtalk、wecom、telegram）
+# notifications:
+#   - type: "dingtalk"
+#     webhook_url: "https://oapi.dingtalk.com/robot/send?access_token=${DINGTALK_TOKEN}"
+#     on: "failure"                      # always（默认）或 failure
 `
 
 // ConfigManager 配置管理器
 type ConfigManager struct {
 	configPath string
-	config     *Config
+	viper      *viper.Viper
+	// config 当前配置，加载完成后不再修改，重新加载时整体替换
+	config atomic.Pointer[Config]
 }
 
-// NewConfigManager 创建配置管理器
-func NewConfigManager(configPath string) *ConfigManager {
-	if configPath == "" {
-		configPath = "config.yaml"
+// PeekLanguage 在加载配置之前读取配置文件中的 language，用于尽早确定输出语言，
+// 文件不存在或无法解析时返回空字符串
+func PeekLanguage(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var cfg struct {
+		Language string `yaml:"language"`
 	}
-	return &ConfigManager{
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return ""
+	}
+	return cfg.Language
+}
+
+// NewConfigManager 创建配置管理器，configPath为空时按 Locate 查找配置文件
+func NewConfigManager(configPath string) *ConfigManager {
+	return newConfigManager(Locate(configPath))
+}
+
+// newConfigManager 创建使用独立viper实例的配置管理器
+func newConfigManager(configPath string) *ConfigManager {
+	cm := &ConfigManager{
 		configPath: configPath,
-		config:     &Config{},
+		viper:      viper.New(),
 	}
+	cm.config.Store(&Config{})
+	return cm
 }
 
 // LoadConfig 加载配置文件
 func (cm *ConfigManager) LoadConfig() (*Config, error) {
 	// 检查配置文件是否存在，不存在则创建默认配置文件
 	if _, err := os.Stat(cm.configPath); os.IsNotExist(err) {
-		fmt.Printf("配置文件 %s 不存在，正在创建默认配置文件...\n", cm.configPath)
+		i18n.Printf("配置文件 %s 不存在，正在创建默认配置文件...\n", cm.configPath)
 		if err := cm.createDefaultConfig(); err != nil {
-			return nil, fmt.Errorf("创建默认配置文件失败: %w", err)
+			return nil, i18n.Errorf("创建默认配置文件失败: %w", err)
 		}
-		fmt.Printf("默认配置文件已创建: %s\n", cm.configPath)
-		fmt.Printf("请编辑配置文件并填入正确的Ceph连接信息，然后重新运行程序。\n")
-		return nil, fmt.Errorf("请先配置 %s 文件", cm.configPath)
+		i18n.Printf("默认配置文件已创建: %s\n", cm.configPath)
+		i18n.Printf("请编辑配置文件并填入正确的Ceph连接信息，然后重新运行程序。\n")
+		return nil, i18n.Errorf("请先配置 %s 文件", cm.configPath)
 	}
 
+	if err := cm.read(); err != nil {
+		return nil, err
+	}
+
+	return cm.config.Load(), nil
+}
+
+// read 使用配置管理器自己的viper实例读取配置文件并解析
+func (cm *ConfigManager) read() error {
 	// 设置配置文件路径和类型
-	viper.SetConfigFile(cm.configPath)
-	viper.SetConfigType("yaml")
+	cm.viper.SetConfigFile(cm.configPath)
+	cm.viper.SetConfigType("yaml")
 
 	// 设置默认值
 	cm.setDefaults()
 
 	// 读取配置文件
-	if err := viper.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+	if err := cm.viper.ReadInConfig(); err != nil {
+		return i18n.Errorf("读取配置文件失败: %w", err)
+	}
+
+	return cm.decode()
+}
+
+// decode 将viper已读取的配置解析到结构体，并解析密钥引用
+func (cm *ConfigManager) decode() error {
+	// 拒绝拼写错误等未知配置项，viper解析时会忽略它们
+	if err := checkUnknownKeys(cm.configPath); err != nil {
+		return i18n.Errorf("配置文件包含无效的配置项:\n%w", err)
 	}
 
 	// 将配置解析到结构体
-	if err := viper.Unmarshal(cm.config); err != nil {
-		return nil, fmt.Errorf("解析配置文件失败: %w", err)
+	if err := cm.viper.Unmarshal(cm.config.Load()); err != nil {
+		return i18n.Errorf("解析配置文件失败: %w", err)
+	}
+
+	// 展开 ${VAR} 形式的环境变量引用
+	if err := expandEnv(cm.config.Load()); err != nil {
+		return i18n.Errorf("配置文件引用的环境变量无效:\n%w", err)
 	}
 
-	return cm.config, nil
+	// 将clusters展开为remotes和buckets，之后按普通的remote处理
+	if err := cm.expandClusters(); err != nil {
+		return err
+	}
+
+	// 从文件、命令或系统密钥环读取密钥
+	if err := cm.resolveSecrets(); err != nil {
+		return i18n.Errorf("读取密钥失败: %w", err)
+	}
+
+	return nil
 }
 
 // createDefaultConfig 创建默认配置文件
@@ -160,84 +499,591 @@ func (cm *ConfigManager) createDefaultConfig() error {
 // setDefaults 设置默认值
 func (cm *ConfigManager) setDefaults() {
 	// Ceph配置默认值
-	viper.SetDefault("ceph.endpoint", "")
-	viper.SetDefault("ceph.access_key", "")
-	viper.SetDefault("ceph.secret_key", "")
+	cm.viper.SetDefault("ceph.endpoint", "")
+	cm.viper.SetDefault("ceph.access_key", "")
+	cm.viper.SetDefault("ceph.secret_key", "")
 
 	// 备份配置默认值
-	viper.SetDefault("backup.incremental", true)
-	viper.SetDefault("backup.workers", 5)
-	viper.SetDefault("backup.verbose", false)
+	cm.viper.SetDefault("backup.incremental", true)
+	cm.viper.SetDefault("backup.workers", 5)
+	cm.viper.SetDefault("backup.parts_concurrency", 5)
+	cm.viper.SetDefault("backup.verbose", false)
+	cm.viper.SetDefault("backup.checkpoint_files", 1000)
+	cm.viper.SetDefault("backup.checkpoint_interval", "5m")
+	cm.viper.SetDefault("backup.prune_deleted_after", "720h")
+
+	// 重试配置默认值
+	cm.viper.SetDefault("retry.max_attempts", 3)
+	cm.viper.SetDefault("retry.delay", "5s")
 }
 
 // ValidateConfig 验证配置
 func (cm *ConfigManager) ValidateConfig() error {
-	// 验证基础连接配置
-	if cm.config.Ceph.Endpoint == "" || cm.config.Ceph.Endpoint == "http://192.168.1.100:7480" {
-		return fmt.Errorf("请在配置文件中设置正确的 ceph.endpoint")
-	}
-	if cm.config.Ceph.AccessKey == "" || cm.config.Ceph.AccessKey == "your-access-key" {
-		return fmt.Errorf("请在配置文件中设置正确的 ceph.access_key")
-	}
-	if cm.config.Ceph.SecretKey == "" || cm.config.Ceph.SecretKey == "your-secret-key" {
-		return fmt.Errorf("请在配置文件中设置正确的 ceph.secret_key")
+	// 验证桶配置
+	if len(cm.config.Load().Buckets) == 0 {
+		return i18n.Errorf("请在配置文件中设置要备份的桶：buckets")
 	}
 
-	// 验证桶配置
-	if len(cm.config.Buckets) == 0 {
-		return fmt.Errorf("请在配置文件中设置要备份的桶：buckets")
+	// 验证连接配置：未引用remote的桶使用ceph配置
+	for name, remote := range cm.config.Load().Remotes {
+		if err := validateConnection(remote, "remotes."+name); err != nil {
+			return err
+		}
+	}
+	for i, bucket := range cm.config.Load().Buckets {
+		if bucket.Remote == "" {
+			if err := cm.ValidateConnection(); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, ok := cm.config.Load().Remotes[bucket.Remote]; !ok {
+			return i18n.Errorf("buckets[%d] 引用的 remote 不存在: %s", i, bucket.Remote)
+		}
 	}
 
 	// 验证每个桶的配置
-	for i, bucket := range cm.config.Buckets {
+	for i, bucket := range cm.config.Load().Buckets {
 		if bucket.Name == "" {
-			return fmt.Errorf("buckets[%d] 缺少桶名称", i)
+			return i18n.Errorf("buckets[%d] 缺少桶名称", i)
+		}
+		if bucket.OutputDir == "" && len(bucket.SourceDirs) == 0 && bucket.Direction != DirectionReplicate {
+			return i18n.Errorf("buckets[%d] 缺少输出目录", i)
+		}
+		for j, source := range bucket.SourceDirs {
+			if source.Path == "" {
+				return i18n.Errorf("buckets[%d].source_dirs[%d] 缺少 path", i, j)
+			}
+		}
+		switch bucket.Direction {
+		case DirectionUpload:
+		case "", DirectionBackup, DirectionSync:
+			// 未设置方向时默认为backup，同样需要下载到的目录
+			if bucket.OutputDir == "" {
+				return i18n.Errorf("buckets[%d] direction 为 %s 时需要设置 output_dir", i, cmp.Or(bucket.Direction, DirectionBackup))
+			}
+		case DirectionReplicate:
+			if err := cm.validateReplicate(bucket, i); err != nil {
+				return err
+			}
+		default:
+			return i18n.Errorf("buckets[%d] direction 无效: %s（可选值: backup, upload, sync, replicate）", i, bucket.Direction)
+		}
+		if bucket.Schedule != "" {
+			if _, err := schedule.Parse(bucket.Schedule); err != nil {
+				return i18n.Errorf("buckets[%d] schedule 无效: %w", i, err)
+			}
+		}
+		switch bucket.DirMarkers {
+		case "", "all", "empty", "none":
+		default:
+			return i18n.Errorf("buckets[%d] dir_markers 无效: %s（可选值: all, empty, none）", i, bucket.DirMarkers)
+		}
+		for j, rule := range bucket.Headers {
+			if rule.Pattern == "" {
+				return i18n.Errorf("buckets[%d].headers[%d] 缺少 pattern", i, j)
+			}
+			if _, err := path.Match(rule.Pattern, ""); err != nil {
+				return i18n.Errorf("buckets[%d].headers[%d] pattern 无效: %s", i, j, rule.Pattern)
+			}
+		}
+		for j, rule := range bucket.Compress {
+			if rule.Pattern == "" {
+				return i18n.Errorf("buckets[%d].compress[%d] 缺少 pattern", i, j)
+			}
+			if _, err := path.Match(rule.Pattern, ""); err != nil {
+				return i18n.Errorf("buckets[%d].compress[%d] pattern 无效: %s", i, j, rule.Pattern)
+			}
+			switch rule.Algorithm {
+			case "gzip", "zstd":
+			default:
+				return i18n.Errorf("buckets[%d].compress[%d] algorithm 无效: %s（可选值: gzip, zstd）", i, j, rule.Algorithm)
+			}
+		}
+	}
+
+	// 验证桶和defaults中的带宽设置
+	if err := validateBandwidth(cm.config.Load().Defaults.Bandwidth, "defaults.bandwidth"); err != nil {
+		return err
+	}
+	for i, bucket := range cm.config.Load().Buckets {
+		if err := validateBandwidth(bucket.Bandwidth, fmt.Sprintf("buckets[%d].bandwidth", i)); err != nil {
+			return err
+		}
+	}
+
+	// 验证包含/排除模式
+	if err := validatePatterns(cm.config.Load().Backup.Include, "backup.include"); err != nil {
+		return err
+	}
+	if err := validatePatterns(cm.config.Load().Defaults.Include, "defaults.include"); err != nil {
+		return err
+	}
+	if err := validatePatterns(cm.config.Load().Defaults.Exclude, "defaults.exclude"); err != nil {
+		return err
+	}
+	if err := validatePatterns(cm.config.Load().Backup.Exclude, "backup.exclude"); err != nil {
+		return err
+	}
+	for i, bucket := range cm.config.Load().Buckets {
+		if err := validatePatterns(bucket.Include, fmt.Sprintf("buckets[%d].include", i)); err != nil {
+			return err
 		}
-		if bucket.OutputDir == "" {
-			return fmt.Errorf("buckets[%d] 缺少输出目录", i)
+		if err := validatePatterns(bucket.Exclude, fmt.Sprintf("buckets[%d].exclude", i)); err != nil {
+			return err
 		}
 	}
 
+	if err := validateNotifications(cm.config.Load().Notifications); err != nil {
+		return err
+	}
+
+	if cm.config.Load().Language != "" && i18n.Normalize(cm.config.Load().Language) == "" {
+		return i18n.Errorf("language 无效: %s（可选值: zh, en）", cm.config.Load().Language)
+	}
+
+	if err := statestore.ValidateBackend(cm.config.Load().Backup.StateBackend); err != nil {
+		return i18n.Errorf("backup.state_backend: %w", err)
+	}
+
+	// 验证重试配置
+	if cm.config.Load().Retry.MaxAttempts < 1 {
+		return i18n.Errorf("retry.max_attempts 必须大于等于1")
+	}
+	if cm.config.Load().Retry.Delay < 0 {
+		return i18n.Errorf("retry.delay 不能为负数")
+	}
+
+	// 验证并发数
+	if cm.config.Load().Backup.Workers < 1 {
+		return i18n.Errorf("backup.workers 必须大于等于1")
+	}
+	if cm.config.Load().Backup.PartsConcurrency < 0 {
+		return i18n.Errorf("backup.parts_concurrency 不能为负数")
+	}
+	if cm.config.Load().Backup.CheckpointFiles < 0 {
+		return i18n.Errorf("backup.checkpoint_files 不能为负数")
+	}
+	if cm.config.Load().Backup.CheckpointInterval < 0 {
+		return i18n.Errorf("backup.checkpoint_interval 不能为负数")
+	}
+	if cm.config.Load().Backup.PruneDeletedAfter < 0 {
+		return i18n.Errorf("backup.prune_deleted_after 不能为负数")
+	}
+	if cm.config.Load().Defaults.Workers < 0 {
+		return i18n.Errorf("defaults.workers 不能为负数")
+	}
+	if cm.config.Load().Defaults.PartsConcurrency < 0 {
+		return i18n.Errorf("defaults.parts_concurrency 不能为负数")
+	}
+	switch cm.config.Load().Defaults.DirMarkers {
+	case "", "all", "empty", "none":
+	default:
+		return i18n.Errorf("defaults.dir_markers 无效: %s（可选值: all, empty, none）", cm.config.Load().Defaults.DirMarkers)
+	}
+	for i, bucket := range cm.config.Load().Buckets {
+		if bucket.Workers < 0 {
+			return i18n.Errorf("buckets[%d].workers 不能为负数", i)
+		}
+		if bucket.PartsConcurrency < 0 {
+			return i18n.Errorf("buckets[%d].parts_concurrency 不能为负数", i)
+		}
+	}
+
+	return nil
+}
+
+// validateReplicate 验证复制的目标：目标连接必须在remotes中定义，且不能与源是同一个桶
+func (cm *ConfigManager) validateReplicate(bucket BucketConfig, i int) error {
+	if bucket.Target == "" {
+		return i18n.Errorf("buckets[%d] direction 为 replicate 时需要设置 target", i)
+	}
+	if _, ok := cm.config.Load().Remotes[bucket.Target]; !ok {
+		return i18n.Errorf("buckets[%d] 引用的 target 不存在: %s", i, bucket.Target)
+	}
+	if bucket.Target == bucket.Remote && cmp.Or(bucket.TargetBucket, bucket.Name) == bucket.Name {
+		return i18n.Errorf("buckets[%d] 复制的目标与源是同一个桶", i)
+	}
+	if bucket.MaxMemory != "" {
+		if _, err := progress.ParseSize(bucket.MaxMemory); err != nil {
+			return i18n.Errorf("buckets[%d].max_memory 无效: %s（如 512MB）", i, bucket.MaxMemory)
+		}
+	}
+	return nil
+}
+
+// validateNotifications 验证通知渠道的类型和必需字段
+func validateNotifications(notifications []NotificationConfig) error {
+	for i, n := range notifications {
+		switch n.Type {
+		case notify.Slack, notify.DingTalk, notify.WeCom:
+			if n.WebhookURL == "" {
+				return i18n.Errorf("notifications[%d] 缺少 webhook_url", i)
+			}
+		case notify.Telegram:
+			if n.BotToken == "" || n.ChatID == "" {
+				return i18n.Errorf("notifications[%d] 类型为 telegram 时需要设置 bot_token 和 chat_id", i)
+			}
+		default:
+			return i18n.Errorf("notifications[%d] type 无效: %s（可选值: %s）", i, n.Type, strings.Join(notify.Types, ", "))
+		}
+		switch n.On {
+		case "", notify.OnAlways, notify.OnFailure:
+		default:
+			return i18n.Errorf("notifications[%d] on 无效: %s（可选值: always, failure）", i, n.On)
+		}
+	}
+	return nil
+}
+
+// ValidateConnection 仅验证连接配置，用于不依赖桶列表的命令
+func (cm *ConfigManager) ValidateConnection() error {
+	return validateConnection(cm.config.Load().Ceph, "ceph")
+}
+
+// validatePatterns 验证包含/排除模式的语法
+func validatePatterns(patterns []string, field string) error {
+	for j, pattern := range patterns {
+		if pattern == "" {
+			return i18n.Errorf("%s[%d] 不能为空", field, j)
+		}
+		if err := filter.Validate(pattern); err != nil {
+			return i18n.Errorf("%s[%d] 模式无效: %s", field, j, pattern)
+		}
+	}
+	return nil
+}
+
+// validateBandwidth 验证带宽设置是否为有效的大小
+func validateBandwidth(value, field string) error {
+	if value == "" {
+		return nil
+	}
+	if _, err := progress.ParseSize(value); err != nil {
+		return i18n.Errorf("%s 无效: %s（如 10MB，表示每秒传输量）", field, value)
+	}
+	return nil
+}
+
+// ValidateRemote 验证remotes中指定名称的连接配置
+func (cm *ConfigManager) ValidateRemote(name string) error {
+	remote, ok := cm.config.Load().Remotes[remoteKey(name)]
+	if !ok {
+		return i18n.Errorf("remote 不存在: %s", name)
+	}
+	return validateConnection(remote, "remotes."+name)
+}
+
+// RemoteSettings 验证并返回remotes（包括clusters）中指定名称的连接，只填写连接相关的字段，
+// 用于不依赖桶列表、操作整个端点的命令
+func (cm *ConfigManager) RemoteSettings(name string) (BucketSettings, error) {
+	if err := cm.ValidateRemote(name); err != nil {
+		return BucketSettings{}, err
+	}
+	name = remoteKey(name)
+	conn := cm.config.Load().Remotes[name]
+	return BucketSettings{
+		Remote:    name,
+		Endpoint:  conn.Endpoint,
+		AccessKey: conn.AccessKey,
+		SecretKey: conn.SecretKey,
+		Profile:   conn.Profile,
+		Region:    conn.Region,
+		PathStyle: conn.UsePathStyle(),
+		Transport: conn.Transport,
+		TLS:       conn.TLS,
+		Proxy:     conn.Proxy,
+		Timeouts:  conn.Timeouts,
+	}, nil
+}
+
+// validateConnection 验证单个连接配置，section用于错误提示
+func validateConnection(conn CephConfig, section string) error {
+	if conn.Endpoint == "" || conn.Endpoint == "http://192.168.1.100:7480" {
+		return i18n.Errorf("请在配置文件中设置正确的 %s.endpoint", section)
+	}
+
+	// 本地目录不需要密钥和HTTP设置
+	if _, ok := storage.LocalPath(conn.Endpoint); ok {
+		return nil
+	}
+
+	switch conn.Transport {
+	case "", s3client.TransportAWS, s3client.TransportMinio:
+	default:
+		return i18n.Errorf("%s.transport 无效: %s（可选值: aws, minio）", section, conn.Transport)
+	}
+	if err := validateTLS(conn.TLS, section); err != nil {
+		return err
+	}
+	if conn.Proxy.URL != "" {
+		if _, err := s3client.ParseProxyURL(conn.Proxy.URL); err != nil {
+			return fmt.Errorf("%s.proxy.url: %w", section, err)
+		}
+	}
+	timeouts := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"connect", conn.Timeouts.Connect},
+		{"response_header", conn.Timeouts.ResponseHeader},
+		{"idle", conn.Timeouts.Idle},
+		{"request", conn.Timeouts.Request},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value < 0 {
+			return i18n.Errorf("%s.timeouts.%s 不能为负数", section, timeout.name)
+		}
+	}
+
+	// WebDAV的密钥作为用户名和密码，公开共享不需要
+	if address, ok := storage.WebDAVURL(conn.Endpoint); ok {
+		if u, err := url.Parse(address); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return i18n.Errorf("%s.endpoint 不是有效的WebDAV地址: %s", section, address)
+		}
+		return nil
+	}
+
+	// 使用共享凭证文件时不需要在配置文件中设置密钥
+	if conn.Profile != "" {
+		return nil
+	}
+	if conn.AccessKey == "" || conn.AccessKey == "your-access-key" {
+		return i18n.Errorf("请在配置文件中设置正确的 %s.access_key", section)
+	}
+	if conn.SecretKey == "" || conn.SecretKey == "your-secret-key" {
+		return i18n.Errorf("请在配置文件中设置正确的 %s.secret_key", section)
+	}
+
+	return nil
+}
+
+// validateTLS 验证TLS证书文件是否存在，客户端证书和私钥必须同时设置
+func validateTLS(tls TLSConfig, section string) error {
+	if (tls.CertFile == "") != (tls.KeyFile == "") {
+		return i18n.Errorf("%s.tls.cert_file 和 %s.tls.key_file 必须同时设置", section, section)
+	}
+	files := []struct{ name, path string }{
+		{"ca_file", tls.CAFile},
+		{"cert_file", tls.CertFile},
+		{"key_file", tls.KeyFile},
+	}
+	for _, file := range files {
+		if file.path == "" {
+			continue
+		}
+		if _, err := os.Stat(file.path); err != nil {
+			return i18n.Errorf("%s.tls.%s 无法读取: %w", section, file.name, err)
+		}
+	}
 	return nil
 }
 
+// OverrideConnection 用命令行参数覆盖连接配置，同时作用于所有桶，空值表示不覆盖
+func (s *MultiBucketSettings) OverrideConnection(endpoint, accessKey, secretKey, region string) {
+	override := func(target *string, value string) {
+		if value != "" {
+			*target = value
+		}
+	}
+
+	override(&s.Endpoint, endpoint)
+	override(&s.AccessKey, accessKey)
+	override(&s.SecretKey, secretKey)
+	override(&s.Region, region)
+	// 命令行指定密钥时不再使用共享凭证文件
+	if accessKey != "" {
+		s.Profile = ""
+	}
+
+	for i := range s.Buckets {
+		bucket := &s.Buckets[i]
+		override(&bucket.Endpoint, endpoint)
+		override(&bucket.AccessKey, accessKey)
+		override(&bucket.SecretKey, secretKey)
+		override(&bucket.Region, region)
+		if accessKey != "" {
+			bucket.Profile = ""
+		}
+	}
+}
+
 // GetBucketCount 获取桶的数量
 func (cm *ConfigManager) GetBucketCount() int {
-	return len(cm.config.Buckets)
+	return len(cm.config.Load().Buckets)
 }
 
 // ToBucketSettings 将配置转换为桶备份设置（统一处理）
 func (cm *ConfigManager) ToBucketSettings() *MultiBucketSettings {
+	cfg := cm.config.Load()
+
 	settings := &MultiBucketSettings{
-		Endpoint:    cm.config.Ceph.Endpoint,
-		AccessKey:   cm.config.Ceph.AccessKey,
-		SecretKey:   cm.config.Ceph.SecretKey,
-		Incremental: viper.GetBool("backup.incremental"),
-		ConfigFile:  cm.configPath,
+		Endpoint:           cfg.Ceph.Endpoint,
+		AccessKey:          cfg.Ceph.AccessKey,
+		SecretKey:          cfg.Ceph.SecretKey,
+		Profile:            cfg.Ceph.Profile,
+		Region:             cfg.Ceph.Region,
+		PathStyle:          cfg.Ceph.UsePathStyle(),
+		Transport:          cfg.Ceph.Transport,
+		TLS:                cfg.Ceph.TLS,
+		Proxy:              cfg.Ceph.Proxy,
+		Timeouts:           cfg.Ceph.Timeouts,
+		Incremental:        cfg.Backup.Incremental,
+		StateBackend:       cmp.Or(cfg.Backup.StateBackend, statestore.BackendJSON),
+		CheckpointFiles:    cfg.Backup.CheckpointFiles,
+		CheckpointInterval: cfg.Backup.CheckpointInterval,
+		PruneDeletedAfter:  cfg.Backup.PruneDeletedAfter,
+		Resume:             true,
+		HistoryFile:        cmp.Or(cfg.Backup.HistoryFile, history.DefaultFile),
+		ConfigFile:         cm.configPath,
+		MaxAttempts:        cfg.Retry.MaxAttempts,
+		RetryDelay:         cfg.Retry.Delay,
+		Notifications:      cfg.Notifications,
 	}
 
 	// 转换桶配置
-	for _, bucketConfig := range cm.config.Buckets {
+	for _, bucketConfig := range cfg.Buckets {
+		// 桶中的设置优先，其次是defaults，最后是backup中的全局值
+		defaults := cfg.Defaults
 		bucketSettings := BucketSettings{
-			Name:      bucketConfig.Name,
-			OutputDir: bucketConfig.OutputDir,
-			StateFile: bucketConfig.StateFile,
-			Workers:   bucketConfig.Workers,
-			Verbose:   bucketConfig.Verbose,
+			Name:             bucketConfig.Name,
+			Remote:           bucketConfig.Remote,
+			Direction:        cmp.Or(bucketConfig.Direction, DirectionBackup),
+			Schedule:         bucketConfig.Schedule,
+			Prefix:           NormalizePrefix(bucketConfig.Prefix),
+			OutputDir:        bucketConfig.OutputDir,
+			StateFile:        cmp.Or(bucketConfig.StateFile, state.FileName(stateDirection(bucketConfig.Direction), bucketConfig.Remote, bucketConfig.Name, bucketConfig.Prefix)),
+			Workers:          cmp.Or(bucketConfig.Workers, defaults.Workers, cfg.Backup.Workers),
+			PartsConcurrency: cmp.Or(bucketConfig.PartsConcurrency, defaults.PartsConcurrency, cfg.Backup.PartsConcurrency),
+			Verbose:          inheritBool(bucketConfig.Verbose, defaults.Verbose, cfg.Backup.Verbose),
+			DirMarkers:       cmp.Or(bucketConfig.DirMarkers, defaults.DirMarkers, "all"),
+			StorageClass:     cmp.Or(bucketConfig.StorageClass, defaults.StorageClass),
+			Headers:          bucketConfig.Headers,
+			Compress:         bucketConfig.Compress,
+			Decompress:       bucketConfig.Decompress,
+			Include:          inheritList(bucketConfig.Include, defaults.Include, cfg.Backup.Include),
+			Exclude:          inheritList(bucketConfig.Exclude, defaults.Exclude, cfg.Backup.Exclude),
+			SourceDirs:       bucketConfig.SourceDirs,
+		}
+		if bandwidth := cmp.Or(bucketConfig.Bandwidth, defaults.Bandwidth); bandwidth != "" {
+			// 已在ValidateConfig中校验过格式
+			bucketSettings.Bandwidth, _ = progress.ParseSize(bandwidth)
 		}
 
-		// 使用全局默认值填充未设置的字段
-		if bucketSettings.StateFile == "" {
-			bucketSettings.StateFile = fmt.Sprintf(".backup_state_%s.json", bucketConfig.Name)
+		// 解析桶使用的连接，未引用remote时使用ceph配置
+		conn := cfg.Ceph
+		if remote, ok := cfg.Remotes[bucketConfig.Remote]; ok && bucketConfig.Remote != "" {
+			conn = remote
 		}
-		if bucketSettings.Workers == 0 {
-			bucketSettings.Workers = viper.GetInt("backup.workers")
+		bucketSettings.Endpoint = conn.Endpoint
+		bucketSettings.AccessKey = conn.AccessKey
+		bucketSettings.SecretKey = conn.SecretKey
+		bucketSettings.Profile = conn.Profile
+		bucketSettings.Region = conn.Region
+		bucketSettings.PathStyle = conn.UsePathStyle()
+		bucketSettings.Transport = conn.Transport
+		bucketSettings.TLS = conn.TLS
+		bucketSettings.Proxy = conn.Proxy
+		bucketSettings.Timeouts = conn.Timeouts
+
+		if bucketConfig.Direction == DirectionReplicate {
+			bucketSettings.Target = &ReplicateTarget{
+				Remote:     bucketConfig.Target,
+				Bucket:     cmp.Or(bucketConfig.TargetBucket, bucketConfig.Name),
+				CephConfig: cfg.Remotes[bucketConfig.Target],
+			}
+			// 已在ValidateConfig中校验过格式
+			bucketSettings.MaxMemory, _ = progress.ParseSize(bucketConfig.MaxMemory)
+			bucketSettings.VerifyCopy = bucketConfig.VerifyCopy
 		}
-		// 注意：verbose是bool类型，false是有效值，不应该被全局配置覆盖
-		// 如果用户在桶配置中明确设置了verbose: false，应该保留这个设置
 
 		settings.Buckets = append(settings.Buckets, bucketSettings)
 	}
 
 	return settings
 }
+
+// ID 返回桶的唯一标识：[连接:]桶名[/前缀]，方向不是backup时附加方向。
+// 不同集群或前缀下可以有同名的桶，按名称区分会相互覆盖
+func (b BucketSettings) ID() string {
+	id := b.Name
+	if b.Remote != "" {
+		id = b.Remote + ":" + id
+	}
+	if prefix := strings.Trim(b.Prefix, "/"); prefix != "" {
+		id += "/" + prefix
+	}
+	if b.Direction != "" && b.Direction != DirectionBackup {
+		id += " (" + b.Direction + ")"
+	}
+	return id
+}
+
+// UploadStateFile 返回上传使用的状态文件，每个连接上的桶（及前缀）独立，与备份状态文件使用相同的压缩方式
+func (b BucketSettings) UploadStateFile() string {
+	return state.FileName(state.Upload, b.Remote, b.Name, b.Prefix) + compress.Suffix(compress.FromSuffix(b.StateFile))
+}
+
+// TargetSettings 返回复制目标的桶设置：使用目标的连接和桶名，其他设置与源桶相同。
+// 桶不是复制方向时返回原来的设置
+func (b BucketSettings) TargetSettings() BucketSettings {
+	if b.Target == nil {
+		return b
+	}
+	target := b
+	target.Name = b.Target.Bucket
+	target.Remote = b.Target.Remote
+	target.Endpoint = b.Target.Endpoint
+	target.AccessKey = b.Target.AccessKey
+	target.SecretKey = b.Target.SecretKey
+	target.Profile = b.Target.Profile
+	target.Region = b.Target.Region
+	target.PathStyle = b.Target.UsePathStyle()
+	target.Transport = b.Target.Transport
+	target.TLS = b.Target.TLS
+	target.Proxy = b.Target.Proxy
+	target.Timeouts = b.Target.Timeouts
+	target.Target = nil
+	return target
+}
+
+// stateDirection 返回桶的默认状态文件名使用的方向，复制以外的方向使用备份状态的文件名（上传状态文件见 UploadStateFile）
+func stateDirection(direction string) string {
+	if direction == DirectionReplicate {
+		return state.Replicate
+	}
+	return state.Backup
+}
+
+// NormalizePrefix 规范化对象键前缀：去掉首尾的"/"，非空时以"/"结尾
+func NormalizePrefix(prefix string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return ""
+	}
+	return prefix + "/"
+}
+
+// inheritBool 桶中设置了值时使用桶的值，其次使用defaults，都未设置时使用fallback
+func inheritBool(bucket, defaults *bool, fallback bool) bool {
+	switch {
+	case bucket != nil:
+		return *bucket
+	case defaults != nil:
+		return *defaults
+	default:
+		return fallback
+	}
+}
+
+// inheritList 桶中设置了列表时使用桶的列表（包括空列表），其次使用defaults，最后使用fallback
+func inheritList(bucket, defaults, fallback []string) []string {
+	switch {
+	case bucket != nil:
+		return bucket
+	case defaults != nil:
+		return defaults
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"objectsync/internal/i18n"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AppendBuckets 将桶配置追加到配置文件的 buckets 数组，保留文件中的注释，返回原文件的备份路径
+func AppendBuckets(path string, buckets []BucketConfig) (string, error) {
+	data, root, err := readConfigNode(path)
+	if err != nil {
+		return "", err
+	}
+	doc := root.Content[0]
+
+	sequence := mappingValue(doc, "buckets")
+	if sequence == nil {
+		sequence = &yaml.Node{Kind: yaml.SequenceNode}
+		doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "buckets"}, sequence)
+	} else if sequence.Kind != yaml.SequenceNode {
+		return "", i18n.Errorf("buckets 不是数组，请手动处理")
+	}
+	// 空数组（buckets: []）使用流式风格，追加条目后改为块风格
+	sequence.Style = 0
+
+	for _, bucket := range buckets {
+		entry := &yaml.Node{Kind: yaml.MappingNode}
+		appendScalar(entry, "name", bucket.Name)
+		appendScalar(entry, "output_dir", bucket.OutputDir)
+		appendScalar(entry, "state_file", bucket.StateFile)
+		sequence.Content = append(sequence.Content, entry)
+	}
+
+	return writeConfigNode(path, data, root)
+}
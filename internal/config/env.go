@@ -0,0 +1,79 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+
+	"objectsync/internal/i18n"
+)
+
+// envPattern 匹配 ${VAR} 和 ${VAR:-默认值} 形式的环境变量引用
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// expandEnv 展开配置中所有字符串值里的环境变量引用，引用了未设置且没有默认值的变量时返回错误
+func expandEnv(cfg *Config) error {
+	missing := make(map[string]bool)
+	expandValue(reflect.ValueOf(cfg).Elem(), missing)
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		errs = append(errs, i18n.Errorf("环境变量 %s 未设置", name))
+	}
+	return errors.Join(errs...)
+}
+
+// expandValue 递归展开结构体、切片和映射中的字符串
+func expandValue(value reflect.Value, missing map[string]bool) {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(expandString(value.String(), missing))
+	case reflect.Pointer:
+		if !value.IsNil() {
+			expandValue(value.Elem(), missing)
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).IsExported() {
+				expandValue(value.Field(i), missing)
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			expandValue(value.Index(i), missing)
+		}
+	case reflect.Map:
+		// 映射的值不可寻址，展开副本后写回
+		for _, key := range value.MapKeys() {
+			item := reflect.New(value.Type().Elem()).Elem()
+			item.Set(value.MapIndex(key))
+			expandValue(item, missing)
+			value.SetMapIndex(key, item)
+		}
+	}
+}
+
+// expandString 展开单个字符串中的环境变量引用
+func expandString(text string, missing map[string]bool) string {
+	return envPattern.ReplaceAllStringFunc(text, func(ref string) string {
+		match := envPattern.FindStringSubmatch(ref)
+		value, ok := os.LookupEnv(match[1])
+		// 与shell一致，:- 在变量未设置或为空时使用默认值
+		if match[2] != "" && value == "" {
+			return match[3]
+		}
+		if ok {
+			return value
+		}
+		missing[match[1]] = true
+		return ref
+	})
+}
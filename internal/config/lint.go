@@ -0,0 +1,160 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"objectsync/internal/i18n"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 配置检查问题的级别
+const (
+	LintError   = "错误" // 配置无法加载
+	LintWarning = "警告" // 可以加载，但很可能不是预期的效果
+	LintInfo    = "提示" // 与生成的默认配置不同
+)
+
+// LintIssue 配置检查发现的问题
+type LintIssue struct {
+	Level   string
+	Message string
+}
+
+// placeholderValues 生成的配置文件中需要替换的示例值
+var placeholderValues = map[string]bool{
+	"http://192.168.1.100:7480": true,
+	"your-access-key":           true,
+	"your-secret-key":           true,
+	"your-bucket-name":          true,
+}
+
+// unusedKeys 可以解析但不会生效的配置项及原因
+var unusedKeys = map[string]string{
+	"backup.state_file": "每个桶使用自己的 state_file（默认为 .backup_state_<桶名>.json）",
+}
+
+// generatedDefaults 生成的配置文件和setDefaults中的默认值
+var generatedDefaults = map[string]string{
+	"backup.incremental":         "true",
+	"backup.workers":             "5",
+	"backup.parts_concurrency":   "5",
+	"backup.verbose":             "false",
+	"backup.checkpoint_files":    "1000",
+	"backup.checkpoint_interval": "5m",
+	"backup.prune_deleted_after": "720h",
+	"retry.max_attempts":         "3",
+	"retry.delay":                "5s",
+}
+
+// Lint 检查配置文件中的废弃或未知配置项、不生效的配置项、未替换的示例值以及与默认值不同的设置
+func Lint(path string) ([]LintIssue, error) {
+	_, root, err := readConfigNode(path)
+	if err != nil {
+		return nil, err
+	}
+	doc := root.Content[0]
+
+	var issues []LintIssue
+
+	// 废弃和未知的配置项
+	var errs []error
+	walkSchema(doc, reflect.TypeOf(Config{}), "", &errs)
+	for _, err := range errs {
+		issues = append(issues, LintIssue{Level: LintError, Message: err.Error()})
+	}
+
+	// 不生效的配置项
+	for key, reason := range sortedMap(unusedKeys) {
+		if keyNode, _ := lookupNode(doc, key); keyNode != nil {
+			issues = append(issues, LintIssue{
+				Level:   LintWarning,
+				Message: i18n.Sprintf("第 %d 行: %s 不会生效，%s", keyNode.Line, key, i18n.T(reason)),
+			})
+		}
+	}
+
+	// 未替换的示例值
+	walkScalars(doc, "", func(name string, node *yaml.Node) {
+		if placeholderValues[node.Value] {
+			issues = append(issues, LintIssue{
+				Level:   LintWarning,
+				Message: i18n.Sprintf("第 %d 行: %s 仍是示例值 %s", node.Line, name, node.Value),
+			})
+		}
+	})
+
+	// 与默认值不同的设置
+	for key, value := range sortedMap(generatedDefaults) {
+		if _, valueNode := lookupNode(doc, key); valueNode != nil && valueNode.Value != value {
+			issues = append(issues, LintIssue{
+				Level:   LintInfo,
+				Message: i18n.Sprintf("第 %d 行: %s 为 %s（默认 %s）", valueNode.Line, key, valueNode.Value, value),
+			})
+		}
+	}
+
+	return issues, nil
+}
+
+// lookupNode 按点分隔的路径查找映射中的键和值节点
+func lookupNode(doc *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	node := doc
+	var keyNode *yaml.Node
+	for _, part := range strings.Split(key, ".") {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil, nil
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == part {
+				keyNode, next = node.Content[i], node.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return nil, nil
+		}
+		node = next
+	}
+	return keyNode, node
+}
+
+// walkScalars 遍历所有标量值，name为值所在的配置项路径
+func walkScalars(node *yaml.Node, name string, visit func(name string, node *yaml.Node)) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		visit(name, node)
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if name != "" {
+				key = name + "." + key
+			}
+			walkScalars(node.Content[i+1], key, visit)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			walkScalars(item, fmt.Sprintf("%s[%d]", name, i), visit)
+		}
+	}
+}
+
+// sortedMap 按键的顺序遍历映射，保证输出稳定
+func sortedMap(m map[string]string) func(yield func(string, string) bool) {
+	return func(yield func(string, string) bool) {
+		keys := make([]string, 0, len(m))
+		for key := range m {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			if !yield(key, m[key]) {
+				return
+			}
+		}
+	}
+}
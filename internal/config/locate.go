@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// EnvConfigFile 指定配置文件路径的环境变量
+const EnvConfigFile = "OBJECTSYNC_CONFIG"
+
+// DefaultConfigFile 找不到配置文件时使用的路径，在当前目录创建默认配置
+const DefaultConfigFile = "config.yaml"
+
+// SearchPaths 未指定配置文件时依次查找的位置：当前目录、用户配置目录和系统配置目录
+func SearchPaths() []string {
+	paths := []string{DefaultConfigFile}
+	if dir := userConfigDir(); dir != "" {
+		paths = append(paths, filepath.Join(dir, "objectsync", "config.yaml"))
+	}
+	if runtime.GOOS != "windows" {
+		paths = append(paths, "/etc/objectsync/config.yaml")
+	}
+	return paths
+}
+
+// userConfigDir 返回用户配置目录：$XDG_CONFIG_HOME，未设置时为 ~/.config。
+// 不使用 os.UserConfigDir，macOS 和 Windows 上也与帮助中说明的位置一致
+func userConfigDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config")
+}
+
+// Locate 返回要使用的配置文件：指定了path时直接使用，其次是 OBJECTSYNC_CONFIG 环境变量，
+// 然后是搜索路径中第一个存在的文件，都没有时返回 DefaultConfigFile
+func Locate(path string) string {
+	if path != "" {
+		return path
+	}
+	if path := os.Getenv(EnvConfigFile); path != "" {
+		return path
+	}
+	for _, candidate := range SearchPaths() {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return DefaultConfigFile
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"objectsync/internal/i18n"
+
+	"gopkg.in/yaml.v3"
+)
+
+// legacyStateFile 旧版单桶配置默认使用的状态文件
+const legacyStateFile = ".backup_state.json"
+
+// MigrateResult 配置迁移结果
+type MigrateResult struct {
+	Changes    []string // 修改说明，为空表示无需迁移
+	BackupPath string   // 原配置文件的备份路径
+}
+
+// Migrate 将旧版单桶配置（ceph.bucket 或顶层 bucket）转换为 buckets 数组，
+// 写回前先备份原文件，保留文件中的注释和其他配置项
+func Migrate(path string) (*MigrateResult, error) {
+	data, root, err := readConfigNode(path)
+	if err != nil {
+		return nil, err
+	}
+	doc := root.Content[0]
+
+	result := &MigrateResult{}
+
+	// 旧版的桶名可能在ceph段中，也可能在顶层
+	var name string
+	if ceph := mappingValue(doc, "ceph"); ceph != nil {
+		if value := removeMappingKey(ceph, "bucket"); value != nil {
+			name = value.Value
+			result.Changes = append(result.Changes, i18n.Sprintf("移除 ceph.bucket（%s）", value.Value))
+		}
+	}
+	if value := removeMappingKey(doc, "bucket"); value != nil {
+		if name != "" && name != value.Value {
+			return nil, i18n.Errorf("ceph.bucket（%s）与 bucket（%s）不一致，请手动处理", name, value.Value)
+		}
+		name = value.Value
+		result.Changes = append(result.Changes, i18n.Sprintf("移除顶层 bucket（%s）", value.Value))
+	}
+	if name == "" {
+		return result, nil
+	}
+
+	buckets := mappingValue(doc, "buckets")
+	if buckets == nil {
+		buckets = &yaml.Node{Kind: yaml.SequenceNode}
+		doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "buckets"}, buckets)
+	} else if buckets.Kind != yaml.SequenceNode {
+		return nil, i18n.Errorf("buckets 不是数组，请手动处理")
+	}
+	for _, item := range buckets.Content {
+		if value := mappingValue(item, "name"); value != nil && value.Value == name {
+			result.Changes = append(result.Changes, i18n.Sprintf("buckets 中已存在桶 %s，未重复添加", name))
+			result.BackupPath, err = writeConfigNode(path, data, root)
+			return result, err
+		}
+	}
+
+	// 旧版的输出目录和状态文件在backup段中，沿用原状态文件以保留增量记录
+	outputDir, stateFile := "", legacyStateFile
+	if backup := mappingValue(doc, "backup"); backup != nil {
+		if value := mappingValue(backup, "output_dir"); value != nil {
+			outputDir = value.Value
+		}
+		if value := mappingValue(backup, "state_file"); value != nil && value.Value != "" {
+			stateFile = value.Value
+		}
+	}
+
+	entry := &yaml.Node{Kind: yaml.MappingNode}
+	appendScalar(entry, "name", name)
+	if outputDir != "" {
+		appendScalar(entry, "output_dir", outputDir)
+	}
+	appendScalar(entry, "state_file", stateFile)
+	buckets.Content = append(buckets.Content, entry)
+
+	result.Changes = append(result.Changes,
+		i18n.Sprintf("添加桶 %s（output_dir: %s，state_file: %s）", name, outputDir, stateFile))
+
+	result.BackupPath, err = writeConfigNode(path, data, root)
+	return result, err
+}
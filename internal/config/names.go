@@ -0,0 +1,59 @@
+package config
+
+import (
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// namesConfig 只包含名称的配置结构，补全时不需要解析密钥和环境变量
+type namesConfig struct {
+	Buckets []struct {
+		Name string `yaml:"name"`
+	} `yaml:"buckets"`
+	Remotes  map[string]yaml.Node `yaml:"remotes"`
+	Clusters []struct {
+		Name    string `yaml:"name"`
+		Buckets []struct {
+			Name string `yaml:"name"`
+		} `yaml:"buckets"`
+	} `yaml:"clusters"`
+}
+
+// ListNames 读取配置文件中的桶名和集群（包括remotes）名，用于命令行补全。
+// 不执行密钥命令、不访问密钥环，文件无法读取或解析时返回空
+func ListNames(path string) (buckets, clusters []string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil
+	}
+	var cfg namesConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool)
+	addBucket := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			buckets = append(buckets, name)
+		}
+	}
+	for _, bucket := range cfg.Buckets {
+		addBucket(bucket.Name)
+	}
+	for _, cluster := range cfg.Clusters {
+		clusters = append(clusters, cluster.Name)
+		for _, bucket := range cluster.Buckets {
+			addBucket(bucket.Name)
+		}
+	}
+	for name := range cfg.Remotes {
+		clusters = append(clusters, name)
+	}
+
+	sort.Strings(buckets)
+	sort.Strings(clusters)
+	return buckets, clusters
+}
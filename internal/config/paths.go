@@ -0,0 +1,121 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"objectsync/internal/i18n"
+	"objectsync/internal/statestore"
+)
+
+// ValidatePaths 检查每个桶的输出目录是否存在或可以创建、状态文件是否可写，
+// 以及桶之间是否共用了输出目录或状态文件，一次返回所有问题
+func (cm *ConfigManager) ValidatePaths() error {
+	var errs []error
+	outputDirs := make(map[string]string)
+	stateFiles := make(map[string]string)
+
+	settings := cm.ToBucketSettings()
+	for _, bucket := range settings.Buckets {
+		if bucket.OutputDir != "" {
+			if err := checkDirCreatable(bucket.OutputDir); err != nil {
+				errs = append(errs, i18n.Errorf("桶 %s 的 output_dir %s: %w", bucket.Name, bucket.OutputDir, err))
+			}
+			key := pathKey(bucket.OutputDir)
+			if other, ok := outputDirs[key]; ok {
+				errs = append(errs, i18n.Errorf("桶 %s 与桶 %s 使用了相同的 output_dir: %s", bucket.Name, other, bucket.OutputDir))
+			} else {
+				outputDirs[key] = bucket.Name
+			}
+		}
+
+		// bbolt后端检查实际使用的数据库文件，不同扩展名的状态文件可能对应同一个数据库
+		stateFile := statestore.Path(bucket.StateFile, settings.StateBackend)
+		if err := checkFileWritable(stateFile); err != nil {
+			errs = append(errs, i18n.Errorf("桶 %s 的 state_file %s: %w", bucket.Name, stateFile, err))
+		}
+		key := pathKey(stateFile)
+		if other, ok := stateFiles[key]; ok {
+			errs = append(errs, i18n.Errorf("桶 %s 与桶 %s 使用了相同的 state_file: %s", bucket.Name, other, stateFile))
+		} else {
+			stateFiles[key] = bucket.Name
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// pathKey 返回用于比较的绝对路径，无法获取时使用清理后的原路径
+func pathKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// checkDirCreatable 检查目录已存在，或者最近的已存在上级目录可写（可以创建该目录）
+func checkDirCreatable(dir string) error {
+	info, err := os.Stat(dir)
+	if err == nil {
+		if !info.IsDir() {
+			return i18n.Errorf("已存在同名文件，不是目录")
+		}
+		return checkDirWritable(dir)
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+
+	parent := filepath.Dir(filepath.Clean(dir))
+	for {
+		info, err := os.Stat(parent)
+		if err == nil {
+			if !info.IsDir() {
+				return i18n.Errorf("上级路径 %s 不是目录", parent)
+			}
+			if err := checkDirWritable(parent); err != nil {
+				return i18n.Errorf("无法创建目录: %w", err)
+			}
+			return nil
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+		next := filepath.Dir(parent)
+		if next == parent {
+			return i18n.Errorf("找不到已存在的上级目录")
+		}
+		parent = next
+	}
+}
+
+// checkDirWritable 通过创建临时文件检查目录是否可写
+func checkDirWritable(dir string) error {
+	file, err := os.CreateTemp(dir, ".objectsync-check-*")
+	if err != nil {
+		return i18n.Errorf("目录不可写: %w", err)
+	}
+	name := file.Name()
+	file.Close()
+	return os.Remove(name)
+}
+
+// checkFileWritable 检查文件可以写入：已存在时以写方式打开，否则检查所在目录
+func checkFileWritable(path string) error {
+	info, err := os.Stat(path)
+	if err == nil {
+		if info.IsDir() {
+			return i18n.Errorf("是一个目录")
+		}
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+		if err != nil {
+			return i18n.Errorf("文件不可写: %w", err)
+		}
+		return file.Close()
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+	return checkDirCreatable(filepath.Dir(path))
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"objectsync/internal/i18n"
+
+	"gopkg.in/yaml.v3"
+)
+
+// deprecatedKeys 旧版本支持、现已移除的配置项及迁移方法
+var deprecatedKeys = map[string]string{
+	"bucket":      "请运行 objectsync config migrate 转换为 buckets",
+	"ceph.bucket": "请运行 objectsync config migrate 转换为 buckets",
+}
+
+// checkUnknownKeys 检查配置文件中是否有结构体中不存在的配置项（如拼写错误），报告所在行号
+func checkUnknownKeys(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return i18n.Errorf("解析配置文件失败: %w", err)
+	}
+	if len(root.Content) == 0 {
+		return nil
+	}
+
+	var errs []error
+	walkSchema(root.Content[0], reflect.TypeOf(Config{}), "", &errs)
+	return errors.Join(errs...)
+}
+
+// walkSchema 按结构体的mapstructure标签递归检查YAML节点
+func walkSchema(node *yaml.Node, typ reflect.Type, prefix string, errs *[]error) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		fields := schemaFields(typ)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+			name := prefix + keyNode.Value
+			field, ok := fields[strings.ToLower(keyNode.Value)]
+			if !ok {
+				if hint, deprecated := deprecatedKeys[name]; deprecated {
+					*errs = append(*errs, i18n.Errorf("第 %d 行: 配置项 %s 已废弃，%s", keyNode.Line, name, i18n.T(hint)))
+					continue
+				}
+				msg := i18n.Sprintf("第 %d 行: 未知的配置项 %s", keyNode.Line, name)
+				if suggestion := closestKey(keyNode.Value, fields); suggestion != "" {
+					msg += i18n.Sprintf("（是否为 %s？）", prefix+suggestion)
+				}
+				*errs = append(*errs, errors.New(msg))
+				continue
+			}
+			walkSchema(valueNode, field, name+".", errs)
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			walkSchema(node.Content[i+1], typ.Elem(), prefix+node.Content[i].Value+".", errs)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return
+		}
+		base := strings.TrimSuffix(prefix, ".")
+		for i, item := range node.Content {
+			walkSchema(item, typ.Elem(), fmt.Sprintf("%s[%d].", base, i), errs)
+		}
+	}
+}
+
+// schemaFields 返回结构体的配置项名称（小写）到字段类型的映射
+func schemaFields(typ reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, options, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		// squash的嵌入结构体字段与外层字段位于同一层级
+		if options == "squash" {
+			for embedded, fieldType := range schemaFields(field.Type) {
+				fields[embedded] = fieldType
+			}
+			continue
+		}
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// closestKey 找出与未知配置项最接近的已知配置项，差异过大时返回空字符串
+func closestKey(key string, fields map[string]reflect.Type) string {
+	key = strings.ToLower(key)
+	best, bestDistance := "", len(key)/2+1
+	for name := range fields {
+		if d := editDistance(key, name); d < bestDistance || (d == bestDistance && name < best) {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// editDistance 计算两个字符串的编辑距离
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"objectsync/internal/i18n"
+
+	"github.com/zalando/go-keyring"
+)
+
+// KeyringPrefix 配置值中引用系统密钥环的前缀，格式为 keyring:服务名/账户名
+const KeyringPrefix = "keyring:"
+
+// ParseKeyringRef 解析 keyring:服务名/账户名 形式的引用，不是引用时ok为false
+func ParseKeyringRef(value string) (service, account string, ok bool, err error) {
+	if !strings.HasPrefix(value, KeyringPrefix) {
+		return "", "", false, nil
+	}
+
+	service, account, found := strings.Cut(strings.TrimPrefix(value, KeyringPrefix), "/")
+	if !found || service == "" || account == "" {
+		return "", "", true, i18n.Errorf("无效的密钥环引用: %s（格式: keyring:服务名/账户名）", value)
+	}
+	return service, account, true, nil
+}
+
+// SetKeyringSecret 将密钥保存到系统密钥环（Windows凭据管理器、macOS钥匙串或Secret Service）
+func SetKeyringSecret(ref, secret string) error {
+	service, account, ok, err := ParseKeyringRef(ref)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return i18n.Errorf("密钥环引用必须以 %s 开头: %s", KeyringPrefix, ref)
+	}
+	return keyring.Set(service, account, secret)
+}
+
+// resolveSecret 将密钥环引用替换为系统密钥环中保存的值，普通值原样返回
+func resolveSecret(value string) (string, error) {
+	service, account, ok, err := ParseKeyringRef(value)
+	if err != nil || !ok {
+		return value, err
+	}
+
+	secret, err := keyring.Get(service, account)
+	if err != nil {
+		return "", i18n.Errorf("从系统密钥环读取 %s 失败: %w", value, err)
+	}
+	return secret, nil
+}
+
+// readSecretSource 从文件或外部命令读取密钥，两者都未设置时返回原值
+func readSecretSource(value, file, command, name string) (string, error) {
+	if file != "" && command != "" {
+		return "", i18n.Errorf("%s_file 和 %s_cmd 不能同时设置", name, name)
+	}
+
+	var output []byte
+	var err error
+	switch {
+	case file != "":
+		if output, err = os.ReadFile(file); err != nil {
+			return "", i18n.Errorf("读取 %s_file 失败: %w", name, err)
+		}
+	case command != "":
+		if output, err = shellCommand(command).Output(); err != nil {
+			return "", i18n.Errorf("执行 %s_cmd 失败: %w", name, err)
+		}
+	default:
+		return value, nil
+	}
+
+	// 文件和命令输出通常以换行结尾
+	secret := strings.TrimRight(string(output), "\r\n")
+	if secret == "" {
+		return "", i18n.Errorf("%s 的来源为空", name)
+	}
+	return secret, nil
+}
+
+// shellCommand 通过系统shell执行命令，支持管道和参数
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+// resolveSecrets 解析所有连接配置中的密钥文件、密钥命令和密钥环引用
+func (cm *ConfigManager) resolveSecrets() error {
+	resolve := func(conn *CephConfig) error {
+		var err error
+		if conn.AccessKey, err = readSecretSource(conn.AccessKey, conn.AccessKeyFile, conn.AccessKeyCmd, "access_key"); err != nil {
+			return err
+		}
+		if conn.SecretKey, err = readSecretSource(conn.SecretKey, conn.SecretKeyFile, conn.SecretKeyCmd, "secret_key"); err != nil {
+			return err
+		}
+		if conn.AccessKey, err = resolveSecret(conn.AccessKey); err != nil {
+			return err
+		}
+		if conn.SecretKey, err = resolveSecret(conn.SecretKey); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	if err := resolve(&cm.config.Load().Ceph); err != nil {
+		return err
+	}
+	for name, remote := range cm.config.Load().Remotes {
+		if err := resolve(&remote); err != nil {
+			return err
+		}
+		cm.config.Load().Remotes[name] = remote
+	}
+	return nil
+}
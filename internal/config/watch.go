@@ -0,0 +1,43 @@
+package config
+
+import (
+	"objectsync/internal/i18n"
+	"objectsync/internal/logging"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// logger 配置模块的日志
+var logger = logging.New("config")
+
+// Watch 监听配置文件变化，新配置解析和验证全部通过后整体替换并调用onChange，失败时继续使用原配置
+// 正在进行的传输使用各自创建时的选项，不受重新加载影响，新配置从下一轮开始生效
+func (cm *ConfigManager) Watch(onChange func(settings *MultiBucketSettings)) {
+	cm.viper.OnConfigChange(func(event fsnotify.Event) {
+		if err := cm.reload(); err != nil {
+			logger.Warnf("配置文件 %s 重新加载失败，继续使用原配置: %v", cm.configPath, err)
+			return
+		}
+
+		logger.Infof("配置文件 %s 已重新加载", cm.configPath)
+		if onChange != nil {
+			onChange(cm.ToBucketSettings())
+		}
+	})
+	cm.viper.WatchConfig()
+}
+
+// reload 用新的viper实例重新读取并验证配置，成功后原子替换当前配置，
+// 监听使用的viper实例只用于接收变化通知
+func (cm *ConfigManager) reload() error {
+	candidate := newConfigManager(cm.configPath)
+	if err := candidate.read(); err != nil {
+		return err
+	}
+	if err := candidate.ValidateConfig(); err != nil {
+		return i18n.Errorf("配置验证失败: %w", err)
+	}
+
+	cm.config.Store(candidate.config.Load())
+	return nil
+}
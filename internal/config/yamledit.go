@@ -0,0 +1,92 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	"objectsync/internal/i18n"
+
+	"gopkg.in/yaml.v3"
+)
+
+// readConfigNode 读取配置文件的YAML节点树，用于在保留注释的前提下修改配置
+func readConfigNode(path string) ([]byte, *yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, nil, i18n.Errorf("解析配置文件失败: %w", err)
+	}
+	if len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, i18n.Errorf("配置文件为空或格式不正确")
+	}
+	return data, &root, nil
+}
+
+// writeConfigNode 备份原配置文件后写入修改后的内容，返回备份文件路径
+func writeConfigNode(path string, original []byte, root *yaml.Node) (string, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return "", i18n.Errorf("生成配置文件失败: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", i18n.Errorf("生成配置文件失败: %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	backupPath := fmt.Sprintf("%s.%s.bak", path, time.Now().Format("20060102150405"))
+	if err := os.WriteFile(backupPath, original, info.Mode().Perm()); err != nil {
+		return "", i18n.Errorf("备份原配置文件失败: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), info.Mode().Perm()); err != nil {
+		return "", i18n.Errorf("写入配置文件失败: %w", err)
+	}
+	return backupPath, nil
+}
+
+// mappingValue 返回映射节点中指定键的值，不存在时返回nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// removeMappingKey 从映射节点中删除指定键，返回被删除的值
+func removeMappingKey(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			value := node.Content[i+1]
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return value
+		}
+	}
+	return nil
+}
+
+// appendScalar 向映射节点追加一个字符串键值对
+func appendScalar(node *yaml.Node, key, value string) {
+	node.Content = append(node.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Value: value},
+	)
+}
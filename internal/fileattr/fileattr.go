@@ -0,0 +1,207 @@
+package fileattr
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"objectsync/internal/i18n"
+)
+
+// 对象元数据键（与rclone兼容，实际请求头为 x-amz-meta-<key>）
+const (
+	KeyMtime = "mtime"
+	KeyMode  = "mode"
+	KeyUID   = "uid"
+	KeyGID   = "gid"
+)
+
+// Unix文件类型位，用于以rclone相同的格式记录mode
+const (
+	unixTypeDir     = 0040000
+	unixTypeRegular = 0100000
+)
+
+// Attrs 文件属性
+type Attrs struct {
+	ModTime  time.Time
+	Mode     os.FileMode
+	UID      int
+	GID      int
+	HasMode  bool
+	HasOwner bool
+}
+
+// FromFileInfo 从本地文件信息中提取文件属性
+func FromFileInfo(info os.FileInfo) Attrs {
+	attrs := Attrs{
+		ModTime: info.ModTime(),
+		Mode:    info.Mode(),
+		HasMode: true,
+		UID:     -1,
+		GID:     -1,
+	}
+	if uid, gid, ok := fileOwner(info); ok {
+		attrs.UID = uid
+		attrs.GID = gid
+		attrs.HasOwner = true
+	}
+	return attrs
+}
+
+// Metadata 将文件属性转换为对象元数据
+func (a Attrs) Metadata() map[string]string {
+	metadata := make(map[string]string)
+
+	if !a.ModTime.IsZero() {
+		metadata[KeyMtime] = FormatMtime(a.ModTime)
+	}
+	if a.HasMode {
+		mode := uint32(a.Mode.Perm())
+		if a.Mode&os.ModeSetuid != 0 {
+			mode |= 04000
+		}
+		if a.Mode&os.ModeSetgid != 0 {
+			mode |= 02000
+		}
+		if a.Mode&os.ModeSticky != 0 {
+			mode |= 01000
+		}
+		if a.Mode.IsDir() {
+			mode |= unixTypeDir
+		} else {
+			mode |= unixTypeRegular
+		}
+		metadata[KeyMode] = strconv.FormatUint(uint64(mode), 8)
+	}
+	if a.HasOwner {
+		metadata[KeyUID] = strconv.Itoa(a.UID)
+		metadata[KeyGID] = strconv.Itoa(a.GID)
+	}
+
+	return metadata
+}
+
+// Parse 从对象元数据中解析文件属性，无法识别的字段会被忽略
+func Parse(metadata map[string]string) Attrs {
+	attrs := Attrs{UID: -1, GID: -1}
+
+	if value, ok := lookup(metadata, KeyMtime); ok {
+		if t, err := ParseMtime(value); err == nil {
+			attrs.ModTime = t
+		}
+	}
+	if value, ok := lookup(metadata, KeyMode); ok {
+		if mode, err := strconv.ParseUint(value, 8, 32); err == nil {
+			attrs.Mode = os.FileMode(mode & 0777)
+			if mode&04000 != 0 {
+				attrs.Mode |= os.ModeSetuid
+			}
+			if mode&02000 != 0 {
+				attrs.Mode |= os.ModeSetgid
+			}
+			if mode&01000 != 0 {
+				attrs.Mode |= os.ModeSticky
+			}
+			attrs.HasMode = true
+		}
+	}
+	uidValue, hasUID := lookup(metadata, KeyUID)
+	gidValue, hasGID := lookup(metadata, KeyGID)
+	if hasUID && hasGID {
+		uid, uidErr := strconv.Atoi(uidValue)
+		gid, gidErr := strconv.Atoi(gidValue)
+		if uidErr == nil && gidErr == nil {
+			attrs.UID = uid
+			attrs.GID = gid
+			attrs.HasOwner = true
+		}
+	}
+
+	return attrs
+}
+
+// Apply 将文件属性应用到本地路径，fallback为元数据中没有mtime时使用的时间
+func Apply(path string, attrs Attrs, fallback time.Time) error {
+	var errs []string
+
+	// 先设置属主再设置权限，chown会清除setuid/setgid位
+	if attrs.HasOwner {
+		if err := applyOwner(path, attrs.UID, attrs.GID); err != nil {
+			errs = append(errs, i18n.Sprintf("设置属主失败: %v", err))
+		}
+	}
+	if attrs.HasMode {
+		if err := os.Chmod(path, attrs.Mode); err != nil {
+			errs = append(errs, i18n.Sprintf("设置权限失败: %v", err))
+		}
+	}
+
+	modTime := attrs.ModTime
+	if modTime.IsZero() {
+		modTime = fallback
+	}
+	if !modTime.IsZero() {
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			errs = append(errs, i18n.Sprintf("设置时间失败: %v", err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// FormatMtime 以rclone格式（Unix秒，带小数）格式化修改时间。
+// 1970年以前的时间写为负号加上秒和小数部分的绝对值，如-0.5秒为 -0.500000000，与 ParseMtime 互逆
+func FormatMtime(t time.Time) string {
+	seconds, nanos := t.Unix(), int64(t.Nanosecond())
+	if seconds >= 0 {
+		return fmt.Sprintf("%d.%09d", seconds, nanos)
+	}
+	// Unix() 向下取整，纳秒部分总是非负，换算为绝对值
+	if nanos > 0 {
+		seconds++
+		nanos = 1e9 - nanos
+	}
+	return fmt.Sprintf("-%d.%09d", -seconds, nanos)
+}
+
+// ParseMtime 解析rclone格式的修改时间，秒和小数部分分别按整数解析，避免浮点数丢失纳秒精度
+func ParseMtime(value string) (time.Time, error) {
+	whole, frac, _ := strings.Cut(strings.TrimSpace(value), ".")
+	seconds, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var nanos int64
+	if frac != "" {
+		// 小数部分补齐或截断到9位（纳秒）
+		if len(frac) > 9 {
+			frac = frac[:9]
+		}
+		n, err := strconv.ParseUint(frac+strings.Repeat("0", 9-len(frac)), 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		nanos = int64(n)
+		if strings.HasPrefix(whole, "-") {
+			nanos = -nanos
+		}
+	}
+	return time.Unix(seconds, nanos), nil
+}
+
+// lookup 忽略大小写查找元数据（SDK返回的键会被规范化为首字母大写）
+func lookup(metadata map[string]string, key string) (string, bool) {
+	for k, v := range metadata {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,25 @@
+//go:build !windows
+
+package fileattr
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileOwner 获取文件的属主信息
+func fileOwner(info os.FileInfo) (int, int, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return -1, -1, false
+	}
+	return int(stat.Uid), int(stat.Gid), true
+}
+
+// applyOwner 设置文件属主，非root用户修改为其他属主时会失败
+func applyOwner(path string, uid, gid int) error {
+	if uid == os.Getuid() && gid == os.Getgid() {
+		return nil
+	}
+	return os.Lchown(path, uid, gid)
+}
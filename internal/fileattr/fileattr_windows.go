@@ -0,0 +1,15 @@
+//go:build windows
+
+package fileattr
+
+import "os"
+
+// fileOwner Windows下没有uid/gid概念，不记录属主
+func fileOwner(info os.FileInfo) (int, int, bool) {
+	return -1, -1, false
+}
+
+// applyOwner Windows下忽略属主设置
+func applyOwner(path string, uid, gid int) error {
+	return nil
+}
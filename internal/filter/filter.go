@@ -0,0 +1,91 @@
+package filter
+
+import (
+	"path"
+	"strings"
+)
+
+// Filter 按包含/排除模式过滤对象键
+// 不含"/"的模式匹配文件名，含"/"的模式匹配完整路径；以"/"结尾的模式匹配目录及其下的所有内容，
+// 其中不含其他"/"的目录模式（如 node_modules/）匹配任意层级的同名目录
+type Filter struct {
+	include []string
+	exclude []string
+}
+
+// New 创建过滤器，include为空时包含所有对象
+func New(include, exclude []string) *Filter {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil
+	}
+	return &Filter{include: include, exclude: exclude}
+}
+
+// Match 判断对象键是否需要处理，nil过滤器匹配所有对象键
+func (f *Filter) Match(key string) bool {
+	if f == nil {
+		return true
+	}
+
+	for _, pattern := range f.exclude {
+		if matchPattern(pattern, key) {
+			return false
+		}
+	}
+
+	if len(f.include) == 0 {
+		return true
+	}
+	// 目录本身不受包含规则限制，否则其中匹配的文件也无法处理
+	if strings.HasSuffix(key, "/") {
+		return true
+	}
+	for _, pattern := range f.include {
+		if matchPattern(pattern, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchRelative 去掉前缀后判断对象键是否需要处理，桶设置了前缀时模式相对于前缀匹配
+func (f *Filter) MatchRelative(key, prefix string) bool {
+	return f.Match(strings.TrimPrefix(key, prefix))
+}
+
+// Validate 检查模式语法是否有效
+func Validate(pattern string) error {
+	_, err := path.Match(strings.TrimSuffix(pattern, "/"), "")
+	return err
+}
+
+// matchPattern 判断对象键是否匹配单个模式
+func matchPattern(pattern, key string) bool {
+	key = strings.TrimSuffix(key, "/")
+
+	// 目录模式：匹配目录本身及其下的所有内容
+	if dir, ok := strings.CutSuffix(pattern, "/"); ok {
+		if !strings.Contains(dir, "/") {
+			for _, name := range strings.Split(key, "/") {
+				if matched, _ := path.Match(dir, name); matched {
+					return true
+				}
+			}
+			return false
+		}
+		parts := strings.Split(key, "/")
+		for i := range parts {
+			if matched, _ := path.Match(dir, strings.Join(parts[:i+1], "/")); matched {
+				return true
+			}
+		}
+		return false
+	}
+
+	if strings.Contains(pattern, "/") {
+		matched, _ := path.Match(pattern, key)
+		return matched
+	}
+	matched, _ := path.Match(pattern, path.Base(key))
+	return matched
+}
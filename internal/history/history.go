@@ -0,0 +1,100 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// DefaultFile 配置中没有设置 history_file 时使用的历史记录文件
+const DefaultFile = ".objectsync_history.jsonl"
+
+// 运行结果
+const (
+	ResultSuccess = "success"
+	ResultFailed  = "failed"
+)
+
+// Record 单个桶的一次运行
+type Record struct {
+	Command string            `json:"command"` // 如 backup、upload、run、daemon
+	Labels  map[string]string `json:"labels,omitempty"`
+	Bucket  string            `json:"bucket"`
+	Started time.Time         `json:"started"`
+	Ended   time.Time         `json:"ended"`
+	Files   int64             `json:"files"`
+	Bytes   int64             `json:"bytes"`
+	Result  string            `json:"result"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// Failed 运行是否失败
+func (r Record) Failed() bool {
+	return r.Result != ResultSuccess
+}
+
+// HasLabels 运行是否带有所有指定的标签
+func (r Record) HasLabels(labels map[string]string) bool {
+	for key, value := range labels {
+		if r.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Duration 运行用时
+func (r Record) Duration() time.Duration {
+	return r.Ended.Sub(r.Started)
+}
+
+// Append 将运行记录追加到历史记录文件，每条记录一行JSON
+func Append(path string, records ...Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			file.Close()
+			return err
+		}
+		writer.Write(append(line, '\n'))
+	}
+	err = writer.Flush()
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Load 按写入顺序读取所有运行记录，文件不存在时返回空列表。
+// 写入时被中断留下的不完整行被忽略
+func Load(path string) ([]Record, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record Record
+		if json.Unmarshal(scanner.Bytes(), &record) == nil {
+			records = append(records, record)
+		}
+	}
+	return records, scanner.Err()
+}
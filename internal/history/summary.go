@@ -0,0 +1,105 @@
+package history
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// BucketSummary 一段时间内单个桶的运行统计
+type BucketSummary struct {
+	Bucket      string
+	Runs        int
+	Failed      int
+	Files       int64 // 传输的文件数
+	Bytes       int64 // 传输的数据量
+	LastRun     time.Time
+	LastSuccess time.Time // 从未成功时为零值
+}
+
+// SuccessRate 成功运行所占的比例（0到1），没有运行时返回0
+func (s BucketSummary) SuccessRate() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return float64(s.Runs-s.Failed) / float64(s.Runs)
+}
+
+// Failure 同一个桶中重复出现的同一种错误
+type Failure struct {
+	Bucket   string
+	Error    string // 错误的第一行
+	Count    int
+	LastSeen time.Time
+}
+
+// Since 返回开始时间不早于since的记录
+func Since(records []Record, since time.Time) []Record {
+	var selected []Record
+	for _, record := range records {
+		if !record.Started.Before(since) {
+			selected = append(selected, record)
+		}
+	}
+	return selected
+}
+
+// Summarize 按桶汇总运行记录，桶按名称排序；失败按出现次数从多到少排序
+func Summarize(records []Record) ([]BucketSummary, []Failure) {
+	buckets := make(map[string]*BucketSummary)
+	failures := make(map[[2]string]*Failure)
+
+	for _, record := range records {
+		summary := buckets[record.Bucket]
+		if summary == nil {
+			summary = &BucketSummary{Bucket: record.Bucket}
+			buckets[record.Bucket] = summary
+		}
+		summary.Runs++
+		summary.Files += record.Files
+		summary.Bytes += record.Bytes
+		if record.Started.After(summary.LastRun) {
+			summary.LastRun = record.Started
+		}
+		if !record.Failed() {
+			if record.Started.After(summary.LastSuccess) {
+				summary.LastSuccess = record.Started
+			}
+			continue
+		}
+		summary.Failed++
+
+		// 错误的后续行通常是具体请求的细节，按第一行归类
+		message, _, _ := strings.Cut(record.Error, "\n")
+		key := [2]string{record.Bucket, message}
+		failure := failures[key]
+		if failure == nil {
+			failure = &Failure{Bucket: record.Bucket, Error: message}
+			failures[key] = failure
+		}
+		failure.Count++
+		if record.Started.After(failure.LastSeen) {
+			failure.LastSeen = record.Started
+		}
+	}
+
+	summaries := make([]BucketSummary, 0, len(buckets))
+	for _, summary := range buckets {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Bucket < summaries[j].Bucket
+	})
+
+	top := make([]Failure, 0, len(failures))
+	for _, failure := range failures {
+		top = append(top, *failure)
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].LastSeen.After(top[j].LastSeen)
+	})
+	return summaries, top
+}
@@ -0,0 +1,1203 @@
+package i18n
+
+// english 英文消息目录
+var english = map[string]string{
+	// app/app.go
+	"密钥环引用必须以 %s 开头: %s":                "keyring reference must start with %s: %s",
+	"请输入要保存的密钥: ":                       "Enter the secret to store: ",
+	"读取密钥失败: %w":                        "failed to read secret: %w",
+	"密钥不能为空":                            "secret must not be empty",
+	"保存密钥失败: %w":                        "failed to store secret: %w",
+	"密钥已保存，可在配置文件中使用: %s\n":             "Secret stored; reference it in the config file as: %s\n",
+	"配置迁移失败: %w":                        "config migration failed: %w",
+	"配置文件 %s 已是多桶格式，无需迁移\n":             "Config file %s already uses the multi-bucket format, nothing to migrate\n",
+	"配置文件已迁移: %s\n":                     "Config file migrated: %s\n",
+	"原配置文件已备份到: %s\n":                   "Original config file backed up to: %s\n",
+	"配置检查失败: %w":                        "config lint failed: %w",
+	"配置文件 %s 未发现问题\n":                   "No problems found in config file %s\n",
+	"配置文件有 %d 个错误":                      "config file has %d error(s)",
+	"配置加载失败: %w":                        "failed to load config: %w",
+	"配置验证失败: %w":                        "config validation failed: %w",
+	"列出桶失败: %w":                         "failed to list buckets: %w",
+	"添加桶 %s? (y/N): ":                   "Add bucket %s? (y/N): ",
+	"发现 %d 个桶，没有需要添加的桶\n":               "Found %d bucket(s), none need to be added\n",
+	"更新配置文件失败: %w":                      "failed to update config file: %w",
+	"已添加 %d 个桶到 %s:\n":                  "Added %d bucket(s) to %s:\n",
+	"配置文件 %s 加载失败: %w":                  "failed to load config file %s: %w",
+	"开始备份（共 %d 个桶）\n":                   "Starting backup (%d bucket(s))\n",
+	"连接信息: %s\n":                        "Connection: %s\n",
+	"桶列表:\n":                            "Buckets:\n",
+	"\n[%d/%d] 备份桶: %s\n":               "\n[%d/%d] Backing up bucket: %s\n",
+	"  端点: %s\n":                        "  Endpoint: %s\n",
+	"  桶名: %s\n":                        "  Bucket: %s\n",
+	"  输出目录: %s\n":                      "  Output directory: %s\n",
+	"  增量备份: %v\n":                      "  Incremental backup: %v\n",
+	"  并发数: %d\n":                       "  Workers: %d\n",
+	"桶 %s 备份失败: %v\n":                   "Backup of bucket %s failed: %v\n",
+	"桶 %s 备份完成!\n":                      "Backup of bucket %s finished!\n",
+	"\n备份完成!\n":                         "\nBackup finished!\n",
+	"成功: %d 个桶\n":                       "Succeeded: %d bucket(s)\n",
+	"失败: %d 个桶\n":                       "Failed: %d bucket(s)\n",
+	"部分桶备份失败":                           "some buckets failed to back up",
+	"验证配置文件: %s\n":                      "Validating config file: %s\n",
+	"配置加载失败: %v\n":                      "Failed to load config: %v\n",
+	"配置验证失败: %v\n":                      "Config validation failed: %v\n",
+	"本地路径检查失败:\n%v\n":                   "Local path check failed:\n%v\n",
+	"本地路径检查失败":                          "local path check failed",
+	"配置文件验证通过!":                         "Config file is valid!",
+	"测试Ceph连接...":                       "Testing Ceph connection...",
+	"没有配置要测试的桶\n":                       "No bucket configured to test\n",
+	"配置中没有桶信息":                          "no buckets in config",
+	"连接失败: %v\n":                        "Connection failed: %v\n",
+	"连接成功!\n":                           "Connection succeeded!\n",
+	"ObjectSync 对象存储下载工具\n":             "ObjectSync object storage tool\n",
+	"版本: %s\n":                          "Version: %s\n",
+	"构建时间: %s\n":                        "Build time: %s\n",
+	"Git提交: %s\n":                       "Git commit: %s\n",
+	"Go版本: %s\n":                        "Go version: %s\n",
+	"操作系统: %s/%s\n":                     "OS: %s/%s\n",
+	"查看备份状态\n":                          "Backup status\n",
+	"配置文件: %s\n":                        "Config file: %s\n",
+	"状态文件: %s\n":                        "State file: %s\n",
+	"状态文件不存在，可能是首次备份\n":                 "State file does not exist, this may be the first backup\n",
+	"无法读取状态文件: %w":                      "cannot read state file: %w",
+	"状态文件格式错误: %w":                      "invalid state file format: %w",
+	"最后备份时间: %s\n":                      "Last backup: %s\n",
+	"已备份文件数: %d\n":                      "Files backed up: %d\n",
+	"总数据大小: %s\n":                       "Total size: %s\n",
+	"\n最近备份的文件:":                        "\nRecently backed up files:",
+	"  ... 还有 %d 个文件\n":                 "  ... and %d more file(s)\n",
+	"配置文件不存在，请先进行配置初始化\n":               "Config file does not exist, please initialize the configuration first\n",
+	"配置中没有配置桶信息\n":                      "No buckets configured\n",
+	"\n显示所有桶的状态（共 %d 个桶）:\n":            "\nStatus of all buckets (%d bucket(s)):\n",
+	"\n[%d] 桶: %s\n":                    "\n[%d] Bucket: %s\n",
+	"    状态文件: %s\n":                    "    State file: %s\n",
+	"    读取状态失败: %v\n":                  "    Failed to read state: %v\n",
+	"配置文件加载失败: %v\n":                    "Failed to load config file: %v\n",
+	"使用默认状态文件: %s\n":                    "Using default state file: %s\n",
+	"%s状态文件不存在，可能是首次备份\n":               "%sState file does not exist, this may be the first backup\n",
+	"%s最后备份时间: %s\n":                    "%sLast backup: %s\n",
+	"%s已备份文件数: %d\n":                    "%sFiles backed up: %d\n",
+	"%s总数据大小: %s\n":                     "%sTotal size: %s\n",
+	"%s最近备份的文件:\n":                      "%sRecently backed up files:\n",
+	"%s  ... 还有 %d 个文件\n":               "%s  ... and %d more file(s)\n",
+	"交互式配置初始化":                          "Interactive configuration",
+	"将创建配置文件: %s\n":                     "Config file to create: %s\n",
+	"配置文件 %s 已存在\n":                     "Config file %s already exists\n",
+	"操作已取消":                             "Operation cancelled",
+	"\n生成配置文件...":                       "\nGenerating config file...",
+	"创建配置文件失败: %w":                      "failed to create config file: %w",
+	"写入配置文件失败: %w":                      "failed to write config file: %w",
+	"配置文件已创建: %s\n":                     "Config file created: %s\n",
+	"请编辑配置文件，填入正确的桶名称和输出目录":             "Edit the config file and fill in the correct bucket names and output directories",
+	"然后运行: objectsync backup --verbose": "Then run: objectsync backup --verbose",
+	"       ObjectSync - 交互式菜单":         "       ObjectSync - Interactive Menu",
+	"欢迎使用 ObjectSync 对象存储下载工具！":         "Welcome to the ObjectSync object storage tool!",
+	"            主菜单":                   "            Main Menu",
+	"[1] 初始化配置":                         "[1] Initialize configuration",
+	"[2] 开始下载":                          "[2] Start download",
+	"[3] 开始上传":                          "[3] Start upload",
+	"[4] 查看状态":                          "[4] View status",
+	"[5] 查看配置":                          "[5] View configuration",
+	"[6] 测试连接":                          "[6] Test connection",
+	"[7] 编辑配置":                          "[7] Edit configuration",
+	"[8] 查看帮助":                          "[8] Help",
+	"[0] 退出":                            "[0] Exit",
+	"[信息] 启动配置向导...":                    "[INFO] Starting configuration wizard...",
+	"配置初始化失败: %v\n":                     "Configuration failed: %v\n",
+	"[信息] 开始下载...":                      "[INFO] Starting download...",
+	"下载失败: %v\n":                        "Download failed: %v\n",
+	"[信息] 开始上传...":                      "[INFO] Starting upload...",
+	"上传失败: %v\n":                        "Upload failed: %v\n",
+	"[信息] 查看备份状态...":                    "[INFO] Viewing backup status...",
+	"查看状态失败: %v\n":                      "Failed to view status: %v\n",
+	"[信息] 当前配置文件内容:":                    "[INFO] Current config file:",
+	"[信息] 显示帮助信息...":                    "[INFO] Showing help...",
+	"[信息] 感谢使用 ObjectSync 工具！":          "[INFO] Thank you for using ObjectSync!",
+	"[错误] 无效的选择，请重新输入":                  "[ERROR] Invalid choice, please try again",
+	"[警告] 配置文件不存在或无法读取，请先进行配置":   "[WARNING] Config file does not exist or cannot be read, please configure first",
+	"--max-upload-size 参数无效: %w": "invalid --max-upload-size: %w",
+	"--pack-threshold 参数无效: %w":  "invalid --pack-threshold: %w",
+	"--pack-size 参数无效: %w":       "invalid --pack-size: %w",
+	"开始上传（共 %d 个桶）\n":            "Starting upload (%d bucket(s))\n",
+	"\n[%d/%d] 上传桶: %s\n":        "\n[%d/%d] Uploading bucket: %s\n",
+	"  输入目录: %s -> %s\n":         "  Input directory: %s -> %s\n",
+	"  输入目录: %s\n":               "  Input directory: %s\n",
+	"  增量上传: %v\n":               "  Incremental upload: %v\n",
+	"桶 %s 上传失败: %v\n":            "Upload of bucket %s failed: %v\n",
+	"桶 %s 上传完成!\n":               "Upload of bucket %s finished!\n",
+	"\n上传完成!\n":                  "\nUpload finished!\n",
+	"部分桶上传失败":                    "some buckets failed to upload",
+	"            上传设置":           "            Upload Settings",
+	"发现 %d 个已配置的桶:\n":            "Found %d configured bucket(s):\n",
+	"  %d. 桶名: %s\n":             "  %d. Bucket: %s\n",
+	"     本地目录: %s -> %s\n":      "     Local directory: %s -> %s\n",
+	"     本地目录: %s\n":            "     Local directory: %s\n",
+	"     状态: 目录不存在 ❌\n":         "     Status: directory missing ❌\n",
+	"     状态: 目录存在 ✅\n":          "     Status: directory exists ✅\n",
+	"上传逻辑:":                      "Upload behavior:",
+	"  • 每个桶将从其配置的本地目录上传数据":      "  • Each bucket is uploaded from its configured local directory",
+	"  • 只有存在本地目录的桶才会被上传":        "  • Only buckets whose local directory exists are uploaded",
+	"  • 每个桶使用独立的上传状态文件":         "  • Each bucket uses its own upload state file",
+	"上传已取消":                      "Upload cancelled",
+	"开始上传...":                    "Starting upload...",
+	"没有配置的桶":                     "no buckets configured",
+	"桶 %s 对应的目录不存在: %s，跳过上传\n":   "Directory for bucket %s does not exist: %s, skipping upload\n",
+	"标准输入不是终端，不能启动交互式菜单，请指定要执行的子命令":            "stdin is not a terminal, cannot start the interactive menu; specify a subcommand to run",
+	"非交互模式下不能启动交互式菜单，请指定要执行的子命令":               "cannot start the interactive menu in non-interactive mode; specify a subcommand to run",
+	"非交互模式下不能运行交互式配置初始化":                       "cannot run interactive config initialization in non-interactive mode",
+	"[信息] 测试所有桶的连接...":                         "[INFO] Testing connection for all buckets...",
+	"测试连接失败: %v\n":                             "Connection test failed: %v\n",
+	"编辑配置失败: %v\n":                             "Failed to edit configuration: %v\n",
+	"%s已从桶中删除: %d 个对象（最近 %s）\n":                "%sDeleted from bucket: %d object(s) (latest %s)\n",
+	"--parts-concurrency 不能为负数":                "--parts-concurrency must not be negative",
+	"Ceph对象存储端点URL (覆盖配置文件)":                   "Ceph object storage endpoint URL (overrides the config file)",
+	"set-secret <keyring:服务名/账户名>":             "set-secret <keyring:service/account>",
+	"一个用于与S3兼容对象存储进行数据同步的工具，支持下载和上传功能，支持增量同步":  "A tool for synchronizing data with S3-compatible object storage, supporting incremental download and upload",
+	"上传后通过HEAD请求校验对象大小和ETag":                   "Verify object size and ETag with a HEAD request after uploading",
+	"上传时附加的校验算法 (CRC32, CRC32C, SHA1, SHA256)": "Additional checksum algorithm sent with uploads (CRC32, CRC32C, SHA1, SHA256)",
+	"上次运行中断时跳过已上传的文件继续 (--resume=false 重新开始)":  "Resume an interrupted run by skipping files already uploaded (--resume=false starts over)",
+	"上次运行中断时跳过已完成的对象继续 (--resume=false 重新开始)":  "Resume an interrupted run by skipping objects already completed (--resume=false starts over)",
+	"不启动交互式菜单和确认提示，需要输入时直接报错（标准输入不是终端时自动启用）":   "Never start the interactive menu or confirmation prompts; fail when input is needed (enabled automatically when stdin is not a terminal)",
+	"交互式创建配置文件":                                "Create a config file interactively",
+	"交互式菜单（默认行为）":                              "Interactive menu (default behavior)",
+	"从对象存储发现桶":                                 "Discover buckets from object storage",
+	"从配置文件中指定的所有桶下载对象到本地，支持增量备份，自动创建本地目录":      "Download objects from every bucket in the config file to local directories, with incremental backup and automatic directory creation",
+	"使用逐行输入的文本菜单，适合不支持全屏界面的终端":                 "Use a line-based text menu, for terminals that cannot show the full-screen interface",
+	"使用配置的连接列出对象存储中的所有桶，将尚未配置的桶逐个确认后添加到配置文件":   "List all buckets on the configured connection and add the ones not yet configured to the config file after confirming each",
+	"保存密钥到系统密钥环":                               "Store a secret in the system keyring",
+	"内容相同的文件（包括重命名的文件）使用服务端复制代替重复上传":           "Use server-side copies instead of re-uploading files with identical content (including renamed files)",
+	"初始化配置": "Initialize configuration",
+	"单个大文件同时上传的分片数 (0表示使用配置文件中的值)":                     "Number of parts of a single large file uploaded in parallel (0 uses the config file value)",
+	"单个打包对象的目标大小":                                      "Target size of a single pack object",
+	"单个文件大小上限，超过的文件跳过并在总结中列出 (如 10GB)":                 "Maximum size of a single file; larger files are skipped and listed in the summary (e.g. 10GB)",
+	"只处理指定集群（clusters 或 remotes 中的名称）下的桶":              "Only process buckets of the given cluster (a name from clusters or remotes)",
+	"只处理指定集群（clusters 或 remotes 中的名称）下的桶（需要 --remote）": "Only process buckets of the given cluster (a name from clusters or remotes; requires --remote)",
+	"只输出错误和一行总结，适合定时任务（适用范围同 --output）":                "Only print errors and a one-line summary, for scheduled jobs (same commands as --output)",
+	"启用增量上传": "Enable incremental upload",
+	"启用增量备份": "Enable incremental backup",
+	"在进度条下方显示每个正在传输的文件的进度和速度，便于发现卡住的传输（仅终端，--verbose 时不显示）": "Show the progress and speed of each file in transfer below the progress bar to spot stalled transfers (terminal only, hidden with --verbose)",
+	"对象存储同步工具": "Object storage sync tool",
+	"将密钥保存到系统密钥环（Windows凭据管理器、macOS钥匙串或Secret Service），之后可在配置文件的 access_key/secret_key 中以相同的引用代替明文": "Store a secret in the system keyring (Windows Credential Manager, macOS Keychain or Secret Service); the same reference can then replace the plain-text access_key/secret_key in the config file",
+	"将旧版单桶配置（ceph.bucket 或顶层 bucket）转换为 buckets 数组，修改前自动备份原配置文件":                                    "Convert a legacy single-bucket config (ceph.bucket or top-level bucket) to the buckets array, backing up the original config file first",
+	"将本地文件上传到配置文件中指定的所有桶，支持增量上传，自动创建不存在的存储桶":                                                        "Upload local files to every bucket in the config file, with incremental upload and automatic creation of missing buckets",
+	"小于该大小的文件打包为tar对象上传，下载时自动解包 (如 256KB)":                                                          "Pack files smaller than this into tar objects for upload; they are unpacked automatically on download (e.g. 256KB)",
+	"并发上传工作数":                   "Number of concurrent upload workers",
+	"并发下载工作数":                   "Number of concurrent download workers",
+	"并发扫描本地目录数":                 "Number of local directories scanned concurrently",
+	"忽略 --on-conflict，始终覆盖远程对象": "Ignore --on-conflict and always overwrite remote objects",
+	"执行上传操作":                    "Run an upload",
+	"执行备份操作":                    "Run a backup",
+	"提供全屏交互界面：桶列表和实时进度、日志窗口、开始/停止任务和编辑配置，这也是直接运行 objectsync 的默认行为": "Full-screen interactive interface with the bucket list and live progress, a log window, starting/stopping jobs and editing the config; this is also what running objectsync without arguments does",
+	"文件稳定检查窗口，窗口内大小或修改时间仍在变化的文件推迟上传 (如 5s，0表示不检查)":                 "File stability window; files whose size or modification time still changes within it are deferred (e.g. 5s, 0 disables the check)",
+	"文件被其他进程占用时从卷影副本读取（仅Windows，需要管理员权限）":                          "Read files locked by other processes from a volume shadow copy (Windows only, requires administrator rights)",
+	"显示版本信息": "Show version information",
+	"显示程序版本、构建时间和Git提交信息":                               "Show the program version, build time and Git commit",
+	"查看上次备份状态和统计信息；--remote 连接对象存储，统计每个桶下次运行需要下载和上传的文件": "Show the last backup state and statistics; --remote connects to object storage and counts the files each bucket will download and upload on the next run",
+	"查看备份状态": "Show backup status",
+	"检查配置文件": "Check the config file",
+	"检查配置文件中的废弃或未知配置项、不会生效的配置项、未替换的示例值，并列出与默认值不同的设置，不连接对象存储": "Check the config file for deprecated or unknown keys, keys that have no effect and unreplaced example values, and list settings that differ from the defaults, without connecting to object storage",
+	"每秒最多发送的请求数，所有桶共享 (0表示不限制)":                              "Maximum requests per second, shared by all buckets (0 means unlimited)",
+	"每秒最多发送的请求数，所有桶和工作协程共享 (0表示不限制)":                         "Maximum requests per second, shared by all buckets and workers (0 means unlimited)",
+	"添加所有未配置的桶，不逐个确认":                                        "Add all unconfigured buckets without confirming each",
+	"状态文件路径":        "State file path",
+	"秘密密钥 (覆盖配置文件)": "Secret key (overrides the config file)",
+	"覆盖前检查远程对象是否比本地文件新: warn 警告后覆盖, skip 跳过 (默认不检查)": "Check whether the remote object is newer than the local file before overwriting: warn overwrites with a warning, skip skips it (not checked by default)",
+	"访问密钥 (覆盖配置文件)":      "Access key (overrides the config file)",
+	"请求签名使用的区域 (覆盖配置文件)": "Region used to sign requests (overrides the config file)",
+	"输出格式: text 或 json（适用于 backup、upload、run、sync、verify、status、ls、du、config validate）": "Output format: text or json (for backup, upload, run, sync, verify, status, ls, du, config validate)",
+	"输出语言: zh 或 en（默认使用配置文件的 language 或 LANG 环境变量）":                                     "Output language: zh or en (defaults to language in the config file or the LANG environment variable)",
+	"迁移旧版配置": "Migrate a legacy config",
+	"运行标签 key=value，记录在运行历史和通知中（可重复）":  "Run label key=value, recorded in the run history and notifications (repeatable)",
+	"连接对象存储，与状态记录和本地文件比较，统计待下载和待上传的文件": "Connect to object storage and compare with the state and local files to count files pending download and upload",
+	"配置文件管理和验证": "Config file management and validation",
+	"配置文件路径（默认使用 OBJECTSYNC_CONFIG 环境变量，或依次查找 ./config.yaml、$XDG_CONFIG_HOME/objectsync/config.yaml（未设置时为 ~/.config/objectsync/config.yaml）、/etc/objectsync/config.yaml）": "Config file path (defaults to the OBJECTSYNC_CONFIG environment variable, or the first of ./config.yaml, $XDG_CONFIG_HOME/objectsync/config.yaml (~/.config/objectsync/config.yaml when unset), /etc/objectsync/config.yaml)",
+	"输出配置文件路径": "Output config file path",
+	"配置管理":     "Config management",
+	"验证配置":     "Validate the config",
+	"验证配置文件是否正确，测试Ceph连接":           "Check that the config file is valid and test the Ceph connection",
+	"是否覆盖? (y/N): ":                 "Overwrite? (y/N): ",
+	"请输入对象存储端点URL: ":                "Enter the object storage endpoint URL: ",
+	"请输入访问密钥: ":                     "Enter the access key: ",
+	"请输入秘密密钥: ":                     "Enter the secret key: ",
+	"请输入默认并发数 (默认: 5): ":            "Enter the default concurrency (default: 5): ",
+	"启用增量备份? (Y/n): ":               "Enable incremental backup? (Y/n): ",
+	"启用详细输出? (y/N): ":               "Enable verbose output? (y/N): ",
+	"请选择操作 (0-8): ":                 "Choose an action (0-8): ",
+	"[信息] 按回车键返回主菜单...":             "[INFO] Press Enter to return to the main menu...",
+	"是否继续上传? (Y/n): ":               "Continue uploading? (Y/n): ",
+	"是否启用详细输出? (y/N): ":             "Enable verbose output? (y/N): ",
+	"跳过桶 %s：没有设置 output_dir，只能上传\n": "Skipping bucket %s: no output_dir set, upload only\n",
+	// app/bucket.go
+	"存储桶 %s 已存在":                        "bucket %s already exists",
+	"存储桶 %s 创建成功，已启用版本控制\n":             "Bucket %s created with versioning enabled\n",
+	"存储桶 %s 不存在":                        "bucket %s does not exist",
+	"警告: 存储桶 %s 仍在配置文件 %s 中使用\n":        "Warning: bucket %s is still used in config file %s\n",
+	"存储桶 %s 不为空，使用 --force 删除其中的所有对象":   "bucket %s is not empty; use --force to delete all of its objects",
+	"将永久删除存储桶 %s 中的所有对象和历史版本，请输入桶名确认: ": "All objects and versions in bucket %s will be permanently deleted. Type the bucket name to confirm: ",
+	"已取消": "Cancelled",
+	"清空存储桶失败（已删除 %d 个对象版本）: %w": "failed to empty bucket (%d object version(s) deleted): %w",
+	"已删除 %d 个对象版本\n":            "Deleted %d object version(s)\n",
+	"删除存储桶失败: %w":               "failed to delete bucket: %w",
+	"存储桶 %s 已删除\n":              "Bucket %s deleted\n",
+	"无效的桶名: %s（格式: s3://桶名）":    "invalid bucket: %s (format: s3://bucket)",
+	"非交互模式下需要使用 --yes 确认删除":     "--yes is required to confirm removal in non-interactive mode",
+	"mb <s3://桶名>":              "mb <s3://bucket>",
+	"rb <s3://桶名>":              "rb <s3://bucket>",
+	"创建后启用版本控制":                 "Enable versioning after creating the bucket",
+	"创建存储桶":                     "Create a bucket",
+	"删除存储桶":                     "Remove a bucket",
+	"删除桶中的所有对象和历史版本后再删除桶":       "Delete all objects and versions in the bucket before removing it",
+	"删除空桶。使用 --force 先删除桶中的所有对象（包括历史版本），删除前需要输入桶名确认": "Remove an empty bucket. With --force, all objects in the bucket (including old versions) are deleted first, after typing the bucket name to confirm",
+	"在对象存储中创建新桶，可选同时启用版本控制。桶已存在时报错":                  "Create a new bucket on object storage, optionally enabling versioning. Fails if the bucket already exists",
+	"配合 --force 使用，不再要求输入桶名确认":                       "With --force, do not ask to type the bucket name to confirm",
+	// app/completion.go
+	"只处理指定的桶（可重复或用逗号分隔）": "Only process the given buckets (repeatable or comma-separated)",
+	"生成命令行补全脚本":          "Generate shell completion scripts",
+	"生成指定shell的命令行补全脚本，--bucket 和 --cluster 等参数会补全配置文件中的名称。\n\n  bash:       source <(objectsync completion bash)\n  zsh:        objectsync completion zsh > \"${fpath[1]}/_objectsync\"\n  fish:       objectsync completion fish > ~/.config/fish/completions/objectsync.fish\n  powershell: objectsync completion powershell | Out-String | Invoke-Expression": "Generate the completion script for the given shell; flags such as --bucket and --cluster complete names from the config file.\n\n  bash:       source <(objectsync completion bash)\n  zsh:        objectsync completion zsh > \"${fpath[1]}/_objectsync\"\n  fish:       objectsync completion fish > ~/.config/fish/completions/objectsync.fish\n  powershell: objectsync completion powershell | Out-String | Invoke-Expression",
+	"跳过指定的桶（可重复或用逗号分隔）": "Skip the given buckets (repeatable or comma-separated)",
+	// app/cp.go
+	"请指定要复制的对象键，复制前缀下的所有对象请使用 --recursive":  "specify the object key to copy; use --recursive to copy every object under a prefix",
+	"源桶 %s（%s）和目标桶 %s（%s）不在同一个端点，无法服务端复制":   "source bucket %s (%s) and destination bucket %s (%s) are on different endpoints; server-side copy is not possible",
+	"对象不存在: %s/%s（复制前缀下的对象请使用 --recursive）": "object does not exist: %s/%s (use --recursive to copy objects under a prefix)",
+	"没有需要复制的对象\n":                           "No objects to copy\n",
+	"复制: %s/%s -> %s/%s\n":                  "Copy: %s/%s -> %s/%s\n",
+	"复制 %s 失败: %v\n":                        "Copy of %s failed: %v\n",
+	"已复制 %d 个对象（%s）\n":                      "Copied %d object(s) (%s)\n",
+	"%d 个对象复制失败":                            "%d object(s) failed to copy",
+	"cp <s3://源桶/对象键|前缀> <s3://目标桶/对象键|前缀>": "cp <s3://source-bucket/key|prefix> <s3://target-bucket/key|prefix>",
+	"在同一个端点内复制对象（CopyObject，超过5GB的对象使用UploadPartCopy），数据不经过本机。目标对象键以/结尾时追加源对象名，--recursive 复制前缀下的所有对象": "Copy objects within the same endpoint (CopyObject, or UploadPartCopy for objects over 5GB) without the data passing through this machine. A target key ending in / gets the source object name appended; --recursive copies every object under the prefix",
+	"复制前缀下的所有对象": "Copy every object under the prefix",
+	"并发复制数":      "Number of concurrent copies",
+	"服务端复制对象":    "Copy objects server-side",
+	"详细输出":       "Verbose output",
+	// app/daemon.go
+	"  %s (%s) 下次运行: %s":            "  %s (%s) next run: %s",
+	"  上次运行: %s，用时 %s\n":            "  Last run: %s, took %s\n",
+	"  下次运行: %s\n":                  "  Next run: %s\n",
+	"  尚未运行\n":                      "  Not run yet\n",
+	"  结果: 失败: %s\n":                "  Result: failed: %s\n",
+	"  结果: 成功，传输 %d 个文件（%s）\n":      "  Result: succeeded, %d file(s) transferred (%s)\n",
+	"  跳过次数: %d\n":                  "  Skipped: %d time(s)\n",
+	"\n桶: %s (%s)\n":                "\nBucket: %s (%s)\n",
+	"写入状态文件失败: %v":                  "Failed to write status file: %v",
+	"启动时间: %s\n":                    "Started: %s\n",
+	"守护进程已启动，%d 个桶按计划运行":            "Daemon started, %d bucket(s) scheduled",
+	"收到退出信号，等待正在运行的桶结束...":          "Received stop signal, waiting for the running bucket to finish...",
+	"更新时间: %s\n":                    "Updated: %s\n",
+	"桶 %s 上一次运行尚未结束，跳过本次":           "Previous run of bucket %s has not finished, skipping this one",
+	"桶 %s 同步完成，传输 %d 个文件（%s），用时 %s": "Sync of bucket %s finished, %d file(s) transferred (%s), took %s",
+	"正在运行: %s\n":                    "Running: %s\n",
+	"状态文件 %s 不存在，守护进程可能尚未启动":        "status file %s does not exist; the daemon may not have started yet",
+	"等待运行的桶过多，跳过桶 %s":               "Too many buckets waiting to run, skipping bucket %s",
+	"调度已更新，%d 个桶按计划运行":              "Schedule updated, %d bucket(s) scheduled",
+	"进程ID: %d\n":                    "PID: %d\n",
+	"配置中没有设置 schedule 的桶":           "no bucket in the config has a schedule",
+	"常驻运行，按每个桶配置的 schedule（cron表达式）执行同步。同一时间只运行一个桶，到期时上一次运行尚未结束的桶本轮跳过。配置文件修改后自动重新加载，状态写入 --status-file": "Keep running and sync each bucket on its configured schedule (a cron expression). Only one bucket runs at a time; a bucket whose previous run is still going when it is due skips that round. The config file is reloaded automatically when it changes, and status is written to --status-file",
+	"按计划持续运行":  "Run continuously on a schedule",
+	"查看守护进程状态": "Show daemon status",
+	"读取守护进程的状态文件，显示每个桶的下次运行时间和上次运行结果": "Read the daemon status file and show each bucket's next run time and last result",
+	// app/doctor.go
+	"%s 中有 %d 个条目无法解析，如 %s": "%s has %d unparsable entries, e.g. %s",
+	"%s 可用 %s": "%[2]s free on %[1]s",
+	"%s 尚未创建，首次运行后生成":                 "%s not created yet, it is written after the first run",
+	"%s 拒绝连接：检查端口是否正确，以及对象存储服务是否正在运行": "%s refused the connection: check the port and that the object storage service is running",
+	"%s 连接成功，用时 %s":                   "connected to %s in %s",
+	"%s，%d 个条目":                       "%s, %d entries",
+	"%s，证书 %s 有效期至 %s":                "%s, certificate %s valid until %s",
+	"TCP连接正常但请求失败：检查endpoint的协议（http/https）和端口、代理及超时设置": "TCP connects but the request failed: check the endpoint scheme (http/https), port, proxy and timeout settings",
+	"\n发现 %d 个问题\n": "\nFound %d problem(s)\n",
+	"\n没有发现问题":      "\nNo problems found",
+	"endpoint 应为完整的URL，如 http://192.168.1.100:7480": "endpoint must be a full URL, e.g. http://192.168.1.100:7480",
+	"上次运行没有完成，下次运行时从中断处继续":                          "the last run did not finish; the next run resumes where it stopped",
+	"与服务端相差 %s": "differs from the server by %s",
+	"代理":        "proxy",
+	"使用 objectsync config init -o %s 生成配置文件":                          "run objectsync config init -o %s to create a config file",
+	"使用 objectsync config lint -c %s 查看具体的配置项":                        "run objectsync config lint -c %s to see the offending settings",
+	"使用 objectsync state rm -f %s <键> 删除这些条目，下次运行时重新传输对应的文件":          "remove these entries with objectsync state rm -f %s <key>; the files are transferred again on the next run",
+	"凭证已过期或无效：更新密钥":                                                   "credentials expired or invalid: update the keys",
+	"凭证有效，但没有列出所有桶的权限":                                                "credentials valid, but not allowed to list all buckets",
+	"凭证有效，可以访问 %d 个桶":                                                 "credentials valid, %d bucket(s) accessible",
+	"只要能访问配置的桶就不影响使用，见下面各桶的检查":                                        "this is fine as long as the configured buckets are accessible, see the bucket checks below",
+	"可用空间不足，下载可能因磁盘已满失败：清理磁盘或更换 output_dir":                           "low free space, downloads may fail with a full disk: free up space or change output_dir",
+	"已设置 tls.insecure_skip_verify，不校验服务端证书；建议改为在 tls.ca_file 中配置CA证书": "tls.insecure_skip_verify is set and the server certificate is not verified; configure the CA certificate in tls.ca_file instead",
+	"无法解析 %s：检查主机名是否拼写正确，以及本机的DNS设置":                                  "cannot resolve %s: check the host name spelling and the DNS settings of this machine",
+	"无法读取凭证：检查 profile 名称和共享凭证文件（~/.aws/credentials）":                 "cannot load credentials: check the profile name and the shared credentials file (~/.aws/credentials)",
+	"无法连接 %s：检查网络是否连通以及防火墙是否放行该端口":                                    "cannot connect to %s: check network connectivity and whether a firewall blocks the port",
+	"时钟": "clock",
+	"服务端不存在该access_key：检查 access_key（或 profile）是否正确":     "the server does not know this access_key: check access_key (or profile)",
+	"服务端证书不是受信任的CA签发的（如自签名证书）：在 tls.ca_file 中配置签发它的CA证书": "the server certificate is not signed by a trusted CA (e.g. self-signed): configure its issuing CA in tls.ca_file",
+	"服务端证书即将过期，请联系管理员更新":                                 "the server certificate expires soon, ask the administrator to renew it",
+	"服务端证书只有CN没有SAN（主题备用名称），不再被接受：重新签发包含SAN的证书":          "the server certificate has a CN but no SAN (subject alternative name) and is no longer accepted: reissue it with a SAN",
+	"服务端证书已过期或本机时间不正确：检查系统时间，或联系管理员更新证书":                 "the server certificate has expired or the local clock is wrong: check the system time or ask the administrator to renew the certificate",
+	"本地路径": "local paths",
+	"本机时钟偏差超过15分钟，请求签名会被拒绝：同步系统时间（如启用NTP）": "the local clock is off by more than 15 minutes and request signatures will be rejected: synchronize the system time (e.g. enable NTP)",
+	"本机时钟偏差较大：建议启用NTP同步系统时间":               "the local clock is noticeably off: enable NTP to synchronize the system time",
+	"本机时钟偏差过大：同步系统时间（如启用NTP）":              "the local clock is too far off: synchronize the system time (e.g. enable NTP)",
+	"桶 %s":       "bucket %s",
+	"桶 %s 前缀 %s": "bucket %s prefix %s",
+	"桶不存在":       "bucket does not exist",
+	"桶位于其他区域或寻址方式不对：检查 region 和 path_style": "the bucket is in another region or the addressing style is wrong: check region and path_style",
+	"桶存在，可以访问":           "bucket exists and is accessible",
+	"检查 proxy.url":       "check proxy.url",
+	"检查 tls 中的证书和私钥文件路径": "check the certificate and key file paths under tls",
+	"检查 tls 中的证书设置，需要双向认证时配置 tls.cert_file 和 tls.key_file": "check the certificate settings under tls; configure tls.cert_file and tls.key_file if mutual TLS is required",
+	"检查桶名是否正确，或使用 objectsync mb %s 创建":                     "check the bucket name, or create it with objectsync mb %s",
+	"没有访问该桶的权限：检查用户权限或桶策略":                                 "no permission to access the bucket: check the user permissions or bucket policy",
+	"状态文件": "state file",
+	"状态文件已损坏：从备份中恢复，或删除后重新运行（会重新传输全部文件）":                           "the state file is corrupted: restore it from a backup, or delete it and run again (all files are transferred again)",
+	"环境变量 AWS_CA_BUNDLE 会替换 tls.ca_file 中的CA证书：取消该环境变量，或把CA证书加入其中": "the AWS_CA_BUNDLE environment variable replaces the CA certificate from tls.ca_file: unset it, or add the CA certificate to that bundle",
+	"磁盘空间":              "disk space",
+	"端点 %s":             "endpoint %s",
+	"端点 %s (remote %s)": "endpoint %s (remote %s)",
+	"端点检查未通过，跳过":        "skipped, endpoint checks failed",
+	"签名不匹配：检查 secret_key 是否正确，服务端要求特定区域时检查 region": "signature mismatch: check secret_key, and region if the server requires a specific one",
+	"缺少主机名: %s": "missing host name: %s",
+	"认证":        "auth",
+	"访问":        "access",
+	"证书中的主机名与endpoint不一致：endpoint改用证书中的主机名": "the certificate host name does not match the endpoint: use the host name from the certificate in endpoint",
+	"诊断发现 %d 个问题": "doctor found %d problem(s)",
+	"该端口不是HTTPS服务：检查endpoint是否应该使用 http://": "the port does not speak HTTPS: check whether endpoint should use http://",
+	"运行日志":         "run journal",
+	"通过代理 %s 访问":   "via proxy %s",
+	"配置":           "config",
+	"配置文件":         "config file",
+	"配置文件 %s 无法加载": "cannot load config file %s",
+	"配置验证":         "validation",
+	"，上次运行 %s":     ", last run %s",
+	"本地目录":         "Local directory",
+	"%s 不存在或不是目录":  "%s does not exist or is not a directory",
+	"检查路径是否正确，以及移动硬盘或网络共享是否已挂载":                                         "check that the path is correct and that the external drive or network share is mounted",
+	"上传时自动创建目录 %s":                                                      "directory %s is created automatically on upload",
+	"检查WebDAV地址，以及作为用户名和密码的 access_key 和 secret_key（Nextcloud建议使用应用密码）": "check the WebDAV URL and access_key/secret_key, which are used as username and password (Nextcloud recommends an app password)",
+	"复制目标":               "Replication target",
+	"%s（%s）存在，可以访问":      "%s (%s) exists and is accessible",
+	"%s（%s）不存在":          "%s (%s) does not exist",
+	"复制时自动创建目标桶":         "The target bucket is created automatically during replication",
+	"只检查指定的桶（可重复或用逗号分隔）": "Only check the given buckets (repeatable or comma-separated)",
+	"每项网络检查的超时":          "Timeout of each network check",
+	"诊断配置、网络和本地环境的问题":    "Diagnose config, network and local environment problems",
+	"逐项检查配置文件、端点的DNS解析、TCP连接和TLS握手、本机时钟偏差、凭证、桶的访问权限、磁盘空间和状态文件，对发现的问题给出处理建议": "Check the config file, endpoint DNS resolution, TCP connection and TLS handshake, local clock skew, credentials, bucket access, disk space and state files one by one, with a suggested fix for each problem found",
+	// app/du.go
+	"--depth 不能为负数":          "--depth must not be negative",
+	"总计: %d 个对象，%s（%s/%s）\n": "Total: %d object(s), %s (%s/%s)\n",
+	"du <s3://桶名[/前缀]>":      "du <s3://bucket[/prefix]>",
+	"按前缀之后的前 --depth 级目录汇总对象数量和大小，适合容量规划。--output json 时输出每个目录的统计": "Summarize object counts and sizes by the first --depth directory levels after the prefix, for capacity planning. --output json prints the statistics of each directory",
+	"汇总的目录层级 (0表示只显示总计)": "Directory depth to summarize (0 shows only the total)",
+	"统计远程对象占用空间":         "Summarize space used by remote objects",
+	// app/healthcheck.go
+	"%d 项检查未通过":               "%d check(s) failed",
+	"无法读取状态文件: %v":            "cannot read status file: %v",
+	"状态文件格式错误: %v":            "invalid status file: %v",
+	"状态文件已经 %s 没有更新":          "status file not updated for %s",
+	"进程 %d 运行中，%s 前更新状态":      "process %d running, status updated %s ago",
+	"读取运行历史失败: %v":            "failed to read run history: %v",
+	"还没有成功的运行":                "no successful run yet",
+	"最近一次成功运行在 %s 前，超过 %s":    "last successful run %s ago, older than %s",
+	"最近一次成功运行在 %s 前":          "last successful run %s ago",
+	"可达，用时 %s":                "reachable in %s",
+	"不检查守护进程（使用定时任务运行 run 时）": "Do not check the daemon (when run is started by a scheduler)",
+	"不检查对象存储端点是否可达":           "Do not check whether object storage endpoints are reachable",
+	"守护进程状态文件路径":              "Daemon status file path",
+	"检查守护进程是否存活、每个桶最近一次成功运行距今的时间以及对象存储端点是否可达，健康时退出码为0，否则为1，可用于Docker HEALTHCHECK和Kubernetes探针": "Check that the daemon is alive, how long ago each bucket last ran successfully and whether object storage endpoints are reachable. Exits 0 when healthy and 1 otherwise, for Docker HEALTHCHECK and Kubernetes probes",
+	"检查运行状况，供容器健康检查使用": "Check health, for container health checks",
+	"每个桶最近一次成功运行距今的最长时间 (0表示按桶的schedule计算，允许错过一次运行；没有schedule的桶为25h)": "Maximum time since each bucket's last successful run (0 derives it from the bucket's schedule, allowing one missed run; 25h for buckets without a schedule)",
+	"不检查每个桶最近一次成功运行的时间": "Do not check the time of each bucket's last successful run",
+	"连接端点的超时":           "Timeout for connecting to endpoints",
+	// app/history.go
+	"读取运行历史失败: %w": "failed to read run history: %w",
+	"没有运行记录":       "No runs recorded",
+	"成功":           "ok",
+	"显示最近 %d 条，共 %d 条记录（使用 --limit 0 显示全部）\n": "Showing the latest %d of %d records (use --limit 0 to show all)\n",
+	"只显示失败的运行":                     "Only show failed runs",
+	"只显示带有指定标签 key=value 的运行（可重复）": "Only show runs with the given label key=value (repeatable)",
+	"只显示指定的桶（可重复或用逗号分隔）":           "Only show the given buckets (repeatable or comma-separated)",
+	"按时间倒序列出以往每次运行中各桶的开始时间、结果、传输的文件数和数据量以及错误摘要": "List past runs, newest first, with each bucket's start time, result, files and bytes transferred, and an error summary",
+	"最多显示的记录数 (0表示全部)":   "Maximum number of records to show (0 shows all)",
+	"查看运行历史":             "Show run history",
+	"直接指定历史记录文件，不读取配置文件": "History file to read directly, without reading the config file",
+	// app/logging.go
+	"日志格式: text 或 json（每行一个JSON对象，便于日志系统收集）": "Log format: text or json (one JSON object per line, for log collectors)",
+	"日志级别: debug、info、warn 或 error":          "Log level: debug, info, warn or error",
+	// app/ls.go
+	"共 %d 项，%s\n":       "%d item(s), %s\n",
+	"ls <s3://桶名[/前缀]>": "ls <s3://bucket[/prefix]>",
+	"列出桶中指定前缀下的对象，默认只列出下一级，更深的对象显示为目录。--output json 时输出对象列表": "List objects under the given prefix. By default only the next level is listed and deeper objects are shown as directories. --output json prints the object list",
+	"列出远程对象": "List remote objects",
+	"显示大小、修改时间、ETag和存储类型": "Show size, modification time, ETag and storage class",
+	"递归列出前缀下的所有对象":        "List every object under the prefix recursively",
+	// app/menu.go
+	"没有需要下载的桶（所有桶的方向都是 upload 或 replicate）": "No buckets to download (all buckets have direction upload or replicate)",
+	"已配置 %d 个下载的桶:\n":                       "%d download buckets configured:\n",
+	"选择要下载的桶（如 1,3 或 1-3，直接回车选择全部，0 取消）: ":  "Select buckets to download (e.g. 1,3 or 1-3, Enter for all, 0 to cancel): ",
+	"下载已取消":     "Download cancelled",
+	"[错误] %v\n": "[ERROR] %v\n",
+	"增量下载，只下载有变化的对象?":                            "Incremental download, only changed objects?",
+	"试运行，只统计需要下载的对象，不实际下载?":                      "Dry run, only count objects to download without downloading?",
+	"并发数（直接回车使用配置中的值）: ":                         "Workers (Enter to use the configured value): ",
+	"[错误] 并发数必须是正整数":                             "[ERROR] Workers must be a positive integer",
+	"桶 %s: 检查失败: %v\n":                           "Bucket %s: check failed: %v\n",
+	"桶 %s: 需要下载 %d 个对象（%s）\n":                    "Bucket %s: %d objects to download (%s)\n",
+	"\n试运行完成，共需要下载 %d 个对象（%s）\n":                 "\nDry run complete, %d objects to download in total (%s)\n",
+	"无效的选择: %s（可选 1-%d）":                         "Invalid selection: %s (valid range 1-%d)",
+	"[失败] %s (%s): %v\n":                         "[FAIL] %s (%s): %v\n",
+	"[通过] %s (%s)，用时 %s\n":                       "[PASS] %s (%s), took %s\n",
+	"%d 个桶连接失败，可以运行 objectsync doctor 查看详细的诊断\n": "%d buckets failed the connection test, run objectsync doctor for detailed diagnostics\n",
+	"全部 %d 个桶连接正常\n":                             "All %d buckets connected successfully\n",
+	"配置文件 %s 不存在，请先初始化配置\n":                      "Config file %s does not exist, please initialize the configuration first\n",
+	"配置没有修改":                                     "Configuration not changed",
+	"继续修改?":                                      "Continue editing?",
+	"修改已放弃，配置文件没有改变":                             "Changes discarded, config file unchanged",
+	"配置验证通过（共 %d 个桶），已保存: %s\n":                  "Configuration is valid (%d buckets), saved: %s\n",
+	"运行编辑器 %s 失败: %w（可以通过 EDITOR 环境变量指定编辑器）":     "failed to run editor %s: %w (set the EDITOR environment variable to choose an editor)",
+	"保存配置文件失败: %w":                               "failed to save config file: %w",
+	// app/migrate.go
+	"--from 和 --to 不能是同一个端点: %s":               "--from and --to cannot be the same endpoint: %s",
+	"--verify 参数无效: %s（可选值: full, list, none）": "invalid --verify value: %s (valid values: full, list, none)",
+	"--max-memory 参数无效: %w":                    "invalid --max-memory value: %w",
+	"开始迁移: %s（%s） -> %s（%s），共 %d 个桶\n":         "Starting migration: %s (%s) -> %s (%s), %d buckets\n",
+	"\n[%d/%d] 迁移桶: %s\n":                      "\n[%d/%d] Migrating bucket: %s\n",
+	"校验失败: %w":                                 "verification failed: %w",
+	"桶 %s 迁移失败: %v\n":                          "Bucket %s migration failed: %v\n",
+	"复制标签或ACL失败 %d 次，见校验结果\n":                  "Copying tags or ACLs failed %d times, see verification results\n",
+	"桶 %s 有 %d 个不一致的对象":                        "bucket %s has %d mismatched objects",
+	"桶 %s 迁移完成!\n":                             "Bucket %s migrated!\n",
+	"\n迁移完成!\n":                                "\nMigration complete!\n",
+	"迁移报告已写入: %s\n":                            "Migration report written to: %s\n",
+	"不一致或失败: %d 个桶（%d 个不一致的对象）\n":              "Mismatched or failed: %d buckets (%d mismatched objects)\n",
+	"迁移发现问题":                                   "migration found problems",
+	"列出源端点的桶失败: %w":                            "failed to list buckets on source endpoint: %w",
+	"没有要迁移的桶":                                  "no buckets to migrate",
+	"校验 %d 个对象，%d 个不一致\n":                      "Verified %d objects, %d mismatched\n",
+	"  ……还有 %d 个，使用 --verbose 或查看报告文件\n":       "  ...and %d more, use --verbose or see the report file\n",
+	"多余":           "extra",
+	"元数据不一致":       "metadata differs",
+	"标签不一致":        "tags differ",
+	"ACL不一致":       "ACL differs",
+	"生成迁移报告失败: %w": "failed to generate migration report: %w",
+	"写入迁移报告失败: %w": "failed to write migration report: %w",
+	"把 --from 端点中的所有桶（或 --bucket 指定的桶）复制到 --to 端点的同名桶，用于更换Ceph集群等场景。对象内容只经过内存，不写入本地磁盘；保留用户元数据和HTTP头，两端都支持时复制对象的标签和ACL（授权给源对象所有者的项改为目标对象的所有者）。复制后逐个比较两端的对象，把不一致的对象写入报告文件。桶的策略、生命周期和版本设置不迁移。中断后重新运行时跳过已经复制的对象": "Copy every bucket on the --from endpoint (or those given with --bucket) to the bucket of the same name on the --to endpoint, e.g. when replacing a Ceph cluster. Object data only passes through memory and is never written to local disk; user metadata and HTTP headers are preserved, and object tags and ACLs are copied when both ends support them (grants to the source object's owner are rewritten to the target object's owner). After copying, objects on both ends are compared one by one and mismatches are written to the report file. Bucket policies, lifecycle rules and versioning settings are not migrated. Re-running after an interruption skips objects that were already copied",
+	"不一致报告的文件路径 (默认为当前目录下的 migrate_report_<from>_<to>.json)": "Path of the mismatch report (defaults to migrate_report_<from>_<to>.json in the current directory)",
+	"单个大对象同时上传的分片数 (0表示使用默认值)":                               "Number of parts of a single large object uploaded in parallel (0 uses the default)",
+	"只迁移指定的桶（可重复或用逗号分隔），默认迁移源端点的所有桶":                         "Only migrate the given buckets (repeatable or comma-separated); by default every bucket on the source endpoint is migrated",
+	"只迁移桶中该前缀下的对象":                                           "Only migrate objects under this prefix",
+	"在进度条下方显示每个正在复制的对象的进度和速度（仅终端，--verbose 时不显示）":            "Show the progress and speed of each object being copied below the progress bar (terminal only, hidden with --verbose)",
+	"复制后的校验方式：full（比较对象列表、元数据、标签和ACL）、list（只比较对象列表）、none":    "Verification after copying: full (compare object lists, metadata, tags and ACLs), list (compare object lists only) or none",
+	"复制对象的标签和ACL（两端都支持时）":                                    "Copy object tags and ACLs (when both ends support them)",
+	"所有工作协程缓冲的数据量上限，如 1GB (默认512MB)":                         "Maximum data buffered by all workers, e.g. 1GB (default 512MB)",
+	"把一个对象存储端点的所有桶迁移到另一个端点":                                  "Migrate all buckets from one object storage endpoint to another",
+	"每个桶的并发复制数": "Number of concurrent copies per bucket",
+	"每秒最多发送的请求数，两端和所有工作协程共享 (0表示不限制)": "Maximum requests per second, shared by both ends and all workers (0 means unlimited)",
+	"源端点（remotes 或 clusters 中的名称）":    "Source endpoint (a name from remotes or clusters)",
+	"目标端点（remotes 或 clusters 中的名称）":   "Target endpoint (a name from remotes or clusters)",
+	"详细输出，列出所有不一致的对象":                 "Verbose output, listing every mismatched object",
+	"跳过上次迁移后没有变化的对象":                  "Skip objects unchanged since the last migration",
+	// app/notify.go
+	"发送 %s 通知失败: %v": "failed to send %s notification: %v",
+	"写入运行历史失败: %v":   "failed to write run history: %v",
+	// app/pending.go
+	"检查待同步的变化（共 %d 个桶）\n": "Checking pending changes (%d buckets)\n",
+	"\n桶 %s: 检查失败: %v\n":  "\nBucket %s: check failed: %v\n",
+	"\n桶 %s（%s）:\n":       "\nBucket %s (%s):\n",
+	"  待下载: %d 个对象（%s）\n": "  To download: %d objects (%s)\n",
+	"  待上传: %d 个文件（%s）\n": "  To upload: %d files (%s)\n",
+	"部分桶检查失败":             "some buckets could not be checked",
+	"  待复制: %d 个对象（%s）\n": "  To copy: %d objects (%s)\n",
+	// app/profile.go
+	"启动pprof接口失败: %w":                 "failed to start pprof endpoint: %w",
+	"pprof接口: http://%s/debug/pprof/": "pprof endpoint: http://%s/debug/pprof/",
+	"创建CPU分析文件失败: %w":                 "failed to create CPU profile: %w",
+	"启动CPU分析失败: %w":                   "failed to start CPU profiling: %w",
+	"CPU分析已写入: %s":                    "CPU profile written to %s",
+	"写入内存分析失败: %v":                    "failed to write heap profile: %v",
+	"内存分析已写入: %s":                     "heap profile written to %s",
+	"命令结束时将堆内存分析写入文件":                 "Write a heap profile to the file when the command ends",
+	"在指定地址提供pprof分析接口，如 localhost:6060（适合 daemon 等长时间运行的命令）": "Serve the pprof endpoints on this address, e.g. localhost:6060 (for long-running commands such as daemon)",
+	"将运行期间的CPU分析写入文件，用 go tool pprof 查看":                     "Write a CPU profile of the run to the file, for go tool pprof",
+	// app/prune.go
+	"清理已从桶中删除的对象的本地备份": "Remove local backups of objects deleted from the bucket",
+	"备份时桶中已删除的对象的本地文件仍然保留，状态中记录删除的时间。prune 删除超过 --older-than 的这些本地文件，并清除对应的删除记录；仍在桶中的对象不会被删除。删除记录超过 prune_deleted_after 后会被清理，--older-than 需要比它短": "Backup keeps the local files of objects deleted from the bucket and records when they were deleted in the state. prune removes those local files deleted longer ago than --older-than and clears their deletion records; files of objects still in the bucket are never removed. Deletion records are cleaned up after prune_deleted_after, so --older-than must be shorter than that",
+	"对象从桶中删除超过该时间的本地文件，如 30d、720h 或日期 2006-01-02": "Remove local files whose objects were deleted from the bucket longer ago than this, e.g. 30d, 720h or a date 2006-01-02",
+	"只列出将要删除的文件，不实际删除":                            "Only list the files that would be deleted, without deleting them",
+	"将删除的文件写入JSON报告文件":                            "Write the deleted files to a JSON report file",
+	"详细输出，列出所有删除的文件":                              "Verbose output, listing every deleted file",
+	"预演模式: 只列出在 %s 之前从桶中删除的对象的本地文件\n":             "Dry run: only listing local files of objects deleted from the bucket before %s\n",
+	"\n桶 %s -> %s\n":              "\nBucket %s -> %s\n",
+	"桶 %s 清理失败: %v\n":             "Bucket %s prune failed: %v\n",
+	"\n清理报告已写入: %s\n":             "\nPrune report written to: %s\n",
+	"\n预演模式: 共 %d 个文件（%s）将被删除\n":  "\nDry run: %d file(s) (%s) would be deleted\n",
+	"\n已删除 %d 个文件（%s）\n":          "\nDeleted %d file(s) (%s)\n",
+	"部分桶清理失败":                     "some buckets failed to prune",
+	"将删除 %d 个文件（%s）\n":            "Would delete %d file(s) (%s)\n",
+	"删除 %d 个文件（%s），清除 %d 条删除记录\n": "Deleted %d file(s) (%s), cleared %d deletion record(s)\n",
+	"生成清理报告失败: %w":                "failed to generate prune report: %w",
+	"写入清理报告失败: %w":                "failed to write prune report: %w",
+	// app/remote.go
+	"桶 %s 的端点是本地目录（%s），请直接使用文件管理命令":          "the endpoint of bucket %s is a local directory (%s); use file management commands instead",
+	"桶 %s 的端点是WebDAV服务（%s），请使用WebDAV客户端管理文件": "the endpoint of bucket %s is a WebDAV service (%s); use a WebDAV client to manage its files",
+	// app/report.go
+	"不支持的报告格式: %s（可选 html、csv）":          "unsupported report format: %s (choose html or csv)",
+	"写入报告失败: %w":                         "failed to write report: %w",
+	"报告已写入: %s（%d 个桶，%d 次运行）\n":          "Report written to %s (%d buckets, %d runs)\n",
+	"无效的时间范围: %s（如 7d、24h 或 2006-01-02）": "invalid time range: %s (e.g. 7d, 24h or 2006-01-02)",
+	"ObjectSync 运行报告":                    "ObjectSync run report",
+	"统计范围":                               "Period",
+	"运行次数":                               "Runs",
+	"各桶情况":                               "Buckets",
+	"成功率":                                "Success rate",
+	"传输文件数":                              "Files transferred",
+	"传输数据量":                              "Data transferred",
+	"当前文件数":                              "Current files",
+	"当前数据量":                              "Current size",
+	"最后运行":                               "Last run",
+	"最后成功":                               "Last success",
+	"最常见的失败":                             "Top failures",
+	"次数":                                 "Count",
+	"最后出现":                               "Last seen",
+	"错误":                                 "Error",
+	"没有失败的运行":                            "No failed runs",
+	"写入报告的文件（默认输出到标准输出）":              "File to write the report to (defaults to standard output)",
+	"历史记录文件（默认使用配置文件中的 history_file）": "History file (defaults to history_file in the config file)",
+	"报告格式: html 或 csv":                "Report format: html or csv",
+	"根据运行历史和状态文件生成HTML或CSV报告：每个桶的成功率、传输的数据量、当前总量和最常见的失败，便于定期检查备份情况": "Generate an HTML or CSV report from the run history and state files: each bucket's success rate, data transferred, current total and most common failures, for reviewing backups regularly",
+	"生成运行报告": "Generate a run report",
+	"统计的时间范围，如 7d、24h 或起始日期 2006-01-02": "Time range to cover, e.g. 7d, 24h or a start date 2006-01-02",
+	// app/rm.go
+	"请指定要删除的对象键，删除整个桶的对象请使用 --recursive": "specify the object key to delete; use --recursive to delete every object in the bucket",
+	"检查对象失败: %w": "failed to check object: %w",
+	"对象不存在: %s/%s（删除前缀下的对象请使用 --recursive）": "object does not exist: %s/%s (use --recursive to delete objects under a prefix)",
+	"没有需要删除的对象\n":                           "No objects to delete\n",
+	"将删除: %s/%s\n":                          "Would delete: %s/%s\n",
+	"预演模式: 共 %d 个对象将被删除\n":                  "Dry run: %d object(s) would be deleted\n",
+	"删除: %s/%s\n":                           "Delete: %s/%s\n",
+	"删除对象失败: %w":                            "failed to delete objects: %w",
+	"已删除 %d 个对象\n":                          "Deleted %d object(s)\n",
+	"%d 个对象删除失败":                            "%d object(s) failed to delete",
+	"rm <s3://桶名/对象键|前缀>":                   "rm <s3://bucket/key|prefix>",
+	"删除前缀下的所有对象":                            "Delete every object under the prefix",
+	"删除桶中的单个对象，或使用 --recursive 删除前缀下的所有对象（通过DeleteObjects每批删除1000个）。前缀按目录处理，logs 只匹配 logs/ 下的对象。交互模式下删除前需要确认，--yes 跳过确认": "Delete a single object from a bucket, or with --recursive every object under the prefix (via DeleteObjects, 1000 per batch). The prefix is treated as a directory: logs only matches objects under logs/. In interactive mode deletion must be confirmed; --yes skips the confirmation",
+	"删除远程对象": "Delete remote objects",
+	"只列出将要删除的对象，不实际删除": "Only list the objects that would be deleted, without deleting them",
+	"不再询问确认": "Do not ask for confirmation",
+	"将永久删除 %s 中的 %d 个对象，是否继续?": "%[2]d object(s) in %[1]s will be permanently deleted. Continue?",
+	// app/output.go
+	"不支持的输出格式: %s（可选 %s、%s）":                  "unsupported output format: %s (choose %s or %s)",
+	"输出JSON结果失败: %w":                          "failed to write JSON result: %w",
+	"%s: 校验 %d 个桶 %d 个对象，%d 个不一致，%d 个桶失败":     "%s: verified %d buckets, %d objects, %d mismatched, %d buckets failed",
+	"%s: %d 个桶，待下载 %d 个对象（%s），待上传 %d 个文件（%s）": "%s: %d buckets, %d objects (%s) to download, %d files (%s) to upload",
+	// app/presign.go
+	"请指定对象键: %s":                "specify an object key: %s",
+	"生成预签名URL失败: %w":            "failed to generate presigned URL: %w",
+	"URL有效期 (如 30m、24h，最长168h)": "URL validity (e.g. 30m, 24h, at most 168h)",
+	"presign <s3://桶名/对象键>":     "presign <s3://bucket/key>",
+	"为对象生成预签名URL，持有URL的人无需密钥即可在有效期内下载（GET）或上传（PUT）该对象，有效期最长7天": "Generate a presigned URL for an object; anyone holding the URL can download (GET) or upload (PUT) the object without keys until it expires, at most 7 days",
+	"生成临时访问URL":            "Generate a temporary access URL",
+	"请求方法: GET 下载, PUT 上传": "Request method: GET to download, PUT to upload",
+	// app/put.go
+	"从标准输入上传时必须指定完整的对象键":           "a full object key is required when uploading from standard input",
+	"无效的远程路径: %s（格式: s3://桶名/对象键）": "invalid remote path: %s (format: s3://bucket/key)",
+	"put <本地文件|-> <s3://桶名/对象键>":   "put <local-file|-> <s3://bucket/key>",
+	"上传单个文件": "Upload a single file",
+	"将单个本地文件或标准输入（使用\"-\"）直接上传到指定的对象键，无需准备目录。对象键以/结尾时自动追加本地文件名": "Upload a single local file or standard input (\"-\") straight to the given key without preparing a directory. A key ending in / gets the local file name appended",
+	// app/run.go
+	"开始同步（共 %d 个桶）\n":           "Starting sync (%d bucket(s))\n",
+	"\n[%d/%d] %s桶: %s\n":       "\n[%d/%d] %s bucket: %s\n",
+	"桶 %s 同步失败: %v\n":           "Sync of bucket %s failed: %v\n",
+	"桶 %s 同步完成!\n":              "Sync of bucket %s finished!\n",
+	"\n同步完成!\n":                 "\nSync finished!\n",
+	"部分桶同步失败":                   "some buckets failed to sync",
+	"下载失败: %w":                  "download failed: %w",
+	"本地目录不存在: %s":               "local directory does not exist: %s",
+	"上传失败: %w":                  "upload failed: %w",
+	"合计: 传输 %d 个文件（%s），用时 %s\n": "Total: %d files transferred (%s) in %s\n",
+	"复制失败: %w":                  "replication failed: %w",
+	"复制":                        "Replicate",
+	"上次运行中断时跳过已完成的部分继续 (--resume=false 重新开始)": "Continue an interrupted run, skipping completed work (--resume=false starts over)",
+	"启用增量同步": "Enable incremental sync",
+	"按每个桶配置的 direction 执行操作：backup 下载到本地，upload 上传到对象存储，sync 先下载后上传，replicate 复制到另一个对象存储": "Act on each bucket by its configured direction: backup downloads to local disk, upload uploads to object storage, sync downloads then uploads, replicate copies to another object storage",
+	"按配置的方向同步所有桶": "Sync all buckets in their configured direction",
+	// app/service.go
+	"卸载服务 %s 失败: %w":                     "failed to uninstall service %s: %w",
+	"服务 %s 已卸载\n":                        "Service %s uninstalled\n",
+	"启动服务 %s 失败: %w":                     "failed to start service %s: %w",
+	"服务 %s 已启动\n":                        "Service %s started\n",
+	"正在停止服务 %s，等待正在运行的桶结束...\n":          "Stopping service %s, waiting for running buckets to finish...\n",
+	"停止服务 %s 失败: %w":                     "failed to stop service %s: %w",
+	"服务 %s 已停止\n":                        "Service %s stopped\n",
+	"配置文件不存在: %s":                        "config file not found: %s",
+	"安装服务 %s 失败: %w":                     "failed to install service %s: %w",
+	"服务 %s 已安装，开机自动启动\n":                 "Service %s installed and set to start at boot\n",
+	"日志文件: %s\n":                         "Log file: %s\n",
+	"使用 objectsync service start 立即启动\n": "Run objectsync service start to start it now\n",
+	"停止服务，等待正在运行的桶结束":                    "Stop the service, waiting for running buckets to finish",
+	"卸载服务":                               "Uninstall the service",
+	"启动服务":                               "Start the service",
+	"守护进程状态文件路径（相对于配置文件所在目录）": "Daemon status file path (relative to the config file directory)",
+	"安装服务，开机自动启动":             "Install the service to start at boot",
+	"将守护进程安装为Windows服务，开机后无人值守地按计划运行；服务停止时等待正在运行的桶结束后退出（仅Windows）": "Install the daemon as a Windows service that runs unattended on schedule after boot; when the service stops it waits for running buckets to finish (Windows only)",
+	"服务名": "Service name",
+	"服务日志文件路径（相对于配置文件所在目录）": "Service log file path (relative to the config file directory)",
+	"管理Windows服务": "Manage the Windows service",
+	// app/service_other.go
+	"仅Windows支持安装服务，其他系统请使用服务管理器运行 objectsync daemon": "services can only be installed on Windows; on other systems run objectsync daemon under your service manager",
+	// app/service_windows.go
+	"服务已存在，请先卸载":                "service already exists, uninstall it first",
+	"ObjectSync 守护进程，按计划同步对象存储": "ObjectSync daemon, syncs object storage on schedule",
+	"等待服务停止超时":                  "timed out waiting for the service to stop",
+	"守护进程退出: %v":                "Daemon exited: %v",
+	// app/state.go
+	"配置了多个桶，请使用 --bucket 指定":               "multiple buckets are configured, specify one with --bucket",
+	"桶 %s 配置了多个前缀，请使用 --state-file 指定状态文件": "bucket %s is configured with several prefixes, specify the state file with --state-file",
+	"状态文件不存在: %s":                          "state file does not exist: %s",
+	"共 %d 个条目\n":                           "%d entries\n",
+	"状态文件 %s 中没有 %s":                       "state file %s has no entry %s",
+	"键: %s\n":                              "Key: %s\n",
+	"本地文件: %s\n":                           "Local file: %s\n",
+	"  不存在，下次备份时会重新下载":                     "  missing, it will be downloaded again on the next backup",
+	"  无法读取: %v\n":                         "  cannot read: %v\n",
+	"  大小: %d（状态记录 %d）\n":                  "  Size: %d (state records %d)\n",
+	"  修改时间: %s\n":                         "  Modified: %s\n",
+	"警告: 状态文件中没有 %s\n":                     "Warning: state file has no entry %s\n",
+	"没有删除任何条目":                             "no entries removed",
+	"保存状态文件失败: %w":                         "failed to save state file: %w",
+	"已从 %s 删除 %d 个条目\n":                    "Removed %[2]d entries from %[1]s\n",
+	"文件大小: %s\n":                           "File size: %s\n",
+	"上次运行时间: %s\n":                         "Last run: %s\n",
+	"条目数: %d\n":                            "Entries: %d\n",
+	"重新生成状态需要从配置文件读取桶的连接信息和输出目录，不能使用 --state-file": "rebuilding state needs the bucket connection and output directory from the config file, --state-file cannot be used",
+	"只能重新生成备份状态":                  "only backup state can be rebuilt",
+	"重新生成状态: 桶 %s，输出目录 %s\n":      "Rebuilding state: bucket %s, output directory %s\n",
+	"原来的状态文件已保存为: %s\n":           "Previous state file kept as: %s\n",
+	"已记录与本地文件一致的对象: %d\n":         "Objects matching local files (recorded): %d\n",
+	"本地不存在或不一致的对象: %d（下次备份时下载）\n": "Objects missing or different locally: %d (downloaded on the next backup)\n",
+	"无法比较的打包或压缩对象: %d（下次备份时下载）\n": "Pack or compressed objects that cannot be compared: %d (downloaded on the next backup)\n",
+	"状态文件已重新生成: %s\n":             "State file rebuilt: %s\n",
+	"不支持的格式: %s（可选 json、csv）":     "unsupported format: %s (json or csv)",
+	"导出状态失败: %w":                  "failed to export state: %w",
+	"已导出 %d 个条目到 %s\n":            "Exported %d entries to %s\n",
+	"导入状态失败: %w":                  "failed to import state: %w",
+	"已导入 %d 个条目到 %s\n":            "Imported %d entries into %s\n",
+	"状态文件格式错误: 没有 files 字段":       "invalid state file: no files field",
+	"条目 %s 格式错误: %w":              "invalid entry %s: %w",
+	"CSV格式错误: %w":                 "invalid CSV: %w",
+	"CSV格式错误: 没有 key 列":           "invalid CSV: no key column",
+	"删除记录: %d（最近 %s）\n":           "Deletion records: %d (latest %s)\n",
+	"删除记录: 于 %s 发现已删除\n":          "Deletion record: found deleted at %s\n",
+	"export [文件]":                 "export [file]",
+	"import <文件>":                 "import <file>",
+	"list [前缀]":                   "list [prefix]",
+	"rm <键>...":                   "rm <key>...",
+	"show <键>":                    "show <key>",
+	"从导出的JSON或CSV文件导入状态":          "Import state from an exported JSON or CSV file",
+	"列出状态中的条目":                    "List state entries",
+	"列出远程对象并与输出目录中的文件比较，把一致的文件记录到新的状态文件中，状态文件丢失或损坏后不需要重新下载已有的文件。原来的状态文件改名为 .bak 保留": "List remote objects and compare them with files in the output directory, recording matching files in a new state file so existing files need not be downloaded again after the state file is lost or corrupted. The old state file is kept renamed to .bak",
+	"删除条目，下次运行时重新传输对应的文件":          "Delete entries so their files are transferred again on the next run",
+	"只列出已删除的对象（或本地文件）的删除记录":        "Only list deletion records of deleted objects (or local files)",
+	"只比较大小，不计算本地文件的MD5":            "Only compare sizes, without computing MD5 of local files",
+	"导入前清空状态中原有的条目":                "Clear existing state entries before importing",
+	"导入格式: json 或 csv（默认按文件扩展名判断）": "Import format: json or csv (inferred from the file extension by default)",
+	"导出格式: json 或 csv":             "Export format: json or csv",
+	"导出状态为JSON或CSV":                "Export state as JSON or CSV",
+	"并发计算MD5的工作数":                  "Number of workers computing MD5 concurrently",
+	"把 state export 导出的条目写入状态，与已有的条目合并，相同的键被替换。状态文件不存在时创建，可以用来在JSON和bbolt后端之间迁移状态": "Write entries exported by state export into the state, merging with existing entries and replacing identical keys. The state file is created if missing; this can migrate state between the JSON and bbolt backends",
+	"把参数作为前缀，删除前缀下的所有条目": "Treat the arguments as prefixes and delete every entry under them",
+	"把状态中的所有条目导出为JSON或CSV，用于在表格软件中查看、归档，或者导入到另一个状态存储后端。不指定文件时输出到标准输出": "Export every state entry as JSON or CSV, for viewing in a spreadsheet, archiving, or importing into another state backend. Writes to standard output when no file is given",
+	"按输出目录中已有的文件重新生成备份状态": "Rebuild the backup state from files in the output directory",
+	"操作上传状态而不是备份状态":       "Operate on the upload state instead of the backup state",
+	"显示单个条目和对应的本地文件":      "Show a single entry and its local file",
+	"显示状态文件的统计信息":         "Show state file statistics",
+	"查看和修改状态文件":           "Inspect and modify state files",
+	"查看备份或上传状态文件中的条目，删除错误的条目使其在下次运行时重新传输，不需要手工编辑JSON": "Inspect entries in backup or upload state files and delete wrong entries so they are transferred again on the next run, without editing JSON by hand",
+	"桶名（配置了多个桶时必须指定）":    "Bucket name (required when several buckets are configured)",
+	"直接指定状态文件路径，不读取配置文件": "State file path to use directly, without reading the config file",
+	// app/sync.go
+	"源目录不存在: %s":            "source directory does not exist: %s",
+	"目标目录不能位于源目录中: %s":      "target directory must not be inside the source directory: %s",
+	"同步: %s -> %s\n":        "Sync: %s -> %s\n",
+	"同步失败: %w":              "sync failed: %w",
+	"同步完成! 复制 %d 个文件（%s）\n": "Sync completed! Copied %d files (%s)\n",
+	"把源目录中新增和修改的文件复制到目标目录（如NAS到U盘），与上传使用相同的增量状态、过滤规则和进度显示，保留文件的修改时间和权限，不删除目标目录中多出的文件。配置文件中把端点设为 local:目录 可以在 backup、upload、run 中使用本地目录": "Copy new and modified files from the source directory to the target directory (e.g. NAS to a USB drive), using the same incremental state, filters and progress display as upload. File modification times and permissions are preserved, and extra files in the target directory are not deleted. Set an endpoint to local:<dir> in the config file to use a local directory with backup, upload and run",
+	"sync <源目录> <目标目录>": "sync <source-dir> <target-dir>",
+	"上次运行中断时跳过已复制的文件继续 (--resume=false 重新开始)":     "Continue an interrupted run, skipping files already copied (--resume=false starts over)",
+	"不同步匹配的文件（可重复）":                               "Do not sync matching files (repeatable)",
+	"只同步匹配的文件（可重复）":                               "Only sync matching files (repeatable)",
+	"同步两个本地目录":                                    "Sync two local directories",
+	"启用增量同步，只复制上次同步后变化的文件":                        "Enable incremental sync, copying only files changed since the last sync",
+	"在进度条下方显示每个正在复制的文件的进度和速度（仅终端，--verbose 时不显示）": "Show the progress and speed of each file being copied below the progress bar (terminal only, hidden with --verbose)",
+	"复制后校验目标文件的大小":                                "Verify the target file size after copying",
+	"状态文件路径 (默认为当前目录下的 .sync_state_<目标目录名>.json)": "State file path (defaults to .sync_state_<target-dir-name>.json in the current directory)",
+	// app/systemd.go
+	"不支持的运行方式: %s（可选 daemon、timer）":                  "unsupported mode: %s (choose daemon or timer)",
+	"写入单元文件失败: %w":                                   "failed to write unit file: %w",
+	"已写入: %s\n":                                      "Wrote %s\n",
+	"使用 systemctl%s enable --now %s 启用\n":            "Run systemctl%s enable --now %s to enable it\n",
+	"已启用并启动 %s，使用 journalctl%s -u %s.service 查看日志\n": "Enabled and started %s; view logs with journalctl%s -u %s.service\n",
+	"删除单元文件失败: %w":                                   "failed to remove unit file: %w",
+	"已删除: %s\n":                                      "Removed %s\n",
+	"%s 中没有 %s 的单元文件":                                "no unit files for %[2]s in %[1]s",
+	"systemctl %s 失败: %w":                            "systemctl %s failed: %w",
+	"timer 模式的触发时间（systemd OnCalendar 格式，如 daily、*-*-* 02:00:00）": "Trigger time in timer mode (systemd OnCalendar format, e.g. daily, *-*-* 02:00:00)",
+	"停止并删除安装的单元":                             "Stop and remove the installed units",
+	"单元名（不含后缀）":                              "Unit name (without suffix)",
+	"只写入单元文件，不启用和启动":                         "Only write the unit files, without enabling and starting them",
+	"只输出生成的单元文件，不安装":                         "Only print the generated unit files, without installing them",
+	"安装为当前用户的单元（systemctl --user），不需要root权限": "Install as units of the current user (systemctl --user), without root",
+	"生成并安装systemd单元：daemon 模式安装常驻服务（支持就绪通知和看门狗），timer 模式安装定时触发 run 的服务和定时器": "Generate and install systemd units: daemon mode installs a long-running service (with readiness notification and watchdog), timer mode installs a service and timer that trigger run",
+	"生成并安装单元，设置开机启动": "Generate and install the units, enabled at boot",
+	"管理systemd单元":    "Manage systemd units",
+	"运行方式: daemon 常驻按各桶 schedule 运行, timer 按 --on-calendar 定时运行 run": "Mode: daemon runs continuously on each bucket's schedule, timer runs run at --on-calendar",
+	"运行服务的用户（默认root，--user 时忽略）":                                     "User running the service (default root, ignored with --user)",
+	// app/tui.go
+	"创建日志管道失败: %w": "failed to create log pipe: %w",
+	"启动交互界面失败: %w": "failed to start interactive UI: %w",
+	" 日志 ":         " Log ",
+	"Enter 开始  a 全部开始  x 停止  t 测试连接  e 编辑配置  r 重新加载  c 清空日志  q 退出": "Enter start  a start all  x stop  t test connection  e edit config  r reload  c clear log  q quit",
+	" %s （Ctrl-S 保存，Esc 返回） ": " %s (Ctrl-S save, Esc back) ",
+	"有任务正在运行，完成后再重新加载配置":      "Jobs are running, reload the config after they finish",
+	"配置加载失败: %v":              "failed to load config: %v",
+	"按 e 编辑配置文件":              "Press e to edit the config file",
+	"配置验证失败: %v":              "config validation failed: %v",
+	"已加载配置 %s（共 %d 个桶）":       "Loaded config %s (%d buckets)",
+	"桶":                       "Bucket",
+	"方向":                      "Direction",
+	"状态":                      "Status",
+	"进度":                      "Progress",
+	"文件":                      "Files",
+	"数据量":                     "Size",
+	"开始%s桶: %s":               "Starting %s of bucket: %s",
+	"桶 %s 同步完成!":              "Bucket %s sync completed!",
+	"桶 %s 已停止":                "Bucket %s stopped",
+	"桶 %s 同步失败: %v":           "Bucket %s sync failed: %v",
+	"正在停止桶 %s，等待正在传输的文件完成...": "Stopping bucket %s, waiting for in-flight files to finish...",
+	"有任务正在运行，请先按 x 停止或等待完成":   "Jobs are running, press x to stop them or wait for them to finish",
+	"读取配置文件失败: %v":            "failed to read config file: %v",
+	"配置文件已保存: %s":             "Config file saved: %s",
+	"确定":                      "OK",
+	"运行中":                     "running",
+	"正在停止":                    "stopping",
+	"完成":                      "done",
+	"已停止":                     "stopped",
+	"失败":                      "failed",
+	"空闲":                      "idle",
+	"开始测试 %d 个桶的连接...":        "Testing connection for %d buckets...",
+	"[失败] %s (%s): %v":        "[FAIL] %s (%s): %v",
+	"[通过] %s (%s)，用时 %s":      "[PASS] %s (%s), took %s",
+	"合计":                      "Total",
+	"%d 个运行中":                 "%d running",
+	// app/verify.go
+	"开始校验（共 %d 个桶）\n":                            "Starting verification (%d buckets)\n",
+	"\n[%d/%d] 校验桶: %s -> %s\n":                  "\n[%d/%d] Verifying bucket: %s -> %s\n",
+	"桶 %s 校验失败: %v\n":                            "Bucket %s verification failed: %v\n",
+	"桶 %s 有 %d 个不一致的文件":                          "bucket %s has %d mismatched files",
+	"\n校验报告已写入: %s\n":                            "\nVerification report written to: %s\n",
+	"\n校验完成!\n":                                  "\nVerification completed!\n",
+	"一致: %d 个桶\n":                                "Consistent: %d buckets\n",
+	"不一致或失败: %d 个桶（%d 个不一致的文件）\n":                "Mismatched or failed: %d buckets (%d mismatched files)\n",
+	"校验发现问题":                                     "verification found problems",
+	"校验 %d 个对象，%d 个不一致":                          "Checked %d objects, %d mismatched",
+	"，跳过 %d 个打包对象":                               ", skipped %d pack objects",
+	"  ……还有 %d 个，使用 --verbose 或 --report 查看全部\n": "  ...and %d more, use --verbose or --report to see all\n",
+	"缺失":           "missing",
+	"大小不一致":        "size differs",
+	"内容不一致":        "content differs",
+	"已过期":          "outdated",
+	"读取失败":         "read error",
+	"生成校验报告失败: %w": "failed to generate verification report: %w",
+	"写入校验报告失败: %w": "failed to write verification report: %w",
+	"将所有不一致的文件写入JSON报告文件": "Write every mismatched file to a JSON report file",
+	"校验本地备份与远程对象是否一致":     "Verify local backups against remote objects",
+	"深度校验时优先比较对象上传时附加的该算法的校验值，分片上传的组合校验值除外 (CRC32, CRC32C, SHA1, SHA256)": "With --deep, prefer the checksum of this algorithm attached at upload, except composite checksums of multipart uploads (CRC32, CRC32C, SHA1, SHA256)",
+	"深度校验时并发计算MD5的工作数": "Number of workers computing MD5 concurrently with --deep",
+	"详细输出，列出所有不一致的文件":  "Verbose output, listing every mismatched file",
+	"逐个比较远程对象与本地备份文件的存在性和大小，并检查状态记录是否过期；--deep 重新计算本地文件的内容，与下载时记录的SHA-256、上传时附加的校验值（--checksum-algorithm）或远程ETag比较": "Compare the existence and size of each remote object with its local backup file and check for stale state records; --deep recomputes local file contents and compares them with the SHA-256 recorded at download, the checksum attached at upload (--checksum-algorithm) or the remote ETag",
+	"重新计算本地文件的SHA-256与下载时的记录比较，没有记录时计算MD5与远程ETag比较（分片上传的对象只比较大小）":                                                   "Recompute the SHA-256 of local files and compare it with the record from download; without a record compute MD5 and compare it with the remote ETag (multipart objects only compare sizes)",
+	// backup/backup.go
+	"初始化S3客户端失败: %w":        "failed to initialize S3 client: %w",
+	"加载备份状态失败: %w":          "failed to load backup state: %w",
+	"创建输出目录失败: %w":          "failed to create output directory: %w",
+	"列出对象失败: %w":            "failed to list objects: %w",
+	"发现 %d 个对象":             "Found %d object(s)",
+	"需要下载 %d 个对象":           "%d object(s) to download",
+	"没有需要下载的文件":             "Nothing to download",
+	"下载对象失败: %w":            "failed to download objects: %w",
+	"保存备份状态失败: %w":          "failed to save backup state: %w",
+	"目录已存在: %s":             "Directory already exists: %s",
+	"下载 %s 失败: %w":          "failed to download %s: %w",
+	"下载: %s -> %s":          "Download: %s -> %s",
+	"创建目录失败: %w":            "failed to create directory: %w",
+	"获取目录元数据失败 %s: %v":      "failed to get directory metadata %s: %v",
+	"设置文件属性失败 %s: %v":       "failed to set file attributes %s: %v",
+	"设置目录属性失败 %s: %v":       "failed to set directory attributes %s: %v",
+	"无法创建运行日志: %v":          "cannot create run journal: %v",
+	"已保存中断前下载完成的 %d 个对象的状态": "saved state for %d objects downloaded before the interruption",
+	"保存备份状态失败: %v":          "failed to save backup state: %v",
+	"%d 个对象已从桶中删除，已在状态中记录":  "%d object(s) were deleted from the bucket, recorded in the state",
+	"已从状态中清理 %d 个已删除对象的记录":  "Pruned %d deleted object record(s) from the state",
+	// backup/compress.go
+	"解压 %s 失败: %w": "failed to decompress %s: %w",
+	"解压: %s -> %s": "Decompress: %s -> %s",
+	// backup/journal.go
+	"无法读取运行日志 %s，重新开始: %v":             "cannot read run journal %s, starting over: %v",
+	"继续 %s 开始的中断运行：已完成 %d 个对象，剩余 %d 个": "Resuming interrupted run started %s: %d objects done, %d remaining",
+	"写入运行日志失败: %v":                     "failed to write run journal: %v",
+	"删除运行日志失败: %v":                     "failed to remove run journal: %v",
+	"不继续上次中断的运行: %v":                   "not resuming the interrupted run: %v",
+	// backup/pack.go
+	"解压打包对象 %s 失败: %w":    "failed to extract pack object %s: %w",
+	"解压: %s（%d 个文件）-> %s": "Extract: %s (%d file(s)) -> %s",
+	// backup/prune.go
+	"未启用增量备份，没有删除记录可以清理": "incremental backup is not enabled, there are no deletion records to prune",
+	"读取备份状态失败: %w":       "failed to read backup state: %w",
+	"更新备份状态失败: %w":       "failed to update backup state: %w",
+	"读取 %s 失败: %w":       "failed to read %s: %w",
+	"删除 %s 失败: %w":       "failed to delete %s: %w",
+	// backup/rebuild.go
+	"未启用增量备份，没有状态文件需要重新生成": "incremental backup is disabled, there is no state file to rebuild",
+	"计算 %d 个本地文件的MD5":      "Computing MD5 of %d local file(s)",
+	"与远程对象不一致: %s（%s）":     "Differs from remote object: %s (%s)",
+	"保留原来的状态文件失败: %w":      "failed to keep the previous state file: %w",
+	"创建状态文件失败: %w":         "failed to create state file: %w",
+	// backup/verify.go
+	"本地 %d，远程 %d":       "local %d, remote %d",
+	"备份时 ETag %s，远程 %s": "ETag %s at backup, remote %s",
+	"校验: %s":            "Verifying: %s",
+	"SHA-256 %s，下载时 %s": "SHA-256 %s, at download %s",
+	// compress/compress.go
+	"不支持的压缩算法: %s（可选值: gzip, zstd）": "unsupported compression algorithm: %s (valid values: gzip, zstd)",
+	"不支持的压缩算法: %s":                  "unsupported compression algorithm: %s",
+	// config/cluster.go
+	"排除后没有要处理的桶":                                 "no buckets left to process after exclusions",
+	"没有要处理的桶（所有桶的方向都是 %s）":                       "no buckets to process (all buckets have direction %s)",
+	"clusters[%d] 缺少 name":                       "clusters[%d] is missing name",
+	"clusters[%d] 的名称 %s 与 remotes 中的连接重复":       "clusters[%d] name %s duplicates a connection in remotes",
+	"clusters[%d].buckets[%d] 不能引用其他 remote: %s": "clusters[%d].buckets[%d] must not reference another remote: %s",
+	"集群 %s 下没有配置桶（可选值: %v）":                      "cluster %s has no buckets configured (valid values: %v)",
+	"没有要处理的桶（所有桶都没有设置 output_dir）":               "no buckets to process (none of the buckets has output_dir set)",
+	// config/config.go
+	"配置文件 %s 不存在，正在创建默认配置文件...\n":                                        "Config file %s does not exist, creating a default config file...\n",
+	"创建默认配置文件失败: %w":                                                     "failed to create default config file: %w",
+	"默认配置文件已创建: %s\n":                                                    "Default config file created: %s\n",
+	"请编辑配置文件并填入正确的Ceph连接信息，然后重新运行程序。\n":                                  "Edit the config file with the correct Ceph connection details, then run the program again.\n",
+	"请先配置 %s 文件":                                                         "please configure %s first",
+	"读取配置文件失败: %w":                                                       "failed to read config file: %w",
+	"配置文件包含无效的配置项:\n%w":                                                  "config file contains invalid keys:\n%w",
+	"解析配置文件失败: %w":                                                       "failed to parse config file: %w",
+	"配置文件引用的环境变量无效:\n%w":                                                 "config file references invalid environment variables:\n%w",
+	"请在配置文件中设置要备份的桶：buckets":                                             "please set the buckets to back up in the config file: buckets",
+	"buckets[%d] 引用的 remote 不存在: %s":                                     "buckets[%d] references a remote that does not exist: %s",
+	"buckets[%d] 缺少桶名称":                                                  "buckets[%d] is missing a bucket name",
+	"buckets[%d] 缺少输出目录":                                                 "buckets[%d] is missing an output directory",
+	"buckets[%d].source_dirs[%d] 缺少 path":                                "buckets[%d].source_dirs[%d] is missing path",
+	"buckets[%d] direction 为 %s 时需要设置 output_dir":                        "buckets[%d] requires output_dir when direction is %s",
+	"buckets[%d] schedule 无效: %w":                                        "buckets[%d] invalid schedule: %w",
+	"buckets[%d] dir_markers 无效: %s（可选值: all, empty, none）":              "buckets[%d] invalid dir_markers: %s (allowed: all, empty, none)",
+	"buckets[%d].headers[%d] 缺少 pattern":                                 "buckets[%d].headers[%d] is missing pattern",
+	"buckets[%d].headers[%d] pattern 无效: %s":                             "buckets[%d].headers[%d] invalid pattern: %s",
+	"buckets[%d].compress[%d] 缺少 pattern":                                "buckets[%d].compress[%d] is missing pattern",
+	"buckets[%d].compress[%d] pattern 无效: %s":                            "buckets[%d].compress[%d] invalid pattern: %s",
+	"buckets[%d].compress[%d] algorithm 无效: %s（可选值: gzip, zstd）":         "buckets[%d].compress[%d] invalid algorithm: %s (allowed: gzip, zstd)",
+	"retry.max_attempts 必须大于等于1":                                         "retry.max_attempts must be at least 1",
+	"retry.delay 不能为负数":                                                  "retry.delay must not be negative",
+	"backup.workers 必须大于等于1":                                             "backup.workers must be at least 1",
+	"backup.parts_concurrency 不能为负数":                                     "backup.parts_concurrency must not be negative",
+	"defaults.workers 不能为负数":                                             "defaults.workers must not be negative",
+	"defaults.parts_concurrency 不能为负数":                                   "defaults.parts_concurrency must not be negative",
+	"defaults.dir_markers 无效: %s（可选值: all, empty, none）":                 "defaults.dir_markers invalid: %s (allowed: all, empty, none)",
+	"buckets[%d].workers 不能为负数":                                          "buckets[%d].workers must not be negative",
+	"buckets[%d].parts_concurrency 不能为负数":                                "buckets[%d].parts_concurrency must not be negative",
+	"%s[%d] 不能为空":                                                        "%s[%d] must not be empty",
+	"%s[%d] 模式无效: %s":                                                    "%s[%d] invalid pattern: %s",
+	"%s 无效: %s（如 10MB，表示每秒传输量）":                                          "%s invalid: %s (e.g. 10MB, bytes per second)",
+	"remote 不存在: %s":                                                     "remote does not exist: %s",
+	"请在配置文件中设置正确的 %s.endpoint":                                           "please set a valid %s.endpoint in the config file",
+	"%s.timeouts.%s 不能为负数":                                               "%s.timeouts.%s must not be negative",
+	"请在配置文件中设置正确的 %s.access_key":                                         "please set a valid %s.access_key in the config file",
+	"请在配置文件中设置正确的 %s.secret_key":                                         "please set a valid %s.secret_key in the config file",
+	"%s.tls.cert_file 和 %s.tls.key_file 必须同时设置":                          "%s.tls.cert_file and %s.tls.key_file must be set together",
+	"%s.tls.%s 无法读取: %w":                                                 "%s.tls.%s cannot be read: %w",
+	"notifications[%d] 缺少 webhook_url":                                   "notifications[%d] is missing webhook_url",
+	"notifications[%d] 类型为 telegram 时需要设置 bot_token 和 chat_id":           "notifications[%d] of type telegram requires bot_token and chat_id",
+	"notifications[%d] type 无效: %s（可选值: %s）":                             "notifications[%d] has invalid type: %s (allowed: %s)",
+	"notifications[%d] on 无效: %s（可选值: always, failure）":                  "notifications[%d] has invalid on: %s (allowed: always, failure)",
+	"backup.checkpoint_files 不能为负数":                                      "backup.checkpoint_files must not be negative",
+	"backup.checkpoint_interval 不能为负数":                                   "backup.checkpoint_interval must not be negative",
+	"backup.prune_deleted_after 不能为负数":                                   "backup.prune_deleted_after must not be negative",
+	"%s.endpoint 不是有效的WebDAV地址: %s":                                      "%s.endpoint is not a valid WebDAV URL: %s",
+	"%s.transport 无效: %s（可选值: aws, minio）":                               "%s.transport is invalid: %s (allowed: aws, minio)",
+	"buckets[%d] direction 无效: %s（可选值: backup, upload, sync, replicate）": "buckets[%d] invalid direction: %s (allowed: backup, upload, sync, replicate)",
+	"buckets[%d] direction 为 replicate 时需要设置 target":                     "buckets[%d] target is required when direction is replicate",
+	"buckets[%d] 引用的 target 不存在: %s":                                     "buckets[%d] references a target that does not exist: %s",
+	"buckets[%d] 复制的目标与源是同一个桶":                                           "buckets[%d] replication target is the same bucket as the source",
+	"buckets[%d].max_memory 无效: %s（如 512MB）":                             "buckets[%d].max_memory is invalid: %s (e.g. 512MB)",
+	// config/discover.go
+	"buckets 不是数组，请手动处理": "buckets is not a list, please fix it manually",
+	// config/env.go
+	"环境变量 %s 未设置": "environment variable %s is not set",
+	// config/lint.go
+	"第 %d 行: %s 不会生效，%s":                               "line %d: %s has no effect, %s",
+	"第 %d 行: %s 仍是示例值 %s":                              "line %d: %s is still the example value %s",
+	"第 %d 行: %s 为 %s（默认 %s）":                           "line %d: %s is %s (default %s)",
+	"每个桶使用自己的 state_file（默认为 .backup_state_<桶名>.json）": "each bucket uses its own state_file (default .backup_state_<bucket>.json)",
+	// config/migrate.go
+	"移除 ceph.bucket（%s）":                    "remove ceph.bucket (%s)",
+	"ceph.bucket（%s）与 bucket（%s）不一致，请手动处理":  "ceph.bucket (%s) differs from bucket (%s), please fix it manually",
+	"移除顶层 bucket（%s）":                       "remove top-level bucket (%s)",
+	"buckets 中已存在桶 %s，未重复添加":                "bucket %s already exists in buckets, not added again",
+	"添加桶 %s（output_dir: %s，state_file: %s）": "add bucket %s (output_dir: %s, state_file: %s)",
+	// config/paths.go
+	"桶 %s 的 output_dir %s: %w":         "bucket %s output_dir %s: %w",
+	"桶 %s 与桶 %s 使用了相同的 output_dir: %s": "bucket %s and bucket %s use the same output_dir: %s",
+	"桶 %s 的 state_file %s: %w":         "bucket %s state_file %s: %w",
+	"桶 %s 与桶 %s 使用了相同的 state_file: %s": "bucket %s and bucket %s use the same state_file: %s",
+	"已存在同名文件，不是目录":                     "a file with the same name exists and is not a directory",
+	"上级路径 %s 不是目录":                     "parent path %s is not a directory",
+	"无法创建目录: %w":                       "cannot create directory: %w",
+	"找不到已存在的上级目录":                      "no existing parent directory found",
+	"目录不可写: %w":                        "directory is not writable: %w",
+	"是一个目录":                            "is a directory",
+	"文件不可写: %w":                        "file is not writable: %w",
+	// config/schema.go
+	"第 %d 行: 配置项 %s 已废弃，%s":                     "line %d: setting %s is deprecated, %s",
+	"第 %d 行: 未知的配置项 %s":                         "line %d: unknown setting %s",
+	"（是否为 %s？）":                                 " (did you mean %s?)",
+	"请运行 objectsync config migrate 转换为 buckets": "run objectsync config migrate to convert it to buckets",
+	// config/secret.go
+	"无效的密钥环引用: %s（格式: keyring:服务名/账户名）": "invalid keyring reference: %s (format: keyring:service/account)",
+	"从系统密钥环读取 %s 失败: %w":                "failed to read %s from the system keyring: %w",
+	"%s_file 和 %s_cmd 不能同时设置":           "%s_file and %s_cmd must not both be set",
+	"读取 %s_file 失败: %w":                 "failed to read %s_file: %w",
+	"执行 %s_cmd 失败: %w":                  "failed to run %s_cmd: %w",
+	"%s 的来源为空":                          "source of %s is empty",
+	// config/watch.go
+	"配置文件 %s 重新加载失败，继续使用原配置: %v": "failed to reload config file %s, keeping the current configuration: %v",
+	"配置文件 %s 已重新加载":              "Config file %s reloaded",
+	// config/yamledit.go
+	"配置文件为空或格式不正确":  "config file is empty or malformed",
+	"生成配置文件失败: %w":  "failed to generate config file: %w",
+	"备份原配置文件失败: %w": "failed to back up the original config file: %w",
+	// fileattr/fileattr.go
+	"设置属主失败: %v": "failed to set owner: %v",
+	"设置权限失败: %v": "failed to set permissions: %v",
+	"设置时间失败: %v": "failed to set times: %v",
+	// journal/journal.go
+	"运行日志属于其他桶或前缀 %s/%s":   "journal belongs to another bucket or prefix %s/%s",
+	"过滤条件或选项与中断的运行不同":      "filters or options differ from the interrupted run",
+	"中断的运行开始于 %s，已超过 %d 天": "interrupted run started at %s, more than %d days ago",
+	// logging/logging.go
+	"无效的日志格式: %s（可选 text、json）":             "invalid log format: %s (valid: text, json)",
+	"无效的日志级别: %s（可选 debug、info、warn、error）": "invalid log level: %s (valid: debug, info, warn, error)",
+	"错误: ": "Error: ",
+	"警告: ": "Warning: ",
+	"调试: ": "Debug: ",
+	// notify/notify.go
+	"%s ObjectSync %s（%s）\n": "%s ObjectSync %s (%s)\n",
+	"成功 %d 个桶，失败 %d 个桶，传输 %d 个文件（%s），用时 %s": "%d bucket(s) succeeded, %d failed, %d file(s) transferred (%s), took %s",
+	"不支持的通知类型: %s":    "unsupported notification type: %s",
+	"通知服务返回 %s: %s":   "notification service returned %s: %s",
+	"通知服务返回错误 %d: %s": "notification service returned error %d: %s",
+	"标签: %s\n":        "Labels: %s\n",
+	"（%d 个对象失败）":      " (%d object(s) failed)",
+	// pack/pack.go
+	"打包 %s 失败: %w": "failed to pack %s: %w",
+	"打包条目路径无效: %s": "invalid pack entry path: %s",
+	// progress/progress.go
+	"开始备份: %d 个文件, 总计 %s\n":                "Starting backup: %d file(s), %s in total\n",
+	"%.1f%% | %d/%d 文件 | %s/%s | %s/s":     "%.1f%% | %d/%d files | %s/%s | %s/s",
+	"进度: %s\n":                             "Progress: %s\n",
+	"\n\n备份完成!\n":                          "\n\nBackup finished!\n",
+	"统计信息:\n":                              "Statistics:\n",
+	"  文件数量: %d\n":                         "  Files: %d\n",
+	"  数据大小: %s\n":                         "  Size: %s\n",
+	"  用时: %s\n":                           "  Elapsed: %s\n",
+	"  平均速度: %s/s\n":                       "  Average speed: %s/s\n",
+	"大小不能为空":                               "size must not be empty",
+	"无效的大小: %s":                            "invalid size: %s",
+	"桶 %d/%d":                              "Bucket %d/%d",
+	"  ... 另有 %d 个文件正在传输":                  "  ... %d more files in transfer",
+	" | 停滞 %s":                             " | stalled %s",
+	"  其中中断前已完成: %d 个文件（%s）\n":             "  Completed before the interruption: %d file(s) (%s)\n",
+	"  单个对象用时: p50 %s, p95 %s, 最长 %s\n":    "  Per-object time: p50 %s, p95 %s, max %s\n",
+	"  按大小:\n":                             "  By size:\n",
+	"    %-12s %d 个文件（%s）\n":               "    %-12s %d file(s) (%s)\n",
+	"  最慢的 %d 个对象:\n":                      "  Slowest %d objects:\n",
+	"  重试: %d 次 | 跳过: %d 个文件 | 失败: %d 次\n": "  Retries: %d | Skipped: %d file(s) | Failed: %d\n",
+	// remote/presign.go
+	"有效期必须大于0且不超过 %s: %s":      "expiry must be greater than 0 and at most %s: %s",
+	"不支持的请求方法: %s（可选 GET、PUT）": "unsupported method: %s (choose GET or PUT)",
+	// replicate/attributes.go
+	"源端或目标端不支持对象标签和ACL，只复制内容和元数据": "Source or target does not support object tags and ACLs, copying content and metadata only",
+	"复制 %s 的标签失败: %v":             "Failed to copy tags of %s: %v",
+	"目标端的客户端不支持设置ACL，不再复制ACL":     "The target client does not support setting ACLs, no longer copying ACLs",
+	"复制 %s 的ACL失败: %v":            "Failed to copy ACL of %s: %v",
+	// replicate/copy.go
+	"复制 %s 失败（第 %d/%d 次）: %v，%s 后重试": "Copying %s failed (attempt %d/%d): %v, retrying in %s",
+	"复制校验失败 %s: %s":                  "copy verification failed for %s: %s",
+	"大小不一致（源 %d，目标 %d）":              "size mismatch (source %d, target %d)",
+	"ETag不一致（源 %s，目标 %s）":            "ETag mismatch (source %s, target %s)",
+	"读取源对象失败: %v":                    "failed to read source object: %v",
+	"复制: %s":                         "Copy: %s",
+	"内容超过对象长度":                       "content exceeds object length",
+	// replicate/replicate.go
+	"确保目标桶存在失败: %w":         "failed to ensure target bucket exists: %w",
+	"加载复制状态失败: %w":          "failed to load replication state: %w",
+	"没有需要复制的对象":             "No objects need to be copied",
+	"保存复制状态失败: %w":          "failed to save replication state: %w",
+	"保存复制状态失败: %v":          "Failed to save replication state: %v",
+	"复制对象失败: %w":            "failed to copy objects: %w",
+	"初始化源端客户端失败: %w":        "failed to initialize source client: %w",
+	"初始化目标端客户端失败: %w":       "failed to initialize target client: %w",
+	"已保存中断前复制完成的 %d 个对象的状态": "Saved state of %d objects copied before the interruption",
+	"%d 个对象已从源桶中删除，已在状态中记录": "%d objects were deleted from the source bucket and recorded in state",
+	"跳过已从源桶删除的对象: %s":       "Skipping object deleted from the source bucket: %s",
+	"复制 %s 失败: %w":          "failed to copy %s: %w",
+	"需要复制 %d 个对象":           "%d objects need to be copied",
+	// replicate/verify.go
+	"列出源桶的对象失败: %w":  "failed to list source bucket objects: %w",
+	"列出目标桶的对象失败: %w": "failed to list target bucket objects: %w",
+	"源 %d，目标 %d":     "source %d, target %d",
+	"源 %s，目标 %s":     "source %s, target %s",
+	// s3client/minio.go
+	"minio传输的端点只能包含协议、主机和端口: %s": "endpoint for the minio transport may only contain scheme, host and port: %s",
+	// s3client/proxy.go
+	"代理地址无效: %w": "invalid proxy address: %w",
+	"不支持的代理协议: %s（可选值: http, https, socks5）": "unsupported proxy scheme: %s (valid values: http, https, socks5)",
+	"代理地址缺少主机: %s":                           "proxy address is missing a host: %s",
+	// s3client/tls.go
+	"读取CA证书失败: %w":          "failed to read CA certificate: %w",
+	"CA证书文件中没有有效的PEM证书: %s": "no valid PEM certificate in CA certificate file: %s",
+	"加载客户端证书失败: %w":         "failed to load client certificate: %w",
+	// schedule/schedule.go
+	"cron表达式应包含5个字段（分 时 日 月 周）: %s": "cron expression must have 5 fields (minute hour day month weekday): %s",
+	"cron表达式 %s 无效: %w":             "invalid cron expression %s: %w",
+	"%s字段的步长无效: %s":                 "invalid step in %s field: %s",
+	"%s字段的值无效: %s":                  "invalid value in %s field: %s",
+	"%s字段超出范围 %d-%d: %s":            "%s field out of range %d-%d: %s",
+	"分钟":                            "minute",
+	"小时":                            "hour",
+	"日期":                            "day",
+	"月份":                            "month",
+	"星期":                            "weekday",
+	// state/state.go
+	"无法将状态文件 %s 改名为 %s: %v": "cannot rename state file %s to %s: %v",
+	"状态文件 %s 已改名为 %s":       "State file %s renamed to %s",
+	// statestore/bolt.go
+	"状态数据库 %s 正被其他进程使用":        "state database %s is in use by another process",
+	"无法打开状态数据库 %s: %w":         "cannot open state database %s: %w",
+	"导入状态文件 %s 失败: %w":         "failed to import state file %s: %w",
+	"已将状态文件 %s 中的 %d 个条目导入 %s": "imported %[2]d entries from state file %[1]s into %[3]s",
+	// statestore/json.go
+	"读取未压缩的状态文件 %s，之后保存到 %s": "read uncompressed state file %s, saving to %s from now on",
+	// statestore/lock.go
+	"无法创建锁文件 %s: %w":  "cannot create lock file %s: %w",
+	"无法锁定状态文件 %s: %w": "cannot lock state file %s: %w",
+	"另一个运行（进程 %s）正持有状态文件 %s 的锁，请等它结束后再运行": "another run (process %s) holds the lock on state file %s, wait for it to finish",
+	"另一个运行正持有状态文件 %s 的锁，请等它结束后再运行":        "another run holds the lock on state file %s, wait for it to finish",
+	// statestore/store.go
+	"无效的状态存储后端: %s（可选 %s）": "invalid state backend: %s (valid: %s)",
+	// storage/local.go
+	"无效的对象键: %s":  "invalid object key: %s",
+	"分片上传不存在: %s": "multipart upload not found: %s",
+	// storage/storage.go
+	"对象不存在": "object does not exist",
+	// storage/webdav.go
+	"无效的WebDAV地址: %s":       "invalid WebDAV URL: %s",
+	"WebDAV请求 %s %s 失败: %s": "WebDAV request %s %s failed: %s",
+	"解析WebDAV响应失败: %w":      "failed to parse WebDAV response: %w",
+	"无效的读取位置: %d":           "invalid read offset: %d",
+	// upload/checksum.go
+	"不支持的校验算法: %s（可选值: CRC32, CRC32C, SHA1, SHA256）": "unsupported checksum algorithm: %s (allowed: CRC32, CRC32C, SHA1, SHA256)",
+	// upload/compress.go
+	"压缩 %s 失败: %w":          "failed to compress %s: %w",
+	"压缩: %s（%s -> %s）":      "Compress: %s (%s -> %s)",
+	"计算校验值失败: %w":           "failed to compute checksum: %w",
+	"校验时获取对象信息失败: %w":       "failed to get object info for verification: %w",
+	"压缩后大小不一致（本地 %d，远程 %d）": "compressed size mismatch (local %d, remote %d)",
+	// upload/conflict.go
+	"不支持的冲突处理方式: %s（可选值: warn, skip）":        "unsupported conflict mode: %s (allowed: warn, skip)",
+	"检查远程对象失败: %w":                           "failed to check remote object: %w",
+	"远程对象比本地文件新，仍将覆盖: %s（远程 %s，本地 %s）":       "remote object is newer than the local file, overwriting anyway: %s (remote %s, local %s)",
+	"跳过 %d 个远程版本比本地新的文件（使用 --force 强制覆盖）:%s": "Skipped %d file(s) whose remote version is newer than local (use --force to overwrite):%s",
+	"远程对象比本地文件新":                             "remote object is newer than the local file",
+	// upload/dedupe.go
+	"计算 %s 的MD5失败: %w":         "failed to compute MD5 of %s: %w",
+	"%d 个文件与已有对象内容相同，将使用服务端复制": "%d file(s) have the same content as existing objects and will use server-side copy",
+	"复制: %s -> %s": "Copy: %s -> %s",
+	"复制来源 %s 内容已变化，改为直接上传: %s": "Content of copy source %s has changed, uploading directly instead: %s",
+	// upload/journal.go
+	"继续 %s 开始的中断运行：已完成 %d 个文件，剩余 %d 个": "Resuming interrupted run started %s: %d files done, %d remaining",
+	"放弃中断的分片上传: %s (%s)":               "aborting interrupted multipart upload: %s (%s)",
+	"放弃分片上传 %s 失败: %v":                 "failed to abort multipart upload %s: %v",
+	// upload/locked.go
+	"文件被占用，从卷影副本读取: %s":    "File is locked, reading from shadow copy: %s",
+	"正在为 %s 所在的卷创建卷影副本...": "Creating a shadow copy of the volume containing %s...",
+	"文件被其他进程占用":            "file is locked by another process",
+	// upload/locked_other.go
+	"卷影副本仅在Windows上可用": "shadow copies are only available on Windows",
+	// upload/locked_windows.go
+	"无法确定 %s 所在的卷":        "cannot determine the volume containing %s",
+	"创建卷影副本失败: %v: %s":    "failed to create shadow copy: %v: %s",
+	"创建卷影副本失败: 无法解析输出 %q": "failed to create shadow copy: cannot parse output %q",
+	"%s 不在卷 %s 上":         "%s is not on volume %s",
+	"删除卷影副本失败: %v: %s":    "failed to delete shadow copy: %v: %s",
+	// upload/pack.go
+	"上传打包对象 %s 失败: %w":   "failed to upload pack object %s: %w",
+	"上传打包对象: %s（%d 个文件）": "Upload pack object: %s (%d file(s))",
+	"上传打包索引失败: %w":       "failed to upload pack index: %w",
+	// upload/put.go
+	"确保存储桶存在失败: %w":            "failed to ensure bucket exists: %w",
+	"%s 是目录，请使用 upload 命令上传目录": "%s is a directory, use the upload command to upload directories",
+	"上传: %s -> %s/%s":          "Upload: %s -> %s/%s",
+	"已上传 %s 到 %s/%s":           "Uploaded %s to %s/%s",
+	// upload/retry.go
+	"上传 %s 失败（第 %d/%d 次）: %v，%s 后重试": "Upload of %s failed (attempt %d/%d): %v, retrying in %s",
+	// upload/stable.go
+	"%d 个文件最近被修改，等待 %s 检查是否仍在写入...": "%d file(s) were modified recently, waiting %s to check whether they are still being written...",
+	"推迟 %d 个正在写入的文件，将在下次上传时处理:%s":   "Deferred %d file(s) still being written, they will be handled by the next upload:%s",
+	"文件正在写入": "file is being written",
+	// upload/upload.go
+	"加载上传状态失败: %w":                 "failed to load upload state: %w",
+	"输入目录不存在: %s":                  "input directory does not exist: %s",
+	"扫描本地文件失败: %w":                 "failed to scan local files: %w",
+	"发现 %d 个文件":                    "Found %d file(s)",
+	"需要上传 %d 个文件":                  "%d file(s) to upload",
+	"没有需要上传的文件":                    "Nothing to upload",
+	"查找重复文件失败: %w":                 "failed to find duplicate files: %w",
+	"上传文件失败: %w":                   "failed to upload files: %w",
+	"复制文件失败: %w":                   "failed to copy files: %w",
+	"上传打包对象失败: %w":                 "failed to upload pack objects: %w",
+	"保存上传状态失败: %w":                 "failed to save upload state: %w",
+	"存储桶 %s 不存在，正在创建...":           "Bucket %s does not exist, creating...",
+	"创建存储桶失败: %w":                  "failed to create bucket: %w",
+	"存储桶 %s 创建成功\n":                "Bucket %s created\n",
+	"检查存储桶失败: %w":                  "failed to check bucket: %w",
+	"跳过超过大小限制（%s）的文件: %s (%s)":     "skipping file larger than the size limit (%s): %s (%s)",
+	"跳过 %d 个超过大小限制的文件（共 %s）:%s":    "Skipped %d file(s) larger than the size limit (%s in total):%s",
+	"跳过 %d 个被其他进程占用的文件:%s":         "Skipped %d file(s) locked by other processes:%s",
+	"跳过被占用的文件 %s: %v":              "skipping locked file %s: %v",
+	"上传 %s 失败: %w":                 "failed to upload %s: %w",
+	"上传: %s -> %s":                 "Upload: %s -> %s",
+	"创建目录标记失败: %w":                 "failed to create directory marker: %w",
+	"language 无效: %s（可选值: zh, en）": "invalid language: %s (allowed: zh, en)",
+	"存储桶 %s 创建成功":                  "Bucket %s created",
+	"已保存中断前上传完成的 %d 个文件的状态":        "saved state for %d files uploaded before the interruption",
+	"保存上传状态失败: %v":                 "failed to save upload state: %v",
+	"%d 个本地文件已删除，已在状态中记录":          "%d local file(s) were deleted, recorded in the state",
+	"已从状态中清理 %d 个已删除文件的记录":         "Pruned %d deleted file record(s) from the state",
+	"内容未变化，只更新修改时间: %s":            "Content unchanged, updating modification time only: %s",
+	// upload/verify.go
+	"上传校验失败 %s: %s":           "upload verification failed %s: %s",
+	"大小不一致（本地 %d，远程 %d）":      "size mismatch (local %d, remote %d)",
+	"%s校验值不一致（本地 %s，远程 %s）":   "%s checksum mismatch (local %s, remote %s)",
+	"ETag不一致（本地MD5 %s，远程 %s）": "ETag mismatch (local MD5 %s, remote %s)",
+	// 其他
+	"配置中没有桶 %s（可选值: %v）": "bucket %s is not configured (available: %v)",
+	"错误: %v":     "Error: %v",
+	"上传":         "Upload",
+	"双向同步":       "Sync",
+	"备份":         "Backup",
+	"%s: 失败: %s": "%s: failed: %s",
+	"%s: 完成":     "%s: done",
+	"%s: 成功 %d 个桶，失败 %d 个桶，传输 %d 个文件（%s），用时 %s": "%s: %d bucket(s) succeeded, %d failed, %d file(s) transferred (%s), took %s",
+	"桶 %s 失败: %s\n":        "Bucket %s failed: %s\n",
+	"不支持的语言: %s（可选 %s、%s）": "unsupported language: %s (choose %s or %s)",
+}
@@ -0,0 +1,112 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// 支持的输出语言
+const (
+	Chinese = "zh" // 中文（默认）
+	English = "en" // 英文
+)
+
+// catalogs 各语言的消息目录，以中文原文为键，缺少的消息使用中文原文
+var catalogs = map[string]map[string]string{
+	English: english,
+}
+
+var (
+	current = Chinese
+	mutex   sync.RWMutex
+)
+
+// Normalize 将语言名或区域设置（如 en_US.UTF-8、zh-CN）转换为支持的语言，无法识别时返回空字符串
+func Normalize(lang string) string {
+	lang = strings.ToLower(lang)
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	switch lang {
+	case Chinese, "chinese":
+		return Chinese
+	case English, "english":
+		return English
+	}
+	return ""
+}
+
+// SetLanguage 设置输出语言
+func SetLanguage(lang string) error {
+	normalized := Normalize(lang)
+	if normalized == "" {
+		return Errorf("不支持的语言: %s（可选 %s、%s）", lang, Chinese, English)
+	}
+	mutex.Lock()
+	current = normalized
+	mutex.Unlock()
+	return nil
+}
+
+// Language 返回当前输出语言
+func Language() string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current
+}
+
+// Detect 按 LC_ALL、LC_MESSAGES、LANG 的顺序从环境变量推断语言，无法识别时返回空字符串
+func Detect() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			// 按POSIX规则，第一个非空的变量生效
+			return Normalize(value)
+		}
+	}
+	return ""
+}
+
+// T 返回消息在当前语言下的文本，目录中没有时返回原文
+func T(message string) string {
+	catalog := catalogs[Language()]
+	if translated, ok := catalog[message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Printf 翻译格式字符串后输出到标准输出
+func Printf(format string, args ...any) {
+	fmt.Printf(T(format), args...)
+}
+
+// Println 翻译消息后输出到标准输出
+func Println(message string) {
+	fmt.Println(T(message))
+}
+
+// Sprintf 翻译格式字符串后格式化
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Errorf 翻译格式字符串后创建错误，支持 %w
+func Errorf(format string, args ...any) error {
+	return fmt.Errorf(T(format), args...)
+}
+
+// messageError 输出时才翻译的错误，创建时输出语言可能还没有确定
+type messageError struct {
+	message string
+}
+
+func (e *messageError) Error() string {
+	return T(e.message)
+}
+
+// New 创建按输出时的语言翻译的错误，用于包级别的哨兵错误
+func New(message string) error {
+	return &messageError{message: message}
+}
@@ -0,0 +1,228 @@
+package journal
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"objectsync/internal/i18n"
+)
+
+// MaxAge 日志的最长有效期，更早开始的运行不再继续：桶和本地文件可能已经变化很多，
+// 服务端也可能已经清理了未完成的分片上传
+const MaxAge = 7 * 24 * time.Hour
+
+// Header 日志的第一行，记录运行开始时确定的传输计划
+type Header struct {
+	Bucket  string          `json:"bucket"`
+	Prefix  string          `json:"prefix"`
+	Options string          `json:"options,omitempty"` // 影响传输计划的过滤条件和选项的哈希，见 Hash
+	Started time.Time       `json:"started"`
+	Items   json.RawMessage `json:"items,omitempty"` // 调用方定义的传输列表
+}
+
+// record 日志中追加的一行：完成一个键，或开始、结束一个分片上传
+type record struct {
+	Done      string          `json:"done,omitempty"`
+	Elapsed   time.Duration   `json:"elapsed,omitempty"` // 到此为止累计的运行用时，不含中断期间
+	State     json.RawMessage `json:"state,omitempty"`
+	Multipart string          `json:"multipart,omitempty"` // 分片上传的对象键
+	UploadID  string          `json:"upload_id,omitempty"` // 为空表示该分片上传已经完成或放弃
+}
+
+// Unfinished 上次没有正常结束的运行
+type Unfinished struct {
+	Header
+	Done      map[string]json.RawMessage // 已完成的键及完成时记录的状态
+	Multipart map[string]string          // 中断时没有结束的分片上传，对象键到上传ID
+	Elapsed   time.Duration              // 中断前累计的运行用时，继续后进度的速度和剩余时间按整个运行计算
+}
+
+// Hash 返回过滤条件和选项的哈希，写入日志头，继续时与本次运行的比较
+func Hash(options any) string {
+	data, err := json.Marshal(options)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Check 检查日志能否用于继续本次运行，桶、前缀或选项不同以及超过 MaxAge 时返回原因
+func (u *Unfinished) Check(bucket, prefix, options string) error {
+	if u.Bucket != bucket || u.Prefix != prefix {
+		return i18n.Errorf("运行日志属于其他桶或前缀 %s/%s", u.Bucket, u.Prefix)
+	}
+	if u.Options != options {
+		return i18n.Errorf("过滤条件或选项与中断的运行不同")
+	}
+	if age := time.Since(u.Started); age > MaxAge {
+		return i18n.Errorf("中断的运行开始于 %s，已超过 %d 天", u.Started.Format("2006-01-02 15:04:05"), int(MaxAge/(24*time.Hour)))
+	}
+	return nil
+}
+
+// Journal 进行中运行的日志：第一行是传输计划，之后每完成一个键追加一行。
+// 运行正常结束时删除，进程崩溃或被中断时保留，下次运行据此跳过已完成的部分
+type Journal struct {
+	path    string
+	file    *os.File
+	opened  time.Time
+	elapsed time.Duration // 之前的运行累计的用时
+	mutex   sync.Mutex
+}
+
+// Path 返回状态文件对应的日志路径
+func Path(stateFile string) string {
+	return stateFile + ".journal"
+}
+
+// Create 创建日志并写入传输计划，覆盖已有的日志。options为 Hash 的结果
+func Create(path, bucket, prefix, options string, items any) (*Journal, error) {
+	header := Header{Bucket: bucket, Prefix: prefix, Options: options, Started: time.Now()}
+	if items != nil {
+		data, err := json.Marshal(items)
+		if err != nil {
+			return nil, err
+		}
+		header.Items = data
+	}
+	line, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先写临时文件再替换，避免留下只有半行计划的日志
+	temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(temp.Name())
+
+	_, err = temp.Write(append(line, '\n'))
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return nil, err
+	}
+	return Open(path, 0)
+}
+
+// Open 打开已有的日志继续追加，用于继续上次中断的运行。
+// elapsed 为中断前累计的用时（Unfinished.Elapsed），之后记录的用时在此基础上累加
+func Open(path string, elapsed time.Duration) (*Journal, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	// 中断时写了半行的话先换行，后续记录才能正常解析
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			file.Write([]byte{'\n'})
+		}
+	}
+	return &Journal{path: path, file: file, opened: time.Now(), elapsed: elapsed}, nil
+}
+
+// Done 记录一个键已经传输完成，state为完成时需要恢复的状态（可以为nil）
+func (j *Journal) Done(key string, state any) error {
+	entry := record{Done: key, Elapsed: j.elapsed + time.Since(j.opened)}
+	if state != nil {
+		data, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+		entry.State = data
+	}
+	return j.write(entry)
+}
+
+// Multipart 记录对象key开始了分片上传，中断后下次运行可以放弃遗留的分片。
+// 分片上传完成或放弃后以空的uploadID再调用一次
+func (j *Journal) Multipart(key, uploadID string) error {
+	return j.write(record{Multipart: key, UploadID: uploadID})
+}
+
+// write 追加一行记录
+func (j *Journal) write(entry record) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	_, err = j.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close 关闭日志并保留，供下次运行继续
+func (j *Journal) Close() error {
+	return j.file.Close()
+}
+
+// Finish 运行正常结束，关闭并删除日志
+func (j *Journal) Finish() error {
+	j.file.Close()
+	if err := os.Remove(j.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Load 读取上次没有正常结束的运行，日志不存在时返回nil。
+// 中断时可能只写了半行，无法解析的行被忽略
+func Load(path string) (*Unfinished, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		// 计划没有完整写入，视为没有可继续的运行
+		return nil, nil
+	}
+
+	unfinished := &Unfinished{Done: make(map[string]json.RawMessage), Multipart: make(map[string]string)}
+	if err := json.Unmarshal(line, &unfinished.Header); err != nil {
+		return nil, err
+	}
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			break
+		}
+		var entry record
+		if json.Unmarshal(line, &entry) != nil {
+			continue
+		}
+		switch {
+		case entry.Done != "":
+			unfinished.Done[entry.Done] = entry.State
+			unfinished.Elapsed = max(unfinished.Elapsed, entry.Elapsed)
+		case entry.Multipart != "" && entry.UploadID != "":
+			unfinished.Multipart[entry.Multipart] = entry.UploadID
+		case entry.Multipart != "":
+			delete(unfinished.Multipart, entry.Multipart)
+		}
+	}
+	return unfinished, nil
+}
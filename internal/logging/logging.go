@@ -0,0 +1,198 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"objectsync/internal/i18n"
+)
+
+// 日志格式
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Options 日志设置
+type Options struct {
+	Level  slog.Level
+	Format string // text 或 json
+	Time   bool   // 文本格式在每行开头显示时间（JSON格式总是包含时间）
+}
+
+// handler 当前使用的日志处理器，Setup 之前输出info及以上级别的文本日志
+var handler atomic.Pointer[slog.Handler]
+
+// interrupt 输出日志前调用，用于清除终端中的进度条
+var interrupt atomic.Pointer[func()]
+
+func init() {
+	var h slog.Handler = newTextHandler(stdout{}, slog.LevelInfo, false)
+	handler.Store(&h)
+}
+
+// Setup 设置日志级别和格式，之后所有模块的日志按新的设置输出
+func Setup(options Options) error {
+	var h slog.Handler
+	switch options.Format {
+	case "", FormatText:
+		h = newTextHandler(stdout{}, options.Level, options.Time)
+	case FormatJSON:
+		h = slog.NewJSONHandler(stdout{}, &slog.HandlerOptions{Level: options.Level})
+	default:
+		return i18n.Errorf("无效的日志格式: %s（可选 text、json）", options.Format)
+	}
+	handler.Store(&h)
+	return nil
+}
+
+// ParseLevel 解析日志级别名称：debug、info、warn、error
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return slog.LevelInfo, i18n.Errorf("无效的日志级别: %s（可选 debug、info、warn、error）", name)
+}
+
+// SetInterrupt 设置输出日志前调用的函数，用于在显示进度条时先清除进度条，避免日志接在进度条后面
+func SetInterrupt(fn func()) {
+	interrupt.Store(&fn)
+}
+
+// stdout 写入当前的标准输出。--quiet、--output json 和菜单界面会替换 os.Stdout，日志跟随替换
+type stdout struct{}
+
+func (stdout) Write(p []byte) (int, error) {
+	return os.Stdout.Write(p)
+}
+
+// Logger 模块的日志记录器，消息格式按当前语言翻译后输出
+type Logger struct {
+	module string
+}
+
+// New 创建模块的日志记录器，模块名显示在每行日志的开头（JSON格式中为 module 字段）
+func New(module string) *Logger {
+	return &Logger{module: module}
+}
+
+// Enabled 指定级别的日志是否会输出，用于跳过代价较高的格式化
+func (l *Logger) Enabled(level slog.Level) bool {
+	return (*handler.Load()).Enabled(context.Background(), level)
+}
+
+// Debugf 输出调试日志，如每个文件的传输
+func (l *Logger) Debugf(format string, args ...any) {
+	l.log(slog.LevelDebug, format, args...)
+}
+
+// Infof 输出一般信息
+func (l *Logger) Infof(format string, args ...any) {
+	l.log(slog.LevelInfo, format, args...)
+}
+
+// Warnf 输出不影响继续运行的问题
+func (l *Logger) Warnf(format string, args ...any) {
+	l.log(slog.LevelWarn, format, args...)
+}
+
+// Errorf 输出错误
+func (l *Logger) Errorf(format string, args ...any) {
+	l.log(slog.LevelError, format, args...)
+}
+
+func (l *Logger) log(level slog.Level, format string, args ...any) {
+	h := *handler.Load()
+	ctx := context.Background()
+	if !h.Enabled(ctx, level) {
+		return
+	}
+	record := slog.NewRecord(time.Now(), level, i18n.Sprintf(format, args...), 0)
+	record.AddAttrs(slog.String("module", l.module))
+	if fn := interrupt.Load(); fn != nil {
+		(*fn)()
+	}
+	h.Handle(ctx, record)
+}
+
+// textHandler 输出便于阅读的文本日志：[时间] [模块] 级别: 消息 key=value
+type textHandler struct {
+	out   io.Writer
+	level slog.Level
+	time  bool
+	attrs []slog.Attr
+}
+
+func newTextHandler(out io.Writer, level slog.Level, time bool) *textHandler {
+	return &textHandler{out: out, level: level, time: time}
+}
+
+func (h *textHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *textHandler) Handle(_ context.Context, record slog.Record) error {
+	var b strings.Builder
+	if h.time {
+		fmt.Fprintf(&b, "[%s] ", record.Time.Format("2006-01-02 15:04:05"))
+	}
+
+	var extra []slog.Attr
+	module := ""
+	collect := func(attr slog.Attr) bool {
+		if attr.Key == "module" {
+			module = attr.Value.String()
+		} else {
+			extra = append(extra, attr)
+		}
+		return true
+	}
+	for _, attr := range h.attrs {
+		collect(attr)
+	}
+	record.Attrs(collect)
+
+	if module != "" {
+		fmt.Fprintf(&b, "[%s] ", module)
+	}
+	switch {
+	case record.Level >= slog.LevelError:
+		b.WriteString(i18n.T("错误: "))
+	case record.Level >= slog.LevelWarn:
+		b.WriteString(i18n.T("警告: "))
+	case record.Level < slog.LevelInfo:
+		b.WriteString(i18n.T("调试: "))
+	}
+	b.WriteString(record.Message)
+	for _, attr := range extra {
+		fmt.Fprintf(&b, " %s=%v", attr.Key, attr.Value)
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(h.out, b.String())
+	return err
+}
+
+func (h *textHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr(nil), h.attrs...), attrs...)
+	return &clone
+}
+
+// WithGroup 文本格式不区分分组，属性直接显示
+func (h *textHandler) WithGroup(string) slog.Handler {
+	return h
+}
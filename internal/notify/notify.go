@@ -0,0 +1,208 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"objectsync/internal/i18n"
+	"objectsync/internal/progress"
+)
+
+// 支持的通知渠道
+const (
+	Slack    = "slack"
+	DingTalk = "dingtalk"
+	WeCom    = "wecom"
+	Telegram = "telegram"
+)
+
+// 发送时机
+const (
+	OnAlways  = "always"  // 每次运行结束都发送（默认）
+	OnFailure = "failure" // 只在有桶失败时发送
+)
+
+// Types 支持的通知渠道列表
+var Types = []string{Slack, DingTalk, WeCom, Telegram}
+
+// telegramAPI Telegram Bot API地址
+const telegramAPI = "https://api.telegram.org"
+
+// sendTimeout 单次通知请求的超时，避免通知服务异常拖住同步任务
+const sendTimeout = 15 * time.Second
+
+// Options 单个通知渠道的配置
+type Options struct {
+	Type       string
+	WebhookURL string // slack、dingtalk、wecom的机器人地址
+	Secret     string // 钉钉机器人的加签密钥，可选
+	BotToken   string // Telegram机器人令牌
+	ChatID     string // Telegram会话ID
+	On         string // 发送时机，为空时等同于always
+}
+
+// BucketResult 单个桶的运行结果
+type BucketResult struct {
+	Bucket string
+	Error  string // 为空表示成功
+	Files  int64
+	Bytes  int64
+	Failed int64 // 传输失败的对象数
+}
+
+// Summary 一次运行的结果
+type Summary struct {
+	Command  string            // 如 backup、upload、run
+	Labels   map[string]string // 运行标签，用于区分不同流水线触发的运行
+	Buckets  []BucketResult
+	Duration time.Duration
+}
+
+// Failed 返回失败的桶数量
+func (s Summary) Failed() int {
+	failed := 0
+	for _, bucket := range s.Buckets {
+		if bucket.Error != "" {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Message 生成简短的运行总结，包含成功/失败图标、统计和失败的桶
+func (s Summary) Message() string {
+	var stats progress.Stats
+	for _, bucket := range s.Buckets {
+		stats.Files += bucket.Files
+		stats.Bytes += bucket.Bytes
+	}
+
+	failed := s.Failed()
+	icon := "✅"
+	if failed > 0 {
+		icon = "❌"
+	}
+	host, _ := os.Hostname()
+
+	var b strings.Builder
+	b.WriteString(i18n.Sprintf("%s ObjectSync %s（%s）\n", icon, s.Command, host))
+	if len(s.Labels) > 0 {
+		b.WriteString(i18n.Sprintf("标签: %s\n", FormatLabels(s.Labels)))
+	}
+	b.WriteString(i18n.Sprintf("成功 %d 个桶，失败 %d 个桶，传输 %d 个文件（%s），用时 %s",
+		len(s.Buckets)-failed, failed, stats.Files, progress.FormatSize(stats.Bytes), s.Duration.Round(time.Second)))
+	for _, bucket := range s.Buckets {
+		if bucket.Error == "" {
+			continue
+		}
+		b.WriteString(fmt.Sprintf("\n• %s: %s", bucket.Bucket, bucket.Error))
+		if bucket.Failed > 0 {
+			b.WriteString(i18n.Sprintf("（%d 个对象失败）", bucket.Failed))
+		}
+	}
+	return b.String()
+}
+
+// FormatLabels 按键排序输出 key=value 形式的标签，用逗号分隔
+func FormatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+labels[key])
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// Send 按渠道配置发送运行总结，on为failure且没有失败时不发送
+func Send(options Options, summary Summary) error {
+	if options.On == OnFailure && summary.Failed() == 0 {
+		return nil
+	}
+
+	text := summary.Message()
+	target := options.WebhookURL
+	var payload any
+	switch options.Type {
+	case Slack:
+		payload = map[string]string{"text": text}
+	case DingTalk:
+		payload = map[string]any{"msgtype": "text", "text": map[string]string{"content": text}}
+		if options.Secret != "" {
+			target = signDingTalk(target, options.Secret, time.Now())
+		}
+	case WeCom:
+		payload = map[string]any{"msgtype": "text", "text": map[string]string{"content": text}}
+	case Telegram:
+		target = fmt.Sprintf("%s/bot%s/sendMessage", telegramAPI, options.BotToken)
+		payload = map[string]string{"chat_id": options.ChatID, "text": text}
+	default:
+		return i18n.Errorf("不支持的通知类型: %s", options.Type)
+	}
+
+	return post(target, payload)
+}
+
+// post 以JSON发送请求并检查响应，钉钉和企业微信在HTTP 200的响应体中用errcode表示失败
+func post(target string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: sendTimeout}
+	resp, err := client.Post(target, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// 地址中带有机器人令牌或签名，只返回底层错误
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return urlErr.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode/100 != 2 {
+		return i18n.Errorf("通知服务返回 %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	var result struct {
+		ErrCode *int   `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if json.Unmarshal(data, &result) == nil && result.ErrCode != nil && *result.ErrCode != 0 {
+		return i18n.Errorf("通知服务返回错误 %d: %s", *result.ErrCode, result.ErrMsg)
+	}
+	return nil
+}
+
+// signDingTalk 按钉钉加签规则在地址上追加timestamp和sign参数
+func signDingTalk(webhook, secret string, now time.Time) string {
+	timestamp := fmt.Sprintf("%d", now.UnixMilli())
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+	sign := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	separator := "?"
+	if strings.Contains(webhook, "?") {
+		separator = "&"
+	}
+	return webhook + separator + "timestamp=" + timestamp + "&sign=" + url.QueryEscape(sign)
+}
@@ -0,0 +1,184 @@
+package pack
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"objectsync/internal/fileattr"
+	"objectsync/internal/i18n"
+)
+
+// 打包对象的存放位置和命名
+const (
+	Prefix      = ".objectsync/packs/"
+	PackSuffix  = ".tar"
+	IndexSuffix = ".index.json"
+)
+
+// Entry 打包对象中的单个文件
+type Entry struct {
+	Key     string      `json:"key"`
+	Size    int64       `json:"size"`
+	ModTime time.Time   `json:"mod_time"`
+	Mode    os.FileMode `json:"mode"`
+}
+
+// Index 打包对象的索引，与打包对象一同上传
+type Index struct {
+	Pack    string    `json:"pack"`
+	Created time.Time `json:"created"`
+	Entries []Entry   `json:"entries"`
+}
+
+// File 待打包的本地文件，Body为已打开的文件内容
+type File struct {
+	Key  string
+	Info os.FileInfo
+	Body io.Reader
+}
+
+// IsPack 判断对象键是否为打包对象
+func IsPack(key string) bool {
+	return strings.HasPrefix(key, Prefix) && strings.HasSuffix(key, PackSuffix)
+}
+
+// IsIndex 判断对象键是否为打包索引
+func IsIndex(key string) bool {
+	return strings.HasPrefix(key, Prefix) && strings.HasSuffix(key, IndexSuffix)
+}
+
+// IsInternal 判断对象键是否属于打包数据（打包对象或索引），这些对象不应作为普通文件下载
+func IsInternal(key string) bool {
+	return strings.HasPrefix(key, Prefix)
+}
+
+// NewKey 生成新的打包对象键，按时间排序即为写入顺序
+func NewKey(seq int) string {
+	return fmt.Sprintf("%s%d-%04d%s", Prefix, time.Now().UnixNano(), seq, PackSuffix)
+}
+
+// IndexKey 返回打包对象对应的索引键
+func IndexKey(packKey string) string {
+	return strings.TrimSuffix(packKey, PackSuffix) + IndexSuffix
+}
+
+// Write 将文件写入tar格式的打包数据，返回索引
+func Write(w io.Writer, packKey string, files []File) (*Index, error) {
+	tw := tar.NewWriter(w)
+	index := &Index{Pack: packKey, Created: time.Now()}
+
+	for _, file := range files {
+		info := file.Info
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return nil, err
+		}
+		header.Name = file.Key
+		header.Format = tar.FormatPAX
+
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, err
+		}
+
+		// 按头部记录的大小写入，文件在打包过程中变化时避免tar格式错误
+		if _, err := io.CopyN(tw, file.Body, header.Size); err != nil {
+			return nil, i18n.Errorf("打包 %s 失败: %w", file.Key, err)
+		}
+
+		index.Entries = append(index.Entries, Entry{
+			Key:     file.Key,
+			Size:    header.Size,
+			ModTime: info.ModTime(),
+			Mode:    info.Mode(),
+		})
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// Extract 将打包数据解压到目录，skip返回true的条目会被跳过，返回已解压的条目。
+// prefix非空时只解压该前缀下的条目，本地路径去掉前缀
+func Extract(r io.Reader, dir, prefix string, skip func(key string) bool) ([]Entry, error) {
+	tr := tar.NewReader(r)
+	var entries []Entry
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return entries, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		key := header.Name
+		if !strings.HasPrefix(key, prefix) || (skip != nil && skip(key)) {
+			continue
+		}
+
+		localPath, err := safeJoin(dir, strings.TrimPrefix(key, prefix))
+		if err != nil {
+			return entries, err
+		}
+		if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+			return entries, err
+		}
+
+		file, err := os.Create(localPath)
+		if err != nil {
+			return entries, err
+		}
+		_, err = io.Copy(file, tr)
+		file.Close()
+		if err != nil {
+			return entries, err
+		}
+
+		attrs := fileattr.Attrs{
+			ModTime: header.ModTime,
+			Mode:    header.FileInfo().Mode(),
+			HasMode: true,
+			UID:     -1,
+			GID:     -1,
+		}
+		// 属性设置失败不影响解压结果
+		_ = fileattr.Apply(localPath, attrs, header.ModTime)
+
+		entries = append(entries, Entry{
+			Key:     key,
+			Size:    header.Size,
+			ModTime: header.ModTime,
+			Mode:    attrs.Mode,
+		})
+	}
+
+	return entries, nil
+}
+
+// EncodeIndex 将索引编码为JSON
+func EncodeIndex(index *Index) ([]byte, error) {
+	return json.MarshalIndent(index, "", "  ")
+}
+
+// safeJoin 拼接本地路径，拒绝跳出目标目录的条目
+func safeJoin(dir, key string) (string, error) {
+	localPath := filepath.Join(dir, filepath.FromSlash(path.Clean("/"+key)))
+	rel, err := filepath.Rel(dir, localPath)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", i18n.Errorf("打包条目路径无效: %s", key)
+	}
+	return localPath, nil
+}
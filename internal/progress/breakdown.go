@@ -0,0 +1,170 @@
+package progress
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"objectsync/internal/i18n"
+)
+
+// slowestCount 最终统计中列出的最慢对象数
+const slowestCount = 10
+
+// sizeClasses 按对象大小分组：每组的上限（不含）和名称，最后一组没有上限
+var sizeClasses = [...]struct {
+	limit int64
+	label string
+}{
+	{1 << 20, "< 1 MB"},
+	{16 << 20, "1-16 MB"},
+	{128 << 20, "16-128 MB"},
+	{1 << 30, "128 MB-1 GB"},
+	{0, ">= 1 GB"},
+}
+
+// latencyBuckets 用时直方图的区间数，从1毫秒起每个区间的上限增大约10%，覆盖到几十年
+const latencyBuckets = 256
+
+// latencyGrowth 直方图相邻区间上限的比例，百分位数的相对误差不超过该比例
+const latencyGrowth = 1.1
+
+// latencyHistogram 单个对象用时的直方图，用固定的区间计数代替保存每个用时，对象数很多时内存不变
+type latencyHistogram struct {
+	counts [latencyBuckets]int64
+	total  int64
+	max    time.Duration
+}
+
+// add 记录一个用时
+func (h *latencyHistogram) add(d time.Duration) {
+	i := 0
+	if d > time.Millisecond {
+		i = min(int(math.Log(float64(d)/float64(time.Millisecond))/math.Log(latencyGrowth))+1, latencyBuckets-1)
+	}
+	h.counts[i]++
+	h.total++
+	h.max = max(h.max, d)
+}
+
+// percentile 返回用时的近似百分位数，取所在区间的上限，不超过最长用时
+func (h *latencyHistogram) percentile(p int) time.Duration {
+	rank := (h.total-1)*int64(p)/100 + 1
+	var seen int64
+	for i, count := range h.counts {
+		seen += count
+		if seen >= rank {
+			limit := time.Duration(float64(time.Millisecond) * math.Pow(latencyGrowth, float64(i)))
+			return min(limit, h.max)
+		}
+	}
+	return h.max
+}
+
+// transfer 一个对象的传输用时
+type transfer struct {
+	name     string
+	size     int64
+	duration time.Duration
+}
+
+// breakdown 已完成传输的分类统计，用于在最终统计中帮助调整工作协程数和分片大小。
+// 用时只保存直方图和最慢的几个对象，对象数很多时也不占用太多内存
+type breakdown struct {
+	latency    latencyHistogram
+	slowest    []transfer // 按用时从长到短，最多 slowestCount 个
+	classFiles [len(sizeClasses)]int64
+	classBytes [len(sizeClasses)]int64
+	retries    int64
+	skipped    int64
+	failed     int64
+}
+
+// add 记录一个完成传输的对象
+func (b *breakdown) add(name string, size int64, duration time.Duration) {
+	b.latency.add(duration)
+
+	class := len(sizeClasses) - 1
+	for i, c := range sizeClasses[:class] {
+		if size < c.limit {
+			class = i
+			break
+		}
+	}
+	b.classFiles[class]++
+	b.classBytes[class] += size
+
+	if len(b.slowest) == slowestCount && duration <= b.slowest[slowestCount-1].duration {
+		return
+	}
+	// 按用时从长到短排列
+	i, _ := slices.BinarySearchFunc(b.slowest, duration, func(t transfer, d time.Duration) int {
+		return cmp.Compare(d, t.duration)
+	})
+	b.slowest = slices.Insert(b.slowest, i, transfer{name: name, size: size, duration: duration})
+	if len(b.slowest) > slowestCount {
+		b.slowest = b.slowest[:slowestCount]
+	}
+}
+
+// print 输出分类统计：各大小区间的文件数、单个对象用时的p50/p95、最慢的对象，以及重试、跳过和失败的次数
+func (b *breakdown) print() {
+	if b.latency.total > 0 {
+		i18n.Printf("  单个对象用时: p50 %s, p95 %s, 最长 %s\n",
+			formatLatency(b.latency.percentile(50)), formatLatency(b.latency.percentile(95)), formatLatency(b.latency.max))
+
+		i18n.Printf("  按大小:\n")
+		for i, c := range sizeClasses {
+			if b.classFiles[i] > 0 {
+				i18n.Printf("    %-12s %d 个文件（%s）\n", c.label, b.classFiles[i], FormatSize(b.classBytes[i]))
+			}
+		}
+
+		if len(b.slowest) > 1 {
+			i18n.Printf("  最慢的 %d 个对象:\n", len(b.slowest))
+			for _, t := range b.slowest {
+				speed := float64(t.size) / t.duration.Seconds()
+				fmt.Printf("    %8s  %9s  %9s/s  %s\n", formatLatency(t.duration), FormatSize(t.size), FormatSize(int64(speed)), t.name)
+			}
+		}
+	}
+	i18n.Printf("  重试: %d 次 | 跳过: %d 个文件 | 失败: %d 次\n", b.retries, b.skipped, b.failed)
+}
+
+// formatLatency 格式化单个对象的用时，秒以下显示毫秒
+func formatLatency(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", d.Seconds()), ".0") + "s"
+	}
+	return formatDuration(d)
+}
+
+// AddRetries 记录传输过程中的重试次数，包括SDK对单个请求的重试和校验失败后重新上传
+func (t *Tracker) AddRetries(n int) {
+	if n <= 0 {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.breakdown.retries += int64(n)
+}
+
+// AddSkipped 记录跳过的文件数（如超过大小限制、被占用或远程版本较新）
+func (t *Tracker) AddSkipped(n int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.breakdown.skipped += int64(n)
+}
+
+// AddRetries 记录该文件传输中的重试次数
+func (c *Counter) AddRetries(n int) {
+	c.tracker.AddRetries(n)
+}
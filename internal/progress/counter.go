@@ -0,0 +1,158 @@
+package progress
+
+import (
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// Counter 统计单个文件已传输的字节数，文件完成前就计入进度，
+// 使大文件传输过程中进度、速度和剩余时间持续更新
+type Counter struct {
+	tracker  *Tracker
+	name     string
+	size     int64
+	start    time.Time
+	count    atomic.Int64 // 已计入进度的字节数
+	lastRead atomic.Int64 // 最近一次传输数据的时间（UnixNano），用于发现停滞的传输
+	slot     int          // 在工作协程进度中占用的行，-1表示不显示
+	done     bool         // 已调用 Done，之后的 Close 不计为失败
+}
+
+// NewCounter 为一个文件的传输创建计数器，name 和 size 用于显示每个工作协程的进度，
+// 传输结束时调用 Done 或 Close
+func (t *Tracker) NewCounter(name string, size int64) *Counter {
+	now := time.Now()
+	c := &Counter{tracker: t, name: name, size: size, start: now, slot: -1}
+	c.lastRead.Store(now.UnixNano())
+
+	if display := t.display(); display.workers {
+		display.mutex.Lock()
+		c.slot = display.occupy(c)
+		display.mutex.Unlock()
+	}
+	if t.reporter != nil {
+		t.reporter.Report(FileStarted{Name: name, Size: size})
+	}
+	return c
+}
+
+// Add 记录传输的字节数
+func (c *Counter) Add(n int64) {
+	transferred := c.add(n)
+	if r := c.tracker.reporter; r != nil {
+		r.Report(FileProgress{Name: c.name, Size: c.size, Transferred: transferred})
+	}
+}
+
+func (c *Counter) add(n int64) int64 {
+	t := c.tracker
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	transferred := c.count.Add(n)
+	c.lastRead.Store(time.Now().UnixNano())
+	t.partialSize += n
+	if t.parent != nil {
+		t.parent.merge(0, 0, 0, 0, n, false)
+	} else {
+		t.update(false)
+	}
+	return transferred
+}
+
+// Done 文件传输完成，用文件大小代替已计入的字节数（压缩或重试时两者可能不同）
+func (c *Counter) Done(size int64) {
+	duration := c.finish(size)
+	if r := c.tracker.reporter; r != nil {
+		r.Report(FileDone{Name: c.name, Size: size, Duration: duration})
+	}
+}
+
+func (c *Counter) finish(size int64) time.Duration {
+	t := c.tracker
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	c.leave()
+	c.done = true
+	duration := time.Since(c.start)
+	t.breakdown.add(c.name, size, duration)
+	delete(t.failedNames, c.name)
+
+	// 在同一次更新中替换，避免进度短暂回退
+	count := c.count.Swap(0)
+	t.partialSize -= count
+	t.currentFiles++
+	t.currentSize += size
+	if t.parent != nil {
+		t.parent.merge(0, 0, 1, size, -count, t.verbose)
+	} else {
+		t.update(t.verbose)
+	}
+	return duration
+}
+
+// Close 传输失败时从进度中去掉已计入的字节数，Done 之后调用没有影响
+func (c *Counter) Close() {
+	if c.close() && c.tracker.reporter != nil {
+		c.tracker.reporter.Report(FileFailed{Name: c.name})
+	}
+}
+
+// close 返回传输是否失败（之前没有调用 Done）
+func (c *Counter) close() bool {
+	t := c.tracker
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	c.leave()
+	failed := !c.done
+	if failed {
+		c.done = true
+		t.breakdown.failed++
+		if t.failedNames == nil {
+			t.failedNames = make(map[string]bool)
+		}
+		t.failedNames[c.name] = true
+	}
+
+	count := c.count.Swap(0)
+	t.partialSize -= count
+	if t.parent != nil && count != 0 {
+		t.parent.merge(0, 0, 0, 0, -count, false)
+	}
+	return failed
+}
+
+// leave 释放在工作协程进度中占用的行，调用时持有 c.tracker 的锁
+func (c *Counter) leave() {
+	if c.slot < 0 {
+		return
+	}
+	display := c.tracker.display()
+	if display != c.tracker {
+		display.mutex.Lock()
+		defer display.mutex.Unlock()
+	}
+	display.slots[c.slot] = nil
+	c.slot = -1
+}
+
+// Reader 包装读取器，读取的字节数计入进度，关闭时关闭原读取器
+func (c *Counter) Reader(r io.ReadCloser) io.ReadCloser {
+	return &countingReader{ReadCloser: r, counter: c}
+}
+
+type countingReader struct {
+	io.ReadCloser
+	counter *Counter
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		r.counter.Add(int64(n))
+	}
+	return n, err
+}
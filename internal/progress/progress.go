@@ -2,98 +2,346 @@ package progress
 
 import (
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"objectsync/internal/i18n"
+
+	"golang.org/x/term"
 )
 
+// redrawInterval 非详细模式下刷新进度条的最短间隔，避免大量小文件时频繁输出
+const redrawInterval = 200 * time.Millisecond
+
+// logInterval 输出重定向到文件时输出进度行的间隔
+const logInterval = 30 * time.Second
+
+// barWidth 进度条图形的最大宽度，终端较窄时缩短
+const barWidth = 20
+
+// drawn 当前在终端中显示进度条的跟踪器
+var drawn atomic.Pointer[Tracker]
+
+// Stats 已完成的传输统计
+type Stats struct {
+	Files    int64
+	Bytes    int64
+	Failed   int64 // 最后一次传输失败的文件数
+	Duration time.Duration
+}
+
+// Snapshot 当前进度，包括已完成和需要传输的文件数、数据量
+type Snapshot struct {
+	Files      int64
+	Bytes      int64
+	TotalFiles int64
+	TotalBytes int64
+}
+
 // Tracker 进度跟踪器
 type Tracker struct {
 	totalFiles   int64
 	totalSize    int64
 	currentFiles int64
 	currentSize  int64
+	partialSize  int64 // 传输中的文件已传输的字节数
+	reporter     Reporter
+	resumedFiles int64 // 继续的运行在中断前已完成的文件数
+	resumedSize  int64 // 继续的运行在中断前已完成的数据量
+	breakdown    breakdown
+	failedNames  map[string]bool // 最后一次传输失败的文件，之后重试成功时移除
 	startTime    time.Time
 	verbose      bool
+	terminal     bool       // 标准输出是终端，在一行中刷新进度条，否则定期输出进度行
+	lastDraw     time.Time  // 上次输出进度的时间
+	lineWidth    int        // 上次输出的进度条宽度，用于覆盖较长的旧内容
+	parent       *Tracker   // 汇总多个桶进度的跟踪器，设置后由它输出进度
+	buckets      int        // 汇总跟踪器：桶的总数
+	bucketIndex  int        // 汇总跟踪器：当前是第几个桶
+	workers      bool       // 显示每个正在传输的文件的进度
+	slots        []*Counter // 正在传输的文件占用的行，nil表示空闲
+	drawnLines   int        // 上次输出的行数，重绘时回到第一行
+	refreshing   bool       // 定期重绘工作协程进度的协程正在运行
 	mutex        sync.Mutex
 }
 
-// New 创建新的进度跟踪器
+// New 创建新的进度跟踪器。标准输出是终端时在一行中刷新进度条，详细模式下每个文件完成时都刷新；
+// 输出重定向到文件或管道时每隔 logInterval 输出一行进度，避免在日志中写入大量回车符
 func New(verbose bool) *Tracker {
+	now := time.Now()
 	return &Tracker{
-		startTime: time.Now(),
+		startTime: now,
+		lastDraw:  now,
 		verbose:   verbose,
+		terminal:  term.IsTerminal(int(os.Stdout.Fd())),
 	}
 }
 
-// SetTotal 设置总数
-func (t *Tracker) SetTotal(files, size int64) {
+// NewTotal 创建汇总多个桶进度的跟踪器，各个桶的跟踪器通过 SetParent 计入其中。
+// 进度行显示当前是第几个桶，以及已开始的桶合计的数据量、速度和剩余时间
+// （还没有开始的桶需要传输的数据量未知，不计入剩余时间）
+func NewTotal(buckets int) *Tracker {
+	t := New(false)
+	t.buckets = buckets
+	return t
+}
+
+// StartBucket 汇总跟踪器开始下一个桶
+func (t *Tracker) StartBucket() {
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
 
-	t.totalFiles = files
-	t.totalSize = size
+	t.bucketIndex++
+}
+
+// SetParent 将进度同时计入汇总跟踪器，之后由汇总跟踪器输出进度行，需要在开始传输前调用
+func (t *Tracker) SetParent(parent *Tracker) {
+	t.parent = parent
+}
+
+// Resume 计入继续的运行在中断前已完成的文件数、数据量和用时，使进度、剩余时间和最终统计
+// 按整个运行计算，而不是从0%重新开始。需要在 SetTotal 之前调用
+func (t *Tracker) Resume(files, size int64, elapsed time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.resumedFiles = files
+	t.resumedSize = size
+	t.currentFiles += files
+	t.currentSize += size
+	t.startTime = t.startTime.Add(-elapsed)
+	if t.parent != nil {
+		// 汇总跟踪器的用时从第一个桶开始计算，只计入文件数和数据量
+		t.parent.merge(files, size, files, size, 0, false)
+	}
+}
+
+// SetTotal 设置本次需要传输的文件数和数据量，继续的运行另外加上中断前已完成的部分
+func (t *Tracker) SetTotal(files, size int64) {
+	t.mutex.Lock()
+	t.totalFiles = t.resumedFiles + files
+	t.totalSize = t.resumedSize + size
 
-	if t.verbose {
-		fmt.Printf("开始备份: %d 个文件, 总计 %s\n", files, FormatSize(size))
+	if t.verbose && t.reporter == nil {
+		i18n.Printf("开始备份: %d 个文件, 总计 %s\n", files, FormatSize(size))
 		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	}
+	switch {
+	case t.parent != nil:
+		t.parent.merge(files, size, 0, 0, 0, true)
+	case t.terminal:
+		t.printProgress()
+	}
+	planned := Planned{Files: files, Bytes: size, ResumedFiles: t.resumedFiles, ResumedBytes: t.resumedSize}
+	t.mutex.Unlock()
+
+	if t.reporter != nil {
+		t.reporter.Report(planned)
+	}
 }
 
-// AddFile 添加已下载的文件
-func (t *Tracker) AddFile(size int64) {
+// AddFile 添加没有传输过程的文件：目录标记、服务端复制和打包中的文件
+func (t *Tracker) AddFile(name string, size int64) {
+	t.mutex.Lock()
+	t.currentFiles++
+	t.currentSize += size
+
+	if t.parent != nil {
+		t.parent.merge(0, 0, 1, size, 0, t.verbose)
+	} else {
+		t.update(t.verbose)
+	}
+	t.mutex.Unlock()
+
+	if t.reporter != nil {
+		t.reporter.Report(FileDone{Name: name, Size: size})
+	}
+}
+
+// merge 汇总跟踪器计入桶的进度变化并刷新。调用方持有桶跟踪器的锁，总是先锁桶再锁汇总跟踪器
+func (t *Tracker) merge(totalFiles, totalSize, files, size, partial int64, force bool) {
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
 
-	t.currentFiles++
+	t.totalFiles += totalFiles
+	t.totalSize += totalSize
+	t.currentFiles += files
 	t.currentSize += size
+	t.partialSize += partial
+	t.update(force)
+}
 
-	if t.verbose {
+// update 按输出方式刷新进度：终端中按间隔重绘进度条（force 时立即重绘），
+// 输出重定向时每隔 logInterval 输出一行。调用时需持有锁
+func (t *Tracker) update(force bool) {
+	elapsed := time.Since(t.lastDraw)
+	switch {
+	case t.terminal && (force || elapsed >= redrawInterval):
 		t.printProgress()
+	case !t.terminal && t.reporter == nil && elapsed >= logInterval:
+		t.printLogLine()
 	}
 }
 
-// printProgress 打印进度信息
-func (t *Tracker) printProgress() {
+// Stats 返回已完成的文件数、数据量和开始以来的用时，继续的运行包括中断前完成的部分
+func (t *Tracker) Stats() Stats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return Stats{
+		Files:    t.currentFiles,
+		Bytes:    t.currentSize,
+		Failed:   int64(len(t.failedNames)),
+		Duration: time.Since(t.startTime),
+	}
+}
+
+// Snapshot 返回当前进度，用于在界面中显示进度条
+func (t *Tracker) Snapshot() Snapshot {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return Snapshot{
+		Files:      t.currentFiles,
+		Bytes:      t.currentSize + t.partialSize,
+		TotalFiles: t.totalFiles,
+		TotalBytes: t.totalSize,
+	}
+}
+
+// summary 返回完成的百分比和进度说明（文件数、数据量、速度和剩余时间），
+// 数据量包括传输中的文件已传输的部分
+func (t *Tracker) summary() (float64, string) {
 	elapsed := time.Since(t.startTime)
+	transferred := t.currentSize + t.partialSize
+	if t.totalSize > 0 {
+		// 重试或压缩时计入的字节数可能超过文件大小
+		transferred = min(transferred, t.totalSize)
+	}
 
 	// 计算百分比
 	var sizePercent float64
 	if t.totalSize > 0 {
-		sizePercent = float64(t.currentSize) / float64(t.totalSize) * 100
+		sizePercent = float64(transferred) / float64(t.totalSize) * 100
 	}
 
 	// 计算速度
-	speed := float64(t.currentSize) / elapsed.Seconds()
+	speed := float64(transferred) / elapsed.Seconds()
 
 	// 估算剩余时间
 	var eta time.Duration
-	if speed > 0 && t.totalSize > t.currentSize {
-		eta = time.Duration(float64(t.totalSize-t.currentSize)/speed) * time.Second
+	if speed > 0 && t.totalSize > transferred {
+		eta = time.Duration(float64(t.totalSize-transferred)/speed) * time.Second
 	}
 
-	// 生成进度条
-	progressBar := t.generateProgressBar(sizePercent)
-
-	fmt.Printf("\r[%s] %.1f%% | %d/%d 文件 | %s/%s | %s/s",
-		progressBar,
+	text := i18n.Sprintf("%.1f%% | %d/%d 文件 | %s/%s | %s/s",
 		sizePercent,
 		t.currentFiles,
 		t.totalFiles,
-		FormatSize(t.currentSize),
+		FormatSize(transferred),
 		FormatSize(t.totalSize),
 		FormatSize(int64(speed)))
 
 	if eta > 0 {
-		fmt.Printf(" | ETA: %s", formatDuration(eta))
+		text += fmt.Sprintf(" | ETA: %s", formatDuration(eta))
 	}
+	if t.buckets > 0 {
+		text = i18n.Sprintf("桶 %d/%d", t.bucketIndex, t.buckets) + " | " + text
+	}
+	return sizePercent, text
 }
 
-// generateProgressBar 生成进度条
-func (t *Tracker) generateProgressBar(percent float64) string {
-	const width = 20
-	filled := int(percent / 100 * width)
+// printProgress 在终端的一行中重绘进度条，按终端宽度缩短进度条图形，
+// 终端过窄时去掉图形并截断，避免换行后无法覆盖。显示工作协程进度时在下方逐行显示
+func (t *Tracker) printProgress() {
+	percent, text := t.summary()
+
+	line := "[" + generateBar(percent, barWidth) + "] " + text
+	columns, rows := terminalSize()
+	if columns > 0 {
+		// 留出最后一列，写满一行时部分终端会自动换行
+		available := columns - 1
+		if width := displayWidth(line); width > available {
+			// "[] " 占3列，图形太短时不再显示
+			if bar := barWidth - (width - available); bar >= 5 {
+				line = "[" + generateBar(percent, bar) + "] " + text
+			} else {
+				line = truncateWidth(text, available)
+			}
+		}
+	}
 
-	bar := "["
+	if t.workers {
+		if columns <= 0 {
+			columns, rows = 80, 24
+		}
+		// 超过终端高度的行无法回到开头重绘，留出总进度和省略提示两行
+		lines := append([]string{line}, t.workerLines(columns, max(rows-3, 1))...)
+		t.printLines(lines)
+		t.lastDraw = time.Now()
+		drawn.Store(t)
+		return
+	}
+
+	// 新的一行比上次短时用空格覆盖剩余部分
+	width := displayWidth(line)
+	padding := ""
+	if width < t.lineWidth {
+		padding = strings.Repeat(" ", t.lineWidth-width)
+	}
+	fmt.Print("\r" + line + padding)
+	t.lineWidth = width
+	t.lastDraw = time.Now()
+	drawn.Store(t)
+}
+
+// flush 重绘最终的进度，之后的输出从新的一行开始，下次重绘不再覆盖之前的内容。调用时需持有锁
+func (t *Tracker) flush() {
+	t.printProgress()
+	t.lineWidth = 0
+	t.drawnLines = 0
+	drawn.CompareAndSwap(t, nil)
+}
+
+// Clear 清除终端中正在显示的进度条，光标回到行首，进度条在下次刷新时重新显示。
+// 传输过程中输出日志前调用，避免日志接在进度条后面
+func Clear() {
+	t := drawn.Swap(nil)
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	switch {
+	case t.drawnLines > 0:
+		if t.drawnLines > 1 {
+			fmt.Printf("\033[%dA", t.drawnLines-1)
+		}
+		fmt.Print("\r\033[J")
+	case t.lineWidth > 0:
+		fmt.Print("\r" + strings.Repeat(" ", t.lineWidth) + "\r")
+	}
+	t.lineWidth = 0
+	t.drawnLines = 0
+}
+
+// printLogLine 输出一行进度，用于输出重定向到文件时
+func (t *Tracker) printLogLine() {
+	_, text := t.summary()
+	i18n.Printf("进度: %s\n", text)
+	t.lastDraw = time.Now()
+}
+
+// generateBar 生成指定宽度的进度条图形
+func generateBar(percent float64, width int) string {
+	filled := int(percent / 100 * float64(width))
+
+	bar := ""
 	for i := 0; i < width; i++ {
 		if i < filled {
 			bar += "█"
@@ -101,26 +349,49 @@ func (t *Tracker) generateProgressBar(percent float64) string {
 			bar += "░"
 		}
 	}
-	bar += "]"
 
 	return bar
 }
 
-// PrintFinal 打印最终统计信息
+// PrintFinal 打印最终统计信息，设置了 Reporter 时改为发送 Finished 事件
 func (t *Tracker) PrintFinal() {
+	if t.reporter != nil {
+		stats := t.Stats()
+		t.mutex.Lock()
+		finished := Finished{Stats: stats, Retries: t.breakdown.retries, Skipped: t.breakdown.skipped, Failed: t.breakdown.failed}
+		t.mutex.Unlock()
+		t.reporter.Report(finished)
+		return
+	}
+
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
 
+	// 进度条按间隔刷新，结束时显示最终的进度，之后的输出从新的一行开始
+	if t.terminal && !t.verbose {
+		if t.parent != nil {
+			t.parent.mutex.Lock()
+			t.parent.flush()
+			t.parent.mutex.Unlock()
+		} else {
+			t.flush()
+		}
+	}
+
 	elapsed := time.Since(t.startTime)
 	averageSpeed := float64(t.currentSize) / elapsed.Seconds()
 
-	fmt.Printf("\n\n备份完成!\n")
+	i18n.Printf("\n\n备份完成!\n")
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Printf("统计信息:\n")
-	fmt.Printf("  文件数量: %d\n", t.currentFiles)
-	fmt.Printf("  数据大小: %s\n", FormatSize(t.currentSize))
-	fmt.Printf("  用时: %s\n", formatDuration(elapsed))
-	fmt.Printf("  平均速度: %s/s\n", FormatSize(int64(averageSpeed)))
+	i18n.Printf("统计信息:\n")
+	i18n.Printf("  文件数量: %d\n", t.currentFiles)
+	i18n.Printf("  数据大小: %s\n", FormatSize(t.currentSize))
+	if t.resumedFiles > 0 {
+		i18n.Printf("  其中中断前已完成: %d 个文件（%s）\n", t.resumedFiles, FormatSize(t.resumedSize))
+	}
+	i18n.Printf("  用时: %s\n", formatDuration(elapsed))
+	i18n.Printf("  平均速度: %s/s\n", FormatSize(int64(averageSpeed)))
+	t.breakdown.print()
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 }
 
@@ -141,6 +412,39 @@ func FormatSize(size int64) string {
 	return fmt.Sprintf("%.1f %s", float64(size)/float64(div), units[exp])
 }
 
+// ParseSize 解析文件大小字符串，支持 B/KB/MB/GB/TB/PB 单位（1024进制），如 "10GB"、"512M"
+func ParseSize(value string) (int64, error) {
+	text := strings.ToUpper(strings.TrimSpace(value))
+	if text == "" {
+		return 0, i18n.Errorf("大小不能为空")
+	}
+
+	units := []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{"PB", 1 << 50}, {"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+		{"P", 1 << 50}, {"T", 1 << 40}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10},
+		{"B", 1},
+	}
+
+	multiplier := int64(1)
+	for _, unit := range units {
+		if strings.HasSuffix(text, unit.suffix) {
+			multiplier = unit.multiplier
+			text = strings.TrimSpace(strings.TrimSuffix(text, unit.suffix))
+			break
+		}
+	}
+
+	number, err := strconv.ParseFloat(text, 64)
+	if err != nil || number < 0 {
+		return 0, i18n.Errorf("无效的大小: %s", value)
+	}
+
+	return int64(number * float64(multiplier)), nil
+}
+
 // formatDuration 格式化时间间隔
 func formatDuration(d time.Duration) string {
 	if d < time.Minute {
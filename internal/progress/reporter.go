@@ -0,0 +1,77 @@
+package progress
+
+import "time"
+
+// Reporter 接收传输事件，用于嵌入备份、上传功能的程序显示自己的界面。
+// 设置后跟踪器不再在终端中输出进度条、进度行和最终统计。
+// Report 可能被多个工作协程同时调用，需要自行同步；调用时不持有跟踪器的锁，可以调用 Snapshot 和 Stats
+type Reporter interface {
+	Report(event Event)
+}
+
+// Event 传输事件，具体类型为下面的结构体之一
+type Event interface {
+	event()
+}
+
+// Planned 确定了本次需要传输的文件，继续的运行另外给出中断前已完成的部分
+type Planned struct {
+	Files        int64
+	Bytes        int64
+	ResumedFiles int64
+	ResumedBytes int64
+}
+
+// FileStarted 开始传输一个文件
+type FileStarted struct {
+	Name string
+	Size int64
+}
+
+// FileProgress 文件传输了一部分数据，Transferred 为该文件累计已传输的字节数。
+// 每次读写数据都会发送，处理需要足够快，界面刷新可以自行节流
+type FileProgress struct {
+	Name        string
+	Size        int64
+	Transferred int64
+}
+
+// FileDone 文件传输完成。目录标记、服务端复制和打包中的文件没有传输过程，Duration 为0
+type FileDone struct {
+	Name     string
+	Size     int64
+	Duration time.Duration
+}
+
+// FileFailed 文件传输失败或被停止，之后可能重试
+type FileFailed struct {
+	Name string
+}
+
+// Finished 全部传输结束，代替终端中输出的最终统计
+type Finished struct {
+	Stats   Stats
+	Retries int64
+	Skipped int64
+	Failed  int64
+}
+
+func (Planned) event()      {}
+func (FileStarted) event()  {}
+func (FileProgress) event() {}
+func (FileDone) event()     {}
+func (FileFailed) event()   {}
+func (Finished) event()     {}
+
+// SetReporter 将进度事件发送给 reporter，不再在终端中输出进度，需要在开始传输前调用
+func (t *Tracker) SetReporter(reporter Reporter) {
+	t.reporter = reporter
+	t.terminal = false
+}
+
+// report 发送事件，没有设置 Reporter 时不做任何事。调用时不能持有锁
+func (t *Tracker) report(event Event) {
+	if t.reporter != nil {
+		t.reporter.Report(event)
+	}
+}
@@ -0,0 +1,52 @@
+package progress
+
+import (
+	"os"
+
+	"golang.org/x/term"
+)
+
+// terminalSize 返回标准输出终端的列数和行数，不是终端或无法获取时返回0
+func terminalSize() (int, int) {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 0, 0
+	}
+	return width, height
+}
+
+// runeWidth 字符在终端中占用的列数，中日韩文字和全角符号占两列
+func runeWidth(r rune) int {
+	switch {
+	case r >= 0x1100 && r <= 0x115F,
+		r >= 0x2E80 && r <= 0xA4CF,
+		r >= 0xAC00 && r <= 0xD7A3,
+		r >= 0xF900 && r <= 0xFAFF,
+		r >= 0xFE30 && r <= 0xFE4F,
+		r >= 0xFF00 && r <= 0xFF60,
+		r >= 0xFFE0 && r <= 0xFFE6:
+		return 2
+	}
+	return 1
+}
+
+// displayWidth 字符串在终端中占用的列数
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// truncateWidth 截断字符串使其不超过指定列数
+func truncateWidth(s string, width int) string {
+	used := 0
+	for i, r := range s {
+		used += runeWidth(r)
+		if used > width {
+			return s[:i]
+		}
+	}
+	return s
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package progress
+
+// enableVirtualTerminal 其他系统的终端都支持控制序列
+func enableVirtualTerminal() bool {
+	return true
+}
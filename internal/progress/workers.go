@@ -0,0 +1,152 @@
+package progress
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"objectsync/internal/i18n"
+)
+
+// stallThreshold 超过该时间没有传输数据的文件在工作协程进度中标记为停滞
+const stallThreshold = 10 * time.Second
+
+// workerBarWidth 工作协程进度条图形的宽度
+const workerBarWidth = 10
+
+// refreshInterval 没有数据传输时重绘工作协程进度的间隔，使停滞的传输也能显示出来
+const refreshInterval = time.Second
+
+// ShowWorkers 在总进度下方为每个正在传输的文件显示一行：对象名、进度和速度，
+// 用于发现卡在大文件或停滞传输上的工作协程。只在终端中有效，
+// 设置了汇总跟踪器时需要在汇总跟踪器上调用
+func (t *Tracker) ShowWorkers() {
+	t.workers = t.terminal && enableVirtualTerminal()
+}
+
+// display 输出进度的跟踪器：设置了汇总跟踪器时由它输出
+func (t *Tracker) display() *Tracker {
+	if t.parent != nil {
+		return t.parent
+	}
+	return t
+}
+
+// occupy 为计数器分配一行，优先使用已结束的传输空出的行，使每行的位置保持稳定。调用时需持有锁
+func (t *Tracker) occupy(c *Counter) int {
+	if !t.refreshing {
+		t.refreshing = true
+		go t.refresh()
+	}
+	for i, slot := range t.slots {
+		if slot == nil {
+			t.slots[i] = c
+			return i
+		}
+	}
+	t.slots = append(t.slots, c)
+	return len(t.slots) - 1
+}
+
+// refresh 有文件正在传输时定期重绘，所有传输结束后退出
+func (t *Tracker) refresh() {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		t.mutex.Lock()
+		busy := false
+		for _, slot := range t.slots {
+			if slot != nil {
+				busy = true
+				break
+			}
+		}
+		if !busy {
+			t.refreshing = false
+			t.mutex.Unlock()
+			return
+		}
+		if time.Since(t.lastDraw) >= refreshInterval {
+			t.printProgress()
+		}
+		t.mutex.Unlock()
+	}
+}
+
+// workerLines 返回每个正在传输的文件的进度行，最多 limit 行，其余的合并为一行。调用时需持有锁
+func (t *Tracker) workerLines(columns, limit int) []string {
+	var lines []string
+	hidden := 0
+	for _, c := range t.slots {
+		if c == nil {
+			continue
+		}
+		if len(lines) >= limit {
+			hidden++
+			continue
+		}
+		lines = append(lines, c.line(columns))
+	}
+	if hidden > 0 {
+		lines = append(lines, i18n.Sprintf("  ... 另有 %d 个文件正在传输", hidden))
+	}
+	return lines
+}
+
+// line 格式化计数器的进度行，对象名过长时保留末尾部分
+func (c *Counter) line(columns int) string {
+	count := c.count.Load()
+	var percent float64
+	if c.size > 0 {
+		percent = min(float64(count)/float64(c.size)*100, 100)
+	}
+	speed := float64(count) / time.Since(c.start).Seconds()
+
+	status := fmt.Sprintf("[%s] %5.1f%% | %s/s", generateBar(percent, workerBarWidth), percent, FormatSize(int64(speed)))
+	if idle := time.Since(time.Unix(0, c.lastRead.Load())); idle >= stallThreshold {
+		status += i18n.Sprintf(" | 停滞 %s", formatDuration(idle))
+	}
+
+	// 行首缩进2列，对象名和状态之间空1列，并留出最后一列
+	available := columns - displayWidth(status) - 4
+	name := c.name
+	if available < 8 {
+		return truncateWidth("  "+status, columns-1)
+	}
+	if displayWidth(name) > available {
+		name = "..." + trimLeftWidth(name, available-3)
+	}
+	return "  " + name + strings.Repeat(" ", available-displayWidth(name)) + " " + status
+}
+
+// trimLeftWidth 从开头去掉字符，使字符串不超过指定列数
+func trimLeftWidth(s string, width int) string {
+	runes := []rune(s)
+	used := 0
+	for i := len(runes) - 1; i >= 0; i-- {
+		used += runeWidth(runes[i])
+		if used > width {
+			return string(runes[i+1:])
+		}
+	}
+	return s
+}
+
+// printLines 在终端中重绘多行进度：先回到上次输出的第一行，逐行覆盖并清除剩余的旧内容
+func (t *Tracker) printLines(lines []string) {
+	var b strings.Builder
+	if t.drawnLines > 1 {
+		fmt.Fprintf(&b, "\033[%dA", t.drawnLines-1)
+	}
+	b.WriteString("\r")
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(line)
+		b.WriteString("\033[K")
+	}
+	b.WriteString("\033[J")
+	fmt.Print(b.String())
+	t.drawnLines = len(lines)
+}
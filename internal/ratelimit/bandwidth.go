@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// bandwidthChunk 每次读取的最大字节数，较小的块使传输速率更平稳
+const bandwidthChunk = 32 << 10
+
+// Bandwidth 带宽限制器，限制上传和下载的总字节速率，可在多个协程之间共享
+type Bandwidth struct {
+	bytesPerSecond int64
+	next           time.Time
+	mutex          sync.Mutex
+}
+
+// NewBandwidth 创建每秒最多传输bytesPerSecond字节的限制器，bytesPerSecond<=0时返回nil表示不限制
+func NewBandwidth(bytesPerSecond int64) *Bandwidth {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	return &Bandwidth{bytesPerSecond: bytesPerSecond}
+}
+
+// WaitN 记录已传输的n个字节，超出速率时阻塞，nil限制器立即返回
+func (b *Bandwidth) WaitN(n int) {
+	if b == nil || n <= 0 {
+		return
+	}
+
+	b.mutex.Lock()
+	now := time.Now()
+	if b.next.Before(now) {
+		b.next = now
+	}
+	b.next = b.next.Add(time.Duration(float64(n) / float64(b.bytesPerSecond) * float64(time.Second)))
+	wait := b.next.Sub(now)
+	b.mutex.Unlock()
+
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+// Transport 返回对请求体和响应体限速的HTTP传输，nil限制器直接返回base
+func (b *Bandwidth) Transport(base http.RoundTripper) http.RoundTripper {
+	if b == nil {
+		return base
+	}
+	return &bandwidthTransport{base: base, limiter: b}
+}
+
+// bandwidthTransport 限速的HTTP传输
+type bandwidthTransport struct {
+	base    http.RoundTripper
+	limiter *Bandwidth
+}
+
+// RoundTrip 包装请求体和响应体后发送请求
+func (t *bandwidthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil && req.Body != http.NoBody {
+		req = req.Clone(req.Context())
+		req.Body = &limitedReader{ReadCloser: req.Body, limiter: t.limiter}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &limitedReader{ReadCloser: resp.Body, limiter: t.limiter}
+	return resp, nil
+}
+
+// limitedReader 按带宽限制读取数据
+type limitedReader struct {
+	io.ReadCloser
+	limiter *Bandwidth
+}
+
+// Read 读取数据后等待限制器放行
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if len(p) > bandwidthChunk {
+		p = p[:bandwidthChunk]
+	}
+	n, err := r.ReadCloser.Read(p)
+	r.limiter.WaitN(n)
+	return n, err
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// Limiter 请求速率限制器，所有请求按固定间隔放行，可在多个协程和桶之间共享
+type Limiter struct {
+	interval time.Duration
+	next     time.Time
+	mutex    sync.Mutex
+}
+
+// New 创建每秒最多放行perSecond个请求的限制器，perSecond<=0时返回nil表示不限制
+func New(perSecond float64) *Limiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &Limiter{
+		interval: time.Duration(float64(time.Second) / perSecond),
+	}
+}
+
+// Wait 阻塞直到允许发送下一个请求，nil限制器立即返回
+func (l *Limiter) Wait() {
+	if l == nil {
+		return
+	}
+
+	l.mutex.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mutex.Unlock()
+
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+// Install 在SDK发送每个HTTP请求（包括重试）之前等待限制器放行
+func Install(handlers *request.Handlers, l *Limiter) {
+	if l == nil {
+		return
+	}
+	handlers.Send.PushFront(func(r *request.Request) {
+		l.Wait()
+	})
+}
+
+// Transport 返回每个请求（包括重试）发送前等待限制器放行的HTTP传输，
+// 用于不经过aws-sdk-go的客户端，nil限制器直接返回base
+func (l *Limiter) Transport(base http.RoundTripper) http.RoundTripper {
+	if l == nil {
+		return base
+	}
+	return &limiterTransport{base: base, limiter: l}
+}
+
+// limiterTransport 限制请求速率的HTTP传输
+type limiterTransport struct {
+	base    http.RoundTripper
+	limiter *Limiter
+}
+
+// RoundTrip 等待限制器放行后发送请求
+func (t *limiterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.limiter.Wait()
+	return t.base.RoundTrip(req)
+}
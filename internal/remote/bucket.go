@@ -0,0 +1,108 @@
+package remote
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// BucketExists 检查桶是否存在
+func (c *Client) BucketExists(bucket string) (bool, error) {
+	_, err := c.s3.HeadBucket(&s3.HeadBucketInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NotFound" {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ListBuckets 列出当前凭证可以访问的桶，同时返回响应头中的服务端时间（请求失败时也会返回），
+// 用于检查本地时钟偏差
+func (c *Client) ListBuckets() ([]string, time.Time, error) {
+	req, output := c.s3.ListBucketsRequest(&s3.ListBucketsInput{})
+	err := req.Send()
+
+	var serverTime time.Time
+	if req.HTTPResponse != nil {
+		serverTime, _ = http.ParseTime(req.HTTPResponse.Header.Get("Date"))
+	}
+	if err != nil {
+		return nil, serverTime, err
+	}
+
+	names := make([]string, 0, len(output.Buckets))
+	for _, bucket := range output.Buckets {
+		names = append(names, aws.StringValue(bucket.Name))
+	}
+	return names, serverTime, nil
+}
+
+// MakeBucket 创建桶，versioning为true时同时启用版本控制
+func (c *Client) MakeBucket(bucket string, versioning bool) error {
+	if _, err := c.s3.CreateBucket(&s3.CreateBucketInput{Bucket: aws.String(bucket)}); err != nil {
+		return err
+	}
+	if !versioning {
+		return nil
+	}
+	_, err := c.s3.PutBucketVersioning(&s3.PutBucketVersioningInput{
+		Bucket: aws.String(bucket),
+		VersioningConfiguration: &s3.VersioningConfiguration{
+			Status: aws.String(s3.BucketVersioningStatusEnabled),
+		},
+	})
+	return err
+}
+
+// IsEmpty 检查桶中是否没有任何对象，包括历史版本和删除标记
+func (c *Client) IsEmpty(bucket string) (bool, error) {
+	output, err := c.s3.ListObjectVersions(&s3.ListObjectVersionsInput{
+		Bucket:  aws.String(bucket),
+		MaxKeys: aws.Int64(1),
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(output.Versions) == 0 && len(output.DeleteMarkers) == 0, nil
+}
+
+// EmptyBucket 删除桶中的所有对象版本和删除标记，返回删除的数量
+func (c *Client) EmptyBucket(bucket string) (int, error) {
+	deleted := 0
+	input := &s3.ListObjectVersionsInput{Bucket: aws.String(bucket)}
+	for {
+		output, err := c.s3.ListObjectVersions(input)
+		if err != nil {
+			return deleted, err
+		}
+
+		var objects []*s3.ObjectIdentifier
+		for _, version := range output.Versions {
+			objects = append(objects, &s3.ObjectIdentifier{Key: version.Key, VersionId: version.VersionId})
+		}
+		for _, marker := range output.DeleteMarkers {
+			objects = append(objects, &s3.ObjectIdentifier{Key: marker.Key, VersionId: marker.VersionId})
+		}
+		if err := c.deleteIdentifiers(bucket, objects); err != nil {
+			return deleted, err
+		}
+		deleted += len(objects)
+
+		if !aws.BoolValue(output.IsTruncated) {
+			return deleted, nil
+		}
+		input.KeyMarker = output.NextKeyMarker
+		input.VersionIdMarker = output.NextVersionIdMarker
+	}
+}
+
+// RemoveBucket 删除空桶
+func (c *Client) RemoveBucket(bucket string) error {
+	_, err := c.s3.DeleteBucket(&s3.DeleteBucketInput{Bucket: aws.String(bucket)})
+	return err
+}
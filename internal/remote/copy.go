@@ -0,0 +1,109 @@
+package remote
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"objectsync/internal/s3client"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// maxCopySize 单次CopyObject请求支持的最大对象大小，更大的对象使用UploadPartCopy分片复制
+const maxCopySize = 5 << 30
+
+// copyPartSize 分片复制的分片大小，对象过大时增大以满足10000个分片的上限
+const copyPartSize = 512 << 20
+
+// Copy 在同一个端点内服务端复制对象，数据不经过本机。size为来源对象大小，用于选择复制方式
+func (c *Client) Copy(srcBucket, srcKey, dstBucket, dstKey string, size int64) error {
+	if size <= maxCopySize {
+		_, err := c.s3.CopyObject(&s3.CopyObjectInput{
+			Bucket:     aws.String(dstBucket),
+			Key:        aws.String(dstKey),
+			CopySource: aws.String(copySource(srcBucket, srcKey)),
+		})
+		return err
+	}
+	return c.copyMultipart(srcBucket, srcKey, dstBucket, dstKey, size)
+}
+
+// copyMultipart 使用UploadPartCopy分片复制大对象，保留来源对象的元数据和HTTP头
+func (c *Client) copyMultipart(srcBucket, srcKey, dstBucket, dstKey string, size int64) error {
+	// 分片复制不会自动复制元数据，需要先读取来源对象
+	head, err := c.s3.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(srcBucket),
+		Key:    aws.String(srcKey),
+	})
+	if err != nil {
+		return err
+	}
+
+	created, err := c.s3.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+		Bucket:             aws.String(dstBucket),
+		Key:                aws.String(dstKey),
+		Metadata:           head.Metadata,
+		CacheControl:       head.CacheControl,
+		ContentEncoding:    head.ContentEncoding,
+		ContentType:        head.ContentType,
+		ContentDisposition: head.ContentDisposition,
+		StorageClass:       head.StorageClass,
+	})
+	if err != nil {
+		return err
+	}
+
+	// 放弃未完成的分片上传，避免残留分片占用空间
+	abort := func() {
+		c.s3.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(dstBucket),
+			Key:      aws.String(dstKey),
+			UploadId: created.UploadId,
+		})
+	}
+
+	partSize := s3client.PartSize(size, copyPartSize)
+
+	var parts []*s3.CompletedPart
+	for number, offset := int64(1), int64(0); offset < size; number, offset = number+1, offset+partSize {
+		end := min(offset+partSize, size) - 1
+		output, err := c.s3.UploadPartCopy(&s3.UploadPartCopyInput{
+			Bucket:          aws.String(dstBucket),
+			Key:             aws.String(dstKey),
+			UploadId:        created.UploadId,
+			PartNumber:      aws.Int64(number),
+			CopySource:      aws.String(copySource(srcBucket, srcKey)),
+			CopySourceRange: aws.String(fmt.Sprintf("bytes=%d-%d", offset, end)),
+		})
+		if err != nil {
+			abort()
+			return err
+		}
+		parts = append(parts, &s3.CompletedPart{
+			ETag:       output.CopyPartResult.ETag,
+			PartNumber: aws.Int64(number),
+		})
+	}
+
+	_, err = c.s3.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(dstBucket),
+		Key:             aws.String(dstKey),
+		UploadId:        created.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		abort()
+	}
+	return err
+}
+
+// copySource 生成URL编码的复制来源
+func copySource(bucket, key string) string {
+	parts := strings.Split(bucket+"/"+key, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
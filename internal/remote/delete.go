@@ -0,0 +1,72 @@
+package remote
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// maxDeleteBatch DeleteObjects单次请求最多删除的对象数
+const maxDeleteBatch = 1000
+
+// DeleteError 批量删除中单个对象的失败原因
+type DeleteError struct {
+	Key     string
+	Message string
+}
+
+// Delete 使用DeleteObjects按每批1000个删除对象，返回单个对象的失败原因；请求本身失败时返回错误
+func (c *Client) Delete(bucket string, keys []string) ([]DeleteError, error) {
+	var failed []DeleteError
+	for start := 0; start < len(keys); start += maxDeleteBatch {
+		end := min(start+maxDeleteBatch, len(keys))
+
+		objects := make([]*s3.ObjectIdentifier, 0, end-start)
+		for _, key := range keys[start:end] {
+			objects = append(objects, &s3.ObjectIdentifier{Key: aws.String(key)})
+		}
+
+		errors, err := c.deleteBatch(bucket, objects)
+		if err != nil {
+			return failed, err
+		}
+		failed = append(failed, errors...)
+	}
+	return failed, nil
+}
+
+// deleteIdentifiers 批量删除指定的对象（可以带版本号），任一对象删除失败时返回错误
+func (c *Client) deleteIdentifiers(bucket string, objects []*s3.ObjectIdentifier) error {
+	for start := 0; start < len(objects); start += maxDeleteBatch {
+		end := min(start+maxDeleteBatch, len(objects))
+		failed, err := c.deleteBatch(bucket, objects[start:end])
+		if err != nil {
+			return err
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("%s: %s", failed[0].Key, failed[0].Message)
+		}
+	}
+	return nil
+}
+
+// deleteBatch 发送一次DeleteObjects请求
+func (c *Client) deleteBatch(bucket string, objects []*s3.ObjectIdentifier) ([]DeleteError, error) {
+	if len(objects) == 0 {
+		return nil, nil
+	}
+	output, err := c.s3.DeleteObjects(&s3.DeleteObjectsInput{
+		Bucket: aws.String(bucket),
+		Delete: &s3.Delete{Objects: objects, Quiet: aws.Bool(true)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var failed []DeleteError
+	for _, e := range output.Errors {
+		failed = append(failed, DeleteError{Key: aws.StringValue(e.Key), Message: aws.StringValue(e.Message)})
+	}
+	return failed, nil
+}
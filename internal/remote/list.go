@@ -0,0 +1,72 @@
+package remote
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// Delimiter 非递归列出时用于划分目录层级的分隔符
+const Delimiter = "/"
+
+// Entry 列出的对象或公共前缀
+type Entry struct {
+	Key          string    `json:"key"`
+	IsPrefix     bool      `json:"is_prefix,omitempty"` // 非递归列出时的下一级"目录"
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified,omitempty"`
+	ETag         string    `json:"etag,omitempty"`
+	StorageClass string    `json:"storage_class,omitempty"`
+}
+
+// List 列出桶中前缀下的对象，recursive为false时只列出下一级，更深的对象合并为公共前缀
+func (c *Client) List(bucket, prefix string, recursive bool) ([]Entry, error) {
+	var entries []Entry
+	err := c.Walk(bucket, prefix, recursive, func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	return entries, err
+}
+
+// Walk 逐页列出桶中前缀下的对象，对每个对象或公共前缀调用fn，fn返回错误时停止
+func (c *Client) Walk(bucket, prefix string, recursive bool, fn func(Entry) error) error {
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	}
+	if !recursive {
+		input.Delimiter = aws.String(Delimiter)
+	}
+
+	for {
+		result, err := c.s3.ListObjectsV2(input)
+		if err != nil {
+			return err
+		}
+
+		for _, common := range result.CommonPrefixes {
+			if err := fn(Entry{Key: aws.StringValue(common.Prefix), IsPrefix: true}); err != nil {
+				return err
+			}
+		}
+		for _, obj := range result.Contents {
+			entry := Entry{
+				Key:          aws.StringValue(obj.Key),
+				Size:         aws.Int64Value(obj.Size),
+				LastModified: aws.TimeValue(obj.LastModified),
+				ETag:         aws.StringValue(obj.ETag),
+				StorageClass: aws.StringValue(obj.StorageClass),
+			}
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+
+		if !aws.BoolValue(result.IsTruncated) {
+			return nil
+		}
+		input.ContinuationToken = result.NextContinuationToken
+	}
+}
@@ -0,0 +1,39 @@
+package remote
+
+import (
+	"strings"
+	"time"
+
+	"objectsync/internal/i18n"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// MaxPresignExpiry SigV4签名URL的最长有效期
+const MaxPresignExpiry = 7 * 24 * time.Hour
+
+// Presign 生成对象的临时访问URL，method为GET（下载）或PUT（上传）
+func (c *Client) Presign(method, bucket, key string, expires time.Duration) (string, error) {
+	if expires <= 0 || expires > MaxPresignExpiry {
+		return "", i18n.Errorf("有效期必须大于0且不超过 %s: %s", MaxPresignExpiry, expires)
+	}
+
+	var req *request.Request
+	switch strings.ToUpper(method) {
+	case "GET":
+		req, _ = c.s3.GetObjectRequest(&s3.GetObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+	case "PUT":
+		req, _ = c.s3.PutObjectRequest(&s3.PutObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+	default:
+		return "", i18n.Errorf("不支持的请求方法: %s（可选 GET、PUT）", method)
+	}
+	return req.Presign(expires)
+}
@@ -0,0 +1,44 @@
+package remote
+
+import (
+	"objectsync/internal/s3client"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// Client 直接操作远程对象的客户端，供 ls、rm 等命令使用
+type Client struct {
+	s3 *s3.S3
+}
+
+// New 创建远程操作客户端
+func New(options s3client.Options) (*Client, error) {
+	client, err := s3client.New(options)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{s3: client}, nil
+}
+
+// Stat 读取单个对象的信息，对象不存在时返回nil
+func (c *Client) Stat(bucket, key string) (*Entry, error) {
+	output, err := c.s3.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NotFound" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &Entry{
+		Key:          key,
+		Size:         aws.Int64Value(output.ContentLength),
+		LastModified: aws.TimeValue(output.LastModified),
+		ETag:         aws.StringValue(output.ETag),
+		StorageClass: aws.StringValue(output.StorageClass),
+	}, nil
+}
@@ -0,0 +1,56 @@
+package remote
+
+import (
+	"sort"
+	"strings"
+)
+
+// Usage 前缀下对象的数量和总大小
+type Usage struct {
+	Prefix  string `json:"prefix"`
+	Objects int64  `json:"objects"`
+	Bytes   int64  `json:"bytes"`
+}
+
+// DiskUsage 按前缀之后的前depth级目录汇总对象数量和大小，结果按前缀排序。
+// 层级不足depth的对象计入它所在的目录，depth为0时只汇总整个前缀
+func (c *Client) DiskUsage(bucket, prefix string, depth int) ([]Usage, error) {
+	totals := make(map[string]*Usage)
+	err := c.Walk(bucket, prefix, true, func(entry Entry) error {
+		group := usageGroup(entry.Key, prefix, depth)
+		usage, ok := totals[group]
+		if !ok {
+			usage = &Usage{Prefix: group}
+			totals[group] = usage
+		}
+		usage.Objects++
+		usage.Bytes += entry.Size
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Usage, 0, len(totals))
+	for _, usage := range totals {
+		result = append(result, *usage)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Prefix < result[j].Prefix
+	})
+	return result, nil
+}
+
+// usageGroup 返回对象键在前缀之后截取depth级目录得到的分组前缀
+func usageGroup(key, prefix string, depth int) string {
+	relative := strings.TrimPrefix(key, prefix)
+	end := 0
+	for level := 0; level < depth; level++ {
+		i := strings.Index(relative[end:], Delimiter)
+		if i < 0 {
+			break
+		}
+		end += i + len(Delimiter)
+	}
+	return prefix + relative[:end]
+}
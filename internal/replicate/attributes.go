@@ -0,0 +1,74 @@
+package replicate
+
+import (
+	"errors"
+
+	"objectsync/internal/storage"
+)
+
+// initAttributes 确定是否复制对象的标签和ACL：需要两端都支持，不支持时只复制内容和元数据
+func (r *Replicate) initAttributes() {
+	if !r.options.CopyAttributes {
+		return
+	}
+	_, source := r.source.(storage.Attributes)
+	_, target := r.target.(storage.Attributes)
+	if !source || !target {
+		logger.Warnf("源端或目标端不支持对象标签和ACL，只复制内容和元数据")
+		return
+	}
+	r.attributes = true
+}
+
+// copyAttributes 把源对象的标签和ACL复制到目标对象。标签和ACL不影响对象的内容，
+// 复制失败时只记录警告并计数，不重新复制对象
+func (r *Replicate) copyAttributes(key string) {
+	source := r.source.(storage.Attributes)
+	target := r.target.(storage.Attributes)
+
+	if err := copyTags(source, target, key); err != nil {
+		logger.Warnf("复制 %s 的标签失败: %v", key, err)
+		r.attributeErrors.Add(1)
+	}
+
+	if r.aclUnsupported.Load() {
+		return
+	}
+	err := copyACL(source, target, key)
+	switch {
+	case errors.Is(err, errors.ErrUnsupported):
+		if !r.aclUnsupported.Swap(true) {
+			logger.Warnf("目标端的客户端不支持设置ACL，不再复制ACL")
+		}
+	case err != nil:
+		logger.Warnf("复制 %s 的ACL失败: %v", key, err)
+		r.attributeErrors.Add(1)
+	}
+}
+
+// copyTags 复制对象的标签。上传新对象时标签为空，源对象没有标签时不需要设置
+func copyTags(source, target storage.Attributes, key string) error {
+	tags, err := source.GetTags(key)
+	if err != nil || len(tags) == 0 {
+		return err
+	}
+	return target.PutTags(key, tags)
+}
+
+// copyACL 复制对象的授权。两端的用户ID可能不同，授权给源对象所有者的项改为授权给目标对象的所有者，
+// 与目标对象当前的ACL相同时（如都是默认的私有）不需要设置
+func copyACL(source, target storage.Attributes, key string) error {
+	sourceACL, err := source.GetACL(key)
+	if err != nil {
+		return err
+	}
+	targetACL, err := target.GetACL(key)
+	if err != nil {
+		return err
+	}
+	wanted := sourceACL.WithOwner(targetACL.Owner)
+	if wanted.Equal(targetACL) {
+		return nil
+	}
+	return target.PutACL(key, wanted)
+}
@@ -0,0 +1,225 @@
+package replicate
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"objectsync/internal/i18n"
+	"objectsync/internal/s3client"
+	"objectsync/internal/storage"
+)
+
+// partSize 大对象分片上传的分片大小，对象过大时加倍以满足分片数量上限；不超过一个分片的对象整个缓冲后上传
+const partSize = 16 << 20
+
+// defaultPartsConcurrency 未指定时单个大对象同时上传的分片数
+const defaultPartsConcurrency = 5
+
+// copyObjectWithRetry 复制单个对象，读取源对象中断或校验不一致时按指数退避重新复制，返回复制时源对象的属性
+func (r *Replicate) copyObjectWithRetry(obj storage.Object) (storage.Object, error) {
+	attempts := r.options.MaxAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+
+	var copied storage.Object
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		copied, err = r.copyObject(obj)
+		if err == nil || !isRetryable(err) || attempt == attempts {
+			break
+		}
+
+		delay := s3client.BackoffDelay(r.options.RetryDelay, attempt)
+		logger.Warnf("复制 %s 失败（第 %d/%d 次）: %v，%s 后重试", obj.Key, attempt, attempts, err, delay)
+		r.progress.AddRetries(1)
+		time.Sleep(delay)
+	}
+	return copied, err
+}
+
+// copyObject 读取源对象并上传到目标桶的同一个键，保留用户元数据和HTTP头，需要时复制标签和ACL。
+// 不超过一个分片的对象整个缓冲后上传，更大的对象边读取边分片上传，缓冲的数据量受内存上限限制
+func (r *Replicate) copyObject(obj storage.Object) (storage.Object, error) {
+	logger.Debugf("复制: %s", obj.Key)
+
+	// 按读取的字节数更新进度，大对象复制过程中进度也会变化
+	counter := r.progress.NewCounter(obj.Key, obj.Size)
+	defer counter.Close()
+
+	body, object, err := r.source.Get(obj.Key, counter)
+	if err != nil {
+		return storage.Object{}, err
+	}
+	defer body.Close()
+
+	// 列出后对象可能被替换，按读取时的属性复制和记录
+	source := *object
+	if source.ETag == "" {
+		source.ETag = obj.ETag
+	}
+	if source.LastModified.IsZero() {
+		source.LastModified = obj.LastModified
+	}
+	reader := &sourceReader{body: body, remaining: source.Size}
+	options := &storage.PutOptions{
+		Metadata:     source.Metadata,
+		Headers:      source.Headers,
+		StorageClass: r.options.StorageClass,
+	}
+
+	var etag string
+	if source.Size <= partSize {
+		etag, err = r.putBuffered(obj.Key, reader, source.Size, options)
+	} else {
+		etag, err = r.putMultipart(obj.Key, reader, source.Size, options)
+	}
+	if err != nil {
+		return storage.Object{}, err
+	}
+
+	if r.options.Verify {
+		if err := r.verifyCopy(source, etag); err != nil {
+			return storage.Object{}, err
+		}
+	}
+	if r.attributes {
+		r.copyAttributes(obj.Key)
+	}
+
+	counter.Done(source.Size)
+	return source, nil
+}
+
+// putBuffered 把整个对象读入内存后上传
+func (r *Replicate) putBuffered(key string, reader io.Reader, size int64, options *storage.PutOptions) (string, error) {
+	reserved := r.memory.acquire(size)
+	defer r.memory.release(reserved)
+
+	buffer := make([]byte, size)
+	if _, err := io.ReadFull(reader, buffer); err != nil {
+		return "", err
+	}
+	return r.target.Put(key, bytes.NewReader(buffer), options)
+}
+
+// putMultipart 边读取边分片上传大对象。同时上传的分片数按内存上限减少，
+// 读取下一个分片时占用一个额外的分片缓冲区
+func (r *Replicate) putMultipart(key string, reader io.Reader, size int64, options *storage.PutOptions) (string, error) {
+	part := s3client.PartSize(size, partSize)
+
+	concurrency := r.options.PartsConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultPartsConcurrency
+	}
+	// 每个工作协程使用内存上限的相等份额，使所有工作协程可以同时传输大对象
+	share := r.memory.limit / int64(max(r.options.Workers, 1))
+	concurrency = int(max(1, min(int64(concurrency), share/part-1)))
+
+	reserved := r.memory.acquire(int64(concurrency+1) * part)
+	defer r.memory.release(reserved)
+
+	return storage.UploadMultipart(r.target, key, reader, size, options, storage.MultipartOptions{
+		PartSize:    part,
+		Concurrency: concurrency,
+	})
+}
+
+// verifyError 复制后校验不一致
+type verifyError struct {
+	key    string
+	reason string
+}
+
+// Error 实现error接口
+func (e *verifyError) Error() string {
+	return i18n.Sprintf("复制校验失败 %s: %s", e.key, e.reason)
+}
+
+// verifyCopy 通过HEAD请求校验目标对象的大小，两端都是整个上传的对象时还比较ETag（内容MD5）
+func (r *Replicate) verifyCopy(source storage.Object, etag string) error {
+	head, err := r.target.Head(source.Key, "")
+	if err != nil {
+		return i18n.Errorf("校验时获取对象信息失败: %w", err)
+	}
+	if head.Size != source.Size {
+		return &verifyError{
+			key:    source.Key,
+			reason: i18n.Sprintf("大小不一致（源 %d，目标 %d）", source.Size, head.Size),
+		}
+	}
+
+	// 分片上传的ETag不是内容MD5（带有"-"后缀），本地目录和WebDAV的ETag也不是，只能校验大小
+	if !isMD5(source.ETag) || !isMD5(etag) {
+		return nil
+	}
+	if !strings.EqualFold(source.ETag, etag) {
+		return &verifyError{
+			key:    source.Key,
+			reason: i18n.Sprintf("ETag不一致（源 %s，目标 %s）", source.ETag, etag),
+		}
+	}
+	return nil
+}
+
+// isMD5 ETag是否为内容的MD5（32个十六进制字符）
+func isMD5(etag string) bool {
+	if len(etag) != 32 {
+		return false
+	}
+	for _, c := range etag {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// sourceError 读取源对象的内容时出错，连接中断等情况下重新复制整个对象
+type sourceError struct {
+	err error
+}
+
+// Error 实现error接口
+func (e *sourceError) Error() string {
+	return i18n.Sprintf("读取源对象失败: %v", e.err)
+}
+
+func (e *sourceError) Unwrap() error {
+	return e.err
+}
+
+// errTooLong 源对象的内容超过读取时的对象长度
+var errTooLong = i18n.New("内容超过对象长度")
+
+// sourceReader 读取源对象的内容，把读取错误包装为 sourceError。
+// 内容与对象长度不一致时返回错误，避免把截断的内容当作完整的对象上传
+type sourceReader struct {
+	body      io.Reader
+	remaining int64 // 还应读取的字节数
+}
+
+func (r *sourceReader) Read(p []byte) (int, error) {
+	n, err := r.body.Read(p)
+	r.remaining -= int64(n)
+	switch {
+	case r.remaining < 0:
+		return n, &sourceError{err: errTooLong}
+	case err == io.EOF && r.remaining > 0:
+		return n, &sourceError{err: io.ErrUnexpectedEOF}
+	case err != nil && err != io.EOF:
+		return n, &sourceError{err: err}
+	}
+	return n, err
+}
+
+// isRetryable 判断是否需要重新复制整个对象。
+// 请求级别的临时错误已由SDK按retry配置重试，读取中断和校验不一致需要重新读取源对象
+func isRetryable(err error) bool {
+	var readErr *sourceError
+	var verifyErr *verifyError
+	return errors.As(err, &readErr) || errors.As(err, &verifyErr)
+}
@@ -0,0 +1,43 @@
+package replicate
+
+import "sync"
+
+// DefaultMaxMemory 未指定时所有工作协程缓冲的数据量上限，足够默认的5个工作协程同时以5个分片并发上传大对象
+const DefaultMaxMemory = 512 << 20
+
+// memoryBudget 限制所有工作协程同时缓冲在内存中的数据量，额度不够时等待其他对象复制完成
+type memoryBudget struct {
+	limit int64
+	used  int64
+	mutex sync.Mutex
+	cond  *sync.Cond
+}
+
+func newMemoryBudget(limit int64) *memoryBudget {
+	if limit <= 0 {
+		limit = DefaultMaxMemory
+	}
+	b := &memoryBudget{limit: limit}
+	b.cond = sync.NewCond(&b.mutex)
+	return b
+}
+
+// acquire 等待n个字节的额度，返回实际占用的额度。n超过上限时按上限占用，即等待其他对象都完成后单独复制
+func (b *memoryBudget) acquire(n int64) int64 {
+	n = min(n, b.limit)
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for b.used+n > b.limit {
+		b.cond.Wait()
+	}
+	b.used += n
+	return n
+}
+
+// release 归还acquire返回的额度
+func (b *memoryBudget) release(n int64) {
+	b.mutex.Lock()
+	b.used -= n
+	b.mutex.Unlock()
+	b.cond.Broadcast()
+}
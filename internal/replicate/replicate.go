@@ -0,0 +1,462 @@
+package replicate
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"objectsync/internal/filter"
+	"objectsync/internal/i18n"
+	"objectsync/internal/logging"
+	"objectsync/internal/progress"
+	"objectsync/internal/s3client"
+	"objectsync/internal/state"
+	"objectsync/internal/statestore"
+	"objectsync/internal/storage"
+)
+
+// logger 复制模块的日志
+var logger = logging.New("replicate")
+
+// Endpoint 复制的一端：连接和桶
+type Endpoint struct {
+	Connection s3client.Options // 连接选项，与上传、备份使用的相同
+	Bucket     string
+	Storage    storage.Storage // 访问桶使用的存储，为nil时按连接选项打开；可以换成其他后端或测试用的实现
+}
+
+// Options 复制配置选项
+type Options struct {
+	Source             Endpoint
+	Target             Endpoint
+	Prefix             string // 只复制该前缀下的对象（以/结尾），目标使用相同的对象键
+	Incremental        bool
+	StateFile          string
+	StateBackend       string        // 状态存储后端（statestore.BackendJSON/BackendBolt），空值使用JSON文件
+	CheckpointFiles    int           // 运行中每复制多少个对象保存一次状态，0表示不按数量保存
+	CheckpointInterval time.Duration // 运行中每隔多久保存一次状态，0表示不按时间保存
+	PruneDeletedAfter  time.Duration // 已从源桶删除的对象的记录在状态中保留的时间，0表示运行成功后立即清理
+	Workers            int
+	PartsConcurrency   int               // 单个大对象同时上传的分片数，0表示使用默认值，内存上限不够时减少
+	MaxMemory          int64             // 所有工作协程缓冲的数据量上限，0表示使用 DefaultMaxMemory
+	MaxAttempts        int               // 单个对象的最大尝试次数
+	RetryDelay         time.Duration     // 首次重试前的等待时间，之后按指数增长
+	Verify             bool              // 复制后通过HEAD校验目标对象的大小和ETag
+	CopyAttributes     bool              // 同时复制对象的标签和ACL（两端都支持时），失败时只记录警告
+	StorageClass       string            // 目标对象使用的存储类别，空表示使用服务端默认值
+	Include            []string          // 包含模式，为空时包含所有对象
+	Exclude            []string          // 排除模式
+	Parent             *progress.Tracker // 汇总多个桶进度的跟踪器，可以为空
+	WorkerProgress     bool              // 在终端中显示每个工作协程的进度，详细模式下不显示
+	Reporter           progress.Reporter // 接收进度事件，设置后不在终端中输出进度，用于嵌入时显示自己的界面
+	Verbose            bool
+}
+
+// Replicate 在两个对象存储之间复制对象。对象内容从源桶读取后直接上传到目标桶，
+// 只在内存中缓冲有限的数据，不写入本地磁盘
+type Replicate struct {
+	options    *Options
+	source     storage.Storage
+	target     storage.Storage
+	state      statestore.Store[state.Entry] // 未启用增量复制时为nil
+	checkpoint *statestore.Checkpoint[state.Entry]
+	progress   *progress.Tracker
+	memory     *memoryBudget
+	vanished   atomic.Int64 // 列出后、复制前从源桶删除的对象数
+	stopped    atomic.Bool
+
+	attributes      bool         // 复制标签和ACL，见 initAttributes
+	aclUnsupported  atomic.Bool  // 目标端不支持设置ACL，不再尝试
+	attributeErrors atomic.Int64 // 复制标签或ACL失败的次数
+}
+
+// New 创建新的复制器
+func New(options *Options) *Replicate {
+	tracker := progress.New(options.Verbose)
+	if options.Parent != nil {
+		tracker.SetParent(options.Parent)
+	}
+	if options.WorkerProgress && !options.Verbose {
+		tracker.ShowWorkers()
+	}
+	if options.Reporter != nil {
+		tracker.SetReporter(options.Reporter)
+	}
+	return &Replicate{
+		options:  options,
+		progress: tracker,
+		memory:   newMemoryBudget(options.MaxMemory),
+	}
+}
+
+// Stop 停止复制，正在复制的对象完成后不再复制新的对象
+func (r *Replicate) Stop() {
+	r.stopped.Store(true)
+}
+
+// Progress 返回本次复制的当前进度
+func (r *Replicate) Progress() progress.Snapshot {
+	return r.progress.Snapshot()
+}
+
+// Stats 返回本次复制的统计
+func (r *Replicate) Stats() progress.Stats {
+	return r.progress.Stats()
+}
+
+// AttributeErrors 返回本次复制中复制标签或ACL失败的次数
+func (r *Replicate) AttributeErrors() int64 {
+	return r.attributeErrors.Load()
+}
+
+// Run 执行复制
+func (r *Replicate) Run() error {
+	// 锁定状态文件，另一个运行正在改写时立即失败
+	lock, err := r.lockState()
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	if err := r.initStorage(); err != nil {
+		return err
+	}
+	r.initAttributes()
+
+	// 确保目标桶存在
+	if err := r.ensureTargetBucket(); err != nil {
+		return i18n.Errorf("确保目标桶存在失败: %w", err)
+	}
+
+	// 加载复制状态
+	if err := r.loadState(); err != nil {
+		return i18n.Errorf("加载复制状态失败: %w", err)
+	}
+	defer r.closeState()
+
+	objects, err := r.listObjects()
+	if err != nil {
+		return i18n.Errorf("列出对象失败: %w", err)
+	}
+	toCopy := r.filterObjects(objects)
+
+	logger.Debugf("发现 %d 个对象", len(objects))
+	logger.Debugf("需要复制 %d 个对象", len(toCopy))
+
+	if len(toCopy) == 0 {
+		logger.Infof("没有需要复制的对象")
+		if err := r.saveDeleted(objects); err != nil {
+			return i18n.Errorf("保存复制状态失败: %w", err)
+		}
+		return nil
+	}
+
+	// 计算总大小并设置进度跟踪
+	var totalSize int64
+	for _, obj := range toCopy {
+		totalSize += obj.Size
+	}
+	r.progress.SetTotal(int64(len(toCopy)), totalSize)
+
+	if err := r.copyObjects(toCopy); err != nil {
+		return i18n.Errorf("复制对象失败: %w", err)
+	}
+
+	// 显示最终统计信息
+	r.progress.AddSkipped(int(r.vanished.Load()))
+	r.progress.PrintFinal()
+
+	if err := r.saveState(objects); err != nil {
+		return i18n.Errorf("保存复制状态失败: %w", err)
+	}
+	return nil
+}
+
+// Pending 列出源桶中的对象并与状态记录比较，返回下次复制需要复制的对象数和数据量，不连接目标
+func (r *Replicate) Pending() (int64, int64, error) {
+	source, err := open(r.options.Source)
+	if err != nil {
+		return 0, 0, i18n.Errorf("初始化源端客户端失败: %w", err)
+	}
+	r.source = source
+	if err := r.loadState(); err != nil {
+		return 0, 0, i18n.Errorf("加载复制状态失败: %w", err)
+	}
+	defer r.closeState()
+
+	objects, err := r.listObjects()
+	if err != nil {
+		return 0, 0, i18n.Errorf("列出对象失败: %w", err)
+	}
+
+	var count, size int64
+	for _, obj := range r.filterObjects(objects) {
+		count++
+		size += obj.Size
+	}
+	return count, size, nil
+}
+
+// initStorage 打开源桶和目标桶
+func (r *Replicate) initStorage() error {
+	source, err := open(r.options.Source)
+	if err != nil {
+		return i18n.Errorf("初始化源端客户端失败: %w", err)
+	}
+	target, err := open(r.options.Target)
+	if err != nil {
+		return i18n.Errorf("初始化目标端客户端失败: %w", err)
+	}
+	r.source, r.target = source, target
+	return nil
+}
+
+// open 返回访问一端的桶的存储，指定了存储时直接使用，否则按端点的类型打开
+func open(endpoint Endpoint) (storage.Storage, error) {
+	if endpoint.Storage != nil {
+		return endpoint.Storage, nil
+	}
+	return storage.Open(endpoint.Connection, endpoint.Bucket)
+}
+
+// ensureTargetBucket 目标桶不存在时创建
+func (r *Replicate) ensureTargetBucket() error {
+	exists, err := r.target.BucketExists()
+	if err != nil {
+		return i18n.Errorf("检查存储桶失败: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	logger.Infof("存储桶 %s 不存在，正在创建...", r.options.Target.Bucket)
+	if err := r.target.CreateBucket(); err != nil {
+		return i18n.Errorf("创建存储桶失败: %w", err)
+	}
+	logger.Infof("存储桶 %s 创建成功", r.options.Target.Bucket)
+	return nil
+}
+
+// lockState 锁定复制状态文件，没有状态文件时不锁定
+func (r *Replicate) lockState() (*statestore.Lock, error) {
+	if r.options.StateFile == "" {
+		return nil, nil
+	}
+	return statestore.Acquire(statestore.Path(r.options.StateFile, r.options.StateBackend))
+}
+
+// loadState 打开复制状态，状态文件不存在时使用空的状态
+func (r *Replicate) loadState() error {
+	if !r.options.Incremental {
+		return nil
+	}
+
+	store, err := state.Open(r.options.StateFile, state.Replicate, r.options.StateBackend)
+	if err != nil {
+		return err
+	}
+	r.state = store
+	r.checkpoint = statestore.NewCheckpoint(store, r.options.CheckpointFiles, r.options.CheckpointInterval)
+	return nil
+}
+
+// closeState 关闭复制状态。运行失败或被停止时先保存已复制完成的对象，下次运行不再重新复制
+func (r *Replicate) closeState() {
+	if r.state == nil {
+		return
+	}
+	if unsaved := r.checkpoint.Unsaved(); unsaved > 0 {
+		if err := r.checkpoint.Flush(); err != nil {
+			logger.Warnf("保存复制状态失败: %v", err)
+		} else {
+			logger.Infof("已保存中断前复制完成的 %d 个对象的状态", unsaved)
+		}
+	}
+	r.state.Close()
+	r.state = nil
+	r.checkpoint = nil
+}
+
+// saveState 记录本次列出的对象中从源桶消失的对象，清理过期的删除记录并保存。
+// 复制完成的对象已经在 markDone 中记录，未能复制的对象保持原来的记录，下次重新复制
+func (r *Replicate) saveState(objects []storage.Object) error {
+	if r.state == nil {
+		return nil
+	}
+
+	if _, err := r.markDeleted(objects); err != nil {
+		return err
+	}
+	if _, err := r.pruneState(); err != nil {
+		return err
+	}
+	r.state.SetLastRun(time.Now())
+	return r.checkpoint.Flush()
+}
+
+// saveDeleted 没有需要复制的对象时只记录从源桶消失的对象并清理过期的删除记录，没有变化时不改写状态
+func (r *Replicate) saveDeleted(objects []storage.Object) error {
+	if r.state == nil {
+		return nil
+	}
+	deleted, err := r.markDeleted(objects)
+	if err != nil {
+		return err
+	}
+	pruned, err := r.pruneState()
+	if err != nil || deleted+pruned == 0 {
+		return err
+	}
+	return r.checkpoint.Flush()
+}
+
+// pruneState 清理超过保留时间的删除记录，返回清理的条目数
+func (r *Replicate) pruneState() (int, error) {
+	pruned, err := state.Prune(r.state, r.options.PruneDeletedAfter)
+	if pruned > 0 {
+		logger.Infof("已从状态中清理 %d 个已删除对象的记录", pruned)
+	}
+	return pruned, err
+}
+
+// markDeleted 把状态中有记录、但本次列出的对象中已经没有的条目标记为已删除，返回新标记的数量。
+// 目标桶中的对象不删除
+func (r *Replicate) markDeleted(objects []storage.Object) (int, error) {
+	listed := make(map[string]bool, len(objects))
+	for _, obj := range objects {
+		listed[obj.Key] = true
+	}
+
+	// 遍历时不能修改存储，先收集消失的对象
+	var deleted []string
+	err := r.state.Range("", func(key string, entry state.Entry) bool {
+		if !listed[key] && !entry.Deleted() {
+			deleted = append(deleted, key)
+		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	for _, key := range deleted {
+		entry, _ := r.state.Get(key)
+		entry.DeletedAt = &now
+		if err := r.state.Put(key, entry); err != nil {
+			return 0, err
+		}
+	}
+	if len(deleted) > 0 {
+		logger.Infof("%d 个对象已从源桶中删除，已在状态中记录", len(deleted))
+	}
+	return len(deleted), nil
+}
+
+// markDone 记录复制完成的对象，定期保存状态
+func (r *Replicate) markDone(obj storage.Object) {
+	if r.checkpoint == nil {
+		return
+	}
+	entry := state.Entry{ETag: obj.ETag, LastModified: obj.LastModified, Size: obj.Size}
+	if err := r.checkpoint.Done(obj.Key, entry); err != nil {
+		// 结束时还会再保存一次，检查点失败不影响本次复制
+		logger.Warnf("保存复制状态失败: %v", err)
+	}
+}
+
+// listObjects 列出源桶中（前缀下）的所有对象
+func (r *Replicate) listObjects() ([]storage.Object, error) {
+	var objects []storage.Object
+	err := r.source.List(r.options.Prefix, func(obj storage.Object) error {
+		objects = append(objects, obj)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// filterObjects 过滤需要复制的对象：匹配包含/排除规则，并且状态中没有相同ETag和大小的记录
+func (r *Replicate) filterObjects(objects []storage.Object) []storage.Object {
+	var toCopy []storage.Object
+	include := filter.New(r.options.Include, r.options.Exclude)
+
+	for _, obj := range objects {
+		if obj.Key == "" || !include.MatchRelative(obj.Key, r.options.Prefix) {
+			continue
+		}
+		if r.state != nil {
+			entry, ok := r.state.Get(obj.Key)
+			if ok && !entry.Deleted() && entry.ETag == obj.ETag && entry.Size == obj.Size {
+				continue
+			}
+		}
+		toCopy = append(toCopy, obj)
+	}
+	return toCopy
+}
+
+// copyObjects 并发复制对象
+func (r *Replicate) copyObjects(objects []storage.Object) error {
+	objectChan := make(chan storage.Object, len(objects))
+	errorChan := make(chan error, r.options.Workers)
+	var wg sync.WaitGroup
+	var failed atomic.Bool // 有工作协程出错后其他工作协程不再开始新的传输
+
+	// 启动工作协程
+	for i := 0; i < r.options.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for obj := range objectChan {
+				if r.stopped.Load() {
+					errorChan <- i18n.Errorf("已停止")
+					return
+				}
+				if failed.Load() {
+					return
+				}
+				copied, err := r.copyObjectWithRetry(obj)
+				if errors.Is(err, storage.ErrNotFound) {
+					// 列出后被删除的对象不再复制，下次运行时记录为已删除
+					logger.Warnf("跳过已从源桶删除的对象: %s", obj.Key)
+					r.vanished.Add(1)
+					continue
+				}
+				if err != nil {
+					errorChan <- i18n.Errorf("复制 %s 失败: %w", obj.Key, err)
+					return
+				}
+				r.markDone(copied)
+			}
+		}()
+	}
+
+	// 发送复制任务
+	go func() {
+		for _, obj := range objects {
+			objectChan <- obj
+		}
+		close(objectChan)
+	}()
+
+	// 等待所有工作完成
+	go func() {
+		wg.Wait()
+		close(errorChan)
+	}()
+
+	// 检查错误，出错后等待正在进行的传输完成，使其记录到状态中
+	var firstErr error
+	for err := range errorChan {
+		if err != nil && firstErr == nil {
+			firstErr = err
+			failed.Store(true)
+		}
+	}
+
+	return firstErr
+}
@@ -0,0 +1,249 @@
+package replicate
+
+import (
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"objectsync/internal/filter"
+	"objectsync/internal/i18n"
+	"objectsync/internal/storage"
+)
+
+// 校验发现的问题类型
+const (
+	ProblemMissing  = "missing"  // 目标桶中没有该对象
+	ProblemExtra    = "extra"    // 目标桶中有源桶中没有的对象
+	ProblemSize     = "size"     // 大小不一致
+	ProblemETag     = "etag"     // 两端都是整个上传的对象，ETag（内容MD5）不一致
+	ProblemMetadata = "metadata" // 用户元数据或HTTP头不一致
+	ProblemTags     = "tags"     // 标签不一致
+	ProblemACL      = "acl"      // 授权不一致
+	ProblemError    = "error"    // 读取对象属性失败
+)
+
+// Mismatch 源桶与目标桶不一致的对象
+type Mismatch struct {
+	Key     string `json:"key"`
+	Problem string `json:"problem"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// VerifyResult 一个桶的校验结果
+type VerifyResult struct {
+	Checked    int        `json:"checked"`
+	Mismatches []Mismatch `json:"mismatches"`
+}
+
+// Verify 列出两端的对象，比较存在性、大小和ETag（两端都是内容MD5时）；attributes为true时
+// 还通过HEAD请求比较两端都存在的对象的用户元数据和HTTP头，两端都支持时比较标签和ACL。
+// 授权给源对象所有者的项视为授权给目标对象的所有者
+func (r *Replicate) Verify(attributes bool) (*VerifyResult, error) {
+	if err := r.initStorage(); err != nil {
+		return nil, err
+	}
+
+	sources, err := r.listObjects()
+	if err != nil {
+		return nil, i18n.Errorf("列出源桶的对象失败: %w", err)
+	}
+	targets := make(map[string]storage.Object)
+	err = r.target.List(r.options.Prefix, func(obj storage.Object) error {
+		targets[obj.Key] = obj
+		return nil
+	})
+	if err != nil {
+		return nil, i18n.Errorf("列出目标桶的对象失败: %w", err)
+	}
+
+	result := &VerifyResult{Mismatches: []Mismatch{}}
+	include := filter.New(r.options.Include, r.options.Exclude)
+	var toCompare []string
+
+	for _, source := range sources {
+		if source.Key == "" || !include.MatchRelative(source.Key, r.options.Prefix) {
+			continue
+		}
+		result.Checked++
+
+		target, ok := targets[source.Key]
+		delete(targets, source.Key)
+		switch {
+		case !ok:
+			result.Mismatches = append(result.Mismatches, Mismatch{Key: source.Key, Problem: ProblemMissing})
+		case target.Size != source.Size:
+			result.Mismatches = append(result.Mismatches, Mismatch{
+				Key:     source.Key,
+				Problem: ProblemSize,
+				Detail:  i18n.Sprintf("源 %d，目标 %d", source.Size, target.Size),
+			})
+		case isMD5(source.ETag) && isMD5(target.ETag) && !strings.EqualFold(source.ETag, target.ETag):
+			result.Mismatches = append(result.Mismatches, Mismatch{
+				Key:     source.Key,
+				Problem: ProblemETag,
+				Detail:  i18n.Sprintf("源 %s，目标 %s", source.ETag, target.ETag),
+			})
+		case attributes:
+			toCompare = append(toCompare, source.Key)
+		}
+	}
+
+	// 目标桶中剩下的对象在源桶中不存在
+	for key := range targets {
+		if key != "" && include.MatchRelative(key, r.options.Prefix) {
+			result.Mismatches = append(result.Mismatches, Mismatch{Key: key, Problem: ProblemExtra})
+		}
+	}
+
+	if len(toCompare) > 0 {
+		result.Mismatches = append(result.Mismatches, r.compareAttributes(toCompare)...)
+	}
+
+	sort.SliceStable(result.Mismatches, func(i, j int) bool {
+		return result.Mismatches[i].Key < result.Mismatches[j].Key
+	})
+	return result, nil
+}
+
+// compareAttributes 并发比较对象两端的元数据、HTTP头、标签和ACL
+func (r *Replicate) compareAttributes(keys []string) []Mismatch {
+	_, sourceOK := r.source.(storage.Attributes)
+	_, targetOK := r.target.(storage.Attributes)
+	withAttributes := sourceOK && targetOK
+
+	keyChan := make(chan string, len(keys))
+	for _, key := range keys {
+		keyChan <- key
+	}
+	close(keyChan)
+
+	var mismatches []Mismatch
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < max(r.options.Workers, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range keyChan {
+				found := r.compareObject(key, withAttributes)
+				mutex.Lock()
+				mismatches = append(mismatches, found...)
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return mismatches
+}
+
+// compareObject 比较单个对象两端的元数据和HTTP头，withAttributes为true时还比较标签和ACL
+func (r *Replicate) compareObject(key string, withAttributes bool) []Mismatch {
+	failed := func(err error) []Mismatch {
+		return []Mismatch{{Key: key, Problem: ProblemError, Detail: err.Error()}}
+	}
+
+	source, err := r.source.Head(key, "")
+	if err != nil {
+		return failed(err)
+	}
+	target, err := r.target.Head(key, "")
+	if err != nil {
+		return failed(err)
+	}
+
+	var mismatches []Mismatch
+	if diff := metadataDiff(source, target); diff != "" {
+		mismatches = append(mismatches, Mismatch{Key: key, Problem: ProblemMetadata, Detail: diff})
+	}
+	if !withAttributes {
+		return mismatches
+	}
+
+	sourceAttributes := r.source.(storage.Attributes)
+	targetAttributes := r.target.(storage.Attributes)
+
+	sourceTags, err := sourceAttributes.GetTags(key)
+	if err != nil {
+		return append(mismatches, failed(err)...)
+	}
+	targetTags, err := targetAttributes.GetTags(key)
+	if err != nil {
+		return append(mismatches, failed(err)...)
+	}
+	if !maps.Equal(sourceTags, targetTags) {
+		mismatches = append(mismatches, Mismatch{
+			Key:     key,
+			Problem: ProblemTags,
+			Detail:  i18n.Sprintf("源 %s，目标 %s", formatTags(sourceTags), formatTags(targetTags)),
+		})
+	}
+
+	sourceACL, err := sourceAttributes.GetACL(key)
+	if err != nil {
+		return append(mismatches, failed(err)...)
+	}
+	targetACL, err := targetAttributes.GetACL(key)
+	if err != nil {
+		return append(mismatches, failed(err)...)
+	}
+	if wanted := sourceACL.WithOwner(targetACL.Owner); !wanted.Equal(targetACL) {
+		mismatches = append(mismatches, Mismatch{
+			Key:     key,
+			Problem: ProblemACL,
+			Detail:  i18n.Sprintf("源 %s，目标 %s", wanted, targetACL),
+		})
+	}
+	return mismatches
+}
+
+// metadataDiff 比较用户元数据（键不区分大小写）和HTTP头，返回不一致的项，一致时返回空字符串
+func metadataDiff(source, target *storage.Object) string {
+	var diffs []string
+	sourceMetadata, targetMetadata := lowerKeys(source.Metadata), lowerKeys(target.Metadata)
+	for _, name := range slices.Sorted(maps.Keys(sourceMetadata)) {
+		if value, ok := targetMetadata[name]; !ok || value != sourceMetadata[name] {
+			diffs = append(diffs, "x-amz-meta-"+name)
+		}
+	}
+	for name := range targetMetadata {
+		if _, ok := sourceMetadata[name]; !ok {
+			diffs = append(diffs, "x-amz-meta-"+name)
+		}
+	}
+
+	headers := []struct {
+		name           string
+		source, target string
+	}{
+		{"Cache-Control", source.Headers.CacheControl, target.Headers.CacheControl},
+		{"Content-Encoding", source.Headers.ContentEncoding, target.Headers.ContentEncoding},
+		{"Content-Type", source.Headers.ContentType, target.Headers.ContentType},
+		{"Content-Disposition", source.Headers.ContentDisposition, target.Headers.ContentDisposition},
+	}
+	for _, header := range headers {
+		if header.source != header.target {
+			diffs = append(diffs, header.name)
+		}
+	}
+	return strings.Join(diffs, ", ")
+}
+
+// lowerKeys 返回键转换为小写的副本，服务端可能改变用户元数据键的大小写
+func lowerKeys(metadata map[string]string) map[string]string {
+	result := make(map[string]string, len(metadata))
+	for name, value := range metadata {
+		result[strings.ToLower(name)] = value
+	}
+	return result
+}
+
+// formatTags 按键的顺序列出标签，用于报告
+func formatTags(tags map[string]string) string {
+	var pairs []string
+	for _, name := range slices.Sorted(maps.Keys(tags)) {
+		pairs = append(pairs, name+"="+tags[name])
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,66 @@
+package s3client
+
+import (
+	"net/url"
+	"strings"
+
+	"objectsync/internal/i18n"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// 可选的S3客户端实现
+const (
+	TransportAWS   = "aws"   // aws-sdk-go v1，默认
+	TransportMinio = "minio" // minio-go，附加校验值使用尾部校验头，小对象的请求开销更小
+)
+
+// NewMinio 使用minio-go创建S3客户端，连接选项与 New 相同。
+// minio-go按自己的退避间隔重试，RetryDelay不生效
+func NewMinio(options Options) (*minio.Core, error) {
+	// 没有协议时与SDK一致按https处理
+	endpoint := options.Endpoint
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	target, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if target.Host == "" || strings.Trim(target.Path, "/") != "" {
+		return nil, i18n.Errorf("minio传输的端点只能包含协议、主机和端口: %s", options.Endpoint)
+	}
+
+	creds := credentials.NewStaticV4(options.AccessKey, options.SecretKey, "")
+	if options.Profile != "" {
+		creds = credentials.NewFileAWSCredentials("", options.Profile)
+	}
+
+	// 指定区域可以避免minio-go先查询桶所在的区域
+	region := options.Region
+	if region == "" {
+		region = DefaultRegion
+	}
+	lookup := minio.BucketLookupPath
+	if options.VirtualHosted {
+		lookup = minio.BucketLookupDNS
+	}
+
+	// minio-go只能设置传输层，速率限制也在传输层实现
+	httpClient, err := NewHTTPClient(options)
+	if err != nil {
+		return nil, err
+	}
+	transport := options.RateLimiter.Transport(httpClient.Transport)
+
+	return minio.NewCore(target.Host, &minio.Options{
+		Creds:           creds,
+		Secure:          target.Scheme == "https",
+		Transport:       transport,
+		Region:          region,
+		BucketLookup:    lookup,
+		TrailingHeaders: true,
+		MaxRetries:      options.MaxAttempts,
+	})
+}
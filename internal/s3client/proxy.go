@@ -0,0 +1,87 @@
+package s3client
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"objectsync/internal/i18n"
+)
+
+// ProxyOptions 访问对象存储时使用的代理，URL为空时使用HTTP_PROXY/HTTPS_PROXY/NO_PROXY环境变量
+type ProxyOptions struct {
+	URL     string   // 代理地址，如 http://proxy:3128
+	NoProxy []string // 不经过代理的主机，支持域名后缀（.example.com）、IP和CIDR
+}
+
+// ParseProxyURL 解析代理地址，支持http、https和socks5
+func ParseProxyURL(value string) (*url.URL, error) {
+	proxyURL, err := url.Parse(value)
+	if err != nil {
+		return nil, i18n.Errorf("代理地址无效: %w", err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, i18n.Errorf("不支持的代理协议: %s（可选值: http, https, socks5）", value)
+	}
+	if proxyURL.Host == "" {
+		return nil, i18n.Errorf("代理地址缺少主机: %s", value)
+	}
+	return proxyURL, nil
+}
+
+// proxyFunc 返回HTTP传输使用的代理选择函数
+func (o ProxyOptions) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	if o.URL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyURL, err := ParseProxyURL(o.URL)
+	if err != nil {
+		return nil, err
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		if o.bypass(req.URL.Hostname()) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}, nil
+}
+
+// ProxyURL 返回访问target时使用的代理，不使用代理时返回nil
+func (o ProxyOptions) ProxyURL(target *url.URL) (*url.URL, error) {
+	proxy, err := o.proxyFunc()
+	if err != nil {
+		return nil, err
+	}
+	return proxy(&http.Request{URL: target})
+}
+
+// bypass 判断主机是否在NoProxy列表中
+func (o ProxyOptions) bypass(host string) bool {
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+	for _, entry := range o.NoProxy {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+			continue
+		case entry == "*":
+			return true
+		case ip != nil && strings.Contains(entry, "/"):
+			if _, network, err := net.ParseCIDR(entry); err == nil && network.Contains(ip) {
+				return true
+			}
+		case strings.HasPrefix(entry, "."):
+			// .example.com 匹配所有子域名和example.com本身
+			if strings.HasSuffix(host, entry) || host == entry[1:] {
+				return true
+			}
+		case host == entry || strings.HasSuffix(host, "."+entry):
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package s3client
+
+import (
+	"time"
+
+	"objectsync/internal/ratelimit"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// MaxRetryDelay 单次重试等待的上限，SDK重试和整个对象的重新上传共用
+const MaxRetryDelay = 2 * time.Minute
+
+// MaxParts 分片上传的分片数量上限
+const MaxParts = 10000
+
+// DefaultRegion 未配置区域时使用的默认值，Ceph通常使用us-east-1
+const DefaultRegion = "us-east-1"
+
+// Options 创建S3客户端的连接选项
+type Options struct {
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+	Profile   string // 共享凭证文件中的配置名，设置后忽略AccessKey和SecretKey
+	Region    string // 签名使用的区域，为空时使用DefaultRegion
+	// VirtualHosted 使用虚拟主机样式寻址（桶名作为域名的一部分），默认使用Ceph需要的路径样式
+	VirtualHosted bool
+	RateLimiter   *ratelimit.Limiter   // 请求速率限制器
+	Bandwidth     *ratelimit.Bandwidth // 带宽限制器
+	MaxAttempts   int                  // 单个请求的最大尝试次数，0表示使用SDK默认值
+	RetryDelay    time.Duration        // 首次重试前的等待时间，之后按指数增长
+	TLS           TLSOptions           // HTTPS证书选项
+	Proxy         ProxyOptions         // 代理设置
+	Timeouts      TimeoutOptions       // HTTP超时设置
+	Transport     string               // 客户端实现，TransportMinio 使用minio-go，其他值使用aws-sdk-go
+}
+
+// New 创建S3客户端
+func New(options Options) (*s3.S3, error) {
+	// 配置了profile时从共享凭证文件读取密钥
+	creds := credentials.NewStaticCredentials(options.AccessKey, options.SecretKey, "")
+	if options.Profile != "" {
+		creds = credentials.NewSharedCredentials("", options.Profile)
+	}
+
+	region := options.Region
+	if region == "" {
+		region = DefaultRegion
+	}
+
+	config := &aws.Config{
+		Endpoint:         aws.String(options.Endpoint),
+		Credentials:      creds,
+		Region:           aws.String(region),
+		S3ForcePathStyle: aws.Bool(!options.VirtualHosted),
+	}
+
+	// 使用自定义的HTTP客户端应用TLS证书、代理和超时设置
+	httpClient, err := NewHTTPClient(options)
+	if err != nil {
+		return nil, err
+	}
+	config.HTTPClient = httpClient
+
+	// 按retry配置重试临时错误（网络错误、5xx、限流）
+	if options.MaxAttempts > 0 {
+		config = request.WithRetryer(config, client.DefaultRetryer{
+			NumMaxRetries:    options.MaxAttempts - 1,
+			MinRetryDelay:    options.RetryDelay,
+			MinThrottleDelay: options.RetryDelay,
+			MaxRetryDelay:    MaxRetryDelay,
+			MaxThrottleDelay: MaxRetryDelay,
+		})
+	}
+
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// 限制请求速率
+	ratelimit.Install(&sess.Handlers, options.RateLimiter)
+
+	return s3.New(sess), nil
+}
+
+// PartSize 返回不小于partSize的分片大小，对象过大时加倍使分片数不超过MaxParts，size小于等于0时不调整
+func PartSize(size, partSize int64) int64 {
+	for size > 0 && (size+partSize-1)/partSize > MaxParts {
+		partSize *= 2
+	}
+	return partSize
+}
+
+// BackoffDelay 计算第attempt次失败后的等待时间：base * 2^(attempt-1)，不超过MaxRetryDelay
+func BackoffDelay(base time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= MaxRetryDelay {
+			return MaxRetryDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,31 @@
+package s3client
+
+import (
+	"cmp"
+	"net"
+	"net/http"
+	"time"
+)
+
+// TimeoutOptions HTTP连接的超时设置，为0的项保持默认行为
+type TimeoutOptions struct {
+	Connect        time.Duration // 建立TCP连接的超时，默认30秒
+	ResponseHeader time.Duration // 发送请求后等待响应头的超时，默认不限制
+	Idle           time.Duration // 空闲连接在连接池中保留的时间，默认90秒
+	Request        time.Duration // 建立连接和等待响应头的超时，未单独设置Connect或ResponseHeader时使用，不限制传输数据的时间
+}
+
+// apply 将超时设置应用到传输层。只限制建立连接和发送完请求后等待响应头的时间，
+// 大文件上传和下载的数据传输不受超时限制
+func (t TimeoutOptions) apply(transport *http.Transport) {
+	if connect := cmp.Or(t.Connect, t.Request); connect > 0 {
+		dialer := &net.Dialer{Timeout: connect, KeepAlive: 30 * time.Second}
+		transport.DialContext = dialer.DialContext
+	}
+	if header := cmp.Or(t.ResponseHeader, t.Request); header > 0 {
+		transport.ResponseHeaderTimeout = header
+	}
+	if t.Idle > 0 {
+		transport.IdleConnTimeout = t.Idle
+	}
+}
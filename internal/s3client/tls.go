@@ -0,0 +1,52 @@
+package s3client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+
+	"objectsync/internal/i18n"
+)
+
+// TLSOptions HTTPS连接的证书选项
+type TLSOptions struct {
+	CAFile             string // 额外信任的CA证书（PEM），与系统证书一起使用
+	InsecureSkipVerify bool   // 不校验服务端证书
+	CertFile           string // 客户端证书（PEM），用于双向认证
+	KeyFile            string // 客户端私钥（PEM）
+}
+
+// isZero 判断是否未设置任何TLS选项
+func (o TLSOptions) isZero() bool {
+	return o == TLSOptions{}
+}
+
+// Config 根据选项构造TLS配置
+func (o TLSOptions) Config() (*tls.Config, error) {
+	config := &tls.Config{InsecureSkipVerify: o.InsecureSkipVerify}
+
+	if o.CAFile != "" {
+		pem, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, i18n.Errorf("读取CA证书失败: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, i18n.Errorf("CA证书文件中没有有效的PEM证书: %s", o.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if o.CertFile != "" || o.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, i18n.Errorf("加载客户端证书失败: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
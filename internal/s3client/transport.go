@@ -0,0 +1,26 @@
+package s3client
+
+import "net/http"
+
+// NewHTTPClient 创建应用了TLS、代理、超时和带宽限制的HTTP客户端，也用于S3以外的HTTP后端
+func NewHTTPClient(options Options) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	proxy, err := options.Proxy.proxyFunc()
+	if err != nil {
+		return nil, err
+	}
+	transport.Proxy = proxy
+
+	if !options.TLS.isZero() {
+		tlsConfig, err := options.TLS.Config()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	options.Timeouts.apply(transport)
+
+	return &http.Client{Transport: options.Bandwidth.Transport(transport)}, nil
+}
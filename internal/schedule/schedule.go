@@ -0,0 +1,163 @@
+package schedule
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"objectsync/internal/i18n"
+)
+
+// macros 常用的预定义表达式
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// field 单个字段的取值范围
+type field struct {
+	name     string
+	min, max int
+}
+
+// fields 标准cron表达式的五个字段：分 时 日 月 周
+var fields = []field{
+	{"分钟", 0, 59},
+	{"小时", 0, 23},
+	{"日期", 1, 31},
+	{"月份", 1, 12},
+	{"星期", 0, 7}, // 0和7都表示周日
+}
+
+// Schedule 解析后的cron表达式
+type Schedule struct {
+	minute, hour, dom, month, dow uint64 // 每一位表示对应的值是否匹配
+	domAny, dowAny                bool   // 日期/星期字段是否为*
+}
+
+// Parse 解析标准的五字段cron表达式（分 时 日 月 周），支持 * , - / 以及 @daily 等预定义表达式
+func Parse(expr string) (*Schedule, error) {
+	text := strings.TrimSpace(expr)
+	if macro, ok := macros[strings.ToLower(text)]; ok {
+		text = macro
+	}
+
+	parts := strings.Fields(text)
+	if len(parts) != len(fields) {
+		return nil, i18n.Errorf("cron表达式应包含5个字段（分 时 日 月 周）: %s", expr)
+	}
+
+	var bits [5]uint64
+	for i, part := range parts {
+		value, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, i18n.Errorf("cron表达式 %s 无效: %w", expr, err)
+		}
+		bits[i] = value
+	}
+
+	// 星期中的7等同于0
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+
+	return &Schedule{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		domAny: parts[2] == "*",
+		dowAny: parts[4] == "*",
+	}, nil
+}
+
+// parseField 解析单个字段，返回匹配值的位图
+func parseField(text string, f field) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(text, ",") {
+		rangeText, stepText, hasStep := strings.Cut(item, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepText); err != nil || step <= 0 {
+				return 0, i18n.Errorf("%s字段的步长无效: %s", i18n.T(f.name), item)
+			}
+		}
+
+		low, high := f.min, f.max
+		if rangeText != "*" {
+			lowText, highText, isRange := strings.Cut(rangeText, "-")
+			var err error
+			if low, err = strconv.Atoi(lowText); err != nil {
+				return 0, i18n.Errorf("%s字段的值无效: %s", i18n.T(f.name), item)
+			}
+			high = low
+			if isRange {
+				if high, err = strconv.Atoi(highText); err != nil {
+					return 0, i18n.Errorf("%s字段的值无效: %s", i18n.T(f.name), item)
+				}
+			} else if hasStep {
+				// 5/15 表示从5开始每15个单位
+				high = f.max
+			}
+		}
+		if low < f.min || high > f.max || low > high {
+			return 0, i18n.Errorf("%s字段超出范围 %d-%d: %s", i18n.T(f.name), f.min, f.max, item)
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << value
+		}
+	}
+	return bits, nil
+}
+
+// Next 返回t之后（不含t所在的分钟）下一次触发的时间，五年内没有匹配时返回零值
+func (s *Schedule) Next(t time.Time) time.Time {
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	limit := next.AddDate(5, 0, 0) // 2月29日等罕见日期最多需要数年
+
+	for next.Before(limit) {
+		if s.month&(1<<uint(next.Month())) == 0 {
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+			continue
+		}
+		if !s.dayMatches(next) {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+			continue
+		}
+		if s.hour&(1<<uint(next.Hour())) == 0 {
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+			continue
+		}
+		if s.minute&(1<<uint(next.Minute())) == 0 {
+			next = next.Add(time.Minute)
+			continue
+		}
+		return next
+	}
+	return time.Time{}
+}
+
+// dayMatches 判断日期是否匹配，日期和星期都有限制时满足其一即可（与cron一致）
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dowMatch
+	case s.dowAny:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
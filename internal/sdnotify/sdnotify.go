@@ -0,0 +1,50 @@
+package sdnotify
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// 常用的状态通知
+const (
+	Ready    = "READY=1"    // 启动完成
+	Stopping = "STOPPING=1" // 开始退出
+	Watchdog = "WATCHDOG=1" // 看门狗心跳
+)
+
+// Notify 向systemd发送状态通知，不是由systemd以 Type=notify 启动时不做任何事
+func Notify(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+	// 以@开头的是抽象命名空间的套接字
+	if socket[0] == '@' {
+		socket = "\x00" + socket[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// WatchdogInterval 返回systemd要求的看门狗超时（WatchdogSec），未启用时返回0。
+// 心跳间隔应小于该值，通常取一半
+func WatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	// 设置了WATCHDOG_PID时只对该进程生效，避免子进程误用
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
@@ -0,0 +1,210 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"objectsync/internal/compress"
+	"objectsync/internal/journal"
+	"objectsync/internal/logging"
+	"objectsync/internal/statestore"
+)
+
+// logger 状态的日志
+var logger = logging.New("state")
+
+// 状态的方向，与配置中桶的 direction 相同
+const (
+	Backup    = "backup"    // 从对象存储下载到本地
+	Upload    = "upload"    // 从本地上传到对象存储
+	Replicate = "replicate" // 从一个对象存储复制到另一个
+)
+
+// LastRunField 状态中上次运行时间的字段名，备份和上传相同
+const LastRunField = "last_run"
+
+// Entry 状态条目，备份和上传使用相同的格式。
+// 备份记录下载时远程对象的属性，复制记录复制时源对象的属性；上传记录上传时本地文件的修改时间和大小，以及上传后对象的ETag
+type Entry struct {
+	ETag         string     `json:"etag"`
+	LastModified time.Time  `json:"last_modified"`
+	Size         int64      `json:"size"`
+	Checksum     string     `json:"checksum,omitempty"`   // 仅上传：附加校验值，格式为 算法:base64值
+	SHA256       string     `json:"sha256,omitempty"`     // 下载或上传时计算的本地文件内容SHA-256（十六进制），目录标记和旧版本的条目为空
+	Pack         string     `json:"pack,omitempty"`       // 仅上传：文件所在的打包对象
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"` // 对象从桶中消失（备份）或本地文件删除（上传）后保留的删除记录，nil表示存在
+}
+
+// Deleted 条目是否为删除记录
+func (e Entry) Deleted() bool {
+	return e.DeletedAt != nil
+}
+
+// Equal 两个条目是否相同，删除记录只比较是否已删除，不比较删除的时间
+func (e Entry) Equal(other Entry) bool {
+	return e.ETag == other.ETag && e.LastModified.Equal(other.LastModified) && e.Size == other.Size &&
+		e.Checksum == other.Checksum && e.SHA256 == other.SHA256 && e.Pack == other.Pack && e.Deleted() == other.Deleted()
+}
+
+// Hash 计算读取内容的SHA-256，返回十六进制字符串
+func Hash(r io.Reader) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// HashFile 计算本地文件内容的SHA-256
+func HashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	return Hash(file)
+}
+
+// FileName 返回桶（及前缀）默认的状态文件名：.<方向>_state_[<remote>@]<桶名>[_<前缀>].json，
+// 桶使用remotes（或clusters）中的连接时加上连接名，以区分不同端点上的同名桶；
+// 设置了前缀时加上前缀以区分同一个桶的多个任务
+func FileName(direction, remote, bucket, prefix string) string {
+	name := bucket
+	if remote != "" {
+		name = remote + remoteSeparator + name
+	}
+	if prefix = strings.Trim(prefix, "/"); prefix != "" {
+		name += "_" + strings.ReplaceAll(prefix, "/", "_")
+	}
+	return fmt.Sprintf(".%s_state_%s.json", direction, name)
+}
+
+// remoteSeparator 默认状态文件名中连接名与桶名的分隔符，桶名中不会出现
+const remoteSeparator = "@"
+
+// Options 返回打开状态存储的选项。旧版本中上次运行时间的字段为 last_backup 或 last_upload
+func Options(direction, backend string) statestore.Options {
+	return statestore.Options{
+		Backend:      backend,
+		LastRunField: LastRunField,
+		LegacyField:  "last_" + direction,
+	}
+}
+
+// Open 打开状态文件，文件不存在时返回空的状态，保存时创建
+func Open(stateFile, direction, backend string) (statestore.Store[Entry], error) {
+	MigrateName(stateFile, backend)
+	return statestore.Open[Entry](stateFile, Options(direction, backend))
+}
+
+// Create 返回空的状态，保存时替换原来的状态，用于重新生成状态
+func Create(stateFile, direction, backend string) (statestore.Store[Entry], error) {
+	return statestore.Create[Entry](stateFile, Options(direction, backend))
+}
+
+// Prune 删除超过age的删除记录，age为0时删除所有删除记录，返回删除的条目数。
+// 不再存在的对象（或本地文件）的条目不会一直留在状态中
+func Prune(store statestore.Store[Entry], age time.Duration) (int, error) {
+	cutoff := time.Now().Add(-age)
+
+	// 遍历时不能修改存储，先收集过期的删除记录
+	var expired []string
+	err := store.Range("", func(key string, entry Entry) bool {
+		if entry.Deleted() && !entry.DeletedAt.After(cutoff) {
+			expired = append(expired, key)
+		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, key := range expired {
+		if err := store.Delete(key); err != nil {
+			return 0, err
+		}
+	}
+	return len(expired), nil
+}
+
+// MigrateName 旧版本上传状态的默认文件名为 .upload_<桶名>_state.json，旧版本的默认文件名也不包含连接名，
+// 使用新文件名的状态文件还不存在时，把旧文件（及运行日志）改为新的文件名
+func MigrateName(stateFile, backend string) {
+	// 改为压缩保存前的未压缩文件也要改名，打开时会读取
+	original, _ := compress.TrimSuffix(stateFile, compress.FromSuffix(stateFile))
+	paths := []string{stateFile, original, statestore.Path(stateFile, backend)}
+	for _, path := range slices.Compact(paths) {
+		if exists(path) {
+			continue
+		}
+		for _, legacy := range legacyNames(path) {
+			if !exists(legacy) {
+				continue
+			}
+			if err := os.Rename(legacy, path); err != nil {
+				logger.Warnf("无法将状态文件 %s 改名为 %s: %v", legacy, path, err)
+				break
+			}
+			logger.Infof("状态文件 %s 已改名为 %s", legacy, path)
+			if exists(journal.Path(legacy)) {
+				os.Rename(journal.Path(legacy), journal.Path(path))
+			}
+			break
+		}
+	}
+}
+
+// legacyNames 返回默认状态文件名在旧版本中可能的文件名，按优先顺序排列
+func legacyNames(path string) []string {
+	var names []string
+	candidates := []string{path}
+	if shared := withoutRemote(path); shared != "" {
+		names = append(names, shared)
+		candidates = append(candidates, shared)
+	}
+	for _, candidate := range candidates {
+		if legacy := legacyName(candidate); legacy != "" {
+			names = append(names, legacy)
+		}
+	}
+	return names
+}
+
+// withoutRemote 返回去掉连接名的默认状态文件名，文件名不包含连接名时返回空
+func withoutRemote(path string) string {
+	dir, base := filepath.Split(path)
+	head, rest, ok := strings.Cut(base, "_state_")
+	if !ok || !strings.HasPrefix(head, ".") {
+		return ""
+	}
+	if _, bucket, ok := strings.Cut(rest, remoteSeparator); ok {
+		return dir + head + "_state_" + bucket
+	}
+	return ""
+}
+
+// legacyName 返回上传状态文件在旧版本中的文件名，不是默认的上传状态文件名时返回空
+func legacyName(path string) string {
+	dir, base := filepath.Split(path)
+	rest, ok := strings.CutPrefix(base, "."+Upload+"_state_")
+	if !ok {
+		return ""
+	}
+	algorithm := compress.FromSuffix(rest)
+	rest, _ = compress.TrimSuffix(rest, algorithm)
+	ext := filepath.Ext(rest)
+	return dir + "." + Upload + "_" + strings.TrimSuffix(rest, ext) + "_state" + ext + compress.Suffix(algorithm)
+}
+
+// exists 文件是否存在
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,293 @@
+package statestore
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"objectsync/internal/i18n"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// 数据库中的桶：files 保存条目（值为JSON），meta 保存上次运行时间等其他字段
+var (
+	filesBucket = []byte("files")
+	metaBucket  = []byte("meta")
+)
+
+// flushSize 累积的修改达到该数量时写入一次，减少事务数，同时限制内存占用
+const flushSize = 10000
+
+// lockTimeout 等待其他进程释放数据库的时间
+const lockTimeout = 10 * time.Second
+
+// boltStore bbolt数据库中的状态：每个条目单独保存，按需读取，修改按批写入
+type boltStore[T any] struct {
+	db      *bolt.DB
+	path    string
+	field   string
+	legacy  string
+	pending map[string][]byte // 尚未写入的修改，值为nil表示删除
+	lastRun time.Time
+	dirty   bool // lastRun 尚未写入
+	mutex   sync.RWMutex
+}
+
+// openBolt 打开数据库，importLegacy为true时新建的数据库导入原来的JSON状态文件
+func openBolt[T any](stateFile string, options Options, importLegacy bool) (*boltStore[T], error) {
+	path := Path(stateFile, BackendBolt)
+	_, statErr := os.Stat(path)
+	created := os.IsNotExist(statErr)
+
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: lockTimeout})
+	if errors.Is(err, bolt.ErrTimeout) {
+		return nil, i18n.Errorf("状态数据库 %s 正被其他进程使用", path)
+	}
+	if err != nil {
+		return nil, i18n.Errorf("无法打开状态数据库 %s: %w", path, err)
+	}
+
+	s := &boltStore[T]{
+		db:      db,
+		path:    path,
+		field:   options.LastRunField,
+		legacy:  options.LegacyField,
+		pending: make(map[string][]byte),
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(filesBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(metaBucket)
+		return err
+	})
+	if err == nil && created && importLegacy && path != stateFile {
+		err = s.importJSON(stateFile)
+	}
+	if err == nil {
+		err = db.View(func(tx *bolt.Tx) error {
+			meta := tx.Bucket(metaBucket)
+			raw := meta.Get([]byte(s.field))
+			if raw == nil && s.legacy != "" {
+				raw = meta.Get([]byte(s.legacy))
+			}
+			if raw != nil {
+				return s.lastRun.UnmarshalText(raw)
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		db.Close()
+		if created {
+			os.Remove(path)
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// reset 删除数据库中的所有条目和上次运行时间
+func (s *boltStore[T]) reset() error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{filesBucket, metaBucket} {
+			if err := tx.DeleteBucket(name); err != nil {
+				return err
+			}
+			if _, err := tx.CreateBucket(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// importJSON 新建数据库时导入原来的JSON状态文件，使切换后端后不需要重新传输所有文件
+func (s *boltStore[T]) importJSON(stateFile string) error {
+	if _, err := os.Stat(stateFile); os.IsNotExist(err) {
+		return nil
+	}
+	old, err := openJSON[json.RawMessage](stateFile, Options{LastRunField: s.field, LegacyField: s.legacy})
+	if err != nil {
+		return i18n.Errorf("导入状态文件 %s 失败: %w", stateFile, err)
+	}
+
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		files := tx.Bucket(filesBucket)
+		for key, raw := range old.files {
+			if err := files.Put([]byte(key), raw); err != nil {
+				return err
+			}
+		}
+		if old.lastRun.IsZero() {
+			return nil
+		}
+		lastRun, err := old.lastRun.MarshalText()
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(metaBucket).Put([]byte(s.field), lastRun)
+	})
+	if err != nil {
+		return err
+	}
+	logger.Infof("已将状态文件 %s 中的 %d 个条目导入 %s", stateFile, len(old.files), s.path)
+	return nil
+}
+
+func (s *boltStore[T]) Get(key string) (T, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var value T
+	raw, ok := s.pending[key]
+	if !ok {
+		s.db.View(func(tx *bolt.Tx) error {
+			// 返回的切片只在事务内有效，解析前复制
+			if data := tx.Bucket(filesBucket).Get([]byte(key)); data != nil {
+				raw = append([]byte(nil), data...)
+			}
+			return nil
+		})
+	}
+	if raw == nil || json.Unmarshal(raw, &value) != nil {
+		return value, false
+	}
+	return value, true
+}
+
+func (s *boltStore[T]) Put(key string, value T) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.pending[key] = raw
+	return s.flushIfFull()
+}
+
+func (s *boltStore[T]) Delete(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.pending[key] = nil
+	return s.flushIfFull()
+}
+
+// flushIfFull 修改达到 flushSize 时写入。调用时需持有锁
+func (s *boltStore[T]) flushIfFull() error {
+	if len(s.pending) < flushSize {
+		return nil
+	}
+	return s.flush()
+}
+
+// flush 在一个事务中写入累积的修改。调用时需持有锁
+func (s *boltStore[T]) flush() error {
+	if len(s.pending) == 0 && !s.dirty {
+		return nil
+	}
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		files := tx.Bucket(filesBucket)
+		for key, raw := range s.pending {
+			var err error
+			if raw == nil {
+				err = files.Delete([]byte(key))
+			} else {
+				err = files.Put([]byte(key), raw)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if !s.dirty {
+			return nil
+		}
+		lastRun, err := s.lastRun.MarshalText()
+		if err != nil {
+			return err
+		}
+		meta := tx.Bucket(metaBucket)
+		if s.legacy != "" {
+			if err := meta.Delete([]byte(s.legacy)); err != nil {
+				return err
+			}
+		}
+		return meta.Put([]byte(s.field), lastRun)
+	})
+	if err != nil {
+		return err
+	}
+	clear(s.pending)
+	s.dirty = false
+	return nil
+}
+
+func (s *boltStore[T]) Range(prefix string, fn func(key string, value T) bool) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.flush(); err != nil {
+		return err
+	}
+	return s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(filesBucket).Cursor()
+		for k, v := cursor.Seek([]byte(prefix)); k != nil && strings.HasPrefix(string(k), prefix); k, v = cursor.Next() {
+			var value T
+			if json.Unmarshal(v, &value) != nil {
+				continue
+			}
+			if !fn(string(k), value) {
+				break
+			}
+		}
+		return nil
+	})
+}
+
+func (s *boltStore[T]) Len() (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.flush(); err != nil {
+		return 0, err
+	}
+	count := 0
+	err := s.db.View(func(tx *bolt.Tx) error {
+		count = tx.Bucket(filesBucket).Stats().KeyN
+		return nil
+	})
+	return count, err
+}
+
+func (s *boltStore[T]) LastRun() time.Time {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.lastRun
+}
+
+func (s *boltStore[T]) SetLastRun(t time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.lastRun = t
+	s.dirty = true
+}
+
+func (s *boltStore[T]) Save() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.flush()
+}
+
+func (s *boltStore[T]) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,94 @@
+package statestore
+
+import (
+	"sync"
+	"time"
+)
+
+// jsonCheckpointRatio JSON后端每次保存都重写整个文件，按数量保存时至少间隔条目总数的这一比例，
+// 条目很多时不会因为频繁重写拖慢传输
+const jsonCheckpointRatio = 10
+
+// Checkpoint 在运行过程中记录完成传输的条目，每完成一定数量或经过一定时间保存一次，
+// 运行在几个小时后失败时不会丢失之前完成的部分
+type Checkpoint[T any] struct {
+	store    Store[T]
+	files    int           // 每完成多少个条目保存一次，0表示不按数量保存
+	interval time.Duration // 距上次保存超过该时间时保存，0表示不按时间保存
+	scale    bool          // 按数量保存的间隔随条目总数增大（JSON后端）
+	unsaved  int
+	saved    time.Time
+	saving   bool // 正在保存，期间完成的条目不再触发保存
+	mutex    sync.Mutex
+}
+
+// NewCheckpoint 创建检查点，files和interval都为0时只在 Flush 时保存。
+// JSON后端按数量保存的间隔不小于条目总数的 1/jsonCheckpointRatio
+func NewCheckpoint[T any](store Store[T], files int, interval time.Duration) *Checkpoint[T] {
+	_, scale := store.(*jsonStore[T])
+	return &Checkpoint[T]{store: store, files: files, interval: interval, scale: scale, saved: time.Now()}
+}
+
+// Done 记录完成传输的条目，达到保存的数量或时间时保存。
+// 保存不持有检查点的锁，其他工作协程可以继续记录完成的条目
+func (c *Checkpoint[T]) Done(key string, value T) error {
+	if err := c.store.Put(key, value); err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	c.unsaved++
+	due := !c.saving && ((c.files > 0 && c.unsaved >= c.threshold()) || (c.interval > 0 && time.Since(c.saved) >= c.interval))
+	if due {
+		c.saving = true
+	}
+	c.mutex.Unlock()
+
+	if !due {
+		return nil
+	}
+	return c.save()
+}
+
+// Flush 保存所有修改，包括 Done 之外直接写入存储的条目
+func (c *Checkpoint[T]) Flush() error {
+	return c.save()
+}
+
+// Unsaved 返回上次保存后完成的条目数
+func (c *Checkpoint[T]) Unsaved() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.unsaved
+}
+
+// threshold 返回按数量保存的间隔。调用时需持有锁
+func (c *Checkpoint[T]) threshold() int {
+	if !c.scale {
+		return c.files
+	}
+	n, _ := c.store.Len()
+	return max(c.files, n/jsonCheckpointRatio)
+}
+
+// save 保存存储并重新计数，保存开始后完成的条目计入下一次
+func (c *Checkpoint[T]) save() error {
+	c.mutex.Lock()
+	c.saving = true
+	unsaved := c.unsaved
+	c.unsaved = 0
+	c.mutex.Unlock()
+
+	err := c.store.Save()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.saving = false
+	if err != nil {
+		c.unsaved += unsaved
+		return err
+	}
+	c.saved = time.Now()
+	return nil
+}
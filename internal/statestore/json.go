@@ -0,0 +1,238 @@
+package statestore
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"objectsync/internal/compress"
+	"objectsync/internal/i18n"
+)
+
+// jsonStore 整个状态保存在一个JSON文件中：{"last_run": ..., "files": {键: 条目}}。
+// 打开时全部加载到内存，保存时整个文件重写；保留文件中其他的字段。
+// 文件名以 .gz 或 .zst 结尾时压缩保存
+type jsonStore[T any] struct {
+	path      string
+	algorithm string // 压缩算法，空表示不压缩
+	field     string
+	legacy    string
+	fields    map[string]json.RawMessage
+	files     map[string]T
+	lastRun   time.Time
+	mutex     sync.RWMutex
+	saveMutex sync.Mutex // 依次写入文件，后生成的内容不会被先生成的覆盖
+}
+
+func newJSON[T any](path string, options Options) *jsonStore[T] {
+	return &jsonStore[T]{
+		path:      path,
+		algorithm: compress.FromSuffix(path),
+		field:     options.LastRunField,
+		legacy:    options.LegacyField,
+		fields:    make(map[string]json.RawMessage),
+		files:     make(map[string]T),
+	}
+}
+
+func openJSON[T any](path string, options Options) (*jsonStore[T], error) {
+	s := newJSON[T](path, options)
+	data, err := readState(path, s.algorithm)
+	if os.IsNotExist(err) && s.algorithm != "" {
+		// 改为压缩保存后第一次运行时读取原来未压缩的文件，保存时写入压缩文件
+		original, _ := compress.TrimSuffix(path, s.algorithm)
+		if data, err = os.ReadFile(original); err == nil {
+			logger.Infof("读取未压缩的状态文件 %s，之后保存到 %s", original, path)
+		}
+	}
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &s.fields); err != nil {
+		return nil, i18n.Errorf("状态文件格式错误: %w", err)
+	}
+	if files, ok := s.fields["files"]; ok && string(files) != "null" {
+		if err := json.Unmarshal(files, &s.files); err != nil {
+			return nil, i18n.Errorf("状态文件格式错误: %w", err)
+		}
+	}
+	if raw, ok := s.fields[s.field]; ok {
+		json.Unmarshal(raw, &s.lastRun)
+	} else if raw, ok := s.fields[s.legacy]; ok && s.legacy != "" {
+		json.Unmarshal(raw, &s.lastRun)
+	}
+	return s, nil
+}
+
+func (s *jsonStore[T]) Get(key string) (T, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	value, ok := s.files[key]
+	return value, ok
+}
+
+func (s *jsonStore[T]) Put(key string, value T) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.files[key] = value
+	return nil
+}
+
+func (s *jsonStore[T]) Delete(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.files, key)
+	return nil
+}
+
+func (s *jsonStore[T]) Range(prefix string, fn func(key string, value T) bool) error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var keys []string
+	for key := range s.files {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if !fn(key, s.files[key]) {
+			break
+		}
+	}
+	return nil
+}
+
+func (s *jsonStore[T]) Len() (int, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.files), nil
+}
+
+func (s *jsonStore[T]) LastRun() time.Time {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.lastRun
+}
+
+func (s *jsonStore[T]) SetLastRun(t time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.lastRun = t
+}
+
+// Save 写回状态文件，先写临时文件再替换，避免中断时损坏。
+// 只在生成内容时持有锁，写入文件期间其他工作协程可以继续读写条目
+func (s *jsonStore[T]) Save() error {
+	s.saveMutex.Lock()
+	defer s.saveMutex.Unlock()
+
+	data, err := s.marshal()
+	if err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	// 临时文件只有所有者可读写，保持原文件的权限
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(s.path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	temp.Chmod(mode)
+
+	err = writeState(temp, append(data, '\n'), s.algorithm)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), s.path)
+}
+
+// marshal 生成状态文件的内容
+func (s *jsonStore[T]) marshal() ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	files, err := json.Marshal(s.files)
+	if err != nil {
+		return nil, err
+	}
+	s.fields["files"] = files
+	if s.field != "" && !s.lastRun.IsZero() {
+		lastRun, err := json.Marshal(s.lastRun)
+		if err != nil {
+			return nil, err
+		}
+		s.fields[s.field] = lastRun
+		delete(s.fields, s.legacy)
+	}
+	return json.MarshalIndent(s.fields, "", "  ")
+}
+
+// readState 读取状态文件，algorithm非空时解压
+func readState(path, algorithm string) ([]byte, error) {
+	if algorithm == "" {
+		return os.ReadFile(path)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader, err := compress.NewReader(file, algorithm)
+	if err != nil {
+		return nil, i18n.Errorf("状态文件格式错误: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, i18n.Errorf("状态文件格式错误: %w", err)
+	}
+	return data, nil
+}
+
+// writeState 写入状态文件的内容，algorithm非空时压缩
+func writeState(w io.Writer, data []byte, algorithm string) error {
+	if algorithm == "" {
+		_, err := w.Write(data)
+		return err
+	}
+	writer, err := compress.NewWriter(w, algorithm)
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+func (s *jsonStore[T]) Close() error {
+	return nil
+}
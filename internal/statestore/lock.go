@@ -0,0 +1,67 @@
+package statestore
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+
+	"objectsync/internal/i18n"
+)
+
+// errLocked 锁已被其他进程（或同一进程中的另一次运行）持有
+var errLocked = errors.New("locked")
+
+// Lock 状态文件的进程间锁（Unix为flock，Windows为LockFileEx），在运行期间持有，
+// 防止两次重叠的运行同时改写同一个状态文件。进程退出时系统自动释放
+type Lock struct {
+	file *os.File
+}
+
+// LockPath 返回状态文件的锁文件。JSON状态保存时整个文件被替换，所以锁加在单独的文件上
+func LockPath(path string) string {
+	return path + ".lock"
+}
+
+// Acquire 锁定状态文件，path为实际保存状态的文件。已被持有时不等待，立即返回错误
+func Acquire(path string) (*Lock, error) {
+	lockPath := LockPath(path)
+	file, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, i18n.Errorf("无法创建锁文件 %s: %w", lockPath, err)
+	}
+
+	if err := lockFile(file); err != nil {
+		file.Close()
+		if !errors.Is(err, errLocked) {
+			return nil, i18n.Errorf("无法锁定状态文件 %s: %w", path, err)
+		}
+		if pid := lockHolder(lockPath); pid != "" {
+			return nil, i18n.Errorf("另一个运行（进程 %s）正持有状态文件 %s 的锁，请等它结束后再运行", pid, path)
+		}
+		return nil, i18n.Errorf("另一个运行正持有状态文件 %s 的锁，请等它结束后再运行", path)
+	}
+
+	// 记录持有锁的进程，便于排查。锁文件不删除，删除后其他进程可能锁定不同的文件
+	file.Truncate(0)
+	file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	return &Lock{file: file}, nil
+}
+
+// Release 释放锁
+func (l *Lock) Release() error {
+	if l == nil {
+		return nil
+	}
+	unlockFile(l.file)
+	return l.file.Close()
+}
+
+// lockHolder 读取锁文件中记录的进程号，无法读取时返回空
+func lockHolder(lockPath string) string {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+package statestore
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockOffset 锁定的字节范围在文件末尾之后，不影响其他进程读取锁文件中的进程号
+const lockOffset = 1 << 30
+
+// lockFile 以非阻塞方式获取文件的排他锁
+func lockFile(file *os.File) error {
+	overlapped := &windows.Overlapped{Offset: lockOffset}
+	err := windows.LockFileEx(windows.Handle(file.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, overlapped)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return errLocked
+	}
+	return err
+}
+
+// unlockFile 释放文件的锁
+func unlockFile(file *os.File) error {
+	overlapped := &windows.Overlapped{Offset: lockOffset}
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, overlapped)
+}
@@ -0,0 +1,116 @@
+package statestore
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"objectsync/internal/compress"
+	"objectsync/internal/i18n"
+	"objectsync/internal/logging"
+)
+
+// logger 状态存储的日志
+var logger = logging.New("state")
+
+// 状态存储后端
+const (
+	BackendJSON = "json"  // 整个状态保存在一个JSON文件中，适合对象数不多的桶
+	BackendBolt = "bbolt" // 嵌入式键值数据库，按键读写，适合有几百万个对象的桶
+)
+
+// Backends 可选的状态存储后端
+var Backends = []string{BackendJSON, BackendBolt}
+
+// boltExt bbolt后端的数据库文件扩展名
+const boltExt = ".db"
+
+// Store 状态存储：对象键到状态条目的映射，以及上次运行的时间。
+// 不同后端的读写方式相同，备份和上传的状态条目类型不同。可以被多个工作协程同时使用
+type Store[T any] interface {
+	// Get 返回键的条目
+	Get(key string) (T, bool)
+	// Put 添加或替换条目，调用 Save 后才保证写入磁盘
+	Put(key string, value T) error
+	// Delete 删除条目，键不存在时不做任何事
+	Delete(key string) error
+	// Range 按键的顺序遍历前缀下的条目，fn返回false时停止。
+	// bbolt后端逐个从数据库读取，不会把所有条目加载到内存；fn中不能修改存储
+	Range(prefix string, fn func(key string, value T) bool) error
+	// Len 返回条目数
+	Len() (int, error)
+	// LastRun 返回上次运行的时间，从未运行过时为零值
+	LastRun() time.Time
+	// SetLastRun 设置上次运行的时间，与条目一起在 Save 时保存
+	SetLastRun(t time.Time)
+	// Save 把修改写入磁盘
+	Save() error
+	// Close 释放存储，未保存的修改丢弃
+	Close() error
+}
+
+// Options 打开状态存储的选项
+type Options struct {
+	Backend      string // json（默认）或 bbolt
+	LastRunField string // 上次运行时间的字段名
+	LegacyField  string // 旧版本中上次运行时间的字段名，读取时兼容，保存时改为 LastRunField
+}
+
+// ValidateBackend 检查后端名称
+func ValidateBackend(backend string) error {
+	switch backend {
+	case "", BackendJSON, BackendBolt:
+		return nil
+	}
+	return i18n.Errorf("无效的状态存储后端: %s（可选 %s）", backend, strings.Join(Backends, "、"))
+}
+
+// Path 返回状态实际保存的文件：JSON后端即配置的状态文件，
+// bbolt后端把扩展名（及压缩后缀）换成 .db，与原来的JSON文件分开，切换后端时可以导入原来的状态
+func Path(stateFile, backend string) string {
+	if backend != BackendBolt || filepath.Ext(stateFile) == boltExt {
+		return stateFile
+	}
+	name, _ := compress.TrimSuffix(stateFile, compress.FromSuffix(stateFile))
+	return strings.TrimSuffix(name, filepath.Ext(name)) + boltExt
+}
+
+// Detect 按文件扩展名判断直接指定的状态文件使用的后端
+func Detect(path string) string {
+	if filepath.Ext(path) == boltExt {
+		return BackendBolt
+	}
+	return BackendJSON
+}
+
+// Open 打开状态文件对应的存储，文件不存在时返回空的存储，保存时创建。
+// 第一次使用bbolt后端时导入原来的JSON状态文件，原文件保留不动
+func Open[T any](stateFile string, options Options) (Store[T], error) {
+	if err := ValidateBackend(options.Backend); err != nil {
+		return nil, err
+	}
+	if options.Backend == BackendBolt {
+		return openBolt[T](stateFile, options, true)
+	}
+	return openJSON[T](stateFile, options)
+}
+
+// Create 返回空的存储，用于重新生成状态：不读取原来的状态，也不导入JSON状态文件，
+// 保存时替换原来的状态
+func Create[T any](stateFile string, options Options) (Store[T], error) {
+	if err := ValidateBackend(options.Backend); err != nil {
+		return nil, err
+	}
+	if options.Backend != BackendBolt {
+		return newJSON[T](stateFile, options), nil
+	}
+	s, err := openBolt[T](stateFile, options, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.reset(); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"slices"
+	"strings"
+)
+
+// Grant 访问控制列表中的一项授权，被授权者是用户（ID）或预定义的组（URI）
+type Grant struct {
+	ID         string `json:"id,omitempty"`
+	URI        string `json:"uri,omitempty"`
+	Permission string `json:"permission"`
+}
+
+// ACL 对象的访问控制列表
+type ACL struct {
+	Owner  string  `json:"owner"`
+	Grants []Grant `json:"grants"`
+}
+
+// Attributes 读写对象的标签和访问控制列表。S3兼容对象存储（S3、Minio）实现了该接口，
+// 本地目录和WebDAV没有这些属性，使用前通过类型断言判断
+type Attributes interface {
+	// GetTags 读取对象的标签，没有标签时返回空的map
+	GetTags(key string) (map[string]string, error)
+	// PutTags 替换对象的标签
+	PutTags(key string, tags map[string]string) error
+	// GetACL 读取对象的所有者和授权
+	GetACL(key string) (*ACL, error)
+	// PutACL 替换对象的授权，acl.Owner需要与对象的所有者相同。客户端不支持时返回 errors.ErrUnsupported
+	PutACL(key string, acl *ACL) error
+}
+
+// WithOwner 返回把授权给owner的项换成授权给newOwner的ACL，所有者也换成newOwner，
+// 用于迁移到用户ID不同的集群
+func (a *ACL) WithOwner(newOwner string) *ACL {
+	result := &ACL{Owner: newOwner, Grants: make([]Grant, len(a.Grants))}
+	for i, grant := range a.Grants {
+		if grant.ID != "" && grant.ID == a.Owner {
+			grant.ID = newOwner
+		}
+		result.Grants[i] = grant
+	}
+	return result
+}
+
+// Equal 两个ACL的所有者和授权是否相同，不考虑授权的顺序
+func (a *ACL) Equal(other *ACL) bool {
+	if a.Owner != other.Owner || len(a.Grants) != len(other.Grants) {
+		return false
+	}
+	return slices.Equal(sortedGrants(a.Grants), sortedGrants(other.Grants))
+}
+
+// String 按"被授权者:权限"的形式列出授权，用于报告
+func (a *ACL) String() string {
+	var grants []string
+	for _, grant := range sortedGrants(a.Grants) {
+		grants = append(grants, grant.ID+grant.URI+":"+grant.Permission)
+	}
+	return strings.Join(grants, ",")
+}
+
+// sortedGrants 返回排序后的授权副本
+func sortedGrants(grants []Grant) []Grant {
+	sorted := slices.Clone(grants)
+	slices.SortFunc(sorted, func(a, b Grant) int {
+		return strings.Compare(a.ID+a.URI+a.Permission, b.ID+b.URI+b.Permission)
+	})
+	return sorted
+}
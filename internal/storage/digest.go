@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// webdavAuth WebDAV请求的认证。不预先发送密码，收到401后按服务端的质询选择认证方式，
+// 优先使用Digest（密码不以明文发送），服务端只支持Basic时使用Basic
+type webdavAuth struct {
+	username string
+	password string
+
+	mutex  sync.Mutex
+	basic  bool
+	digest *digestChallenge
+	count  uint32 // Digest请求计数（nc），同一个nonce每次请求递增
+}
+
+// digestChallenge 服务端的Digest质询
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string // 为空表示服务端不要求qop（RFC 2069兼容）
+}
+
+// authorize 按已选择的认证方式设置请求的认证头
+func (a *webdavAuth) authorize(req *http.Request) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	switch {
+	case a.digest != nil:
+		a.count++
+		req.Header.Set("Authorization", a.digest.authorization(a.username, a.password, req.Method, req.URL.RequestURI(), a.count))
+	case a.basic:
+		req.SetBasicAuth(a.username, a.password)
+	}
+}
+
+// challenge 根据401响应的 WWW-Authenticate 头选择认证方式，返回是否可以重新发送请求
+func (a *webdavAuth) challenge(values []string) bool {
+	if a.username == "" {
+		return false
+	}
+
+	var basic bool
+	var digest *digestChallenge
+	for _, value := range values {
+		scheme, params, _ := strings.Cut(strings.TrimSpace(value), " ")
+		switch {
+		case strings.EqualFold(scheme, "Digest"):
+			if c, ok := parseDigestChallenge(params); ok && digest == nil {
+				digest = c
+			}
+		case strings.EqualFold(scheme, "Basic"):
+			basic = true
+		}
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	switch {
+	case digest != nil:
+		a.digest = digest
+		a.count = 0
+	case basic:
+		a.basic = true
+	default:
+		return false
+	}
+	return true
+}
+
+// parseDigestChallenge 解析Digest质询的参数，不支持的算法和qop返回false
+func parseDigestChallenge(params string) (*digestChallenge, bool) {
+	values := parseAuthParams(params)
+	c := &digestChallenge{
+		realm:     values["realm"],
+		nonce:     values["nonce"],
+		opaque:    values["opaque"],
+		algorithm: values["algorithm"],
+	}
+	if c.nonce == "" || c.hash() == nil {
+		return nil, false
+	}
+	if qop, ok := values["qop"]; ok {
+		for _, option := range strings.Split(qop, ",") {
+			if strings.TrimSpace(option) == "auth" {
+				c.qop = "auth"
+			}
+		}
+		// 只支持auth，auth-int需要对请求体计算摘要
+		if c.qop == "" {
+			return nil, false
+		}
+	}
+	return c, true
+}
+
+// parseAuthParams 解析 key=value 或 key="value" 形式的参数列表，引号中可以有逗号
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+	for {
+		s = strings.TrimLeft(s, " ,")
+		if s == "" {
+			return params
+		}
+		key, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			return params
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		rest = strings.TrimLeft(rest, " ")
+
+		var value string
+		if strings.HasPrefix(rest, "\"") {
+			var b strings.Builder
+			i := 1
+			for ; i < len(rest) && rest[i] != '"'; i++ {
+				if rest[i] == '\\' && i+1 < len(rest) {
+					i++
+				}
+				b.WriteByte(rest[i])
+			}
+			value = b.String()
+			s = rest[min(i+1, len(rest)):]
+		} else {
+			value, s, _ = strings.Cut(rest, ",")
+			value = strings.TrimSpace(value)
+		}
+		params[key] = value
+	}
+}
+
+// hash 返回质询算法使用的摘要函数，不支持的算法返回nil
+func (c *digestChallenge) hash() func() hash.Hash {
+	switch strings.TrimSuffix(strings.ToUpper(c.algorithm), "-SESS") {
+	case "", "MD5":
+		return md5.New
+	case "SHA-256":
+		return sha256.New
+	}
+	return nil
+}
+
+// authorization 生成一次请求的Digest认证头
+func (c *digestChallenge) authorization(username, password, method, uri string, count uint32) string {
+	newHash := c.hash()
+	digest := func(parts ...string) string {
+		h := newHash()
+		h.Write([]byte(strings.Join(parts, ":")))
+		return hex.EncodeToString(h.Sum(nil))
+	}
+
+	cnonce := make([]byte, 16)
+	rand.Read(cnonce)
+	clientNonce := hex.EncodeToString(cnonce)
+	nc := fmt.Sprintf("%08x", count)
+
+	ha1 := digest(username, c.realm, password)
+	if strings.HasSuffix(strings.ToLower(c.algorithm), "-sess") {
+		ha1 = digest(ha1, c.nonce, clientNonce)
+	}
+	ha2 := digest(method, uri)
+
+	var response string
+	if c.qop != "" {
+		response = digest(ha1, c.nonce, nc, clientNonce, c.qop, ha2)
+	} else {
+		response = digest(ha1, c.nonce, ha2)
+	}
+
+	header := fmt.Sprintf(`Digest username=%q, realm=%q, nonce=%q, uri=%q, response=%q`, username, c.realm, c.nonce, uri, response)
+	if c.algorithm != "" {
+		header += ", algorithm=" + c.algorithm
+	}
+	if c.opaque != "" {
+		header += fmt.Sprintf(", opaque=%q", c.opaque)
+	}
+	if c.qop != "" {
+		header += fmt.Sprintf(", qop=%s, nc=%s, cnonce=%q", c.qop, nc, clientNonce)
+	}
+	return header
+}
@@ -0,0 +1,423 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"objectsync/internal/fileattr"
+	"objectsync/internal/i18n"
+)
+
+// LocalScheme 本地目录端点的前缀，如 local:/mnt/usb，桶对应该目录下的同名子目录
+const LocalScheme = "local:"
+
+// localTempPrefix 写入过程中的临时文件和分片目录的名称前缀，列出对象时跳过
+const localTempPrefix = ".objectsync-"
+
+// LocalPath 端点是本地目录时返回目录路径
+func LocalPath(endpoint string) (string, bool) {
+	dir, ok := strings.CutPrefix(endpoint, LocalScheme)
+	return dir, ok && dir != ""
+}
+
+// Local 把本地目录当作桶，对象键对应目录下的相对路径，用于目录之间的同步（如NAS到U盘）。
+// 文件属性保存在文件本身：元数据中的修改时间和权限写入时应用到文件，读取时从文件生成。
+// 空目录列出为目录标记。ETag由修改时间和大小生成（带有"-"，与分片上传的对象一样不与MD5比较），
+// 不读取文件内容就能判断是否变化；不支持附加校验值
+type Local struct {
+	dir  string // 端点目录，桶是其中的子目录
+	root string // 桶目录
+
+	mutex   sync.Mutex
+	uploads map[string]map[string]string // 进行中的分片上传ID -> 完成时应用的元数据
+}
+
+// NewLocal 返回端点目录dir下名为bucket的桶
+func NewLocal(dir, bucket string) *Local {
+	return &Local{
+		dir:     dir,
+		root:    filepath.Join(dir, bucket),
+		uploads: make(map[string]map[string]string),
+	}
+}
+
+func (l *Local) List(prefix string, fn func(Object) error) error {
+	// 只遍历前缀所在的目录
+	start := l.root
+	if dir := path.Dir(prefix); strings.Contains(prefix, "/") && dir != "." {
+		var err error
+		if start, err = l.path(dir); err != nil {
+			return err
+		}
+	}
+
+	var objects []Object
+	err := filepath.WalkDir(start, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if strings.HasPrefix(entry.Name(), localTempPrefix) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if file == l.root || !(entry.IsDir() || entry.Type().IsRegular()) {
+			return nil
+		}
+
+		relative, err := filepath.Rel(l.root, file)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(relative)
+		if entry.IsDir() {
+			// 非空目录由其中的文件体现，只列出空目录
+			key += "/"
+			if empty, err := emptyDir(file); err != nil || !empty {
+				return err
+			}
+		}
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, localObject(key, info))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 目录中的文件按名称排序，与按完整键排序的顺序不同
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Key < objects[j].Key
+	})
+	for _, object := range objects {
+		if err := fn(object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *Local) Head(key, checksumAlgorithm string) (*Object, error) {
+	_, info, err := l.stat(key)
+	if err != nil {
+		return nil, err
+	}
+	object := localObject(key, info)
+	return &object, nil
+}
+
+func (l *Local) Get(key string, progress Progress) (io.ReadCloser, *Object, error) {
+	file, info, err := l.stat(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	object := localObject(key, info)
+	if info.IsDir() {
+		return io.NopCloser(strings.NewReader("")), &object, nil
+	}
+
+	body, err := os.Open(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	return withProgress(body, progress), &object, nil
+}
+
+func (l *Local) Put(key string, body io.ReadSeeker, options *PutOptions) (string, error) {
+	if options == nil {
+		options = &PutOptions{}
+	}
+	file, err := l.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	// 目录标记创建目录
+	if strings.HasSuffix(key, "/") {
+		if err := os.MkdirAll(file, 0755); err != nil {
+			return "", err
+		}
+		return l.finish(file, fileattr.Parse(options.Metadata))
+	}
+
+	return l.write(file, withProgress(io.NopCloser(body), options.Progress), fileattr.Parse(options.Metadata))
+}
+
+func (l *Local) Copy(srcKey, dstKey string, options *PutOptions) (string, error) {
+	src, info, err := l.stat(srcKey)
+	if err != nil {
+		return "", err
+	}
+	dst, err := l.path(dstKey)
+	if err != nil {
+		return "", err
+	}
+
+	// 不替换元数据时保留来源文件的属性
+	attrs := fileattr.FromFileInfo(info)
+	if options != nil {
+		attrs = fileattr.Parse(options.Metadata)
+	}
+
+	body, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	return l.write(dst, body, attrs)
+}
+
+func (l *Local) Delete(key string) error {
+	file, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	// 目录标记只在目录为空时删除，与对象存储中删除目录标记不影响其中的对象相同
+	if strings.HasSuffix(key, "/") {
+		if empty, err := emptyDir(file); err != nil || !empty {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	if err := os.Remove(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (l *Local) CreateMultipart(key string, options *PutOptions) (*Multipart, error) {
+	if options == nil {
+		options = &PutOptions{}
+	}
+	if _, err := l.path(key); err != nil {
+		return nil, err
+	}
+
+	// 分片保存在桶目录下的临时目录中，合并时不需要跨文件系统复制
+	if err := os.MkdirAll(l.root, 0755); err != nil {
+		return nil, err
+	}
+	parts, err := os.MkdirTemp(l.root, localTempPrefix+"multipart-*")
+	if err != nil {
+		return nil, err
+	}
+
+	upload := &Multipart{Key: key, UploadID: filepath.Base(parts)}
+	l.mutex.Lock()
+	l.uploads[upload.UploadID] = options.Metadata
+	l.mutex.Unlock()
+	return upload, nil
+}
+
+func (l *Local) UploadPart(upload *Multipart, number int, body io.ReadSeeker, progress Progress) (Part, error) {
+	file, err := os.Create(l.partPath(upload, number))
+	if err != nil {
+		return Part{}, err
+	}
+	_, err = io.Copy(file, withProgress(io.NopCloser(body), progress))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return Part{}, err
+	}
+	return Part{Number: number, ETag: strconv.Itoa(number)}, nil
+}
+
+func (l *Local) CompleteMultipart(upload *Multipart, parts []Part) (string, error) {
+	l.mutex.Lock()
+	metadata, ok := l.uploads[upload.UploadID]
+	l.mutex.Unlock()
+	if !ok {
+		return "", i18n.Errorf("分片上传不存在: %s", upload.UploadID)
+	}
+
+	file, err := l.path(upload.Key)
+	if err != nil {
+		return "", err
+	}
+
+	// 按顺序把分片合并为一个读取器
+	readers := make([]io.Reader, 0, len(parts))
+	for _, part := range parts {
+		body, err := os.Open(l.partPath(upload, part.Number))
+		if err != nil {
+			return "", err
+		}
+		defer body.Close()
+		readers = append(readers, body)
+	}
+
+	etag, err := l.write(file, io.MultiReader(readers...), fileattr.Parse(metadata))
+	if err != nil {
+		return "", err
+	}
+	l.AbortMultipart(upload)
+	return etag, nil
+}
+
+func (l *Local) AbortMultipart(upload *Multipart) error {
+	l.mutex.Lock()
+	delete(l.uploads, upload.UploadID)
+	l.mutex.Unlock()
+	return os.RemoveAll(filepath.Join(l.root, upload.UploadID))
+}
+
+func (l *Local) BucketExists() (bool, error) {
+	info, err := os.Stat(l.root)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return info.IsDir(), nil
+}
+
+func (l *Local) CreateBucket() error {
+	return os.MkdirAll(l.root, 0755)
+}
+
+func (l *Local) ListBuckets() ([]string, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil, err
+	}
+	var buckets []string
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), localTempPrefix) {
+			buckets = append(buckets, entry.Name())
+		}
+	}
+	return buckets, nil
+}
+
+// path 返回对象键对应的本地路径，键不能指向桶目录之外
+func (l *Local) path(key string) (string, error) {
+	relative := filepath.FromSlash(strings.TrimSuffix(key, "/"))
+	if !filepath.IsLocal(relative) {
+		return "", i18n.Errorf("无效的对象键: %s", key)
+	}
+	return filepath.Join(l.root, relative), nil
+}
+
+// stat 返回对象键对应的本地路径和文件信息，文件不存在或类型与键不符（目录标记对应目录）时返回 ErrNotFound
+func (l *Local) stat(key string) (string, os.FileInfo, error) {
+	file, err := l.path(key)
+	if err != nil {
+		return "", nil, err
+	}
+	info, err := os.Stat(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil, ErrNotFound
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if info.IsDir() != strings.HasSuffix(key, "/") {
+		return "", nil, ErrNotFound
+	}
+	return file, info, nil
+}
+
+// partPath 返回分片的本地路径
+func (l *Local) partPath(upload *Multipart, number int) string {
+	return filepath.Join(l.root, upload.UploadID, strconv.Itoa(number))
+}
+
+// write 先写入同一目录下的临时文件再改名，写入中断时不会留下不完整的文件
+func (l *Local) write(file string, body io.Reader, attrs fileattr.Attrs) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), localTempPrefix+"*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return "", err
+	}
+	return l.finish(file, attrs)
+}
+
+// finish 应用文件属性并返回新的ETag。属性尽量保留，
+// 目标文件系统不支持时（如U盘的FAT文件系统不能设置属主）忽略
+func (l *Local) finish(file string, attrs fileattr.Attrs) (string, error) {
+	fileattr.Apply(file, attrs, time.Time{})
+	info, err := os.Stat(file)
+	if err != nil {
+		return "", err
+	}
+	return localETag(info), nil
+}
+
+// localObject 根据本地文件信息生成对象属性
+func localObject(key string, info os.FileInfo) Object {
+	object := Object{
+		Key:          key,
+		LastModified: info.ModTime(),
+		ETag:         localETag(info),
+		Metadata:     fileattr.FromFileInfo(info).Metadata(),
+	}
+	if !info.IsDir() {
+		object.Size = info.Size()
+	}
+	return object
+}
+
+// localETag 由修改时间和大小生成ETag，文件内容变化时通常两者之一也会变化
+func localETag(info os.FileInfo) string {
+	size := info.Size()
+	if info.IsDir() {
+		size = 0
+	}
+	return fmt.Sprintf("%x-%x", info.ModTime().UnixNano(), size)
+}
+
+// emptyDir 目录中是否没有文件和子目录（写入中的临时文件不计）
+func emptyDir(dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), localTempPrefix) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,418 @@
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/tags"
+)
+
+// emptySHA256 空内容的SHA-256（十六进制）
+const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// Minio 通过minio-go访问S3兼容对象存储中的桶，功能与 S3 相同。附加校验值作为请求头或尾部校验头发送，
+// 桶不存在和没有权限可以区分，单个对象的请求开销更小。minio-go不报告重试次数，不计入进度统计
+type Minio struct {
+	client *minio.Core
+	bucket string
+}
+
+// NewMinio 返回client中名为bucket的桶，client由 s3client.NewMinio 创建
+func NewMinio(client *minio.Core, bucket string) *Minio {
+	return &Minio{client: client, bucket: bucket}
+}
+
+func (m *Minio) List(prefix string, fn func(Object) error) error {
+	// Core.ListObjects 是单页的底层接口，使用 Client 的逐页列出；fn返回错误时取消列出，释放列出协程
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	objects := m.client.Client.ListObjects(ctx, m.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true})
+	for info := range objects {
+		if info.Err != nil {
+			return info.Err
+		}
+		err := fn(Object{
+			Key:          info.Key,
+			Size:         info.Size,
+			LastModified: info.LastModified,
+			ETag:         strings.Trim(info.ETag, "\""),
+			StorageClass: info.StorageClass,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Minio) Head(key, checksumAlgorithm string) (*Object, error) {
+	info, err := m.client.StatObject(context.Background(), m.bucket, key, minio.StatObjectOptions{Checksum: checksumAlgorithm != ""})
+	if err != nil {
+		if minioNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	object := minioObject(key, info)
+	object.Checksum = minioChecksum(info, checksumAlgorithm)
+	return &object, nil
+}
+
+func (m *Minio) Get(key string, progress Progress) (io.ReadCloser, *Object, error) {
+	// 保留对象的原始编码，避免HTTP客户端自动解压gzip对象
+	options := minio.GetObjectOptions{}
+	options.Set("Accept-Encoding", "identity")
+
+	body, info, _, err := m.client.GetObject(context.Background(), m.bucket, key, options)
+	if err != nil {
+		if minioNotFound(err) {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, err
+	}
+	object := minioObject(key, info)
+	return withProgress(body, progress), &object, nil
+}
+
+func (m *Minio) Put(key string, body io.ReadSeeker, options *PutOptions) (string, error) {
+	if options == nil {
+		options = &PutOptions{}
+	}
+	size, err := remaining(body)
+	if err != nil {
+		return "", err
+	}
+
+	putOptions := minioPutOptions(options)
+	if options.Checksum != "" {
+		putOptions.UserMetadata[checksumHeader(options.ChecksumAlgorithm)] = options.Checksum
+	}
+	if options.Progress != nil {
+		putOptions.Progress = progressHook{options.Progress}
+	}
+	// 空对象（如目录标记）使用流式签名时minio-go会以chunked编码发送，AWS等服务不接受，改为直接签名空内容
+	var sha256Hex string
+	if size == 0 {
+		putOptions.DisableContentSha256 = true
+		sha256Hex = emptySHA256
+	}
+
+	// Core.PutObject 总是使用单个请求，大文件由调用方分片上传
+	info, err := m.client.PutObject(context.Background(), m.bucket, key, body, size, "", sha256Hex, putOptions)
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(info.ETag, "\""), nil
+}
+
+func (m *Minio) Copy(srcKey, dstKey string, options *PutOptions) (string, error) {
+	// Core.CopyObject 直接使用给出的请求头
+	headers := make(map[string]string)
+	if options != nil {
+		// 替换元数据时HTTP头也需要重新设置，附加校验值由服务端重新计算
+		headers["x-amz-metadata-directive"] = "REPLACE"
+		for name, value := range options.Metadata {
+			headers["x-amz-meta-"+name] = value
+		}
+		for name, value := range options.Headers.header() {
+			headers[name] = value
+		}
+		if options.StorageClass != "" {
+			headers["x-amz-storage-class"] = options.StorageClass
+		}
+		if options.ChecksumAlgorithm != "" {
+			headers["x-amz-checksum-algorithm"] = options.ChecksumAlgorithm
+		}
+	}
+
+	info, err := m.client.CopyObject(context.Background(), m.bucket, srcKey, m.bucket, dstKey, headers, minio.CopySrcOptions{}, minio.PutObjectOptions{})
+	if err != nil {
+		if minioNotFound(err) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return strings.Trim(info.ETag, "\""), nil
+}
+
+func (m *Minio) Delete(key string) error {
+	return m.client.RemoveObject(context.Background(), m.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (m *Minio) CreateMultipart(key string, options *PutOptions) (*Multipart, error) {
+	if options == nil {
+		options = &PutOptions{}
+	}
+	putOptions := minioPutOptions(options)
+	if options.ChecksumAlgorithm != "" {
+		putOptions.UserMetadata["x-amz-checksum-algorithm"] = options.ChecksumAlgorithm
+	}
+
+	uploadID, err := m.client.NewMultipartUpload(context.Background(), m.bucket, key, putOptions)
+	if err != nil {
+		return nil, err
+	}
+	return &Multipart{
+		Key:               key,
+		UploadID:          uploadID,
+		ChecksumAlgorithm: options.ChecksumAlgorithm,
+	}, nil
+}
+
+func (m *Minio) UploadPart(upload *Multipart, number int, body io.ReadSeeker, progress Progress) (Part, error) {
+	size, err := remaining(body)
+	if err != nil {
+		return Part{}, err
+	}
+
+	// 开始分片上传时指定了算法时，每个分片都要带上校验值
+	var partOptions minio.PutObjectPartOptions
+	var checksum string
+	if upload.ChecksumAlgorithm != "" {
+		if checksum, err = readerChecksum(body, upload.ChecksumAlgorithm); err != nil {
+			return Part{}, err
+		}
+		partOptions.CustomHeader = http.Header{checksumHeader(upload.ChecksumAlgorithm): {checksum}}
+	}
+
+	var reader io.Reader = body
+	if progress != nil {
+		reader = &progressSeeker{ReadSeeker: body, progress: progress}
+	}
+	part, err := m.client.PutObjectPart(context.Background(), m.bucket, upload.Key, upload.UploadID, number, reader, size, partOptions)
+	if err != nil {
+		return Part{}, err
+	}
+	return Part{Number: number, ETag: part.ETag, Checksum: checksum}, nil
+}
+
+func (m *Minio) CompleteMultipart(upload *Multipart, parts []Part) (string, error) {
+	completed := make([]minio.CompletePart, 0, len(parts))
+	for _, part := range parts {
+		completedPart := minio.CompletePart{PartNumber: part.Number, ETag: part.ETag}
+		switch upload.ChecksumAlgorithm {
+		case ChecksumCRC32:
+			completedPart.ChecksumCRC32 = part.Checksum
+		case ChecksumCRC32C:
+			completedPart.ChecksumCRC32C = part.Checksum
+		case ChecksumSHA1:
+			completedPart.ChecksumSHA1 = part.Checksum
+		case ChecksumSHA256:
+			completedPart.ChecksumSHA256 = part.Checksum
+		}
+		completed = append(completed, completedPart)
+	}
+
+	info, err := m.client.CompleteMultipartUpload(context.Background(), m.bucket, upload.Key, upload.UploadID, completed, minio.PutObjectOptions{})
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(info.ETag, "\""), nil
+}
+
+func (m *Minio) AbortMultipart(upload *Multipart) error {
+	return m.client.AbortMultipartUpload(context.Background(), m.bucket, upload.Key, upload.UploadID)
+}
+
+func (m *Minio) BucketExists() (bool, error) {
+	return m.client.BucketExists(context.Background(), m.bucket)
+}
+
+func (m *Minio) CreateBucket() error {
+	return m.client.MakeBucket(context.Background(), m.bucket, minio.MakeBucketOptions{})
+}
+
+func (m *Minio) ListBuckets() ([]string, error) {
+	buckets, err := m.client.ListBuckets(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(buckets))
+	for _, bucket := range buckets {
+		names = append(names, bucket.Name)
+	}
+	return names, nil
+}
+
+// minioObject 根据minio-go返回的对象信息生成对象属性
+func minioObject(key string, info minio.ObjectInfo) Object {
+	return Object{
+		Key:          key,
+		Size:         info.Size,
+		LastModified: info.LastModified,
+		ETag:         strings.Trim(info.ETag, "\""),
+		StorageClass: info.StorageClass,
+		Metadata:     info.UserMetadata,
+		Headers: Headers{
+			CacheControl:       info.Metadata.Get("Cache-Control"),
+			ContentEncoding:    info.Metadata.Get("Content-Encoding"),
+			ContentType:        info.ContentType,
+			ContentDisposition: info.Metadata.Get("Content-Disposition"),
+		},
+	}
+}
+
+// minioPutOptions 把上传选项转换为minio-go的选项，用户元数据复制一份以便加入校验头
+func minioPutOptions(options *PutOptions) minio.PutObjectOptions {
+	metadata := make(map[string]string, len(options.Metadata)+1)
+	for name, value := range options.Metadata {
+		metadata[name] = value
+	}
+	return minio.PutObjectOptions{
+		UserMetadata:       metadata,
+		CacheControl:       options.Headers.CacheControl,
+		ContentEncoding:    options.Headers.ContentEncoding,
+		ContentType:        options.Headers.ContentType,
+		ContentDisposition: options.Headers.ContentDisposition,
+		StorageClass:       options.StorageClass,
+	}
+}
+
+// minioChecksum 从对象信息中取出指定算法的校验值
+func minioChecksum(info minio.ObjectInfo, algorithm string) string {
+	switch algorithm {
+	case ChecksumCRC32:
+		return info.ChecksumCRC32
+	case ChecksumCRC32C:
+		return info.ChecksumCRC32C
+	case ChecksumSHA1:
+		return info.ChecksumSHA1
+	case ChecksumSHA256:
+		return info.ChecksumSHA256
+	}
+	return ""
+}
+
+// minioNotFound 错误是否表示对象不存在
+func minioNotFound(err error) bool {
+	code := minio.ToErrorResponse(err).Code
+	return code == "NoSuchKey" || code == "NotFound"
+}
+
+// header 返回非空的HTTP头
+func (h Headers) header() map[string]string {
+	headers := make(map[string]string)
+	for name, value := range map[string]string{
+		"Cache-Control":       h.CacheControl,
+		"Content-Encoding":    h.ContentEncoding,
+		"Content-Type":        h.ContentType,
+		"Content-Disposition": h.ContentDisposition,
+	} {
+		if value != "" {
+			headers[name] = value
+		}
+	}
+	return headers
+}
+
+// checksumHeader 返回附加校验值的请求头名称
+func checksumHeader(algorithm string) string {
+	return "x-amz-checksum-" + strings.ToLower(algorithm)
+}
+
+// readerChecksum 计算读取器从当前位置到末尾内容的附加校验值（base64），计算后回到原来的位置
+func readerChecksum(body io.ReadSeeker, algorithm string) (string, error) {
+	start, err := body.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", err
+	}
+	h := NewChecksumHash(algorithm)
+	if _, err := io.Copy(h, body); err != nil {
+		return "", err
+	}
+	if _, err := body.Seek(start, io.SeekStart); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+// remaining 返回读取器从当前位置到末尾的字节数，位置保持不变
+func remaining(body io.ReadSeeker) (int64, error) {
+	start, err := body.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	end, err := body.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := body.Seek(start, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return end - start, nil
+}
+
+// progressHook 把minio-go对进度读取器的调用（参数为刚发送的数据）计入进度
+type progressHook struct {
+	progress Progress
+}
+
+func (h progressHook) Read(p []byte) (int, error) {
+	h.progress.Add(int64(len(p)))
+	return len(p), nil
+}
+
+// progressSeeker 读取的字节数计入进度，可以重新定位以便minio-go重试
+type progressSeeker struct {
+	io.ReadSeeker
+	progress Progress
+}
+
+func (r *progressSeeker) Read(p []byte) (int, error) {
+	n, err := r.ReadSeeker.Read(p)
+	if n > 0 {
+		r.progress.Add(int64(n))
+	}
+	return n, err
+}
+
+func (m *Minio) GetTags(key string) (map[string]string, error) {
+	result, err := m.client.GetObjectTagging(context.Background(), m.bucket, key, minio.GetObjectTaggingOptions{})
+	if err != nil {
+		if minioNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return result.ToMap(), nil
+}
+
+func (m *Minio) PutTags(key string, tagMap map[string]string) error {
+	objectTags, err := tags.MapToObjectTags(tagMap)
+	if err != nil {
+		return err
+	}
+	err = m.client.PutObjectTagging(context.Background(), m.bucket, key, objectTags, minio.PutObjectTaggingOptions{})
+	if minioNotFound(err) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (m *Minio) GetACL(key string) (*ACL, error) {
+	info, err := m.client.GetObjectACL(context.Background(), m.bucket, key)
+	if err != nil {
+		if minioNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	// minio-go解析所有者时把ID和显示名称弄反了
+	acl := &ACL{Owner: info.Owner.DisplayName, Grants: make([]Grant, 0, len(info.Grant))}
+	for _, grant := range info.Grant {
+		acl.Grants = append(acl.Grants, Grant{ID: grant.Grantee.ID, URI: grant.Grantee.URI, Permission: grant.Permission})
+	}
+	return acl, nil
+}
+
+// PutACL minio-go没有设置对象ACL的接口
+func (m *Minio) PutACL(key string, acl *ACL) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"sync"
+
+	"objectsync/internal/s3client"
+)
+
+// defaultPartSize 未指定时的分片大小
+const defaultPartSize = 16 << 20
+
+// defaultConcurrency 未指定时单个对象同时上传的分片数
+const defaultConcurrency = 5
+
+// MultipartOptions 分片上传的分片大小和并发数
+type MultipartOptions struct {
+	PartSize    int64 // 分片大小，0表示使用默认值，对象过大时自动加倍以满足分片数量上限
+	Concurrency int   // 同时上传的分片数，0表示使用默认值
+	// Started 开始分片上传后调用，可以为nil。调用方可以记录上传ID，进程中断后放弃遗留的分片
+	Started func(upload *Multipart)
+}
+
+// UploadMultipart 把body分片并发上传为一个对象，返回对象的ETag。
+// size为-1表示长度未知（如标准输入），按分片大小依次读取到内存；
+// body实现了io.ReaderAt且长度已知时直接按区间读取，不复制到内存。
+// 内容不超过一个分片时改为普通上传，出错时放弃分片上传
+func UploadMultipart(s Storage, key string, body io.Reader, size int64, options *PutOptions, multipart MultipartOptions) (string, error) {
+	partSize := multipart.PartSize
+	if partSize <= 0 {
+		partSize = defaultPartSize
+	}
+	partSize = s3client.PartSize(size, partSize)
+	concurrency := multipart.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	if options == nil {
+		options = &PutOptions{}
+	}
+
+	parts := newPartReader(body, size, partSize)
+	first, err := parts.next()
+	if err != nil {
+		return "", err
+	}
+	if parts.done {
+		return s.Put(key, first.body, options)
+	}
+
+	upload, err := s.CreateMultipart(key, options)
+	if err != nil {
+		return "", err
+	}
+	if multipart.Started != nil {
+		multipart.Started(upload)
+	}
+
+	var (
+		completed []Part
+		firstErr  error
+		mutex     sync.Mutex
+		wg        sync.WaitGroup
+	)
+	jobs := make(chan filePart)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				part, err := s.UploadPart(upload, job.number, job.body, options.Progress)
+				parts.release(job)
+
+				mutex.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				completed = append(completed, part)
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	// 按顺序读取分片交给工作协程，出错后不再读取
+	for job := first; ; {
+		jobs <- job
+		mutex.Lock()
+		failed := firstErr != nil
+		mutex.Unlock()
+		if failed || parts.done {
+			break
+		}
+		if job, err = parts.next(); err != nil {
+			mutex.Lock()
+			firstErr = err
+			mutex.Unlock()
+			break
+		}
+		if job.size == 0 {
+			// 长度未知的内容恰好在分片边界结束
+			parts.release(job)
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		s.AbortMultipart(upload)
+		return "", firstErr
+	}
+
+	sort.Slice(completed, func(i, j int) bool {
+		return completed[i].Number < completed[j].Number
+	})
+	etag, err := s.CompleteMultipart(upload, completed)
+	if err != nil {
+		s.AbortMultipart(upload)
+		return "", err
+	}
+	return etag, nil
+}
+
+// filePart 待上传的分片
+type filePart struct {
+	number int
+	body   io.ReadSeeker
+	size   int64
+	buffer []byte // 从内存缓冲区读取时使用的缓冲区，上传后放回缓冲池
+}
+
+// partReader 依次切分分片
+type partReader struct {
+	body     io.Reader
+	at       io.ReaderAt // 长度已知且可以按区间读取时不为nil
+	size     int64
+	partSize int64
+	offset   int64
+	number   int
+	done     bool // 已读到最后一个分片
+	buffers  sync.Pool
+}
+
+func newPartReader(body io.Reader, size, partSize int64) *partReader {
+	r := &partReader{body: body, size: size, partSize: partSize}
+	if at, ok := body.(io.ReaderAt); ok && size >= 0 {
+		r.at = at
+	}
+	r.buffers.New = func() any {
+		return make([]byte, partSize)
+	}
+	return r
+}
+
+// next 返回下一个分片，长度未知的内容读完时返回大小为0的分片
+func (r *partReader) next() (filePart, error) {
+	r.number++
+	if r.at != nil {
+		n := min(r.partSize, r.size-r.offset)
+		part := filePart{number: r.number, body: io.NewSectionReader(r.at, r.offset, n), size: n}
+		r.offset += n
+		r.done = r.offset >= r.size
+		return part, nil
+	}
+
+	buffer := r.buffers.Get().([]byte)
+	n, err := io.ReadFull(r.body, buffer)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		r.done = true
+	} else if err != nil {
+		r.buffers.Put(buffer)
+		return filePart{}, err
+	}
+	r.offset += int64(n)
+	return filePart{number: r.number, body: bytes.NewReader(buffer[:n]), size: int64(n), buffer: buffer}, nil
+}
+
+// release 分片上传结束后放回缓冲区
+func (r *partReader) release(part filePart) {
+	if part.buffer != nil {
+		r.buffers.Put(part.buffer)
+	}
+}
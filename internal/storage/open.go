@@ -0,0 +1,51 @@
+package storage
+
+import "objectsync/internal/s3client"
+
+// Open 按端点的类型返回访问桶的存储：local:目录 使用本地目录，webdav:URL 使用WebDAV服务，
+// 其他端点使用S3兼容对象存储，按连接选项中的Transport选择aws-sdk-go或minio-go。
+// WebDAV使用连接选项中的密钥作为用户名和密码
+func Open(options s3client.Options, bucket string) (Storage, error) {
+	if dir, ok := LocalPath(options.Endpoint); ok {
+		return NewLocal(dir, bucket), nil
+	}
+
+	if endpoint, ok := WebDAVURL(options.Endpoint); ok {
+		httpClient, err := s3client.NewHTTPClient(options)
+		if err != nil {
+			return nil, err
+		}
+		webdav, err := NewWebDAV(httpClient, endpoint, bucket, WebDAVOptions{
+			Username:    options.AccessKey,
+			Password:    options.SecretKey,
+			RateLimiter: options.RateLimiter,
+			MaxAttempts: options.MaxAttempts,
+			RetryDelay:  options.RetryDelay,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return webdav, nil
+	}
+
+	if options.Transport == s3client.TransportMinio {
+		client, err := s3client.NewMinio(options)
+		if err != nil {
+			return nil, err
+		}
+		return NewMinio(client, bucket), nil
+	}
+
+	client, err := s3client.New(options)
+	if err != nil {
+		return nil, err
+	}
+	return NewS3(client, bucket), nil
+}
+
+// IsS3 端点是否为S3兼容对象存储，而不是本地目录或WebDAV服务
+func IsS3(endpoint string) bool {
+	_, local := LocalPath(endpoint)
+	_, webdav := WebDAVURL(endpoint)
+	return !local && !webdav
+}
@@ -0,0 +1,487 @@
+package storage
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// S3 通过aws-sdk-go访问S3兼容对象存储中的桶
+type S3 struct {
+	client *s3.S3
+	bucket string
+}
+
+// NewS3 返回client中名为bucket的桶，client的重试、限速等设置由 s3client.New 配置
+func NewS3(client *s3.S3, bucket string) *S3 {
+	return &S3{client: client, bucket: bucket}
+}
+
+func (s *S3) List(prefix string, fn func(Object) error) error {
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}
+
+	for {
+		result, err := s.client.ListObjectsV2(input)
+		if err != nil {
+			return err
+		}
+
+		for _, obj := range result.Contents {
+			err := fn(Object{
+				Key:          aws.StringValue(obj.Key),
+				Size:         aws.Int64Value(obj.Size),
+				LastModified: aws.TimeValue(obj.LastModified),
+				ETag:         trimETag(obj.ETag),
+				StorageClass: aws.StringValue(obj.StorageClass),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if !aws.BoolValue(result.IsTruncated) {
+			return nil
+		}
+		input.ContinuationToken = result.NextContinuationToken
+	}
+}
+
+func (s *S3) Head(key, checksumAlgorithm string) (*Object, error) {
+	input := &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}
+	if checksumAlgorithm != "" {
+		input.ChecksumMode = aws.String(s3.ChecksumModeEnabled)
+	}
+
+	head, err := s.client.HeadObject(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NotFound" {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &Object{
+		Key:          key,
+		Size:         aws.Int64Value(head.ContentLength),
+		LastModified: aws.TimeValue(head.LastModified),
+		ETag:         trimETag(head.ETag),
+		StorageClass: aws.StringValue(head.StorageClass),
+		Metadata:     aws.StringValueMap(head.Metadata),
+		Headers: Headers{
+			CacheControl:       aws.StringValue(head.CacheControl),
+			ContentEncoding:    aws.StringValue(head.ContentEncoding),
+			ContentType:        aws.StringValue(head.ContentType),
+			ContentDisposition: aws.StringValue(head.ContentDisposition),
+		},
+		Checksum: headChecksum(head, checksumAlgorithm),
+	}, nil
+}
+
+func (s *S3) Get(key string, progress Progress) (io.ReadCloser, *Object, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}
+
+	// 保留对象的原始编码，避免HTTP客户端自动解压gzip对象
+	result, err := s.client.GetObjectWithContext(aws.BackgroundContext(), input, identityEncoding, countRetries(progress))
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, err
+	}
+	return withProgress(result.Body, progress), &Object{
+		Key:          key,
+		Size:         aws.Int64Value(result.ContentLength),
+		LastModified: aws.TimeValue(result.LastModified),
+		ETag:         trimETag(result.ETag),
+		StorageClass: aws.StringValue(result.StorageClass),
+		Metadata:     aws.StringValueMap(result.Metadata),
+		Headers: Headers{
+			CacheControl:       aws.StringValue(result.CacheControl),
+			ContentEncoding:    aws.StringValue(result.ContentEncoding),
+			ContentType:        aws.StringValue(result.ContentType),
+			ContentDisposition: aws.StringValue(result.ContentDisposition),
+		},
+	}, nil
+}
+
+func (s *S3) Put(key string, body io.ReadSeeker, options *PutOptions) (string, error) {
+	if options == nil {
+		options = &PutOptions{}
+	}
+	input := &s3.PutObjectInput{
+		Bucket:             aws.String(s.bucket),
+		Key:                aws.String(key),
+		Body:               body,
+		Metadata:           aws.StringMap(options.Metadata),
+		CacheControl:       optional(options.Headers.CacheControl),
+		ContentEncoding:    optional(options.Headers.ContentEncoding),
+		ContentType:        optional(options.Headers.ContentType),
+		ContentDisposition: optional(options.Headers.ContentDisposition),
+		StorageClass:       optional(options.StorageClass),
+	}
+	if options.Checksum != "" {
+		setChecksum(input, options.ChecksumAlgorithm, options.Checksum)
+	}
+
+	output, err := s.client.PutObjectWithContext(aws.BackgroundContext(), input, sendProgress(options.Progress))
+	if err != nil {
+		return "", err
+	}
+	return trimETag(output.ETag), nil
+}
+
+func (s *S3) Copy(srcKey, dstKey string, options *PutOptions) (string, error) {
+	input := &s3.CopyObjectInput{
+		Bucket:     aws.String(s.bucket),
+		Key:        aws.String(dstKey),
+		CopySource: aws.String(copySource(s.bucket, srcKey)),
+	}
+	if options != nil {
+		// 替换元数据时HTTP头也需要重新设置，附加校验值由服务端重新计算
+		input.MetadataDirective = aws.String(s3.MetadataDirectiveReplace)
+		input.Metadata = aws.StringMap(options.Metadata)
+		input.CacheControl = optional(options.Headers.CacheControl)
+		input.ContentEncoding = optional(options.Headers.ContentEncoding)
+		input.ContentType = optional(options.Headers.ContentType)
+		input.ContentDisposition = optional(options.Headers.ContentDisposition)
+		input.StorageClass = optional(options.StorageClass)
+		input.ChecksumAlgorithm = optional(options.ChecksumAlgorithm)
+	}
+
+	output, err := s.client.CopyObject(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	if output.CopyObjectResult == nil {
+		return "", nil
+	}
+	return trimETag(output.CopyObjectResult.ETag), nil
+}
+
+func (s *S3) Delete(key string) error {
+	_, err := s.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+func (s *S3) CreateMultipart(key string, options *PutOptions) (*Multipart, error) {
+	if options == nil {
+		options = &PutOptions{}
+	}
+	output, err := s.client.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+		Bucket:             aws.String(s.bucket),
+		Key:                aws.String(key),
+		Metadata:           aws.StringMap(options.Metadata),
+		CacheControl:       optional(options.Headers.CacheControl),
+		ContentEncoding:    optional(options.Headers.ContentEncoding),
+		ContentType:        optional(options.Headers.ContentType),
+		ContentDisposition: optional(options.Headers.ContentDisposition),
+		StorageClass:       optional(options.StorageClass),
+		ChecksumAlgorithm:  optional(options.ChecksumAlgorithm),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Multipart{
+		Key:               key,
+		UploadID:          aws.StringValue(output.UploadId),
+		ChecksumAlgorithm: options.ChecksumAlgorithm,
+	}, nil
+}
+
+func (s *S3) UploadPart(upload *Multipart, number int, body io.ReadSeeker, progress Progress) (Part, error) {
+	output, err := s.client.UploadPartWithContext(aws.BackgroundContext(), &s3.UploadPartInput{
+		Bucket:            aws.String(s.bucket),
+		Key:               aws.String(upload.Key),
+		UploadId:          aws.String(upload.UploadID),
+		PartNumber:        aws.Int64(int64(number)),
+		Body:              body,
+		ChecksumAlgorithm: optional(upload.ChecksumAlgorithm),
+	}, sendProgress(progress))
+	if err != nil {
+		return Part{}, err
+	}
+	return Part{Number: number, ETag: aws.StringValue(output.ETag), Checksum: partChecksum(output, upload.ChecksumAlgorithm)}, nil
+}
+
+func (s *S3) CompleteMultipart(upload *Multipart, parts []Part) (string, error) {
+	completed := make([]*s3.CompletedPart, 0, len(parts))
+	for _, part := range parts {
+		completedPart := &s3.CompletedPart{
+			ETag:       aws.String(part.ETag),
+			PartNumber: aws.Int64(int64(part.Number)),
+		}
+		setPartChecksum(completedPart, upload.ChecksumAlgorithm, part.Checksum)
+		completed = append(completed, completedPart)
+	}
+	output, err := s.client.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(s.bucket),
+		Key:             aws.String(upload.Key),
+		UploadId:        aws.String(upload.UploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		return "", err
+	}
+	return trimETag(output.ETag), nil
+}
+
+func (s *S3) AbortMultipart(upload *Multipart) error {
+	_, err := s.client.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(upload.Key),
+		UploadId: aws.String(upload.UploadID),
+	})
+	return err
+}
+
+func (s *S3) BucketExists() (bool, error) {
+	_, err := s.client.HeadBucket(&s3.HeadBucketInput{
+		Bucket: aws.String(s.bucket),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NotFound" {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *S3) CreateBucket() error {
+	_, err := s.client.CreateBucket(&s3.CreateBucketInput{
+		Bucket: aws.String(s.bucket),
+	})
+	return err
+}
+
+func (s *S3) ListBuckets() ([]string, error) {
+	output, err := s.client.ListBuckets(&s3.ListBucketsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(output.Buckets))
+	for _, bucket := range output.Buckets {
+		names = append(names, aws.StringValue(bucket.Name))
+	}
+	return names, nil
+}
+
+// identityEncoding 请求原始编码的数据，Go的HTTP客户端默认会透明解压gzip响应
+func identityEncoding(r *request.Request) {
+	r.HTTPRequest.Header.Set("Accept-Encoding", "identity")
+}
+
+// countRetries 请求选项：请求结束时将SDK的重试次数计入进度统计
+func countRetries(progress Progress) request.Option {
+	return func(r *request.Request) {
+		if progress == nil {
+			return
+		}
+		r.Handlers.Complete.PushBack(func(r *request.Request) {
+			progress.AddRetries(r.RetryCount)
+		})
+	}
+}
+
+// sendProgress 请求选项：发送请求体时按已发送的字节数更新进度，请求结束时记录SDK的重试次数。
+// 请求体在签名时会被完整读取一次，所以在发送阶段而不是在文件读取上统计
+func sendProgress(progress Progress) request.Option {
+	return func(r *request.Request) {
+		if progress == nil {
+			return
+		}
+		r.Handlers.Send.PushFront(func(r *request.Request) {
+			if r.HTTPRequest.Body != nil && r.HTTPRequest.Body != http.NoBody {
+				r.HTTPRequest.Body = withProgress(r.HTTPRequest.Body, progress)
+			}
+		})
+		r.Handlers.Complete.PushBack(func(r *request.Request) {
+			progress.AddRetries(r.RetryCount)
+		})
+	}
+}
+
+// setChecksum 将附加校验值设置到上传请求中，由服务端校验并随对象保存
+func setChecksum(input *s3.PutObjectInput, algorithm, value string) {
+	switch algorithm {
+	case ChecksumCRC32:
+		input.ChecksumCRC32 = aws.String(value)
+	case ChecksumCRC32C:
+		input.ChecksumCRC32C = aws.String(value)
+	case ChecksumSHA1:
+		input.ChecksumSHA1 = aws.String(value)
+	case ChecksumSHA256:
+		input.ChecksumSHA256 = aws.String(value)
+	}
+}
+
+// headChecksum 从HEAD响应中取出指定算法的校验值
+func headChecksum(head *s3.HeadObjectOutput, algorithm string) string {
+	switch algorithm {
+	case ChecksumCRC32:
+		return aws.StringValue(head.ChecksumCRC32)
+	case ChecksumCRC32C:
+		return aws.StringValue(head.ChecksumCRC32C)
+	case ChecksumSHA1:
+		return aws.StringValue(head.ChecksumSHA1)
+	case ChecksumSHA256:
+		return aws.StringValue(head.ChecksumSHA256)
+	}
+	return ""
+}
+
+// partChecksum 从分片上传的响应中取出指定算法的校验值
+func partChecksum(output *s3.UploadPartOutput, algorithm string) string {
+	switch algorithm {
+	case ChecksumCRC32:
+		return aws.StringValue(output.ChecksumCRC32)
+	case ChecksumCRC32C:
+		return aws.StringValue(output.ChecksumCRC32C)
+	case ChecksumSHA1:
+		return aws.StringValue(output.ChecksumSHA1)
+	case ChecksumSHA256:
+		return aws.StringValue(output.ChecksumSHA256)
+	}
+	return ""
+}
+
+// setPartChecksum 完成分片上传时提交分片的校验值，没有校验值时不设置
+func setPartChecksum(part *s3.CompletedPart, algorithm, value string) {
+	if value == "" {
+		return
+	}
+	switch algorithm {
+	case ChecksumCRC32:
+		part.ChecksumCRC32 = aws.String(value)
+	case ChecksumCRC32C:
+		part.ChecksumCRC32C = aws.String(value)
+	case ChecksumSHA1:
+		part.ChecksumSHA1 = aws.String(value)
+	case ChecksumSHA256:
+		part.ChecksumSHA256 = aws.String(value)
+	}
+}
+
+// copySource 生成URL编码的复制来源
+func copySource(bucket, key string) string {
+	parts := strings.Split(bucket+"/"+key, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// trimETag 去掉ETag两端的引号
+func trimETag(etag *string) string {
+	return strings.Trim(aws.StringValue(etag), "\"")
+}
+
+// optional 空字符串返回nil，使请求中不设置该字段
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return aws.String(value)
+}
+
+func (s *S3) GetTags(key string) (map[string]string, error) {
+	output, err := s.client.GetObjectTagging(&s3.GetObjectTaggingInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, s3NotFound(err)
+	}
+	tags := make(map[string]string, len(output.TagSet))
+	for _, tag := range output.TagSet {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tags, nil
+}
+
+func (s *S3) PutTags(key string, tags map[string]string) error {
+	tagSet := make([]*s3.Tag, 0, len(tags))
+	for name, value := range tags {
+		tagSet = append(tagSet, &s3.Tag{Key: aws.String(name), Value: aws.String(value)})
+	}
+	_, err := s.client.PutObjectTagging(&s3.PutObjectTaggingInput{
+		Bucket:  aws.String(s.bucket),
+		Key:     aws.String(key),
+		Tagging: &s3.Tagging{TagSet: tagSet},
+	})
+	return s3NotFound(err)
+}
+
+func (s *S3) GetACL(key string) (*ACL, error) {
+	output, err := s.client.GetObjectAcl(&s3.GetObjectAclInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, s3NotFound(err)
+	}
+	acl := &ACL{Grants: make([]Grant, 0, len(output.Grants))}
+	if output.Owner != nil {
+		acl.Owner = aws.StringValue(output.Owner.ID)
+	}
+	for _, grant := range output.Grants {
+		if grant.Grantee == nil {
+			continue
+		}
+		acl.Grants = append(acl.Grants, Grant{
+			ID:         aws.StringValue(grant.Grantee.ID),
+			URI:        aws.StringValue(grant.Grantee.URI),
+			Permission: aws.StringValue(grant.Permission),
+		})
+	}
+	return acl, nil
+}
+
+func (s *S3) PutACL(key string, acl *ACL) error {
+	grants := make([]*s3.Grant, 0, len(acl.Grants))
+	for _, grant := range acl.Grants {
+		grantee := &s3.Grantee{Type: aws.String(s3.TypeCanonicalUser), ID: aws.String(grant.ID)}
+		if grant.URI != "" {
+			grantee = &s3.Grantee{Type: aws.String(s3.TypeGroup), URI: aws.String(grant.URI)}
+		}
+		grants = append(grants, &s3.Grant{Grantee: grantee, Permission: aws.String(grant.Permission)})
+	}
+	_, err := s.client.PutObjectAcl(&s3.PutObjectAclInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		AccessControlPolicy: &s3.AccessControlPolicy{
+			Owner:  &s3.Owner{ID: aws.String(acl.Owner)},
+			Grants: grants,
+		},
+	})
+	return s3NotFound(err)
+}
+
+// s3NotFound 把对象不存在的错误转换为 ErrNotFound，其他错误原样返回
+func s3NotFound(err error) error {
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"hash"
+	"hash/crc32"
+	"io"
+	"time"
+
+	"objectsync/internal/i18n"
+)
+
+// ErrNotFound 对象不存在
+var ErrNotFound = i18n.New("对象不存在")
+
+// 附加校验算法，与S3的 x-amz-checksum-* 相同
+const (
+	ChecksumCRC32  = "CRC32"
+	ChecksumCRC32C = "CRC32C"
+	ChecksumSHA1   = "SHA1"
+	ChecksumSHA256 = "SHA256"
+)
+
+// NewChecksumHash 创建附加校验算法对应的哈希
+func NewChecksumHash(algorithm string) hash.Hash {
+	switch algorithm {
+	case ChecksumCRC32:
+		return crc32.NewIEEE()
+	case ChecksumCRC32C:
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	case ChecksumSHA1:
+		return sha1.New()
+	default:
+		return sha256.New()
+	}
+}
+
+// Object 对象的属性。List 只填写键、大小、修改时间、ETag和存储类别，Head 和 Get 还填写元数据和HTTP头
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+	ETag         string // 不含引号，分片上传的对象带有"-分片数"后缀
+	StorageClass string
+	Metadata     map[string]string // 用户元数据，键的大小写可能被服务端改变
+	Headers      Headers
+	Checksum     string // Head 请求的附加校验值（base64），对象没有该算法的校验值时为空
+}
+
+// Headers 对象的HTTP头，空值表示不设置
+type Headers struct {
+	CacheControl       string
+	ContentEncoding    string
+	ContentType        string
+	ContentDisposition string
+}
+
+// PutOptions 上传、复制对象和开始分片上传的选项
+type PutOptions struct {
+	Metadata          map[string]string
+	Headers           Headers
+	StorageClass      string   // 存储类别，空表示使用服务端默认值
+	ChecksumAlgorithm string   // 附加校验算法，空表示不使用
+	Checksum          string   // 预先计算的附加校验值（base64），由服务端校验；分片上传时忽略
+	Progress          Progress // 发送请求体时报告进度，可以为nil
+}
+
+// Progress 接收单个传输的进度，*progress.Counter 实现了该接口
+type Progress interface {
+	// Add 传输了n个字节，请求重试时已传输的字节会再计入一次
+	Add(n int64)
+	// AddRetries 请求重试了n次
+	AddRetries(n int)
+}
+
+// Multipart 进行中的分片上传
+type Multipart struct {
+	Key               string
+	UploadID          string
+	ChecksumAlgorithm string // 开始时指定的附加校验算法，每个分片使用相同的算法
+}
+
+// Part 已上传的分片
+type Part struct {
+	Number   int
+	ETag     string
+	Checksum string // 分片的附加校验值（base64），开始分片上传时指定了算法才有，完成时一并提交
+}
+
+// Storage 对象存储中的一个桶。备份和上传只通过该接口访问对象存储，
+// 可以换成其他后端或测试用的实现。可以被多个工作协程同时使用
+type Storage interface {
+	// List 按键的顺序逐页列出前缀下的所有对象，对每个对象调用fn，fn返回错误时停止并返回该错误
+	List(prefix string, fn func(Object) error) error
+	// Head 读取对象的属性，对象不存在时返回 ErrNotFound。
+	// checksumAlgorithm非空时同时读取该算法的附加校验值
+	Head(key, checksumAlgorithm string) (*Object, error)
+	// Get 读取对象内容，调用方关闭返回的读取器。内容保持上传时的编码，不自动解压；
+	// 读取的字节数计入progress（可以为nil）
+	Get(key string, progress Progress) (io.ReadCloser, *Object, error)
+	// Put 上传对象，返回新对象的ETag。body需要能重新定位，以便重试时重新发送
+	Put(key string, body io.ReadSeeker, options *PutOptions) (string, error)
+	// Copy 服务端复制桶中的对象，数据不经过本机，返回新对象的ETag。
+	// options非nil时替换元数据和HTTP头，为nil时保留来源对象的；来源不存在时返回 ErrNotFound
+	Copy(srcKey, dstKey string, options *PutOptions) (string, error)
+	// Delete 删除对象，对象不存在时不返回错误
+	Delete(key string) error
+
+	// CreateMultipart 开始分片上传，分片全部上传后调用 CompleteMultipart，出错时调用 AbortMultipart
+	CreateMultipart(key string, options *PutOptions) (*Multipart, error)
+	// UploadPart 上传一个分片，number从1开始，发送的字节数计入progress（可以为nil）
+	UploadPart(upload *Multipart, number int, body io.ReadSeeker, progress Progress) (Part, error)
+	// CompleteMultipart 按编号的顺序把分片合并为对象，返回对象的ETag
+	CompleteMultipart(upload *Multipart, parts []Part) (string, error)
+	// AbortMultipart 放弃分片上传，删除已上传的分片
+	AbortMultipart(upload *Multipart) error
+
+	// BucketExists 桶是否存在
+	BucketExists() (bool, error)
+	// CreateBucket 创建桶
+	CreateBucket() error
+	// ListBuckets 列出当前凭证可以访问的所有桶
+	ListBuckets() ([]string, error)
+}
+
+// progressReader 读取的字节数计入进度
+type progressReader struct {
+	io.ReadCloser
+	progress Progress
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		r.progress.Add(int64(n))
+	}
+	return n, err
+}
+
+// withProgress 包装读取器，progress为nil时原样返回
+func withProgress(r io.ReadCloser, progress Progress) io.ReadCloser {
+	if progress == nil {
+		return r
+	}
+	return &progressReader{ReadCloser: r, progress: progress}
+}
@@ -0,0 +1,821 @@
+package storage
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"objectsync/internal/fileattr"
+	"objectsync/internal/i18n"
+	"objectsync/internal/ratelimit"
+)
+
+// WebDAVScheme WebDAV端点的前缀，如 webdav:https://cloud.example.com/remote.php/dav/files/alice，
+// 桶对应该地址下的同名目录
+const WebDAVScheme = "webdav:"
+
+// WebDAV请求的默认重试设置，与SDK的默认值相近
+const (
+	webdavMaxAttempts   = 4
+	webdavRetryDelay    = time.Second
+	webdavMaxRetryDelay = 2 * time.Minute
+)
+
+// webdavPropfind PROPFIND请求的内容，只读取需要的属性
+const webdavPropfind = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:"><d:prop><d:resourcetype/><d:getcontentlength/><d:getlastmodified/><d:getetag/><d:getcontenttype/></d:prop></d:propfind>`
+
+// WebDAVURL 端点是WebDAV服务时返回服务地址
+func WebDAVURL(endpoint string) (string, bool) {
+	address, ok := strings.CutPrefix(endpoint, WebDAVScheme)
+	return address, ok && address != ""
+}
+
+// WebDAVOptions WebDAV连接选项
+type WebDAVOptions struct {
+	Username    string
+	Password    string
+	RateLimiter *ratelimit.Limiter // 请求速率限制器
+	MaxAttempts int                // 单个请求的最大尝试次数，0表示使用默认值
+	RetryDelay  time.Duration      // 首次重试前的等待时间，之后按指数增长
+}
+
+// WebDAV 把WebDAV服务（如Nextcloud、ownCloud的共享目录）中的目录当作桶，对象键对应目录下的相对路径。
+// WebDAV不能保存用户元数据，修改时间通过Nextcloud/ownCloud支持的 X-OC-Mtime 头设置，
+// 读取时从文件的修改时间生成。空目录列出为目录标记。ETag使用服务端的ETag并加上"dav-"前缀，
+// 不与MD5比较；不支持附加校验值。分片上传的分片先保存在本地临时目录，完成时一次上传
+type WebDAV struct {
+	client   *http.Client
+	base     string // 端点地址，以"/"结尾
+	root     string // 桶目录的地址，以"/"结尾
+	rootPath string // 桶目录地址中未编码的路径，用于从PROPFIND响应中的地址得到对象键
+	options  WebDAVOptions
+	auth     *webdavAuth
+	probe    sync.Once
+
+	mutex       sync.Mutex
+	collections map[string]bool        // 已确认存在的目录
+	uploads     map[string]*PutOptions // 进行中的分片上传（本地临时目录） -> 完成时使用的选项
+}
+
+// NewWebDAV 返回WebDAV服务endpoint下名为bucket的桶
+func NewWebDAV(client *http.Client, endpoint, bucket string, options WebDAVOptions) (*WebDAV, error) {
+	base, err := url.Parse(endpoint)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, i18n.Errorf("无效的WebDAV地址: %s", endpoint)
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = webdavMaxAttempts
+	}
+	if options.RetryDelay <= 0 {
+		options.RetryDelay = webdavRetryDelay
+	}
+
+	root := base.JoinPath(bucket)
+	return &WebDAV{
+		client:      client,
+		base:        strings.TrimSuffix(base.String(), "/") + "/",
+		root:        strings.TrimSuffix(root.String(), "/") + "/",
+		rootPath:    strings.TrimSuffix(root.Path, "/") + "/",
+		options:     options,
+		auth:        &webdavAuth{username: options.Username, password: options.Password},
+		collections: make(map[string]bool),
+		uploads:     make(map[string]*PutOptions),
+	}, nil
+}
+
+func (w *WebDAV) List(prefix string, fn func(Object) error) error {
+	// 从前缀所在的目录开始逐层列出，只进入与前缀相关的子目录
+	start := ""
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		start = prefix[:i+1]
+		if _, err := w.url(start); err != nil {
+			return err
+		}
+	}
+
+	var objects []Object
+	queue := []string{start}
+	for len(queue) > 0 {
+		dir := queue[0]
+		queue = queue[1:]
+
+		target, _ := w.url(dir)
+		entries, err := w.propfind(target, dir, "1")
+		if err == ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		children := 0
+		for _, entry := range entries {
+			if entry.key == dir || !strings.HasPrefix(entry.key, dir) {
+				continue
+			}
+			children++
+			if entry.collection {
+				if strings.HasPrefix(entry.key, prefix) || strings.HasPrefix(prefix, entry.key) {
+					queue = append(queue, entry.key)
+				}
+			} else if strings.HasPrefix(entry.key, prefix) {
+				objects = append(objects, entry.object())
+			}
+		}
+
+		// 非空目录由其中的文件体现，只列出空目录
+		if children == 0 && dir != "" && strings.HasPrefix(dir, prefix) {
+			for _, entry := range entries {
+				if entry.key == dir {
+					objects = append(objects, entry.object())
+				}
+			}
+		}
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Key < objects[j].Key
+	})
+	for _, object := range objects {
+		if err := fn(object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *WebDAV) Head(key, checksumAlgorithm string) (*Object, error) {
+	target, err := w.url(key)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := w.propfind(target, key, "0")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		// 目录标记对应目录，其他键对应文件
+		if entry.key == key && entry.collection == strings.HasSuffix(key, "/") {
+			object := entry.object()
+			return &object, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (w *WebDAV) Get(key string, progress Progress) (io.ReadCloser, *Object, error) {
+	if strings.HasSuffix(key, "/") {
+		object, err := w.Head(key, "")
+		if err != nil {
+			return nil, nil, err
+		}
+		return io.NopCloser(strings.NewReader("")), object, nil
+	}
+
+	target, err := w.url(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 保留文件的原始编码，避免HTTP客户端自动解压
+	header := http.Header{"Accept-Encoding": {"identity"}}
+	resp, err := w.do(http.MethodGet, target, header, nil, progress)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		discard(resp)
+		return nil, nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		discard(resp)
+		return nil, nil, webdavError(http.MethodGet, key, resp)
+	}
+
+	modified, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	entry := webdavEntry{
+		key:         key,
+		size:        resp.ContentLength,
+		modified:    modified,
+		etag:        resp.Header.Get("ETag"),
+		contentType: resp.Header.Get("Content-Type"),
+	}
+	object := entry.object()
+	return withProgress(resp.Body, progress), &object, nil
+}
+
+func (w *WebDAV) Put(key string, body io.ReadSeeker, options *PutOptions) (string, error) {
+	if options == nil {
+		options = &PutOptions{}
+	}
+	target, err := w.url(key)
+	if err != nil {
+		return "", err
+	}
+
+	// 目录标记创建目录
+	if strings.HasSuffix(key, "/") {
+		if err := w.mkdirAll(key); err != nil {
+			return "", err
+		}
+		return w.etag(key)
+	}
+
+	if err := w.mkdirAll(parentKey(key)); err != nil {
+		return "", err
+	}
+	header := http.Header{}
+	if options.Headers.ContentType != "" {
+		header.Set("Content-Type", options.Headers.ContentType)
+	}
+	if mtime := fileattr.Parse(options.Metadata).ModTime; !mtime.IsZero() {
+		header.Set("X-OC-Mtime", strconv.FormatInt(mtime.Unix(), 10))
+	}
+
+	resp, err := w.do(http.MethodPut, target, header, body, options.Progress)
+	if err != nil {
+		return "", err
+	}
+	discard(resp)
+	if resp.StatusCode/100 != 2 {
+		return "", webdavError(http.MethodPut, key, resp)
+	}
+
+	// 不是所有服务端都在响应中返回ETag
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		return webdavETag(etag), nil
+	}
+	return w.etag(key)
+}
+
+func (w *WebDAV) Copy(srcKey, dstKey string, options *PutOptions) (string, error) {
+	src, err := w.url(srcKey)
+	if err != nil {
+		return "", err
+	}
+	dst, err := w.url(dstKey)
+	if err != nil {
+		return "", err
+	}
+	if err := w.mkdirAll(parentKey(dstKey)); err != nil {
+		return "", err
+	}
+
+	// WebDAV不能替换元数据，复制后保留来源文件的属性
+	header := http.Header{
+		"Destination": {dst},
+		"Overwrite":   {"T"},
+		"Depth":       {"0"},
+	}
+	resp, err := w.do("COPY", src, header, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	discard(resp)
+	if resp.StatusCode == http.StatusNotFound {
+		return "", ErrNotFound
+	}
+	if resp.StatusCode/100 != 2 {
+		return "", webdavError("COPY", srcKey, resp)
+	}
+	return w.etag(dstKey)
+}
+
+func (w *WebDAV) Delete(key string) error {
+	target, err := w.url(key)
+	if err != nil {
+		return err
+	}
+
+	// WebDAV删除目录时会删除其中的所有文件，目录标记只在目录为空时删除
+	if strings.HasSuffix(key, "/") {
+		entries, err := w.propfind(target, key, "1")
+		if err == ErrNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if entry.key != key {
+				return nil
+			}
+		}
+	}
+
+	resp, err := w.do(http.MethodDelete, target, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	discard(resp)
+	if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusNotFound {
+		return webdavError(http.MethodDelete, key, resp)
+	}
+
+	w.mutex.Lock()
+	delete(w.collections, key)
+	w.mutex.Unlock()
+	return nil
+}
+
+func (w *WebDAV) CreateMultipart(key string, options *PutOptions) (*Multipart, error) {
+	if options == nil {
+		options = &PutOptions{}
+	}
+	if _, err := w.url(key); err != nil {
+		return nil, err
+	}
+
+	// WebDAV没有通用的分片上传，分片先保存在本地，完成时合并上传
+	parts, err := os.MkdirTemp("", localTempPrefix+"webdav-*")
+	if err != nil {
+		return nil, err
+	}
+
+	// 分片已经计入进度，合并上传时不再重复计入
+	stored := *options
+	stored.Progress = nil
+	w.mutex.Lock()
+	w.uploads[parts] = &stored
+	w.mutex.Unlock()
+	return &Multipart{Key: key, UploadID: parts}, nil
+}
+
+func (w *WebDAV) UploadPart(upload *Multipart, number int, body io.ReadSeeker, progress Progress) (Part, error) {
+	file, err := os.Create(filepath.Join(upload.UploadID, strconv.Itoa(number)))
+	if err != nil {
+		return Part{}, err
+	}
+	_, err = io.Copy(file, withProgress(io.NopCloser(body), progress))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return Part{}, err
+	}
+	return Part{Number: number, ETag: strconv.Itoa(number)}, nil
+}
+
+func (w *WebDAV) CompleteMultipart(upload *Multipart, parts []Part) (string, error) {
+	w.mutex.Lock()
+	options, ok := w.uploads[upload.UploadID]
+	w.mutex.Unlock()
+	if !ok {
+		return "", i18n.Errorf("分片上传不存在: %s", upload.UploadID)
+	}
+
+	// 按顺序把分片连接为一个可以重新定位的读取器，不需要在本地再合并一次
+	body := &concatReader{}
+	defer body.Close()
+	for _, part := range parts {
+		if err := body.add(filepath.Join(upload.UploadID, strconv.Itoa(part.Number))); err != nil {
+			return "", err
+		}
+	}
+
+	etag, err := w.Put(upload.Key, body, options)
+	if err != nil {
+		return "", err
+	}
+	w.AbortMultipart(upload)
+	return etag, nil
+}
+
+func (w *WebDAV) AbortMultipart(upload *Multipart) error {
+	w.mutex.Lock()
+	_, ok := w.uploads[upload.UploadID]
+	delete(w.uploads, upload.UploadID)
+	w.mutex.Unlock()
+	// 上传ID是本地临时目录，只删除本实例创建的目录
+	if !ok {
+		return nil
+	}
+	return os.RemoveAll(upload.UploadID)
+}
+
+func (w *WebDAV) BucketExists() (bool, error) {
+	entries, err := w.propfind(w.root, "", "0")
+	if err == ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return len(entries) > 0 && entries[0].collection, nil
+}
+
+func (w *WebDAV) CreateBucket() error {
+	return w.mkcol(w.root, "")
+}
+
+func (w *WebDAV) ListBuckets() ([]string, error) {
+	resp, err := w.do("PROPFIND", w.base, propfindHeader("1"), strings.NewReader(webdavPropfind), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer discard(resp)
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, webdavError("PROPFIND", w.base, resp)
+	}
+
+	responses, err := parseMultistatus(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	basePath := w.rootPath[:strings.LastIndex(strings.TrimSuffix(w.rootPath, "/"), "/")+1]
+	var buckets []string
+	for _, response := range responses {
+		name, ok := strings.CutPrefix(strings.TrimSuffix(response.path, "/"), basePath)
+		if ok && name != "" && !strings.Contains(name, "/") && response.collection {
+			buckets = append(buckets, name)
+		}
+	}
+	return buckets, nil
+}
+
+// webdavEntry PROPFIND响应中的一个文件或目录
+type webdavEntry struct {
+	key         string
+	collection  bool
+	size        int64
+	modified    time.Time
+	etag        string
+	contentType string
+}
+
+// object 生成对象属性，修改时间同时作为元数据，与本地目录一样可以在恢复时应用
+func (e webdavEntry) object() Object {
+	object := Object{
+		Key:          e.key,
+		LastModified: e.modified,
+		ETag:         webdavETag(e.etag),
+		Headers:      Headers{ContentType: e.contentType},
+	}
+	if !e.collection {
+		object.Size = e.size
+	}
+	if object.ETag == "" {
+		object.ETag = fmt.Sprintf("%x-%x", e.modified.UnixNano(), object.Size)
+	}
+	if !e.modified.IsZero() {
+		object.Metadata = fileattr.Attrs{ModTime: e.modified}.Metadata()
+	}
+	return object
+}
+
+// url 返回对象键对应的地址，键中不能有空的路径段和 "."、".."
+func (w *WebDAV) url(key string) (string, error) {
+	if key == "" {
+		return w.root, nil
+	}
+	segments := strings.Split(strings.TrimSuffix(key, "/"), "/")
+	for i, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", i18n.Errorf("无效的对象键: %s", key)
+		}
+		segments[i] = url.PathEscape(segment)
+	}
+	target := w.root + strings.Join(segments, "/")
+	if strings.HasSuffix(key, "/") {
+		target += "/"
+	}
+	return target, nil
+}
+
+// propfind 列出地址对应的文件或目录（depth为"0"）或目录及其中的项目（depth为"1"），
+// 地址不存在时返回 ErrNotFound
+func (w *WebDAV) propfind(target, key, depth string) ([]webdavEntry, error) {
+	resp, err := w.do("PROPFIND", target, propfindHeader(depth), strings.NewReader(webdavPropfind), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer discard(resp)
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, webdavError("PROPFIND", key, resp)
+	}
+
+	responses, err := parseMultistatus(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var entries []webdavEntry
+	for _, response := range responses {
+		// 桶目录之外的地址不是对象
+		if !strings.HasPrefix(response.path+"/", w.rootPath) {
+			continue
+		}
+		response.key = strings.TrimPrefix(strings.TrimSuffix(response.path, "/"), strings.TrimSuffix(w.rootPath, "/"))
+		response.key = strings.TrimPrefix(response.key, "/")
+		if response.collection && response.key != "" {
+			response.key += "/"
+		}
+		entries = append(entries, response.webdavEntry)
+	}
+	return entries, nil
+}
+
+// etag 读取对象当前的ETag
+func (w *WebDAV) etag(key string) (string, error) {
+	object, err := w.Head(key, "")
+	if err != nil {
+		return "", err
+	}
+	return object.ETag, nil
+}
+
+// mkdirAll 逐级创建目录键dir对应的目录及桶目录，已创建的目录会被记住
+func (w *WebDAV) mkdirAll(dir string) error {
+	keys := []string{""}
+	for i := range dir {
+		if dir[i] == '/' {
+			keys = append(keys, dir[:i+1])
+		}
+	}
+
+	for _, key := range keys {
+		w.mutex.Lock()
+		exists := w.collections[key]
+		w.mutex.Unlock()
+		if exists {
+			continue
+		}
+
+		target, err := w.url(key)
+		if err != nil {
+			return err
+		}
+		if err := w.mkcol(target, key); err != nil {
+			return err
+		}
+		w.mutex.Lock()
+		w.collections[key] = true
+		w.mutex.Unlock()
+	}
+	return nil
+}
+
+// mkcol 创建目录，目录已经存在时不返回错误
+func (w *WebDAV) mkcol(target, key string) error {
+	resp, err := w.do("MKCOL", target, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	discard(resp)
+	// 405表示地址已经存在
+	if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusMethodNotAllowed {
+		return webdavError("MKCOL", key, resp)
+	}
+	return nil
+}
+
+// do 发送请求：按速率限制等待，网络错误、5xx和429响应按退避重试，重试次数计入progress。
+// 请求体从当前位置发送，重试时重新定位。返回的响应由调用方关闭
+func (w *WebDAV) do(method, target string, header http.Header, body io.ReadSeeker, progress Progress) (*http.Response, error) {
+	var start, size int64
+	if body != nil {
+		var err error
+		if start, err = body.Seek(0, io.SeekCurrent); err != nil {
+			return nil, err
+		}
+		end, err := body.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, err
+		}
+		size = end - start
+	}
+	// 上传前先用不带内容的请求完成认证，避免认证失败时把文件内容发送两次
+	if method == http.MethodPut {
+		w.probe.Do(func() {
+			if resp, err := w.send("PROPFIND", w.base, propfindHeader("0"), nil, 0, 0, nil); err == nil {
+				discard(resp)
+			}
+		})
+	}
+
+	delay := w.options.RetryDelay
+	for attempt := 1; ; attempt++ {
+		resp, err := w.send(method, target, header, body, start, size, progress)
+		retry := err != nil || resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		if !retry || attempt >= w.options.MaxAttempts {
+			return resp, err
+		}
+		if resp != nil {
+			discard(resp)
+		}
+
+		if progress != nil {
+			progress.AddRetries(1)
+		}
+		time.Sleep(delay)
+		delay = min(delay*2, webdavMaxRetryDelay)
+	}
+}
+
+// send 发送一次请求，收到401时按服务端的质询认证后重新发送一次
+func (w *WebDAV) send(method, target string, header http.Header, body io.ReadSeeker, start, size int64, progress Progress) (*http.Response, error) {
+	for authenticated := false; ; authenticated = true {
+		var reader io.Reader
+		if body != nil {
+			if _, err := body.Seek(start, io.SeekStart); err != nil {
+				return nil, err
+			}
+			reader = withProgress(io.NopCloser(body), progress)
+		}
+		req, err := http.NewRequest(method, target, reader)
+		if err != nil {
+			return nil, err
+		}
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		if body != nil {
+			req.ContentLength = size
+			if size == 0 {
+				req.Body = http.NoBody
+			}
+		}
+		w.auth.authorize(req)
+
+		w.options.RateLimiter.Wait()
+		resp, err := w.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusUnauthorized || authenticated || !w.auth.challenge(resp.Header.Values("WWW-Authenticate")) {
+			return resp, nil
+		}
+		discard(resp)
+	}
+}
+
+// webdavResponse multistatus中一个地址的属性
+type webdavResponse struct {
+	webdavEntry
+	path string // 未编码的地址路径
+}
+
+// multistatus PROPFIND的响应
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"DAV: href"`
+		Propstat []struct {
+			Status string `xml:"DAV: status"`
+			Prop   struct {
+				ResourceType struct {
+					Collection *struct{} `xml:"DAV: collection"`
+				} `xml:"DAV: resourcetype"`
+				ContentLength string `xml:"DAV: getcontentlength"`
+				LastModified  string `xml:"DAV: getlastmodified"`
+				ETag          string `xml:"DAV: getetag"`
+				ContentType   string `xml:"DAV: getcontenttype"`
+			} `xml:"DAV: prop"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+// parseMultistatus 解析PROPFIND响应，只使用状态为200的属性
+func parseMultistatus(r io.Reader) ([]webdavResponse, error) {
+	var result multistatus
+	if err := xml.NewDecoder(r).Decode(&result); err != nil {
+		return nil, i18n.Errorf("解析WebDAV响应失败: %w", err)
+	}
+
+	responses := make([]webdavResponse, 0, len(result.Responses))
+	for _, response := range result.Responses {
+		href, err := url.Parse(response.Href)
+		if err != nil {
+			continue
+		}
+		entry := webdavResponse{path: path.Clean("/" + href.Path)}
+		for _, propstat := range response.Propstat {
+			if fields := strings.Fields(propstat.Status); len(fields) < 2 || fields[1] != "200" {
+				continue
+			}
+			prop := propstat.Prop
+			entry.collection = prop.ResourceType.Collection != nil
+			entry.size, _ = strconv.ParseInt(strings.TrimSpace(prop.ContentLength), 10, 64)
+			entry.modified, _ = http.ParseTime(strings.TrimSpace(prop.LastModified))
+			entry.etag = prop.ETag
+			entry.contentType = prop.ContentType
+		}
+		responses = append(responses, entry)
+	}
+	return responses, nil
+}
+
+// propfindHeader PROPFIND请求的头
+func propfindHeader(depth string) http.Header {
+	return http.Header{
+		"Depth":        {depth},
+		"Content-Type": {"application/xml; charset=utf-8"},
+	}
+}
+
+// webdavETag 去掉服务端ETag的弱校验标记和引号并加上"dav-"前缀，空ETag返回空字符串
+func webdavETag(etag string) string {
+	etag = strings.Trim(strings.TrimPrefix(strings.TrimSpace(etag), "W/"), "\"")
+	if etag == "" {
+		return ""
+	}
+	return "dav-" + etag
+}
+
+// webdavError 生成请求失败的错误
+func webdavError(method, key string, resp *http.Response) error {
+	return i18n.Errorf("WebDAV请求 %s %s 失败: %s", method, key, resp.Status)
+}
+
+// parentKey 返回对象键所在目录的键，桶目录中的对象返回空字符串
+func parentKey(key string) string {
+	key = strings.TrimSuffix(key, "/")
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		return key[:i+1]
+	}
+	return ""
+}
+
+// discard 读完并关闭响应，使连接可以复用
+func discard(resp *http.Response) {
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+// concatReader 按顺序读取多个文件，可以重新定位
+type concatReader struct {
+	files  []*os.File
+	sizes  []int64
+	offset int64
+}
+
+// add 在末尾加入一个文件
+func (c *concatReader) add(name string) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	c.files = append(c.files, file)
+	c.sizes = append(c.sizes, info.Size())
+	return nil
+}
+
+func (c *concatReader) Read(p []byte) (int, error) {
+	offset := c.offset
+	for i, file := range c.files {
+		if offset >= c.sizes[i] {
+			offset -= c.sizes[i]
+			continue
+		}
+		n, err := file.ReadAt(p[:min(int64(len(p)), c.sizes[i]-offset)], offset)
+		c.offset += int64(n)
+		if err == io.EOF && n > 0 {
+			err = nil
+		}
+		return n, err
+	}
+	return 0, io.EOF
+}
+
+func (c *concatReader) Seek(offset int64, whence int) (int64, error) {
+	var size int64
+	for _, s := range c.sizes {
+		size += s
+	}
+	switch whence {
+	case io.SeekCurrent:
+		offset += c.offset
+	case io.SeekEnd:
+		offset += size
+	}
+	if offset < 0 {
+		return 0, i18n.Errorf("无效的读取位置: %d", offset)
+	}
+	c.offset = offset
+	return offset, nil
+}
+
+// Close 关闭所有文件
+func (c *concatReader) Close() error {
+	for _, file := range c.files {
+		file.Close()
+	}
+	return nil
+}
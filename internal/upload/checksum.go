@@ -0,0 +1,42 @@
+package upload
+
+import (
+	"encoding/base64"
+	"io"
+	"strings"
+
+	"objectsync/internal/i18n"
+	"objectsync/internal/storage"
+)
+
+// ParseChecksumAlgorithm 解析附加校验算法名称（不区分大小写），空字符串表示不使用
+func ParseChecksumAlgorithm(name string) (string, error) {
+	algorithm := strings.ToUpper(strings.TrimSpace(name))
+	switch algorithm {
+	case "", "NONE":
+		return "", nil
+	case storage.ChecksumCRC32, storage.ChecksumCRC32C, storage.ChecksumSHA1, storage.ChecksumSHA256:
+		return algorithm, nil
+	default:
+		return "", i18n.Errorf("不支持的校验算法: %s（可选值: CRC32, CRC32C, SHA1, SHA256）", name)
+	}
+}
+
+// readerChecksum 计算内容的附加校验值（base64编码，与S3一致）
+func readerChecksum(r io.Reader, algorithm string) (string, error) {
+	h := storage.NewChecksumHash(algorithm)
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+// localChecksum 计算本地文件的附加校验值，文件被占用时读取卷影副本
+func (u *Upload) localChecksum(file *LocalFile) (string, error) {
+	localFile, err := u.openLocalFile(file)
+	if err != nil {
+		return "", err
+	}
+	defer localFile.Close()
+	return readerChecksum(localFile, u.options.Checksum)
+}
@@ -0,0 +1,115 @@
+package upload
+
+import (
+	"io"
+	"os"
+
+	"objectsync/internal/compress"
+	"objectsync/internal/i18n"
+	"objectsync/internal/progress"
+	"objectsync/internal/storage"
+)
+
+// CompressRule 按文件名模式压缩上传的规则
+type CompressRule struct {
+	Pattern   string
+	Algorithm string // compress.Gzip 或 compress.Zstd
+}
+
+// compressAlgorithm 返回对象键匹配的压缩算法，后面的规则覆盖前面的，不压缩时返回空字符串
+func (u *Upload) compressAlgorithm(key string) string {
+	algorithm := ""
+	for _, rule := range u.options.Compress {
+		if matchPattern(rule.Pattern, key) {
+			algorithm = rule.Algorithm
+		}
+	}
+	return algorithm
+}
+
+// remoteKey 返回文件在桶中的对象键，压缩上传的文件带有压缩后缀
+func (u *Upload) remoteKey(file *LocalFile) string {
+	if file.IsDir {
+		return file.Key
+	}
+	return file.Key + compress.Suffix(u.compressAlgorithm(file.Key))
+}
+
+// uploadCompressed 压缩文件后上传，对象键追加压缩后缀并设置Content-Encoding
+func (u *Upload) uploadCompressed(file *LocalFile, src io.Reader, options *storage.PutOptions, algorithm string, counter *progress.Counter) error {
+	// 先压缩到临时文件，上传请求需要可重复读取且长度已知的请求体
+	tmp, err := os.CreateTemp("", "objectsync-compress-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	writer, err := compress.NewWriter(tmp, algorithm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(writer, src); err != nil {
+		writer.Close()
+		return i18n.Errorf("压缩 %s 失败: %w", file.Path, err)
+	}
+	if err := writer.Close(); err != nil {
+		return i18n.Errorf("压缩 %s 失败: %w", file.Path, err)
+	}
+
+	info, err := tmp.Stat()
+	if err != nil {
+		return err
+	}
+	size := info.Size()
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	key := file.Key + compress.Suffix(algorithm)
+	options.Headers.ContentEncoding = algorithm
+
+	logger.Debugf("压缩: %s（%s -> %s）", key, progress.FormatSize(file.Size), progress.FormatSize(size))
+
+	if size > multipartThreshold {
+		if err := u.uploadMultipart(file, key, tmp, size, options); err != nil {
+			return err
+		}
+	} else {
+		// 附加校验值针对压缩后的数据，由服务端校验
+		if u.options.Checksum != "" {
+			checksum, err := readerChecksum(tmp, u.options.Checksum)
+			if err != nil {
+				return i18n.Errorf("计算校验值失败: %w", err)
+			}
+			if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			options.ChecksumAlgorithm = u.options.Checksum
+			options.Checksum = checksum
+		}
+
+		etag, err := u.storage.Put(key, tmp, options)
+		if err != nil {
+			return err
+		}
+		file.ETag = etag
+	}
+
+	// 压缩对象无法与本地文件直接比较，只校验压缩后的大小
+	if u.options.Verify {
+		head, err := u.storage.Head(key, "")
+		if err != nil {
+			return i18n.Errorf("校验时获取对象信息失败: %w", err)
+		}
+		if remoteSize := head.Size; remoteSize != size {
+			return &verifyError{
+				key:    key,
+				reason: i18n.Sprintf("压缩后大小不一致（本地 %d，远程 %d）", size, remoteSize),
+			}
+		}
+	}
+
+	counter.Done(file.Size)
+	return nil
+}
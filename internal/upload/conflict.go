@@ -0,0 +1,84 @@
+package upload
+
+import (
+	"errors"
+	"strings"
+
+	"objectsync/internal/fileattr"
+	"objectsync/internal/i18n"
+	"objectsync/internal/storage"
+)
+
+// 远程对象比本地文件新时的处理方式
+const (
+	ConflictWarn = "warn" // 给出警告后仍然覆盖
+	ConflictSkip = "skip" // 跳过该文件
+)
+
+// errRemoteNewer 远程对象比本地文件新，跳过上传
+var errRemoteNewer = i18n.New("远程对象比本地文件新")
+
+// ParseConflictMode 解析冲突处理方式，空字符串表示不检查
+func ParseConflictMode(value string) (string, error) {
+	mode := strings.ToLower(strings.TrimSpace(value))
+	switch mode {
+	case "", ConflictWarn, ConflictSkip:
+		return mode, nil
+	default:
+		return "", i18n.Errorf("不支持的冲突处理方式: %s（可选值: warn, skip）", value)
+	}
+}
+
+// checkConflict 覆盖前检查远程对象是否比本地文件新（可能被其他人更新过）
+func (u *Upload) checkConflict(file *LocalFile) error {
+	if u.options.Conflict == "" || file.IsDir {
+		return nil
+	}
+
+	head, err := u.storage.Head(u.remoteKey(file), "")
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return i18n.Errorf("检查远程对象失败: %w", err)
+	}
+
+	// 远程对象与上次上传的记录一致（本地文件删除后又重新创建时也是），说明没有被其他人修改
+	if entry, ok := u.uploaded(file.Key); ok && entry.ETag != "" && entry.ETag == head.ETag {
+		return nil
+	}
+
+	// 优先使用元数据中记录的原始修改时间，没有时使用对象的上传时间
+	remoteTime := fileattr.Parse(head.Metadata).ModTime
+	if remoteTime.IsZero() {
+		remoteTime = head.LastModified
+	}
+	if !remoteTime.After(file.LastModified) {
+		return nil
+	}
+
+	if u.options.Conflict == ConflictWarn {
+		logger.Warnf("远程对象比本地文件新，仍将覆盖: %s（远程 %s，本地 %s）",
+			file.Key, remoteTime.Format("2006-01-02 15:04:05"), file.LastModified.Format("2006-01-02 15:04:05"))
+		return nil
+	}
+	return errRemoteNewer
+}
+
+// markConflict 将文件标记为因远程较新而跳过
+func (u *Upload) markConflict(file *LocalFile) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	file.Deferred = true
+	u.conflicts = append(u.conflicts, file)
+}
+
+// printConflicts 在总结中列出因远程较新而跳过的文件
+func (u *Upload) printConflicts() {
+	if len(u.conflicts) == 0 {
+		return
+	}
+
+	logger.Warnf("跳过 %d 个远程版本比本地新的文件（使用 --force 强制覆盖）:%s", len(u.conflicts), keyList(u.conflicts))
+}
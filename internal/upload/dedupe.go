@@ -0,0 +1,164 @@
+package upload
+
+import (
+	"errors"
+	"strings"
+
+	"objectsync/internal/i18n"
+	"objectsync/internal/state"
+	"objectsync/internal/storage"
+)
+
+// maxCopySize 单次CopyObject请求支持的最大对象大小
+const maxCopySize = 5 << 30
+
+// planCopies 找出内容与已上传对象或本次其他文件相同的文件，返回需要实际上传的文件和可以服务端复制的文件
+// 只有大小与其他文件相同的文件才计算MD5，避免读取所有文件
+func (u *Upload) planCopies(files []*LocalFile) ([]*LocalFile, []*LocalFile, error) {
+	if !u.options.Dedupe {
+		return files, nil, nil
+	}
+
+	sizes := make(map[int64]int)
+	for _, file := range files {
+		if u.copyCandidate(file) {
+			sizes[file.Size]++
+		}
+	}
+
+	// 状态中单次上传的对象ETag即内容MD5，可以作为复制来源。
+	// 只保留与本次文件大小相同的条目，状态很大时不占用太多内存
+	remote := make(map[int64]map[string]string)
+	if u.state != nil {
+		err := u.state.Range("", func(key string, entry state.Entry) bool {
+			if _, ok := sizes[entry.Size]; !ok || entry.Deleted() || entry.Pack != "" || entry.ETag == "" || strings.Contains(entry.ETag, "-") {
+				return true
+			}
+			if remote[entry.Size] == nil {
+				remote[entry.Size] = make(map[string]string)
+			}
+			remote[entry.Size][strings.ToLower(entry.ETag)] = key
+			return true
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var uploads, copies []*LocalFile
+	local := make(map[string]string)
+	for _, file := range files {
+		if !u.copyCandidate(file) || (sizes[file.Size] < 2 && remote[file.Size] == nil) {
+			uploads = append(uploads, file)
+			continue
+		}
+
+		md5sum, err := u.localMD5(file)
+		if err != nil {
+			return nil, nil, i18n.Errorf("计算 %s 的MD5失败: %w", file.Path, err)
+		}
+		file.MD5 = md5sum
+
+		if source, ok := remote[file.Size][md5sum]; ok && source != file.Key {
+			file.CopySource = source
+			copies = append(copies, file)
+			continue
+		}
+		if source, ok := local[md5sum]; ok {
+			file.CopySource = source
+			copies = append(copies, file)
+			continue
+		}
+
+		local[md5sum] = file.Key
+		uploads = append(uploads, file)
+	}
+
+	if len(copies) > 0 {
+		logger.Debugf("%d 个文件与已有对象内容相同，将使用服务端复制", len(copies))
+	}
+
+	return uploads, copies, nil
+}
+
+// copyCandidate 判断文件是否可以通过服务端复制上传，压缩上传的文件对象内容与本地不同，不参与复制
+func (u *Upload) copyCandidate(file *LocalFile) bool {
+	return !file.IsDir && file.Size > 0 && file.Size <= maxCopySize && u.compressAlgorithm(file.Key) == ""
+}
+
+// resolveCopies 复制来源在本次运行中未能上传时，改为直接上传
+func resolveCopies(copies, uploaded []*LocalFile) {
+	skipped := make(map[string]bool)
+	for _, file := range uploaded {
+		if file.Deferred {
+			skipped[file.Key] = true
+		}
+	}
+	for _, file := range copies {
+		if skipped[file.CopySource] {
+			file.CopySource = ""
+		}
+	}
+}
+
+// copyFile 通过服务端复制创建对象，复制结果与本地内容不一致时改为直接上传
+func (u *Upload) copyFile(file *LocalFile) error {
+	logger.Debugf("复制: %s -> %s", file.CopySource, file.Key)
+
+	// 文件在扫描后发生变化，说明仍在写入
+	if u.options.StableWindow > 0 && u.fileChanged(file) {
+		return errFileChanging
+	}
+
+	// 复制请求替换元数据时HTTP头也需要重新设置
+	options := u.putOptions(file)
+
+	// 复制不读取文件内容，单独计算记录到状态中的SHA-256
+	localFile, err := u.openLocalFile(file)
+	if err != nil {
+		return err
+	}
+	sum, err := state.Hash(localFile)
+	localFile.Close()
+	if err != nil {
+		return err
+	}
+	file.SHA256 = sum
+
+	// 附加校验值由服务端重新计算
+	if u.options.Checksum != "" {
+		checksum, err := u.localChecksum(file)
+		if err != nil {
+			return i18n.Errorf("计算校验值失败: %w", err)
+		}
+		file.Checksum = checksum
+		options.ChecksumAlgorithm = u.options.Checksum
+	}
+
+	etag, err := u.storage.Copy(file.CopySource, file.Key, options)
+	if errors.Is(err, storage.ErrNotFound) {
+		// 来源对象已被删除
+		file.CopySource = ""
+		return u.uploadFile(file)
+	}
+	if err != nil {
+		return err
+	}
+	file.ETag = etag
+
+	// 来源对象已被修改，内容不再相同
+	if !strings.EqualFold(file.ETag, file.MD5) {
+		logger.Debugf("复制来源 %s 内容已变化，改为直接上传: %s", file.CopySource, file.Key)
+		file.CopySource = ""
+		return u.uploadFile(file)
+	}
+
+	if u.options.Verify {
+		if err := u.verifyUpload(file); err != nil {
+			return err
+		}
+	}
+
+	u.progress.AddFile(file.Key, file.Size)
+	return nil
+}
@@ -0,0 +1,73 @@
+package upload
+
+import (
+	"path"
+	"strings"
+
+	"objectsync/internal/storage"
+)
+
+// HeaderRule 按文件名模式设置的HTTP头
+type HeaderRule struct {
+	Pattern            string
+	CacheControl       string
+	ContentEncoding    string
+	ContentType        string
+	ContentDisposition string
+}
+
+// matches 判断对象键是否匹配规则
+func (r HeaderRule) matches(key string) bool {
+	return matchPattern(r.Pattern, key)
+}
+
+// matchPattern 判断对象键是否匹配文件名模式，不含"/"的模式只匹配文件名
+func matchPattern(pattern, key string) bool {
+	name := key
+	if !strings.Contains(pattern, "/") {
+		name = path.Base(key)
+	}
+	matched, _ := path.Match(pattern, name)
+	return matched
+}
+
+// putOptions 返回上传文件使用的选项：文件属性记录在对象元数据中，并按规则设置HTTP头
+func (u *Upload) putOptions(file *LocalFile) *storage.PutOptions {
+	options := &storage.PutOptions{
+		Metadata:     file.Attrs.Metadata(),
+		StorageClass: u.options.StorageClass,
+	}
+	u.applyHeaders(&options.Headers, file.Key)
+	return options
+}
+
+// hasHeaders 判断对象键是否匹配任何头规则
+func (u *Upload) hasHeaders(key string) bool {
+	for _, rule := range u.options.Headers {
+		if rule.matches(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyHeaders 将匹配的头规则应用到上传请求，后面的规则覆盖前面的
+func (u *Upload) applyHeaders(headers *storage.Headers, key string) {
+	for _, rule := range u.options.Headers {
+		if !rule.matches(key) {
+			continue
+		}
+		if rule.CacheControl != "" {
+			headers.CacheControl = rule.CacheControl
+		}
+		if rule.ContentEncoding != "" {
+			headers.ContentEncoding = rule.ContentEncoding
+		}
+		if rule.ContentType != "" {
+			headers.ContentType = rule.ContentType
+		}
+		if rule.ContentDisposition != "" {
+			headers.ContentDisposition = rule.ContentDisposition
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package upload
+
+import (
+	"encoding/json"
+	"os"
+
+	"objectsync/internal/journal"
+	"objectsync/internal/state"
+	"objectsync/internal/storage"
+)
+
+// resume 上次运行中断时从运行日志中找出已经上传、之后没有修改过的文件，返回仍需上传的文件。
+// 没有可继续的运行时丢弃旧的运行日志并原样返回
+func (u *Upload) resume(files []*LocalFile) []*LocalFile {
+	if u.options.StateFile == "" {
+		return files
+	}
+	path := journal.Path(u.options.StateFile)
+
+	unfinished, err := journal.Load(path)
+	if err != nil {
+		logger.Warnf("无法读取运行日志 %s，重新开始: %v", path, err)
+	}
+	if unfinished == nil {
+		// 无法读取的日志同样丢弃
+		os.Remove(path)
+		return files
+	}
+	// 中断的文件无论是否继续都重新上传，放弃遗留的分片
+	u.abortMultiparts(unfinished)
+	if !u.options.Resume {
+		// 不继续时丢弃上次留下的运行日志，避免以后误用过期的记录
+		os.Remove(path)
+		return files
+	}
+	if err := unfinished.Check(u.options.Bucket, u.options.Prefix, u.journalOptions()); err != nil {
+		logger.Infof("不继续上次中断的运行: %v", err)
+		os.Remove(path)
+		return files
+	}
+
+	j, err := journal.Open(path, unfinished.Elapsed)
+	if err != nil {
+		logger.Warnf("无法读取运行日志 %s，重新开始: %v", path, err)
+		return files
+	}
+	u.journal = j
+	for key := range unfinished.Multipart {
+		j.Multipart(key, "")
+	}
+	u.resumed = make(map[string]state.Entry)
+
+	var doneSize int64
+	remaining := files[:0]
+	for _, file := range files {
+		var entry state.Entry
+		raw, done := unfinished.Done[file.Key]
+		if done && json.Unmarshal(raw, &entry) == nil &&
+			entry.Size == file.Size && entry.LastModified.Equal(file.LastModified) {
+			u.resumed[file.Key] = entry
+			doneSize += file.Size
+			continue
+		}
+		remaining = append(remaining, file)
+	}
+
+	logger.Infof("继续 %s 开始的中断运行：已完成 %d 个文件，剩余 %d 个",
+		unfinished.Started.Format("2006-01-02 15:04:05"), len(u.resumed), len(remaining))
+	u.progress.Resume(int64(len(u.resumed)), doneSize, unfinished.Elapsed)
+	return remaining
+}
+
+// journalOptions 返回影响上传内容的选项的哈希，这些选项改变后已上传的对象不能沿用
+func (u *Upload) journalOptions() string {
+	return journal.Hash(struct {
+		Sources       []Source
+		Include       []string
+		Exclude       []string
+		MaxFileSize   int64
+		DirMarkers    string
+		Checksum      string
+		PackThreshold int64
+		PackSize      int64
+		Headers       []HeaderRule
+		Compress      []CompressRule
+		StorageClass  string
+	}{u.sources(), u.options.Include, u.options.Exclude, u.options.MaxFileSize, u.options.DirMarkers, u.options.Checksum,
+		u.options.PackThreshold, u.options.PackSize, u.options.Headers, u.options.Compress, u.options.StorageClass})
+}
+
+// abortMultiparts 放弃上次运行中断时没有结束的分片上传，避免已上传的分片一直占用存储空间
+func (u *Upload) abortMultiparts(unfinished *journal.Unfinished) {
+	if unfinished.Bucket != u.options.Bucket {
+		return
+	}
+	for key, uploadID := range unfinished.Multipart {
+		logger.Debugf("放弃中断的分片上传: %s (%s)", key, uploadID)
+		if err := u.storage.AbortMultipart(&storage.Multipart{Key: key, UploadID: uploadID}); err != nil {
+			logger.Debugf("放弃分片上传 %s 失败: %v", key, err)
+		}
+	}
+}
+
+// startMultipart 在运行日志中记录开始的分片上传，中断后下次运行可以放弃遗留的分片
+func (u *Upload) startMultipart(upload *storage.Multipart) {
+	if u.journal == nil {
+		return
+	}
+	if err := u.journal.Multipart(upload.Key, upload.UploadID); err != nil {
+		logger.Debugf("写入运行日志失败: %v", err)
+	}
+}
+
+// endMultipart 在运行日志中记录对象key的分片上传已经完成或放弃
+func (u *Upload) endMultipart(key string) {
+	if u.journal == nil {
+		return
+	}
+	if err := u.journal.Multipart(key, ""); err != nil {
+		logger.Debugf("写入运行日志失败: %v", err)
+	}
+}
+
+// startJournal 开始记录本次运行已上传的文件，继续上次的运行时沿用原来的日志
+func (u *Upload) startJournal() {
+	if u.journal != nil || u.options.StateFile == "" {
+		return
+	}
+	j, err := journal.Create(journal.Path(u.options.StateFile), u.options.Bucket, u.options.Prefix, u.journalOptions(), nil)
+	if err != nil {
+		// 运行日志只用于中断后继续，写不了不影响本次上传
+		logger.Warnf("无法创建运行日志: %v", err)
+		return
+	}
+	u.journal = j
+}
+
+// markDone 在运行日志和状态中记录文件已上传完成及其状态
+func (u *Upload) markDone(file *LocalFile) {
+	u.checkpointDone(file)
+	if u.journal == nil {
+		return
+	}
+	if err := u.journal.Done(file.Key, u.fileState(file)); err != nil {
+		logger.Debugf("写入运行日志失败: %v", err)
+	}
+}
+
+// closeJournal 运行失败时保留运行日志，下次运行从中断处继续
+func (u *Upload) closeJournal() {
+	if u.journal != nil {
+		u.journal.Close()
+	}
+}
+
+// finishJournal 运行正常结束，删除运行日志
+func (u *Upload) finishJournal() {
+	if u.journal == nil {
+		return
+	}
+	if err := u.journal.Finish(); err != nil {
+		logger.Warnf("删除运行日志失败: %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package upload
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"objectsync/internal/i18n"
+	"objectsync/internal/s3client"
+)
+
+// errFileLocked 文件被其他进程独占打开，重试后仍无法读取
+var errFileLocked = i18n.New("文件被其他进程占用")
+
+// lockedRetryAttempts 遇到共享冲突时的打开重试次数
+const lockedRetryAttempts = 3
+
+// lockedRetryDelay 遇到共享冲突时的首次重试等待时间
+const lockedRetryDelay = time.Second
+
+// openLocalFile 打开本地文件，遇到共享冲突时按退避重试，仍失败时尝试从卷影副本读取。
+// 从卷影副本读取过的文件之后都读取副本，使校验值与上传的内容一致
+func (u *Upload) openLocalFile(file *LocalFile) (*os.File, error) {
+	if file.snapshotPath != "" {
+		return os.Open(file.snapshotPath)
+	}
+
+	var err error
+	for attempt := 1; attempt <= lockedRetryAttempts; attempt++ {
+		var localFile *os.File
+		localFile, err = os.Open(file.Path)
+		if err == nil {
+			return localFile, nil
+		}
+		if !isSharingViolation(err) {
+			return nil, err
+		}
+		if attempt < lockedRetryAttempts {
+			time.Sleep(s3client.BackoffDelay(lockedRetryDelay, attempt))
+		}
+	}
+
+	if !u.options.UseVSS {
+		return nil, fmt.Errorf("%w: %v", errFileLocked, err)
+	}
+
+	// 从卷影副本读取被占用的文件
+	snapshot, snapErr := u.shadowCopy(file.Path)
+	if snapErr != nil {
+		return nil, fmt.Errorf("%w: %v（%v）", errFileLocked, err, snapErr)
+	}
+	snapshotPath, snapErr := snapshot.path(file.Path)
+	if snapErr != nil {
+		return nil, fmt.Errorf("%w: %v（%v）", errFileLocked, err, snapErr)
+	}
+
+	logger.Debugf("文件被占用，从卷影副本读取: %s", file.Path)
+	file.snapshotPath = snapshotPath
+	return os.Open(snapshotPath)
+}
+
+// shadowCopy 首次需要时为文件所在卷创建卷影副本
+func (u *Upload) shadowCopy(path string) (*shadowCopy, error) {
+	u.vssOnce.Do(func() {
+		logger.Infof("正在为 %s 所在的卷创建卷影副本...", path)
+		u.vss, u.vssErr = createShadowCopy(path)
+	})
+	return u.vss, u.vssErr
+}
+
+// releaseShadowCopy 删除运行期间创建的卷影副本
+func (u *Upload) releaseShadowCopy() {
+	if u.vss == nil {
+		return
+	}
+	if err := u.vss.release(); err != nil {
+		logger.Warnf("%v", err)
+	}
+	u.vss = nil
+}
+
+// markLocked 将文件标记为因占用而跳过
+func (u *Upload) markLocked(file *LocalFile) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	file.Deferred = true
+	u.locked = append(u.locked, file)
+}
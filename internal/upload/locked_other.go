@@ -0,0 +1,28 @@
+//go:build !windows
+
+package upload
+
+import "objectsync/internal/i18n"
+
+// isSharingViolation 非Windows系统没有独占打开的概念
+func isSharingViolation(err error) bool {
+	return false
+}
+
+// shadowCopy 卷影副本，仅Windows支持
+type shadowCopy struct{}
+
+// createShadowCopy 非Windows系统不支持卷影副本
+func createShadowCopy(path string) (*shadowCopy, error) {
+	return nil, i18n.Errorf("卷影副本仅在Windows上可用")
+}
+
+// path 非Windows系统不支持卷影副本
+func (s *shadowCopy) path(path string) (string, error) {
+	return "", i18n.Errorf("卷影副本仅在Windows上可用")
+}
+
+// release 非Windows系统不支持卷影副本
+func (s *shadowCopy) release() error {
+	return nil
+}
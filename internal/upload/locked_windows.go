@@ -0,0 +1,79 @@
+//go:build windows
+
+package upload
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"objectsync/internal/i18n"
+
+	"golang.org/x/sys/windows"
+)
+
+// isSharingViolation 判断错误是否因为文件被其他进程独占打开
+func isSharingViolation(err error) bool {
+	return errors.Is(err, windows.ERROR_SHARING_VIOLATION) || errors.Is(err, windows.ERROR_LOCK_VIOLATION)
+}
+
+// shadowCopy 卷影副本（VSS快照）
+type shadowCopy struct {
+	id     string
+	device string
+	volume string
+}
+
+// createShadowCopy 为指定路径所在的卷创建卷影副本，需要管理员权限
+func createShadowCopy(path string) (*shadowCopy, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	volume := filepath.VolumeName(absPath)
+	if volume == "" {
+		return nil, i18n.Errorf("无法确定 %s 所在的卷", path)
+	}
+
+	script := fmt.Sprintf(`$r = Invoke-CimMethod -ClassName Win32_ShadowCopy -MethodName Create -Arguments @{Volume='%s\'; Context='ClientAccessible'}
+if ($r.ReturnValue -ne 0) { Write-Error "Win32_ShadowCopy.Create 返回 $($r.ReturnValue)"; exit 1 }
+$s = Get-CimInstance Win32_ShadowCopy | Where-Object { $_.ID -eq $r.ShadowID }
+Write-Output $s.ID
+Write-Output $s.DeviceObject`, volume)
+
+	output, err := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script).CombinedOutput()
+	if err != nil {
+		return nil, i18n.Errorf("创建卷影副本失败: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	lines := strings.Fields(string(output))
+	if len(lines) < 2 {
+		return nil, i18n.Errorf("创建卷影副本失败: 无法解析输出 %q", string(output))
+	}
+
+	return &shadowCopy{id: lines[0], device: lines[1], volume: volume}, nil
+}
+
+// path 将原始路径映射为卷影副本中的路径
+func (s *shadowCopy) path(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if !strings.EqualFold(filepath.VolumeName(absPath), s.volume) {
+		return "", i18n.Errorf("%s 不在卷 %s 上", path, s.volume)
+	}
+	return s.device + strings.TrimPrefix(absPath, filepath.VolumeName(absPath)), nil
+}
+
+// release 删除卷影副本
+func (s *shadowCopy) release() error {
+	script := fmt.Sprintf(`Get-CimInstance Win32_ShadowCopy | Where-Object { $_.ID -eq '%s' } | Remove-CimInstance`, s.id)
+	output, err := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script).CombinedOutput()
+	if err != nil {
+		return i18n.Errorf("删除卷影副本失败: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
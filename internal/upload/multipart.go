@@ -0,0 +1,31 @@
+package upload
+
+import (
+	"os"
+
+	"objectsync/internal/storage"
+)
+
+// multipartThreshold 超过该大小的文件使用分片上传
+const multipartThreshold = 64 << 20
+
+// multipartPartSize 分片大小，文件过大时自动增大以满足分片数量上限
+const multipartPartSize = 16 << 20
+
+// uploadMultipart 分片上传大文件到key，PartsConcurrency控制单个文件同时上传的分片数
+func (u *Upload) uploadMultipart(file *LocalFile, key string, body *os.File, size int64, options *storage.PutOptions) error {
+	// 分片上传的附加校验值按分片计算，由服务端逐片校验，无法与整文件校验值比较
+	options.ChecksumAlgorithm = u.options.Checksum
+
+	etag, err := storage.UploadMultipart(u.storage, key, body, size, options, storage.MultipartOptions{
+		PartSize:    multipartPartSize,
+		Concurrency: u.options.PartsConcurrency,
+		Started:     u.startMultipart,
+	})
+	u.endMultipart(key)
+	if err != nil {
+		return err
+	}
+	file.ETag = etag
+	return nil
+}
@@ -0,0 +1,183 @@
+package upload
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+
+	"objectsync/internal/i18n"
+	"objectsync/internal/pack"
+	"objectsync/internal/state"
+	"objectsync/internal/storage"
+)
+
+// defaultPackSize 默认的单个打包对象大小
+const defaultPackSize = 64 << 20
+
+// groupPacks 将小于打包阈值的文件按目标大小分组，返回不需要打包的文件和分组结果
+func (u *Upload) groupPacks(files []*LocalFile) ([]*LocalFile, [][]*LocalFile) {
+	if u.options.PackThreshold <= 0 {
+		return files, nil
+	}
+
+	packSize := u.options.PackSize
+	if packSize <= 0 {
+		packSize = defaultPackSize
+	}
+
+	var rest, small []*LocalFile
+	for _, file := range files {
+		// 需要压缩或设置HTTP头的文件单独上传
+		if !file.IsDir && file.Size < u.options.PackThreshold && u.compressAlgorithm(file.Key) == "" && !u.hasHeaders(file.Key) {
+			small = append(small, file)
+		} else {
+			rest = append(rest, file)
+		}
+	}
+
+	// 只有一个小文件时打包没有意义
+	if len(small) < 2 {
+		return files, nil
+	}
+
+	var groups [][]*LocalFile
+	var current []*LocalFile
+	var currentSize int64
+	for _, file := range small {
+		if len(current) > 0 && currentSize+file.Size > packSize {
+			groups = append(groups, current)
+			current = nil
+			currentSize = 0
+		}
+		current = append(current, file)
+		currentSize += file.Size
+	}
+	if len(current) > 0 {
+		groups = append(groups, current)
+	}
+
+	return rest, groups
+}
+
+// uploadPacks 依次上传所有打包对象
+func (u *Upload) uploadPacks(groups [][]*LocalFile) error {
+	for i, group := range groups {
+		packKey := pack.NewKey(i)
+		if err := u.uploadPack(packKey, group); err != nil {
+			return i18n.Errorf("上传打包对象 %s 失败: %w", packKey, err)
+		}
+	}
+	return nil
+}
+
+// uploadPack 将一组小文件打包为tar对象上传，并上传对应的索引。
+// 与逐个上传相同，仍在写入的文件推迟，被占用的文件跳过
+func (u *Upload) uploadPack(packKey string, files []*LocalFile) error {
+	// 先写入临时文件，上传请求需要可重复读取的请求体
+	tmp, err := os.CreateTemp("", "objectsync-pack-*.tar")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	files, index, err := u.writePack(tmp, packKey, files)
+	if err != nil || len(files) == 0 {
+		return err
+	}
+
+	logger.Debugf("上传打包对象: %s（%d 个文件）", packKey, len(files))
+
+	indexData, err := pack.EncodeIndex(index)
+	if err != nil {
+		return err
+	}
+
+	var etag string
+	err = u.withRetry(packKey, func() error {
+		if _, err := tmp.Seek(0, 0); err != nil {
+			return err
+		}
+		etag, err = u.storage.Put(packKey, tmp, &storage.PutOptions{StorageClass: u.options.StorageClass})
+		if err != nil {
+			return err
+		}
+		_, err = u.storage.Put(pack.IndexKey(packKey), bytes.NewReader(indexData), &storage.PutOptions{
+			Headers:      storage.Headers{ContentType: "application/json"},
+			StorageClass: u.options.StorageClass,
+		})
+		if err != nil {
+			return i18n.Errorf("上传打包索引失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 记录文件所在的打包对象，并更新进度
+	for _, file := range files {
+		file.ETag = etag
+		file.Pack = packKey
+		u.progress.AddFile(file.Key, file.Size)
+		u.markDone(file)
+	}
+
+	return nil
+}
+
+// writePack 打开文件并写入打包数据，返回实际打包的文件和索引
+func (u *Upload) writePack(w io.Writer, packKey string, files []*LocalFile) ([]*LocalFile, *pack.Index, error) {
+	var packed []*LocalFile
+	var packFiles []pack.File
+	var opened []*os.File
+	defer func() {
+		for _, localFile := range opened {
+			localFile.Close()
+		}
+	}()
+
+	for _, file := range files {
+		// 文件在扫描后发生变化，说明仍在写入
+		if u.options.StableWindow > 0 && u.fileChanged(file) {
+			u.markDeferred(file)
+			continue
+		}
+
+		localFile, err := u.openLocalFile(file)
+		if errors.Is(err, errFileLocked) {
+			logger.Warnf("跳过被占用的文件 %s: %v", file.Path, err)
+			u.markLocked(file)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		opened = append(opened, localFile)
+
+		// 记录打包内容的SHA-256，之后只有修改时间变化时可以判断内容是否相同
+		if file.SHA256, err = state.Hash(localFile); err != nil {
+			return nil, nil, err
+		}
+		if _, err := localFile.Seek(0, 0); err != nil {
+			return nil, nil, err
+		}
+		info, err := localFile.Stat()
+		if err != nil {
+			return nil, nil, err
+		}
+		packFiles = append(packFiles, pack.File{Key: file.Key, Info: info, Body: localFile})
+		packed = append(packed, file)
+	}
+
+	if len(packed) == 0 {
+		return nil, nil, nil
+	}
+
+	index, err := pack.Write(w, packKey, packFiles)
+	if err != nil {
+		return nil, nil, err
+	}
+	return packed, index, nil
+}
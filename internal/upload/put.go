@@ -0,0 +1,85 @@
+package upload
+
+import (
+	"io"
+	"os"
+
+	"objectsync/internal/fileattr"
+	"objectsync/internal/i18n"
+	"objectsync/internal/progress"
+	"objectsync/internal/storage"
+)
+
+// StdinPath 表示从标准输入读取数据
+const StdinPath = "-"
+
+// Put 上传单个本地文件或标准输入（path为"-"）到指定对象键
+func (u *Upload) Put(path, key string) error {
+	// 初始化S3客户端
+	if err := u.initStorage(); err != nil {
+		return i18n.Errorf("初始化S3客户端失败: %w", err)
+	}
+
+	// 确保存储桶存在
+	if err := u.ensureBucketExists(); err != nil {
+		return i18n.Errorf("确保存储桶存在失败: %w", err)
+	}
+
+	options := &storage.PutOptions{StorageClass: u.options.StorageClass}
+
+	var body io.Reader
+	var counter *countingReader
+	size := int64(-1)
+	if path == StdinPath {
+		// 标准输入长度未知，统计实际读取的字节数
+		counter = &countingReader{reader: os.Stdin}
+		body = counter
+	} else {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return i18n.Errorf("%s 是目录，请使用 upload 命令上传目录", path)
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		body = file
+		options.Metadata = fileattr.FromFileInfo(info).Metadata()
+		size = info.Size()
+	}
+
+	logger.Debugf("上传: %s -> %s/%s", path, u.options.Bucket, key)
+
+	// 分片上传，支持未知长度的流式数据
+	_, err := storage.UploadMultipart(u.storage, key, body, size, options, storage.MultipartOptions{
+		Concurrency: u.options.PartsConcurrency,
+	})
+	if err != nil {
+		return err
+	}
+
+	if counter != nil {
+		size = counter.count
+	}
+	logger.Infof("已上传 %s 到 %s/%s", progress.FormatSize(size), u.options.Bucket, key)
+	return nil
+}
+
+// countingReader 统计读取的字节数
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+// Read 实现io.Reader接口
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
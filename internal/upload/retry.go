@@ -0,0 +1,49 @@
+package upload
+
+import (
+	"errors"
+	"time"
+
+	"objectsync/internal/s3client"
+)
+
+// uploadFileWithRetry 上传单个文件，校验不一致时按指数退避重新上传
+func (u *Upload) uploadFileWithRetry(file *LocalFile) error {
+	return u.withRetry(file.Key, func() error {
+		if err := u.checkConflict(file); err != nil {
+			return err
+		}
+		return u.uploadFile(file)
+	})
+}
+
+// withRetry 执行上传操作，需要重新上传时按指数退避重试
+func (u *Upload) withRetry(name string, fn func() error) error {
+	attempts := u.options.MaxAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = fn()
+		if err == nil || !isRetryable(err) || attempt == attempts {
+			break
+		}
+
+		delay := s3client.BackoffDelay(u.options.RetryDelay, attempt)
+		logger.Warnf("上传 %s 失败（第 %d/%d 次）: %v，%s 后重试", name, attempt, attempts, err, delay)
+		u.progress.AddRetries(1)
+		time.Sleep(delay)
+	}
+
+	return err
+}
+
+// isRetryable 判断是否需要重新上传整个文件
+// 网络错误、5xx和限流等请求级别的临时错误已由SDK按retry配置重试，这里不再重复重试
+func isRetryable(err error) bool {
+	// 校验不一致可能是网关异常截断，重新上传
+	var verifyErr *verifyError
+	return errors.As(err, &verifyErr)
+}
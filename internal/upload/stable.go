@@ -0,0 +1,77 @@
+package upload
+
+import (
+	"os"
+	"time"
+
+	"objectsync/internal/i18n"
+)
+
+// errFileChanging 文件在扫描后仍在变化，推迟到下次上传
+var errFileChanging = i18n.New("文件正在写入")
+
+// deferStable 检查最近修改过的文件在稳定窗口内是否仍在变化，返回可以上传的文件
+// 仍在变化的文件会被标记为推迟，不会上传也不会记录到状态中
+func (u *Upload) deferStable(files []*LocalFile) []*LocalFile {
+	window := u.options.StableWindow
+	if window <= 0 {
+		return files
+	}
+
+	// 找出修改时间在窗口内的文件，只有它们可能正在写入
+	var recent []*LocalFile
+	now := time.Now()
+	for _, file := range files {
+		if !file.IsDir && now.Sub(file.LastModified) < window {
+			recent = append(recent, file)
+		}
+	}
+	if len(recent) == 0 {
+		return files
+	}
+
+	logger.Debugf("%d 个文件最近被修改，等待 %s 检查是否仍在写入...", len(recent), window)
+	time.Sleep(window)
+
+	for _, file := range recent {
+		if u.fileChanged(file) {
+			u.markDeferred(file)
+		}
+	}
+
+	stable := files[:0]
+	for _, file := range files {
+		if !file.Deferred {
+			stable = append(stable, file)
+		}
+	}
+	return stable
+}
+
+// fileChanged 检查文件的大小或修改时间是否与扫描时不同
+func (u *Upload) fileChanged(file *LocalFile) bool {
+	info, err := os.Stat(file.Path)
+	if err != nil {
+		// 文件消失同样视为正在变化
+		return true
+	}
+	return info.Size() != file.Size || !info.ModTime().Equal(file.LastModified)
+}
+
+// markDeferred 将文件标记为推迟上传
+func (u *Upload) markDeferred(file *LocalFile) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	file.Deferred = true
+	u.deferred = append(u.deferred, file)
+}
+
+// printDeferred 在总结中列出被推迟的文件
+func (u *Upload) printDeferred() {
+	if len(u.deferred) == 0 {
+		return
+	}
+
+	logger.Warnf("推迟 %d 个正在写入的文件，将在下次上传时处理:%s", len(u.deferred), keyList(u.deferred))
+}
@@ -1,108 +1,217 @@
 package upload
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
+	"path"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"objectsync/internal/fileattr"
+	"objectsync/internal/filter"
+	"objectsync/internal/i18n"
+	"objectsync/internal/journal"
+	"objectsync/internal/logging"
 	"objectsync/internal/progress"
+	"objectsync/internal/ratelimit"
+	"objectsync/internal/s3client"
+	"objectsync/internal/state"
+	"objectsync/internal/statestore"
+	"objectsync/internal/storage"
+)
+
+// logger 上传模块的日志
+var logger = logging.New("upload")
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
+// 目录标记创建方式
+const (
+	DirMarkersAll   = "all"   // 为所有目录创建标记对象
+	DirMarkersEmpty = "empty" // 仅为空目录创建标记对象
+	DirMarkersNone  = "none"  // 不创建目录标记对象
 )
 
 // Options 上传配置选项
 type Options struct {
-	Endpoint    string
-	AccessKey   string
-	SecretKey   string
-	Bucket      string
-	InputDir    string
-	Incremental bool
-	StateFile   string
-	Workers     int
-	Verbose     bool
-}
-
-// State 上传状态
-type State struct {
-	LastUpload time.Time            `json:"last_upload"`
-	Files      map[string]FileState `json:"files"`
-}
-
-// FileState 文件状态
-type FileState struct {
-	ETag         string    `json:"etag"`
-	LastModified time.Time `json:"last_modified"`
-	Size         int64     `json:"size"`
+	Endpoint           string
+	AccessKey          string
+	SecretKey          string
+	Profile            string                  // 共享凭证文件中的配置名，设置后忽略AccessKey和SecretKey
+	Region             string                  // 签名使用的区域，为空时使用默认值
+	VirtualHosted      bool                    // 使用虚拟主机样式寻址，默认使用路径样式
+	Transport          string                  // S3客户端实现，见 s3client.TransportMinio，为空时使用aws-sdk-go
+	TLS                s3client.TLSOptions     // HTTPS证书选项
+	Proxy              s3client.ProxyOptions   // 代理设置
+	Timeouts           s3client.TimeoutOptions // HTTP超时设置
+	Bucket             string
+	Prefix             string // 对象键前缀，所有源目录都上传到该前缀下
+	InputDir           string
+	Sources            []Source // 多个本地源目录，为空时使用InputDir
+	Incremental        bool
+	StateFile          string
+	StateBackend       string        // 状态存储后端（statestore.BackendJSON/BackendBolt），空值使用JSON文件
+	CheckpointFiles    int           // 运行中每上传多少个文件保存一次状态，0表示不按数量保存
+	CheckpointInterval time.Duration // 运行中每隔多久保存一次状态，0表示不按时间保存
+	PruneDeletedAfter  time.Duration // 已删除的本地文件的记录在状态中保留的时间，0表示运行成功后立即清理
+	Workers            int
+	ScanWorkers        int                  // 并发扫描目录数，0表示使用默认值
+	DirMarkers         string               // 目录标记创建方式，空值等同于DirMarkersAll
+	MaxAttempts        int                  // 单个文件的最大尝试次数
+	RetryDelay         time.Duration        // 首次重试前的等待时间，之后按指数增长
+	Verify             bool                 // 上传后通过HEAD校验大小和ETag
+	StableWindow       time.Duration        // 文件稳定检查窗口，0表示不检查
+	MaxFileSize        int64                // 单个文件大小上限，超过的文件跳过，0表示不限制
+	UseVSS             bool                 // 文件被占用时从卷影副本读取（仅Windows）
+	Checksum           string               // 附加校验算法（CRC32/CRC32C/SHA1/SHA256），空表示不使用
+	PackThreshold      int64                // 小于该大小的文件打包上传，0表示不打包
+	PackSize           int64                // 单个打包对象的目标大小，0表示使用默认值
+	RateLimiter        *ratelimit.Limiter   // 请求速率限制器，可在多个桶之间共享
+	Headers            []HeaderRule         // 按文件名模式设置的HTTP头
+	Compress           []CompressRule       // 按文件名模式压缩上传
+	Include            []string             // 包含模式，为空时包含所有文件
+	Exclude            []string             // 排除模式
+	Dedupe             bool                 // 内容相同的文件使用服务端复制代替重复上传
+	PartsConcurrency   int                  // 单个大文件同时上传的分片数，0表示使用默认值
+	Conflict           string               // 远程对象比本地新时的处理方式（ConflictWarn/ConflictSkip），空表示不检查
+	StorageClass       string               // 上传对象使用的存储类别，空表示使用服务端默认值
+	Bandwidth          *ratelimit.Bandwidth // 带宽限制器，同一个桶的所有工作协程共享
+	Resume             bool                 // 上次运行中断时从运行日志继续，跳过已经上传的文件
+	Parent             *progress.Tracker    // 汇总多个桶进度的跟踪器，可以为空
+	WorkerProgress     bool                 // 在终端中显示每个工作协程的进度，详细模式下不显示
+	Reporter           progress.Reporter    // 接收进度事件，设置后不在终端中输出进度，用于嵌入时显示自己的界面
+	Storage            storage.Storage      // 访问桶使用的存储，为nil时按连接选项创建S3客户端；可以换成其他后端或测试用的实现
+	Verbose            bool
 }
 
 // Upload 上传器
 type Upload struct {
-	options  *Options
-	s3       *s3.S3
-	state    *State
-	progress *progress.Tracker
+	options    *Options
+	storage    storage.Storage
+	state      statestore.Store[state.Entry] // 未启用增量上传时为nil
+	checkpoint *statestore.Checkpoint[state.Entry]
+	progress   *progress.Tracker
+	deferred   []*LocalFile
+	oversize   []*LocalFile
+	locked     []*LocalFile
+	conflicts  []*LocalFile
+	journal    *journal.Journal       // 进行中运行的日志
+	resumed    map[string]state.Entry // 上次中断前已经上传的文件
+	scanned    map[string]bool        // 本次扫描到的所有本地文件，用于记录删除的文件
+	touched    []*LocalFile           // 只有修改时间变化、内容与上次上传时相同的文件，只更新状态中的修改时间
+	stopped    atomic.Bool
+	mutex      sync.Mutex
+
+	vssOnce sync.Once
+	vss     *shadowCopy
+	vssErr  error
 }
 
 // New 创建新的上传器
 func New(options *Options) *Upload {
+	tracker := progress.New(options.Verbose)
+	if options.Parent != nil {
+		tracker.SetParent(options.Parent)
+	}
+	if options.WorkerProgress && !options.Verbose {
+		tracker.ShowWorkers()
+	}
+	if options.Reporter != nil {
+		tracker.SetReporter(options.Reporter)
+	}
 	return &Upload{
 		options:  options,
-		state:    &State{Files: make(map[string]FileState)},
-		progress: progress.New(options.Verbose),
+		progress: tracker,
 	}
 }
 
+// Stop 停止上传，正在上传的文件完成后不再上传新的文件
+func (u *Upload) Stop() {
+	u.stopped.Store(true)
+}
+
+// Progress 返回本次上传的当前进度
+func (u *Upload) Progress() progress.Snapshot {
+	return u.progress.Snapshot()
+}
+
+// Stats 返回本次上传的统计
+func (u *Upload) Stats() progress.Stats {
+	return u.progress.Stats()
+}
+
 // Run 执行上传
 func (u *Upload) Run() error {
+	// 锁定状态文件，另一个运行正在改写时立即失败
+	lock, err := u.lockState()
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
 	// 初始化S3客户端
-	if err := u.initS3Client(); err != nil {
-		return fmt.Errorf("初始化S3客户端失败: %w", err)
+	if err := u.initStorage(); err != nil {
+		return i18n.Errorf("初始化S3客户端失败: %w", err)
 	}
 
 	// 确保存储桶存在
 	if err := u.ensureBucketExists(); err != nil {
-		return fmt.Errorf("确保存储桶存在失败: %w", err)
+		return i18n.Errorf("确保存储桶存在失败: %w", err)
 	}
 
+	// 运行结束后删除可能创建的卷影副本
+	defer u.releaseShadowCopy()
+
 	// 加载上传状态
 	if err := u.loadState(); err != nil {
-		return fmt.Errorf("加载上传状态失败: %w", err)
+		return i18n.Errorf("加载上传状态失败: %w", err)
 	}
+	defer u.closeState()
 
 	// 检查输入目录
-	if _, err := os.Stat(u.options.InputDir); os.IsNotExist(err) {
-		return fmt.Errorf("输入目录不存在: %s", u.options.InputDir)
+	for _, source := range u.sources() {
+		if _, err := os.Stat(source.Dir); os.IsNotExist(err) {
+			return i18n.Errorf("输入目录不存在: %s", source.Dir)
+		}
 	}
 
-	// 扫描本地文件
-	files, err := u.scanLocalFiles()
+	// 并发扫描本地文件，扫描结果直接进入过滤阶段
+	fileCount, toUpload, err := u.scanLocalFiles()
 	if err != nil {
-		return fmt.Errorf("扫描本地文件失败: %w", err)
+		return i18n.Errorf("扫描本地文件失败: %w", err)
 	}
 
-	if u.options.Verbose {
-		fmt.Printf("发现 %d 个文件\n", len(files))
-	}
+	// 跳过超过大小限制的文件
+	toUpload = u.skipOversized(toUpload)
 
-	// 过滤需要上传的文件
-	toUpload := u.filterFiles(files)
-	if u.options.Verbose {
-		fmt.Printf("需要上传 %d 个文件\n", len(toUpload))
-	}
+	// 跳过仍在写入的文件
+	toUpload = u.deferStable(toUpload)
+
+	// 上次运行中断时跳过已经上传的文件
+	toUpload = u.resume(toUpload)
+
+	logger.Debugf("发现 %d 个文件", fileCount)
+	logger.Debugf("需要上传 %d 个文件", len(toUpload))
 
 	if len(toUpload) == 0 {
-		fmt.Println("没有需要上传的文件")
+		logger.Infof("没有需要上传的文件")
+		u.printSkipped()
+		if u.journal != nil {
+			// 继续的运行在中断前已经上传完所有文件，只差保存状态
+			if err := u.saveState(nil); err != nil {
+				u.closeJournal()
+				return i18n.Errorf("保存上传状态失败: %w", err)
+			}
+			u.finishJournal()
+		} else if err := u.saveDeleted(); err != nil {
+			return i18n.Errorf("保存上传状态失败: %w", err)
+		}
 		return nil
 	}
+	u.startJournal()
 
 	// 计算总大小并设置进度跟踪
 	var totalSize int64
@@ -111,25 +220,97 @@ func (u *Upload) Run() error {
 	}
 	u.progress.SetTotal(int64(len(toUpload)), totalSize)
 
+	// 小文件打包上传，其余文件逐个上传
+	files, packs := u.groupPacks(toUpload)
+
+	// 内容重复的文件改为服务端复制
+	files, copies, err := u.planCopies(files)
+	if err != nil {
+		u.closeJournal()
+		return i18n.Errorf("查找重复文件失败: %w", err)
+	}
+
 	// 上传文件
-	if err := u.uploadFiles(toUpload); err != nil {
-		return fmt.Errorf("上传文件失败: %w", err)
+	if err := u.uploadFiles(files); err != nil {
+		u.closeJournal()
+		return i18n.Errorf("上传文件失败: %w", err)
+	}
+
+	// 复制来源上传完成后再复制
+	resolveCopies(copies, files)
+	if err := u.uploadFiles(copies); err != nil {
+		u.closeJournal()
+		return i18n.Errorf("复制文件失败: %w", err)
+	}
+
+	// 上传打包对象
+	if err := u.uploadPacks(packs); err != nil {
+		u.closeJournal()
+		return i18n.Errorf("上传打包对象失败: %w", err)
 	}
 
 	// 显示最终统计信息
+	u.progress.AddSkipped(len(u.oversize) + len(u.locked) + len(u.conflicts) + len(u.deferred))
 	u.progress.PrintFinal()
+	u.printSkipped()
 
-	// 更新上传状态
-	u.updateState(toUpload)
-
-	// 保存状态
-	if err := u.saveState(); err != nil {
-		return fmt.Errorf("保存上传状态失败: %w", err)
+	// 更新并保存上传状态
+	if err := u.saveState(toUpload); err != nil {
+		u.closeJournal()
+		return i18n.Errorf("保存上传状态失败: %w", err)
 	}
 
+	u.finishJournal()
 	return nil
 }
 
+// Source 本地源目录及其在桶中对应的键前缀
+type Source struct {
+	Dir    string
+	Prefix string
+}
+
+// Pending 扫描本地文件并与状态记录比较，返回下次上传需要上传的文件数和数据量，不连接对象存储。
+// 超过大小限制的文件不计入，最近修改的文件不等待确认是否仍在写入
+func (u *Upload) Pending() (int64, int64, error) {
+	if err := u.loadState(); err != nil {
+		return 0, 0, i18n.Errorf("加载上传状态失败: %w", err)
+	}
+	defer u.closeState()
+	for _, source := range u.sources() {
+		if _, err := os.Stat(source.Dir); os.IsNotExist(err) {
+			return 0, 0, i18n.Errorf("输入目录不存在: %s", source.Dir)
+		}
+	}
+
+	_, toUpload, err := u.scanLocalFiles()
+	if err != nil {
+		return 0, 0, i18n.Errorf("扫描本地文件失败: %w", err)
+	}
+
+	var count, size int64
+	for _, file := range toUpload {
+		if !file.IsDir && u.options.MaxFileSize > 0 && file.Size > u.options.MaxFileSize {
+			continue
+		}
+		count++
+		size += file.Size
+	}
+	return count, size, nil
+}
+
+// sources 返回所有需要上传的源目录，源目录的前缀位于Prefix之下
+func (u *Upload) sources() []Source {
+	if len(u.options.Sources) == 0 {
+		return []Source{{Dir: u.options.InputDir, Prefix: u.options.Prefix}}
+	}
+	sources := make([]Source, 0, len(u.options.Sources))
+	for _, source := range u.options.Sources {
+		sources = append(sources, Source{Dir: source.Dir, Prefix: normalizePrefix(u.options.Prefix) + source.Prefix})
+	}
+	return sources
+}
+
 // LocalFile 本地文件信息
 type LocalFile struct {
 	Path         string
@@ -137,156 +318,258 @@ type LocalFile struct {
 	Size         int64
 	LastModified time.Time
 	IsDir        bool
+	Attrs        fileattr.Attrs
+	ETag         string // 上传成功后服务器返回的ETag
+	Checksum     string // 附加校验值（base64）
+	Pack         string // 文件被打包上传时所在的打包对象
+	MD5          string // 内容MD5，仅在查找重复内容时计算
+	SHA256       string // 上传的内容的SHA-256，记录到状态中
+	CopySource   string // 内容相同的已有对象键，非空时使用服务端复制
+	Deferred     bool   // 本次未上传（正在写入或被占用），留待下次处理
+
+	snapshotPath string // 文件被占用时卷影副本中的路径，之后计算校验值等都读取副本
 }
 
 // TestConnection 测试连接
 func (u *Upload) TestConnection() error {
 	// 初始化S3客户端
-	if err := u.initS3Client(); err != nil {
+	if err := u.initStorage(); err != nil {
 		return err
 	}
 
 	// 尝试列出桶
-	_, err := u.s3.ListBuckets(&s3.ListBucketsInput{})
+	_, err := u.storage.ListBuckets()
 	return err
 }
 
-// initS3Client 初始化S3客户端
-func (u *Upload) initS3Client() error {
-	sess, err := session.NewSession(&aws.Config{
-		Endpoint:         aws.String(u.options.Endpoint),
-		Credentials:      credentials.NewStaticCredentials(u.options.AccessKey, u.options.SecretKey, ""),
-		Region:           aws.String("us-east-1"), // Ceph通常使用us-east-1
-		S3ForcePathStyle: aws.Bool(true),          // Ceph需要路径样式
-	})
+// initStorage 初始化访问桶的存储，选项中指定了存储时直接使用，否则按端点的类型打开
+func (u *Upload) initStorage() error {
+	if u.options.Storage != nil {
+		u.storage = u.options.Storage
+		return nil
+	}
+
+	store, err := storage.Open(s3client.Options{
+		Endpoint:      u.options.Endpoint,
+		AccessKey:     u.options.AccessKey,
+		SecretKey:     u.options.SecretKey,
+		Profile:       u.options.Profile,
+		Region:        u.options.Region,
+		VirtualHosted: u.options.VirtualHosted,
+		Transport:     u.options.Transport,
+		RateLimiter:   u.options.RateLimiter,
+		Bandwidth:     u.options.Bandwidth,
+		MaxAttempts:   u.options.MaxAttempts,
+		RetryDelay:    u.options.RetryDelay,
+		TLS:           u.options.TLS,
+		Proxy:         u.options.Proxy,
+		Timeouts:      u.options.Timeouts,
+	}, u.options.Bucket)
 	if err != nil {
 		return err
 	}
 
-	u.s3 = s3.New(sess)
+	u.storage = store
 	return nil
 }
 
 // ensureBucketExists 确保存储桶存在
 func (u *Upload) ensureBucketExists() error {
 	// 检查桶是否存在
-	_, err := u.s3.HeadBucket(&s3.HeadBucketInput{
-		Bucket: aws.String(u.options.Bucket),
-	})
-
+	exists, err := u.storage.BucketExists()
 	if err != nil {
-		// 如果是404错误，说明桶不存在，需要创建
-		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NotFound" {
-			if u.options.Verbose {
-				fmt.Printf("存储桶 %s 不存在，正在创建...\n", u.options.Bucket)
-			}
+		return i18n.Errorf("检查存储桶失败: %w", err)
+	}
 
-			// 创建桶
-			_, err = u.s3.CreateBucket(&s3.CreateBucketInput{
-				Bucket: aws.String(u.options.Bucket),
-			})
-			if err != nil {
-				return fmt.Errorf("创建存储桶失败: %w", err)
-			}
+	// 桶不存在，需要创建
+	if !exists {
+		logger.Infof("存储桶 %s 不存在，正在创建...", u.options.Bucket)
 
-			if u.options.Verbose {
-				fmt.Printf("存储桶 %s 创建成功\n", u.options.Bucket)
-			}
-		} else {
-			return fmt.Errorf("检查存储桶失败: %w", err)
+		if err := u.storage.CreateBucket(); err != nil {
+			return i18n.Errorf("创建存储桶失败: %w", err)
 		}
+
+		logger.Infof("存储桶 %s 创建成功", u.options.Bucket)
 	}
 
 	return nil
 }
 
-// loadState 加载上传状态
+// lockState 锁定上传状态文件，没有状态文件时不锁定。未启用增量上传时运行日志仍然写在状态文件旁边，同样需要锁定
+func (u *Upload) lockState() (*statestore.Lock, error) {
+	if u.options.StateFile == "" {
+		return nil, nil
+	}
+	return statestore.Acquire(statestore.Path(u.options.StateFile, u.options.StateBackend))
+}
+
+// loadState 打开上传状态，状态文件不存在时使用空的状态
 func (u *Upload) loadState() error {
 	if !u.options.Incremental {
 		return nil
 	}
 
-	file, err := os.Open(u.options.StateFile)
+	store, err := state.Open(u.options.StateFile, state.Upload, u.options.StateBackend)
 	if err != nil {
-		if os.IsNotExist(err) {
-			// 状态文件不存在，使用默认状态
-			return nil
-		}
 		return err
 	}
-	defer file.Close()
+	u.state = store
+	u.checkpoint = statestore.NewCheckpoint(store, u.options.CheckpointFiles, u.options.CheckpointInterval)
+	return nil
+}
 
-	return json.NewDecoder(file).Decode(u.state)
+// closeState 关闭上传状态。运行失败或被停止时先保存已上传完成的文件，下次运行不再重新上传
+func (u *Upload) closeState() {
+	if u.state == nil {
+		return
+	}
+	if unsaved := u.checkpoint.Unsaved(); unsaved > 0 {
+		if err := u.checkpoint.Flush(); err != nil {
+			logger.Warnf("保存上传状态失败: %v", err)
+		} else {
+			logger.Infof("已保存中断前上传完成的 %d 个文件的状态", unsaved)
+		}
+	}
+	u.state.Close()
+	u.state = nil
+	u.checkpoint = nil
 }
 
-// saveState 保存上传状态
-func (u *Upload) saveState() error {
-	if !u.options.Incremental {
+// saveState 按本次上传的文件更新上传状态并保存
+func (u *Upload) saveState(files []*LocalFile) error {
+	if u.state == nil {
 		return nil
 	}
 
-	u.state.LastUpload = time.Now()
-
-	file, err := os.Create(u.options.StateFile)
-	if err != nil {
+	if err := u.updateState(files); err != nil {
+		return err
+	}
+	if _, err := u.pruneState(); err != nil {
 		return err
 	}
-	defer file.Close()
+	u.state.SetLastRun(time.Now())
+	return u.checkpoint.Flush()
+}
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(u.state)
+// checkpointDone 记录上传完成的文件，定期保存状态
+func (u *Upload) checkpointDone(file *LocalFile) {
+	if u.checkpoint == nil {
+		return
+	}
+	if err := u.checkpoint.Done(file.Key, u.fileState(file)); err != nil {
+		// 结束时还会再保存一次，检查点失败不影响本次上传
+		logger.Warnf("保存上传状态失败: %v", err)
+	}
 }
 
-// scanLocalFiles 扫描本地文件
-func (u *Upload) scanLocalFiles() ([]*LocalFile, error) {
-	var files []*LocalFile
+// saveDeleted 没有需要上传的文件时只记录修改时间变化的文件和删除的本地文件，并清理过期的删除记录，
+// 没有变化时不改写状态
+func (u *Upload) saveDeleted() error {
+	if u.state == nil {
+		return nil
+	}
+	touched, err := u.updateTouched()
+	if err != nil {
+		return err
+	}
+	deleted, err := u.markDeleted()
+	if err != nil {
+		return err
+	}
+	pruned, err := u.pruneState()
+	if err != nil || touched+deleted+pruned == 0 {
+		return err
+	}
+	return u.checkpoint.Flush()
+}
 
-	err := filepath.Walk(u.options.InputDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+// pruneState 清理超过保留时间的删除记录，返回清理的条目数
+func (u *Upload) pruneState() (int, error) {
+	pruned, err := state.Prune(u.state, u.options.PruneDeletedAfter)
+	if pruned > 0 {
+		logger.Infof("已从状态中清理 %d 个已删除文件的记录", pruned)
+	}
+	return pruned, err
+}
 
-		// 计算相对路径作为对象键
-		relPath, err := filepath.Rel(u.options.InputDir, path)
-		if err != nil {
-			return err
-		}
+// recorded 返回状态中记录的文件，未启用增量上传时总是不存在，已删除的文件视为不存在
+func (u *Upload) recorded(key string) (state.Entry, bool) {
+	entry, ok := u.uploaded(key)
+	return entry, ok && !entry.Deleted()
+}
 
-		// 跳过根目录
-		if relPath == "." {
-			return nil
-		}
+// uploaded 返回状态中记录的上次上传，包括本地文件已删除的条目
+func (u *Upload) uploaded(key string) (state.Entry, bool) {
+	if u.state == nil {
+		return state.Entry{}, false
+	}
+	return u.state.Get(key)
+}
 
-		// 将路径分隔符转换为正斜杠（对象存储标准）
-		key := strings.ReplaceAll(relPath, "\\", "/")
+// scanLocalFiles 并发扫描本地文件，返回发现的文件总数和需要上传的文件
+func (u *Upload) scanLocalFiles() (int, []*LocalFile, error) {
+	fileChan := make(chan *LocalFile, 1024)
+	errChan := make(chan error, 1)
 
-		file := &LocalFile{
-			Path:         path,
-			Key:          key,
-			Size:         info.Size(),
-			LastModified: info.ModTime(),
-			IsDir:        info.IsDir(),
+	go func() {
+		defer close(fileChan)
+		for _, source := range u.sources() {
+			if err := walkParallel(source.Dir, source.Prefix, u.options.ScanWorkers, fileChan); err != nil {
+				errChan <- err
+				return
+			}
 		}
+		errChan <- nil
+	}()
 
-		// 如果是目录，添加目录标记（以/结尾）
-		if info.IsDir() {
-			file.Key += "/"
-			file.Size = 0
-		}
+	fileCount, toUpload := u.filterFiles(fileChan)
+	if err := <-errChan; err != nil {
+		return 0, nil, err
+	}
 
-		files = append(files, file)
-		return nil
+	// 并发扫描的结果无序，按对象键排序保证上传顺序稳定
+	sort.Slice(toUpload, func(i, j int) bool {
+		return toUpload[i].Key < toUpload[j].Key
 	})
 
-	return files, err
+	return fileCount, toUpload, nil
 }
 
-// filterFiles 过滤需要上传的文件
-func (u *Upload) filterFiles(files []*LocalFile) []*LocalFile {
+// filterFiles 过滤需要上传的文件，边接收扫描结果边过滤
+func (u *Upload) filterFiles(files <-chan *LocalFile) (int, []*LocalFile) {
 	var toUpload []*LocalFile
+	fileCount := 0
+
+	// 记录包含子项的目录，用于判断目录是否为空
+	nonEmptyDirs := make(map[string]bool)
+
+	include := filter.New(u.options.Include, u.options.Exclude)
+	prefix := normalizePrefix(u.options.Prefix)
+
+	if u.options.Incremental {
+		u.scanned = make(map[string]bool)
+	}
+
+	for file := range files {
+		fileCount++
+		if u.scanned != nil {
+			u.scanned[file.Key] = true
+		}
+
+		// 跳过不匹配包含/排除规则的文件
+		if !include.MatchRelative(file.Key, prefix) {
+			continue
+		}
+
+		if parent := path.Dir(strings.TrimSuffix(file.Key, "/")); parent != "." {
+			nonEmptyDirs[parent+"/"] = true
+		}
+
+		// 不创建目录标记时直接跳过目录
+		if file.IsDir && u.options.DirMarkers == DirMarkersNone {
+			continue
+		}
 
-	for _, file := range files {
 		// 如果不是增量上传，上传所有文件
 		if !u.options.Incremental {
 			toUpload = append(toUpload, file)
@@ -299,23 +582,105 @@ func (u *Upload) filterFiles(files []*LocalFile) []*LocalFile {
 		}
 	}
 
-	return toUpload
+	// 仅为空目录创建标记时，移除非空目录
+	if u.options.DirMarkers == DirMarkersEmpty {
+		filtered := toUpload[:0]
+		for _, file := range toUpload {
+			if file.IsDir && nonEmptyDirs[file.Key] {
+				continue
+			}
+			filtered = append(filtered, file)
+		}
+		toUpload = filtered
+	}
+
+	return fileCount, toUpload
+}
+
+// skipOversized 跳过超过大小上限的文件，并立即给出警告
+func (u *Upload) skipOversized(files []*LocalFile) []*LocalFile {
+	if u.options.MaxFileSize <= 0 {
+		return files
+	}
+
+	kept := files[:0]
+	for _, file := range files {
+		if !file.IsDir && file.Size > u.options.MaxFileSize {
+			logger.Warnf("跳过超过大小限制（%s）的文件: %s (%s)",
+				progress.FormatSize(u.options.MaxFileSize), file.Path, progress.FormatSize(file.Size))
+			u.oversize = append(u.oversize, file)
+			continue
+		}
+		kept = append(kept, file)
+	}
+	return kept
+}
+
+// printSkipped 在总结中列出被跳过和推迟的文件
+func (u *Upload) printSkipped() {
+	if len(u.oversize) > 0 {
+		var total int64
+		for _, file := range u.oversize {
+			total += file.Size
+		}
+		var list strings.Builder
+		for _, file := range u.oversize {
+			fmt.Fprintf(&list, "\n  %s (%s)", file.Key, progress.FormatSize(file.Size))
+		}
+		logger.Warnf("跳过 %d 个超过大小限制的文件（共 %s）:%s", len(u.oversize), progress.FormatSize(total), list.String())
+	}
+
+	if len(u.locked) > 0 {
+		logger.Warnf("跳过 %d 个被其他进程占用的文件:%s", len(u.locked), keyList(u.locked))
+	}
+
+	u.printConflicts()
+	u.printDeferred()
+}
+
+// keyList 将文件的对象键格式化为多行列表，每行一个，附加在日志消息之后
+func keyList(files []*LocalFile) string {
+	var b strings.Builder
+	for _, file := range files {
+		b.WriteString("\n  " + file.Key)
+	}
+	return b.String()
 }
 
 // needsUpload 检查文件是否需要上传
 func (u *Upload) needsUpload(file *LocalFile) bool {
 	// 检查状态记录
-	state, exists := u.state.Files[file.Key]
+	entry, exists := u.recorded(file.Key)
 	if !exists {
 		return true
 	}
 
 	// 比较修改时间和大小
-	if !state.LastModified.Equal(file.LastModified) || state.Size != file.Size {
+	if entry.Size != file.Size {
 		return true
 	}
+	if entry.LastModified.Equal(file.LastModified) {
+		return false
+	}
 
-	return false
+	// 只有修改时间变化（如被touch或重新复制）时，内容与上次上传时相同则不需要重新上传
+	return !u.sameContent(file, entry)
+}
+
+// sameContent 计算文件内容的SHA-256并与上次上传时记录的比较，相同时记录下来，保存状态时更新修改时间。
+// 状态中没有记录SHA-256（旧版本上传或打包上传之前的条目）时视为不同
+func (u *Upload) sameContent(file *LocalFile, entry state.Entry) bool {
+	if file.IsDir || entry.SHA256 == "" {
+		return false
+	}
+	sum, err := state.HashFile(file.Path)
+	if err != nil || sum != entry.SHA256 {
+		return false
+	}
+	logger.Debugf("内容未变化，只更新修改时间: %s", file.Path)
+	file.SHA256 = sum
+	u.touched = append(u.touched, file)
+	return true
 }
 
 // uploadFiles 上传文件
@@ -323,6 +688,7 @@ func (u *Upload) uploadFiles(files []*LocalFile) error {
 	fileChan := make(chan *LocalFile, len(files))
 	errorChan := make(chan error, u.options.Workers)
 	var wg sync.WaitGroup
+	var failed atomic.Bool // 有工作协程出错后其他工作协程不再开始新的传输
 
 	// 启动工作协程
 	for i := 0; i < u.options.Workers; i++ {
@@ -330,10 +696,33 @@ func (u *Upload) uploadFiles(files []*LocalFile) error {
 		go func() {
 			defer wg.Done()
 			for file := range fileChan {
-				if err := u.uploadFile(file); err != nil {
-					errorChan <- fmt.Errorf("上传 %s 失败: %w", file.Key, err)
+				if u.stopped.Load() {
+					errorChan <- i18n.Errorf("已停止")
+					return
+				}
+				if failed.Load() {
+					return
+				}
+				err := u.uploadFileWithRetry(file)
+				if errors.Is(err, errFileChanging) {
+					u.markDeferred(file)
+					continue
+				}
+				if errors.Is(err, errFileLocked) {
+					// 被占用的文件跳过而不是中止整个上传
+					logger.Warnf("跳过被占用的文件 %s: %v", file.Path, err)
+					u.markLocked(file)
+					continue
+				}
+				if errors.Is(err, errRemoteNewer) {
+					u.markConflict(file)
+					continue
+				}
+				if err != nil {
+					errorChan <- i18n.Errorf("上传 %s 失败: %w", file.Key, err)
 					return
 				}
+				u.markDone(file)
 			}
 		}()
 	}
@@ -352,76 +741,209 @@ func (u *Upload) uploadFiles(files []*LocalFile) error {
 		close(errorChan)
 	}()
 
-	// 检查错误
+	// 检查错误，出错后等待正在进行的传输完成，使其记录到状态中
+	var firstErr error
 	for err := range errorChan {
-		if err != nil {
-			return err
+		if err != nil && firstErr == nil {
+			firstErr = err
+			failed.Store(true)
 		}
 	}
 
-	return nil
+	return firstErr
 }
 
 // uploadFile 上传单个文件
 func (u *Upload) uploadFile(file *LocalFile) error {
-	if u.options.Verbose {
-		fmt.Printf("上传: %s -> %s\n", file.Path, file.Key)
+	if file.CopySource != "" {
+		return u.copyFile(file)
 	}
 
+	logger.Debugf("上传: %s -> %s", file.Path, file.Key)
+
 	// 如果是目录标记，只需要创建一个空对象
 	if file.IsDir {
-		input := &s3.PutObjectInput{
-			Bucket: aws.String(u.options.Bucket),
-			Key:    aws.String(file.Key),
-			Body:   strings.NewReader(""),
+		etag, err := u.storage.Put(file.Key, strings.NewReader(""), &storage.PutOptions{
+			Metadata:     file.Attrs.Metadata(),
+			StorageClass: u.options.StorageClass,
+		})
+		if err != nil {
+			return i18n.Errorf("创建目录标记失败: %w", err)
 		}
+		file.ETag = etag
 
-		_, err := u.s3.PutObject(input)
-		if err != nil {
-			return fmt.Errorf("创建目录标记失败: %w", err)
+		if u.options.Verify {
+			if err := u.verifyUpload(file); err != nil {
+				return err
+			}
 		}
 
 		// 更新进度
-		u.progress.AddFile(0)
+		u.progress.AddFile(file.Key, 0)
 		return nil
 	}
 
+	// 文件在扫描后发生变化，说明仍在写入
+	if u.options.StableWindow > 0 && u.fileChanged(file) {
+		return errFileChanging
+	}
+
 	// 打开本地文件
-	localFile, err := os.Open(file.Path)
+	localFile, err := u.openLocalFile(file)
 	if err != nil {
 		return err
 	}
 	defer localFile.Close()
 
-	// 上传文件
-	input := &s3.PutObjectInput{
-		Bucket: aws.String(u.options.Bucket),
-		Key:    aws.String(file.Key),
-		Body:   localFile,
+	// 记录上传内容的SHA-256，之后只有修改时间变化时可以判断内容是否相同
+	if file.SHA256, err = state.Hash(localFile); err != nil {
+		return err
 	}
-
-	_, err = u.s3.PutObject(input)
-	if err != nil {
+	if _, err := localFile.Seek(0, io.SeekStart); err != nil {
 		return err
 	}
 
+	// 上传文件（文件属性记录在对象元数据中，按规则设置Cache-Control等HTTP头）
+	options := u.putOptions(file)
+
+	// 按发送的字节数更新进度，大文件上传过程中进度也会变化
+	counter := u.progress.NewCounter(file.Key, file.Size)
+	defer counter.Close()
+	options.Progress = counter
+
+	// 按规则压缩后上传
+	if algorithm := u.compressAlgorithm(file.Key); algorithm != "" {
+		return u.uploadCompressed(file, localFile, options, algorithm, counter)
+	}
+
+	if file.Size > multipartThreshold {
+		// 大文件分片并发上传
+		if err := u.uploadMultipart(file, file.Key, localFile, file.Size, options); err != nil {
+			return err
+		}
+	} else {
+		// 附加校验值由服务端验证并随对象保存
+		if u.options.Checksum != "" {
+			checksum, err := readerChecksum(localFile, u.options.Checksum)
+			if err != nil {
+				return i18n.Errorf("计算校验值失败: %w", err)
+			}
+			if _, err := localFile.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			file.Checksum = checksum
+			options.ChecksumAlgorithm = u.options.Checksum
+			options.Checksum = checksum
+		}
+
+		etag, err := u.storage.Put(file.Key, localFile, options)
+		if err != nil {
+			return err
+		}
+		file.ETag = etag
+	}
+
+	// 校验上传结果
+	if u.options.Verify {
+		if err := u.verifyUpload(file); err != nil {
+			return err
+		}
+	}
+
 	// 更新进度
-	u.progress.AddFile(file.Size)
+	counter.Done(file.Size)
 
 	return nil
 }
 
 // updateState 更新上传状态
-func (u *Upload) updateState(files []*LocalFile) {
-	if !u.options.Incremental {
-		return
+func (u *Upload) updateState(files []*LocalFile) error {
+	// 上次中断前已经上传的文件
+	for key, entry := range u.resumed {
+		if err := u.state.Put(key, entry); err != nil {
+			return err
+		}
 	}
 
 	for _, file := range files {
-		u.state.Files[file.Key] = FileState{
-			ETag:         "", // 上传后可以从响应中获取ETag，这里简化处理
-			LastModified: file.LastModified,
-			Size:         file.Size,
+		// 推迟的文件未上传，保持原状态以便下次重新检查
+		if file.Deferred {
+			continue
 		}
+
+		if err := u.state.Put(file.Key, u.fileState(file)); err != nil {
+			return err
+		}
+	}
+
+	if _, err := u.updateTouched(); err != nil {
+		return err
+	}
+	_, err := u.markDeleted()
+	return err
+}
+
+// updateTouched 更新只有修改时间变化的文件在状态中的修改时间，返回更新的数量
+func (u *Upload) updateTouched() (int, error) {
+	for _, file := range u.touched {
+		entry, _ := u.state.Get(file.Key)
+		entry.LastModified = file.LastModified
+		if err := u.state.Put(file.Key, entry); err != nil {
+			return 0, err
+		}
+	}
+	return len(u.touched), nil
+}
+
+// markDeleted 把状态中有记录、但本次扫描中已经没有的本地文件标记为已删除，返回新标记的数量。
+// 删除记录保留删除的时间，文件重新出现并上传后被新的状态替换
+func (u *Upload) markDeleted() (int, error) {
+	if u.scanned == nil {
+		return 0, nil
+	}
+
+	// 遍历时不能修改存储，先收集删除的文件
+	var deleted []string
+	err := u.state.Range("", func(key string, entry state.Entry) bool {
+		if !u.scanned[key] && !entry.Deleted() {
+			deleted = append(deleted, key)
+		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	for _, key := range deleted {
+		entry, _ := u.state.Get(key)
+		entry.DeletedAt = &now
+		if err := u.state.Put(key, entry); err != nil {
+			return 0, err
+		}
+	}
+	if len(deleted) > 0 {
+		logger.Infof("%d 个本地文件已删除，已在状态中记录", len(deleted))
+	}
+	return len(deleted), nil
+}
+
+// fileState 生成已上传文件的状态记录
+func (u *Upload) fileState(file *LocalFile) state.Entry {
+	return state.Entry{
+		ETag:         file.ETag,
+		Checksum:     u.stateChecksum(file),
+		SHA256:       file.SHA256,
+		Pack:         file.Pack,
+		LastModified: file.LastModified,
+		Size:         file.Size,
+	}
+}
+
+// stateChecksum 生成状态文件中记录的校验值
+func (u *Upload) stateChecksum(file *LocalFile) string {
+	if file.Checksum == "" {
+		return ""
 	}
+	return u.options.Checksum + ":" + file.Checksum
 }
@@ -0,0 +1,88 @@
+package upload
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"strings"
+
+	"objectsync/internal/i18n"
+)
+
+// verifyError 上传后校验不一致
+type verifyError struct {
+	key    string
+	reason string
+}
+
+// Error 实现error接口
+func (e *verifyError) Error() string {
+	return i18n.Sprintf("上传校验失败 %s: %s", e.key, e.reason)
+}
+
+// verifyUpload 上传后通过HEAD请求校验对象大小和ETag是否与本地文件一致
+func (u *Upload) verifyUpload(file *LocalFile) error {
+	head, err := u.storage.Head(file.Key, u.options.Checksum)
+	if err != nil {
+		return i18n.Errorf("校验时获取对象信息失败: %w", err)
+	}
+
+	remoteSize := head.Size
+	if remoteSize != file.Size {
+		return &verifyError{
+			key:    file.Key,
+			reason: i18n.Sprintf("大小不一致（本地 %d，远程 %d）", file.Size, remoteSize),
+		}
+	}
+
+	if file.IsDir {
+		return nil
+	}
+
+	// 有附加校验值时优先比较校验值
+	if file.Checksum != "" {
+		if remote := head.Checksum; remote != "" {
+			if remote != file.Checksum {
+				return &verifyError{
+					key:    file.Key,
+					reason: i18n.Sprintf("%s校验值不一致（本地 %s，远程 %s）", u.options.Checksum, file.Checksum, remote),
+				}
+			}
+			return nil
+		}
+	}
+
+	// 分片上传的ETag不是内容MD5（带有"-"后缀），只能校验大小
+	etag := head.ETag
+	if etag == "" || strings.Contains(etag, "-") {
+		return nil
+	}
+
+	localMD5, err := u.localMD5(file)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(localMD5, etag) {
+		return &verifyError{
+			key:    file.Key,
+			reason: i18n.Sprintf("ETag不一致（本地MD5 %s，远程 %s）", localMD5, etag),
+		}
+	}
+
+	return nil
+}
+
+// localMD5 计算本地文件的MD5，文件被占用时读取卷影副本
+func (u *Upload) localMD5(file *LocalFile) (string, error) {
+	localFile, err := u.openLocalFile(file)
+	if err != nil {
+		return "", err
+	}
+	defer localFile.Close()
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, localFile); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
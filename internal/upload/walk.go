@@ -0,0 +1,189 @@
+package upload
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"objectsync/internal/fileattr"
+)
+
+// defaultScanWorkers 默认的并发目录扫描数
+const defaultScanWorkers = 8
+
+// walker 并发目录遍历器
+type walker struct {
+	root   string
+	prefix string
+	out    chan<- *LocalFile
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending []string // 等待读取的目录
+	active  int      // 正在读取目录的协程数
+	stopped bool
+
+	errOnce sync.Once
+	err     error
+	failed  chan struct{}
+}
+
+// walkParallel 并发遍历目录树，发现的文件和目录立即发送到out，遍历结束后返回
+// 固定数量的协程从待读取目录队列中取目录，协程数和同时打开的目录数都不超过workers，对象键会加上prefix前缀
+func walkParallel(root, prefix string, workers int, out chan<- *LocalFile) error {
+	if workers <= 0 {
+		workers = defaultScanWorkers
+	}
+
+	w := &walker{
+		root:    root,
+		prefix:  normalizePrefix(prefix),
+		out:     out,
+		pending: []string{root},
+		failed:  make(chan struct{}),
+	}
+	w.cond = sync.NewCond(&w.mu)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				dir, ok := w.next()
+				if !ok {
+					return
+				}
+				w.walkDir(dir)
+				w.done()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return w.err
+}
+
+// next 取出一个待读取的目录，队列为空时等待其他协程发现新目录，全部读完或出错时返回false
+func (w *walker) next() (string, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for len(w.pending) == 0 && w.active > 0 && !w.stopped {
+		w.cond.Wait()
+	}
+	if len(w.pending) == 0 || w.stopped {
+		return "", false
+	}
+
+	// 后进先出，接近深度优先，队列中的目录数量较少
+	dir := w.pending[len(w.pending)-1]
+	w.pending = w.pending[:len(w.pending)-1]
+	w.active++
+	return dir, true
+}
+
+// done 标记一个目录读取完成，没有待读取和正在读取的目录时唤醒等待的协程结束
+func (w *walker) done() {
+	w.mu.Lock()
+	w.active--
+	if w.active == 0 && len(w.pending) == 0 {
+		w.cond.Broadcast()
+	}
+	w.mu.Unlock()
+}
+
+// push 将发现的子目录加入待读取队列
+func (w *walker) push(dir string) {
+	w.mu.Lock()
+	w.pending = append(w.pending, dir)
+	w.cond.Signal()
+	w.mu.Unlock()
+}
+
+// walkDir 读取单个目录，子目录加入待读取队列
+func (w *walker) walkDir(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		w.fail(err)
+		return
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+
+		info, err := entry.Info()
+		if err != nil {
+			w.fail(err)
+			return
+		}
+
+		file, err := w.newLocalFile(path, info)
+		if err != nil {
+			w.fail(err)
+			return
+		}
+
+		select {
+		case <-w.failed:
+			return
+		case w.out <- file:
+		}
+
+		if entry.IsDir() {
+			w.push(path)
+		}
+	}
+}
+
+// newLocalFile 根据文件信息创建本地文件描述
+func (w *walker) newLocalFile(path string, info os.FileInfo) (*LocalFile, error) {
+	// 计算相对路径作为对象键
+	relPath, err := filepath.Rel(w.root, path)
+	if err != nil {
+		return nil, err
+	}
+
+	// 将路径分隔符转换为正斜杠（对象存储标准）
+	key := w.prefix + strings.ReplaceAll(relPath, "\\", "/")
+
+	file := &LocalFile{
+		Path:         path,
+		Key:          key,
+		Size:         info.Size(),
+		LastModified: info.ModTime(),
+		IsDir:        info.IsDir(),
+		Attrs:        fileattr.FromFileInfo(info),
+	}
+
+	// 如果是目录，添加目录标记（以/结尾）
+	if info.IsDir() {
+		file.Key += "/"
+		file.Size = 0
+	}
+
+	return file, nil
+}
+
+// fail 记录第一个错误并通知所有协程停止
+func (w *walker) fail(err error) {
+	w.errOnce.Do(func() {
+		w.err = err
+		close(w.failed)
+
+		w.mu.Lock()
+		w.stopped = true
+		w.cond.Broadcast()
+		w.mu.Unlock()
+	})
+}
+
+// normalizePrefix 规范化键前缀：去掉开头的"/"，非空时以"/"结尾
+func normalizePrefix(prefix string) string {
+	prefix = strings.TrimLeft(strings.ReplaceAll(prefix, "\\", "/"), "/")
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return prefix
+}